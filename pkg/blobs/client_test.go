@@ -178,7 +178,7 @@ func TestBlobClientReadFile(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			reader, err := blobClient.ReadFile(ctx, tc.filename)
+			reader, size, err := blobClient.ReadFile(ctx, tc.filename, 0)
 			if err != nil {
 				if testutils.IsError(err, tc.err) {
 					// correct error was returned
@@ -194,6 +194,21 @@ func TestBlobClientReadFile(t *testing.T) {
 			if !bytes.Equal(content, tc.fileContent) {
 				t.Fatal(fmt.Sprintf(`fetched file content incorrect, expected %s, got %s`, tc.fileContent, content))
 			}
+			if size != int64(len(tc.fileContent)) {
+				t.Fatalf(`expected file size %d, got %d`, len(tc.fileContent), size)
+			}
+			// Check that reading from an offset skips the start of the file.
+			reader, _, err = blobClient.ReadFile(ctx, tc.filename, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err = ioutil.ReadAll(reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(content, tc.fileContent[2:]) {
+				t.Fatalf(`fetched file content incorrect, expected %s, got %s`, tc.fileContent[2:], content)
+			}
 		})
 	}
 }
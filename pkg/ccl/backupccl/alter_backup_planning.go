@@ -0,0 +1,107 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// alterBackupPlanHook implements PlanHookFn.
+//
+// ALTER BACKUP ... ADD NEW_KMS re-encrypts the key of a backup encrypted with
+// a KMS with the master keys of additional KMSs. Only the encryption info
+// stored alongside the backup is rewritten; its files are left untouched.
+func alterBackupPlanHook(
+	_ context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	alterBackupStmt, ok := stmt.(*tree.AlterBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	backupFn, err := p.TypeAsString(alterBackupStmt.Backup, "ALTER BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	newKMSFn, err := p.TypeAsStringArray(alterBackupStmt.NewKMSURIs, "ALTER BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	oldKMSFn, err := p.TypeAsStringArray(alterBackupStmt.OldKMSURIs, "ALTER BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(), "ALTER BACKUP",
+		); err != nil {
+			return err
+		}
+
+		if err := p.RequireAdminRole(ctx, "ALTER BACKUP"); err != nil {
+			return err
+		}
+
+		backup, err := backupFn()
+		if err != nil {
+			return err
+		}
+		newKMSURIs, err := newKMSFn()
+		if err != nil {
+			return err
+		}
+		oldKMSURIs, err := oldKMSFn()
+		if err != nil {
+			return err
+		}
+
+		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, backup)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open backup storage location")
+		}
+		defer store.Close()
+
+		encInfo, err := readEncryptionOptions(ctx, store)
+		if err != nil {
+			return err
+		}
+		if len(encInfo.EncryptedDataKeyByKMSMasterKeyID) == 0 {
+			return errors.Errorf("ALTER BACKUP can only add KMSs to a backup encrypted with a KMS")
+		}
+		key, err := unwrapEncryptionKey(ctx, p.ExecCfg().Settings, encInfo, oldKMSURIs)
+		if err != nil {
+			return err
+		}
+		if err := wrapEncryptionKey(ctx, p.ExecCfg().Settings, encInfo, key, newKMSURIs); err != nil {
+			return err
+		}
+		if err := writeEncryptionOptions(ctx, encInfo, store); err != nil {
+			return err
+		}
+		telemetry.Count("backup.alter.add-kms")
+		return nil
+	}
+	return fn, nil, nil, false, nil
+}
+
+func init() {
+	sql.AddPlanHook(alterBackupPlanHook)
+}
@@ -330,6 +330,7 @@ func backup(
 						Sha512:      file.Sha512,
 						EntryCounts: countRows(file.Exported, pkIDs),
 						LocalityKV:  file.LocalityKV,
+						FileSize:    file.FileSize,
 					}
 					if span.start != backupManifest.StartTime {
 						f.StartTime = span.start
@@ -558,6 +559,14 @@ func (b *backupResumer) Resume(
 		}
 	}
 
+	if details.ScheduleID != 0 {
+		// The backup supersedes the older backups of its schedule, whose
+		// retention may have expired now that it succeeded.
+		if err := expireScheduledCollections(ctx, p.ExecCfg(), details.ScheduleID, details.URI); err != nil {
+			log.Warningf(ctx, "%v", err)
+		}
+	}
+
 	resultsCh <- tree.Datums{
 		tree.NewDInt(tree.DInt(*b.job.ID())),
 		tree.NewDString(string(jobs.StatusSucceeded)),
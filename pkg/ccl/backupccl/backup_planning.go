@@ -14,7 +14,6 @@ import (
 	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
-	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
@@ -47,7 +46,10 @@ import (
 const (
 	backupOptRevisionHistory = "revision_history"
 	backupOptEncPassphrase   = "encryption_passphrase"
+	backupOptEncKMS          = "kms"
 	backupOptWithPrivileges  = "privileges"
+	backupOptDetached        = "detached"
+	backupOptCheckFiles      = "check_files"
 	localityURLParam         = "COCKROACH_LOCALITY"
 	defaultLocalityValue     = "default"
 )
@@ -77,6 +79,7 @@ var useTBI = settings.RegisterBoolSetting(
 var backupOptionExpectValues = map[string]sql.KVStringOptValidate{
 	backupOptRevisionHistory: sql.KVStringOptRequireNoValue,
 	backupOptEncPassphrase:   sql.KVStringOptRequireValue,
+	backupOptDetached:        sql.KVStringOptRequireNoValue,
 }
 
 type tableAndIndex struct {
@@ -151,6 +154,47 @@ func optsToKVOptions(opts map[string]string) tree.KVOptions {
 	return kvopts
 }
 
+// splitKMSOptions separates the kms options, which can be repeated to encrypt
+// a backup with the master keys of several KMSs, from the other options.
+func splitKMSOptions(opts tree.KVOptions) (kmsURIs tree.Exprs, rest tree.KVOptions) {
+	for _, opt := range opts {
+		if opt.Key == backupOptEncKMS {
+			kmsURIs = append(kmsURIs, opt.Value)
+		} else {
+			rest = append(rest, opt)
+		}
+	}
+	return kmsURIs, rest
+}
+
+// typeAsKMSURIs type checks the kms options of a statement.
+func typeAsKMSURIs(
+	p sql.PlanHookState, kmsURIs tree.Exprs, op string,
+) (func() ([]string, error), error) {
+	for _, uri := range kmsURIs {
+		if uri == nil {
+			return nil, errors.Errorf("option %q requires a value", backupOptEncKMS)
+		}
+	}
+	return p.TypeAsStringArray(kmsURIs, op)
+}
+
+// kmsToKVOptions returns the kms options of a statement, with the secrets of
+// their URIs redacted.
+func kmsToKVOptions(kmsURIs []string) (tree.KVOptions, error) {
+	kvopts := make(tree.KVOptions, 0, len(kmsURIs))
+	for _, uri := range kmsURIs {
+		sanitized, err := cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+		if err != nil {
+			return nil, err
+		}
+		kvopts = append(kvopts, tree.KVOption{
+			Key: backupOptEncKMS, Value: tree.NewDString(sanitized),
+		})
+	}
+	return kvopts, nil
+}
+
 // getURIsByLocalityKV takes a slice of URIs for a single (possibly partitioned)
 // backup, and returns the default backup destination URI and a map of all other
 // URIs by locality KV, apppending appendPath to the path component of both the
@@ -225,14 +269,29 @@ func backupJobDescription(
 	p sql.PlanHookState,
 	backup *tree.Backup,
 	to []string,
+	collection []string,
+	subdir string,
 	incrementalFrom []string,
 	opts map[string]string,
+	kmsURIs []string,
 ) (string, error) {
 	b := &tree.Backup{
 		AsOf:    backup.AsOf,
 		Options: optsToKVOptions(opts),
 		Targets: backup.Targets,
 	}
+	// The description of a backup into a collection refers to the resolved
+	// subdirectory of the collection rather than to LATEST.
+	if backup.Nested {
+		b.Nested = true
+		b.Subdir = tree.NewDString(subdir)
+		to = collection
+	}
+	kmsOpts, err := kmsToKVOptions(kmsURIs)
+	if err != nil {
+		return "", err
+	}
+	b.Options = append(b.Options, kmsOpts...)
 
 	for _, t := range to {
 		sanitizedTo, err := cloud.SanitizeExternalStorageURI(t, nil /* extraParams */)
@@ -271,11 +330,31 @@ func backupPlanHook(
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
-	optsFn, err := p.TypeAsStringOpts(backupStmt.Options, backupOptionExpectValues)
+	var subdirFn func() (string, error)
+	if backupStmt.Subdir != nil {
+		if subdirFn, err = p.TypeAsString(backupStmt.Subdir, "BACKUP"); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+	kmsExprs, options := splitKMSOptions(backupStmt.Options)
+	kmsFn, err := typeAsKMSURIs(p, kmsExprs, "BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	optsFn, err := p.TypeAsStringOpts(options, backupOptionExpectValues)
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
 
+	// A detached backup only creates its job, which the registry adopts once the
+	// transaction commits, and returns its ID.
+	var detached bool
+	for _, opt := range options {
+		if opt.Key == backupOptDetached {
+			detached = true
+		}
+	}
+
 	header := sqlbase.ResultColumns{
 		{Name: "job_id", Typ: types.Int},
 		{Name: "status", Typ: types.String},
@@ -284,6 +363,9 @@ func backupPlanHook(
 		{Name: "index_entries", Typ: types.Int},
 		{Name: "bytes", Typ: types.Int},
 	}
+	if detached {
+		header = sqlbase.ResultColumns{{Name: "job_id", Typ: types.Int}}
+	}
 
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 		// TODO(dan): Move this span into sql.
@@ -300,8 +382,9 @@ func backupPlanHook(
 			return err
 		}
 
-		if !p.ExtendedEvalContext().TxnImplicit {
-			return errors.Errorf("BACKUP cannot be used inside a transaction")
+		if !detached && !p.ExtendedEvalContext().TxnImplicit {
+			return errors.Errorf("BACKUP cannot be used inside a transaction without the %s option",
+				backupOptDetached)
 		}
 
 		to, err := toFn()
@@ -326,6 +409,33 @@ func backupPlanHook(
 			}
 		}
 
+		makeCloudStorage := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+
+		// BACKUP INTO writes a full backup to a new subdirectory of the collection
+		// named after its end time, or appends an incremental backup to the full
+		// backup in an existing subdirectory.
+		collection := to
+		appendToCollection := backupStmt.AppendToLatest || subdirFn != nil
+		var subdir string
+		if backupStmt.Nested {
+			switch {
+			case backupStmt.AppendToLatest:
+				subdir = latestBackupSubdir
+			case subdirFn != nil:
+				if subdir, err = subdirFn(); err != nil {
+					return err
+				}
+			default:
+				subdir = endTime.GoTime().Format(backupCollectionSubdirFormat)
+			}
+			if subdir, err = resolveBackupSubdir(ctx, makeCloudStorage, collection, subdir); err != nil {
+				return err
+			}
+			if to, err = appendPathToURIs(collection, subdir); err != nil {
+				return err
+			}
+		}
+
 		opts, err := optsFn()
 		if err != nil {
 			return err
@@ -371,11 +481,13 @@ func backupPlanHook(
 			return err
 		}
 
-		makeCloudStorage := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
-
-		var encryptionPassphrase []byte
-		if passphrase, ok := opts[backupOptEncPassphrase]; ok {
-			encryptionPassphrase = []byte(passphrase)
+		kmsURIs, err := kmsFn()
+		if err != nil {
+			return err
+		}
+		encryptionParams, err := makeEncryptionParams(opts, kmsURIs)
+		if err != nil {
+			return err
 		}
 
 		defaultURI, urisByLocalityKV, err := getURIsByLocalityKV(to, "")
@@ -397,19 +509,16 @@ func backupPlanHook(
 		var prevBackups []BackupManifest
 		g := ctxgroup.WithContext(ctx)
 		if len(incrementalFrom) > 0 {
-			if encryptionPassphrase != nil {
+			if encryptionParams.enabled() {
 				exportStore, err := makeCloudStorage(ctx, incrementalFrom[0])
 				if err != nil {
 					return err
 				}
 				defer exportStore.Close()
-				opts, err := readEncryptionOptions(ctx, exportStore)
+				encryption, err = readEncryption(ctx, p.ExecCfg().Settings, exportStore, encryptionParams)
 				if err != nil {
 					return err
 				}
-				encryption = &roachpb.FileEncryptionOptions{
-					Key: storageccl.GenerateKey(encryptionPassphrase, opts.Salt),
-				}
 			}
 			prevBackups = make([]BackupManifest, len(incrementalFrom))
 			for i := range incrementalFrom {
@@ -440,15 +549,18 @@ func backupPlanHook(
 			if err != nil {
 				return err
 			}
+			if backupStmt.Nested {
+				if appendToCollection && !exists {
+					return errors.Errorf("no full backup found in %s", subdir)
+				}
+				if !appendToCollection && exists {
+					return errors.Errorf("a backup already exists in %s", subdir)
+				}
+			}
 			if exists {
-				if encryptionPassphrase != nil {
-					encOpts, err := readEncryptionOptions(ctx, defaultStore)
-					if err != nil {
-						return err
-					}
-					encryption = &roachpb.FileEncryptionOptions{
-						Key: storageccl.GenerateKey(encryptionPassphrase, encOpts.Salt),
-					}
+				encryption, err = readEncryption(ctx, p.ExecCfg().Settings, defaultStore, encryptionParams)
+				if err != nil {
+					return err
 				}
 
 				prev, err := findPriorBackups(ctx, defaultStore)
@@ -619,15 +731,16 @@ func backupPlanHook(
 			return err
 		}
 
-		description, err := backupJobDescription(p, backupStmt, to, incrementalFrom, opts)
+		description, err := backupJobDescription(p, backupStmt, to, collection, subdir, incrementalFrom, opts, kmsURIs)
 		if err != nil {
 			return err
 		}
 
 		// If we didn't load any prior backups from which get encryption info, we
-		// need to pick a new salt and record it.
-		if encryptionPassphrase != nil && encryption == nil {
-			salt, err := storageccl.GenerateSalt()
+		// need to pick a new salt or key and record it.
+		if encryptionParams.enabled() && encryption == nil {
+			var encInfo *EncryptionInfo
+			encryption, encInfo, err = makeNewEncryption(ctx, p.ExecCfg().Settings, encryptionParams)
 			if err != nil {
 				return err
 			}
@@ -636,10 +749,9 @@ func backupPlanHook(
 				return err
 			}
 			defer exportStore.Close()
-			if err := writeEncryptionOptions(ctx, &EncryptionInfo{Salt: salt}, exportStore); err != nil {
+			if err := writeEncryptionOptions(ctx, encInfo, exportStore); err != nil {
 				return err
 			}
-			encryption = &roachpb.FileEncryptionOptions{Key: storageccl.GenerateKey(encryptionPassphrase, salt)}
 		}
 
 		// TODO (lucy): For partitioned backups, also add verification for other
@@ -675,18 +787,42 @@ func backupPlanHook(
 			Details:  backupDetails,
 			Progress: jobspb.BackupProgress{},
 		}
+		protectSpans := func(ctx context.Context, txn *kv.Txn, jobID int64) error {
+			if len(spans) > 0 {
+				tsToProtect := endTime
+				rec := jobsprotectedts.MakeRecord(*backupDetails.ProtectedTimestampRecord, jobID, tsToProtect, spans)
+				return p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, txn, rec)
+			}
+			return nil
+		}
+
+		if detached {
+			// The job is created in the transaction of the statement, and adopted by
+			// the registry once that transaction commits.
+			txn := p.ExtendedEvalContext().Txn
+			j, err := p.ExecCfg().JobRegistry.CreateJobWithTxn(ctx, jr, txn)
+			if err != nil {
+				return err
+			}
+			if err := protectSpans(ctx, txn, *j.ID()); err != nil {
+				return err
+			}
+			collectBackupTelemetry(startTime, backupStmt, incrementalFrom, mvccFilter, encryption, encryptionParams)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(*j.ID()))}:
+			}
+			return nil
+		}
+
 		var sj *jobs.StartableJob
 		if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
 			sj, err = p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, jr, txn, resultsCh)
 			if err != nil {
 				return err
 			}
-			if len(spans) > 0 {
-				tsToProtect := endTime
-				rec := jobsprotectedts.MakeRecord(*backupDetails.ProtectedTimestampRecord, *sj.ID(), tsToProtect, spans)
-				return p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, txn, rec)
-			}
-			return nil
+			return protectSpans(ctx, txn, *sj.ID())
 		}); err != nil {
 			if sj != nil {
 				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
@@ -695,31 +831,7 @@ func backupPlanHook(
 			}
 		}
 
-		// Collect telemetry.
-		{
-			telemetry.Count("backup.total.started")
-			if startTime.IsEmpty() {
-				telemetry.Count("backup.span.full")
-			} else {
-				telemetry.Count("backup.span.incremental")
-				telemetry.CountBucketed("backup.incremental-span-sec", int64(timeutil.Since(startTime.GoTime()).Seconds()))
-				if len(incrementalFrom) == 0 {
-					telemetry.Count("backup.auto-incremental")
-				}
-			}
-			if len(backupStmt.To) > 1 {
-				telemetry.Count("backup.partitioned")
-			}
-			if mvccFilter == MVCCFilter_All {
-				telemetry.Count("backup.revision-history")
-			}
-			if encryption != nil {
-				telemetry.Count("backup.encrypted")
-			}
-			if backupStmt.DescriptorCoverage == tree.AllDescriptors {
-				telemetry.Count("backup.targets.full_cluster")
-			}
-		}
+		collectBackupTelemetry(startTime, backupStmt, incrementalFrom, mvccFilter, encryption, encryptionParams)
 
 		errCh, err := sj.Start(ctx)
 		if err != nil {
@@ -730,6 +842,45 @@ func backupPlanHook(
 	return fn, header, nil, false, nil
 }
 
+// collectBackupTelemetry counts the telemetry of a newly created backup job.
+func collectBackupTelemetry(
+	startTime hlc.Timestamp,
+	backupStmt *tree.Backup,
+	incrementalFrom []string,
+	mvccFilter MVCCFilter,
+	encryption *roachpb.FileEncryptionOptions,
+	encryptionParams backupEncryptionParams,
+) {
+	telemetry.Count("backup.total.started")
+	if startTime.IsEmpty() {
+		telemetry.Count("backup.span.full")
+	} else {
+		telemetry.Count("backup.span.incremental")
+		telemetry.CountBucketed("backup.incremental-span-sec", int64(timeutil.Since(startTime.GoTime()).Seconds()))
+		if len(incrementalFrom) == 0 {
+			telemetry.Count("backup.auto-incremental")
+		}
+	}
+	if len(backupStmt.To) > 1 {
+		telemetry.Count("backup.partitioned")
+	}
+	if mvccFilter == MVCCFilter_All {
+		telemetry.Count("backup.revision-history")
+	}
+	if encryption != nil {
+		telemetry.Count("backup.encrypted")
+		if len(encryptionParams.kmsURIs) > 0 {
+			telemetry.Count("backup.encryption.kms")
+		}
+	}
+	if backupStmt.DescriptorCoverage == tree.AllDescriptors {
+		telemetry.Count("backup.targets.full_cluster")
+	}
+	if backupStmt.Nested {
+		telemetry.Count("backup.collection")
+	}
+}
+
 // checkForNewTables returns an error if any new tables were introduced with the
 // following exceptions:
 // 1. A previous backup contained the entire DB.
@@ -0,0 +1,223 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"io/ioutil"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/errors"
+)
+
+const backupRevisionsName = "crdb_internal.backup_revisions"
+
+// backupRevisionsGenerator implements crdb_internal.backup_revisions, which
+// lists the revisions of a span of keys found in a chain of backups.
+//
+// The revisions are all read when the generator starts, so it is meant to be
+// used on small spans, e.g. to find the rows to recover with the span_filter
+// option of RESTORE.
+type backupRevisionsGenerator struct {
+	p                sql.PlanHookState
+	uris             []string
+	span             roachpb.Span
+	encryptionParams backupEncryptionParams
+
+	revisions []storage.MVCCKeyValue
+	cur       storage.MVCCKeyValue
+}
+
+var _ tree.ValueGenerator = &backupRevisionsGenerator{}
+
+func makeBackupRevisionsGenerator(
+	evalCtx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	p, ok := evalCtx.Planner.(sql.PlanHookState)
+	if !ok {
+		return nil, errors.Errorf("%s cannot be used in this context", backupRevisionsName)
+	}
+	var uris []string
+	for _, d := range tree.MustBeDArray(args[0]).Array {
+		if d == tree.DNull {
+			return nil, errors.Errorf("%s: backup URIs cannot be NULL", backupRevisionsName)
+		}
+		uris = append(uris, string(tree.MustBeDString(d)))
+	}
+	if len(uris) == 0 {
+		return nil, errors.Errorf("%s: no backup URIs given", backupRevisionsName)
+	}
+	span := roachpb.Span{
+		Key:    roachpb.Key(*args[1].(*tree.DBytes)),
+		EndKey: roachpb.Key(*args[2].(*tree.DBytes)),
+	}
+	if len(span.EndKey) == 0 {
+		span.EndKey = roachpb.KeyMax
+	}
+	if span.Key.Compare(span.EndKey) >= 0 {
+		return nil, errors.New("start key must be less than end key")
+	}
+	g := &backupRevisionsGenerator{p: p, uris: uris, span: span}
+	if len(args) > 3 && args[3] != tree.DNull {
+		g.encryptionParams.passphrase = []byte(tree.MustBeDString(args[3]))
+	}
+	return g, nil
+}
+
+// ResolvedType is part of the tree.ValueGenerator interface.
+func (*backupRevisionsGenerator) ResolvedType() *types.T {
+	return builtins.BackupRevisionsGeneratorType
+}
+
+// Start is part of the tree.ValueGenerator interface.
+func (g *backupRevisionsGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	execCfg := g.p.ExecCfg()
+	if err := utilccl.CheckEnterpriseEnabled(
+		execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), backupRevisionsName,
+	); err != nil {
+		return err
+	}
+	if err := g.p.RequireAdminRole(ctx, backupRevisionsName); err != nil {
+		return err
+	}
+
+	makeCloudStorage := execCfg.DistSQLSrv.ExternalStorageFromURI
+	// The backups of a chain are all encrypted with the key of the full backup.
+	encryption, err := func() (*roachpb.FileEncryptionOptions, error) {
+		store, err := makeCloudStorage(ctx, g.uris[0])
+		if err != nil {
+			return nil, err
+		}
+		defer store.Close()
+		return readEncryption(ctx, execCfg.Settings, store, g.encryptionParams)
+	}()
+	if err != nil {
+		return err
+	}
+	manifests, err := loadBackupManifests(ctx, g.uris, makeCloudStorage, encryption)
+	if err != nil {
+		return err
+	}
+	for i := range manifests {
+		if err := g.readBackupRevisions(
+			ctx, makeCloudStorage, g.uris[i], &manifests[i], encryption,
+		); err != nil {
+			return err
+		}
+	}
+	// Return the revisions of every key from the latest to the oldest, like
+	// they are stored.
+	sort.Slice(g.revisions, func(i, j int) bool {
+		return g.revisions[i].Key.Less(g.revisions[j].Key)
+	})
+	return nil
+}
+
+// readBackupRevisions adds to the revisions of g those of its span found in
+// the files of the backup stored at uri.
+func (g *backupRevisionsGenerator) readBackupRevisions(
+	ctx context.Context,
+	makeCloudStorage cloud.ExternalStorageFromURIFactory,
+	uri string,
+	manifest *BackupManifest,
+	encryption *roachpb.FileEncryptionOptions,
+) error {
+	store, err := makeCloudStorage(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	startKey, endKey := storage.MVCCKey{Key: g.span.Key}, storage.MVCCKey{Key: g.span.EndKey}
+	for _, f := range manifest.Files {
+		if !f.Span.Overlaps(g.span) {
+			continue
+		}
+		if f.LocalityKV != "" {
+			return errors.Errorf("%s: cannot read the files stored in locality %s of a partitioned backup",
+				backupRevisionsName, f.LocalityKV)
+		}
+		r, err := store.ReadFile(ctx, f.Path)
+		if err != nil {
+			return errors.Wrapf(err, "reading backup file %s", f.Path)
+		}
+		data, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return errors.Wrapf(err, "reading backup file %s", f.Path)
+		}
+		if encryption != nil {
+			if data, err = storageccl.DecryptFile(data, encryption.Key); err != nil {
+				return errors.Wrapf(err, "decrypting backup file %s", f.Path)
+			}
+		}
+		iter, err := storage.NewMemSSTIterator(data, false)
+		if err != nil {
+			return err
+		}
+		for iter.SeekGE(startKey); ; iter.Next() {
+			ok, err := iter.Valid()
+			if err != nil {
+				iter.Close()
+				return err
+			}
+			if !ok || !iter.UnsafeKey().Less(endKey) {
+				break
+			}
+			key := iter.UnsafeKey()
+			g.revisions = append(g.revisions, storage.MVCCKeyValue{
+				Key:   storage.MVCCKey{Key: append(roachpb.Key(nil), key.Key...), Timestamp: key.Timestamp},
+				Value: append([]byte(nil), iter.UnsafeValue()...),
+			})
+		}
+		iter.Close()
+	}
+	return nil
+}
+
+// Next is part of the tree.ValueGenerator interface.
+func (g *backupRevisionsGenerator) Next(_ context.Context) (bool, error) {
+	if len(g.revisions) == 0 {
+		return false, nil
+	}
+	g.cur = g.revisions[0]
+	g.revisions = g.revisions[1:]
+	return true, nil
+}
+
+// Values is part of the tree.ValueGenerator interface.
+func (g *backupRevisionsGenerator) Values() (tree.Datums, error) {
+	value := tree.Datum(tree.DNull)
+	if len(g.cur.Value) > 0 {
+		value = tree.NewDBytes(tree.DBytes(g.cur.Value))
+	}
+	return tree.Datums{
+		tree.NewDBytes(tree.DBytes(g.cur.Key.Key)),
+		tree.NewDString(g.cur.Key.Key.String()),
+		tree.TimestampToDecimal(g.cur.Key.Timestamp),
+		value,
+	}, nil
+}
+
+// Close is part of the tree.ValueGenerator interface.
+func (g *backupRevisionsGenerator) Close() {}
+
+func init() {
+	builtins.MakeBackupRevisionsGenerator = makeBackupRevisionsGenerator
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	_ "github.com/cockroachdb/cockroach/pkg/ccl/partitionccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/sampledataccl"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
@@ -428,6 +429,11 @@ func TestBackupRestoreAppend(t *testing.T) {
 
 	sqlDB.ExpectErr(t, "cannot append a backup of specific", "BACKUP system.users TO ($1, $2, $3)", backups...)
 
+	// The files of every locality of the appended backups are checked, which
+	// requires the URIs of all the localities.
+	sqlDB.Exec(t, "SHOW BACKUP ($1, $2, $3) WITH check_files", backups...)
+	sqlDB.ExpectErr(t, "not found in backup locations", "SHOW BACKUP $1 WITH check_files", backups[0])
+
 	sqlDB.Exec(t, "DROP DATABASE data CASCADE")
 	sqlDB.Exec(t, "RESTORE DATABASE data FROM ($1, $2, $3)", backups...)
 	sqlDB.ExpectErr(t, "relation \"data.bank\" does not exist", "SELECT * FROM data.bank ORDER BY id")
@@ -436,6 +442,46 @@ func TestBackupRestoreAppend(t *testing.T) {
 	// TODO(dt): test restoring to other backups via AOST.
 }
 
+func TestBackupRestoreCollection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	const collection = localFoo + "/collection"
+	sqlDB.ExpectErr(t, "no full backup found in collection",
+		"BACKUP DATABASE data INTO LATEST IN $1", collection)
+
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO $1", collection)
+	sqlDB.Exec(t, "UPDATE data.bank SET balance = 100")
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO LATEST IN $1", collection)
+	rowsFirst := sqlDB.QueryStr(t, "SELECT * FROM data.bank ORDER BY id")
+
+	sqlDB.Exec(t, "UPDATE data.bank SET balance = 200")
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO $1", collection)
+	rowsSecond := sqlDB.QueryStr(t, "SELECT * FROM data.bank ORDER BY id")
+
+	var backups []string
+	for _, row := range sqlDB.QueryStr(t, "SHOW BACKUPS IN $1", collection) {
+		backups = append(backups, row[0])
+	}
+	require.Len(t, backups, 2)
+
+	// The incremental backup is appended to the first full backup.
+	require.Equal(t, [][]string{{"2"}}, sqlDB.QueryStr(t,
+		"SELECT count(DISTINCT end_time) FROM [SHOW BACKUP $1 IN $2]", backups[0], collection,
+	))
+
+	sqlDB.Exec(t, "DROP DATABASE data CASCADE")
+	sqlDB.Exec(t, "RESTORE DATABASE data FROM LATEST IN $1", collection)
+	sqlDB.CheckQueryResults(t, "SELECT * FROM data.bank ORDER BY id", rowsSecond)
+
+	sqlDB.Exec(t, "DROP DATABASE data CASCADE")
+	sqlDB.Exec(t, "RESTORE DATABASE data FROM $1 IN $2", backups[0], collection)
+	sqlDB.CheckQueryResults(t, "SELECT * FROM data.bank ORDER BY id", rowsFirst)
+}
+
 func TestBackupRestorePartitionedMergeDirectories(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1386,6 +1432,133 @@ func TestRestoreFailCleanup(t *testing.T) {
 	)
 }
 
+// TestBackupCheckFilesAndVerifyOnly tests that SHOW BACKUP WITH check_files and
+// RESTORE WITH verify_only detect missing and corrupted backup files.
+func TestBackupCheckFilesAndVerifyOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1000
+	_, _, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	dir = dir + "/foo"
+
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, localFoo)
+	sqlDB.Exec(t, `SHOW BACKUP $1 WITH check_files`, localFoo)
+
+	var tablesBefore int
+	sqlDB.QueryRow(t, `SELECT count(*) FROM crdb_internal.tables`).Scan(&tablesBefore)
+	var unused string
+	var restored struct {
+		rows, idx, bytes int64
+	}
+	sqlDB.QueryRow(t, `RESTORE data.* FROM $1 WITH verify_only`, localFoo).Scan(
+		&unused, &unused, &unused, &restored.rows, &restored.idx, &restored.bytes,
+	)
+	if restored.rows != numAccounts {
+		t.Fatalf("expected %d rows to be verified, got %d", numAccounts, restored.rows)
+	}
+	// Nothing is restored when verifying.
+	var tablesAfter int
+	sqlDB.QueryRow(t, `SELECT count(*) FROM crdb_internal.tables`).Scan(&tablesAfter)
+	if tablesBefore != tablesAfter {
+		t.Fatalf("expected %d tables, got %d", tablesBefore, tablesAfter)
+	}
+
+	ssts, err := filepath.Glob(filepath.Join(dir, "*.sst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ssts) == 0 {
+		t.Fatal("no sst found in the backup")
+	}
+	// Bugger the backup by truncating an SST file.
+	data, err := ioutil.ReadFile(ssts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(ssts[0], data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.ExpectErr(t, "backup file .* has size", `SHOW BACKUP $1 WITH check_files`, localFoo)
+	sqlDB.ExpectErr(t, ".+", `RESTORE data.* FROM $1 WITH verify_only`, localFoo)
+
+	if err := os.Remove(ssts[0]); err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.ExpectErr(t, "checking backup file", `SHOW BACKUP $1 WITH check_files`, localFoo)
+}
+
+// TestRestoreSpanFilter tests the restore of a range of the rows of a table
+// with the span_filter option, and the listing of the revisions of the rows
+// with crdb_internal.backup_revisions.
+func TestRestoreSpanFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	const full, inc = localFoo + "/full", localFoo + "/inc"
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH revision_history`, full)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id >= 3 AND id < 6`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 INCREMENTAL FROM $2 WITH revision_history`, inc, full)
+
+	sqlDB.Exec(t, `CREATE DATABASE side`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM $1 WITH into_db = 'side', span_filter = '3, 6'`, full)
+	sqlDB.CheckQueryResults(t, `SELECT id FROM side.bank ORDER BY id`, [][]string{{"3"}, {"4"}, {"5"}})
+
+	sqlDB.Exec(t, `DROP TABLE side.bank`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM $1 WITH into_db = 'side', span_filter = '(7)'`, full)
+	sqlDB.CheckQueryResults(t, `SELECT id FROM side.bank`, [][]string{{"7"}})
+
+	sqlDB.Exec(t, `DROP TABLE side.bank`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM $1 WITH into_db = 'side', span_filter = 'NULL, 2'`, full)
+	sqlDB.CheckQueryResults(t, `SELECT id FROM side.bank ORDER BY id`, [][]string{{"0"}, {"1"}})
+
+	sqlDB.ExpectErr(t, "can only be used when restoring a single table",
+		`RESTORE DATABASE data FROM $1 WITH span_filter = '1'`, full)
+	sqlDB.ExpectErr(t, "selects no rows",
+		`RESTORE data.bank FROM $1 WITH into_db = 'side', span_filter = '6, 3'`, full)
+	sqlDB.ExpectErr(t, "primary key of bank has 1 columns",
+		`RESTORE data.bank FROM $1 WITH into_db = 'side', span_filter = '(1, 2)'`, full)
+
+	tableDesc := sqlbase.GetTableDescriptor(tc.Servers[0].DB(), keys.SystemSQLCodec, "data", "bank")
+	span := tableDesc.PrimaryIndexSpan(keys.SystemSQLCodec)
+	var revisions, deletions int
+	sqlDB.QueryRow(t, `SELECT count(*), count(*) FILTER (WHERE value IS NULL)
+FROM crdb_internal.backup_revisions(ARRAY[$1, $2], $3, $4)`,
+		full, inc, []byte(span.Key), []byte(span.EndKey),
+	).Scan(&revisions, &deletions)
+	if revisions != numAccounts+3 || deletions != 3 {
+		t.Fatalf("expected %d revisions including 3 deletions, got %d including %d",
+			numAccounts+3, revisions, deletions)
+	}
+
+	// The revisions of encrypted backups are read with their passphrase.
+	const encrypted = localFoo + "/encrypted"
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH encryption_passphrase = 'abc'`, encrypted)
+	sqlDB.QueryRow(t, `SELECT count(*)
+FROM crdb_internal.backup_revisions(ARRAY[$1], $2, $3, 'abc')`,
+		encrypted, []byte(span.Key), []byte(span.EndKey),
+	).Scan(&revisions)
+	if revisions != numAccounts-3 {
+		t.Fatalf("expected %d revisions, got %d", numAccounts-3, revisions)
+	}
+
+	// The secondary indexes of the restored table, including unique ones, are
+	// backfilled from the restored rows.
+	const unique = localFoo + "/unique"
+	sqlDB.Exec(t, `CREATE UNIQUE INDEX bank_payload_key ON data.bank (payload)`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, unique)
+	sqlDB.Exec(t, `DROP TABLE side.bank`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM $1 WITH into_db = 'side', span_filter = '6, 9'`, unique)
+	sqlDB.CheckQueryResults(t, `SELECT id FROM side.bank@bank_payload_key ORDER BY id`,
+		[][]string{{"6"}, {"7"}, {"8"}})
+	sqlDB.ExpectErr(t, "duplicate key value",
+		`INSERT INTO side.bank SELECT 100, balance, payload FROM side.bank WHERE id = 6`)
+}
+
 // TestRestoreFailDatabaseCleanup tests that a failed RESTORE is cleaned up
 // when restoring an entire database.
 func TestRestoreFailDatabaseCleanup(t *testing.T) {
@@ -2771,6 +2944,59 @@ func TestBackupEncrypted(t *testing.T) {
 	sqlDB.CheckQueryResults(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE neverappears.neverappears`, before)
 }
 
+func TestBackupEncryptedWithKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, _, sqlDB, rawDir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	// Each master key is a file of the local stand-in KMS.
+	kmsURI := func(name string) string {
+		key, err := storageccl.GenerateDataKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(rawDir, name)
+		if err := ioutil.WriteFile(path, key, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return "localkms://" + path
+	}
+	regionA, regionB, regionC := kmsURI("a"), kmsURI("b"), kmsURI("c")
+	unreachable := "localkms://" + filepath.Join(rawDir, "missing")
+
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH kms = $2, kms = $3`, localFoo, regionA, regionB)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH kms = $2`, localFoo, regionB)
+	before := sqlDB.QueryStr(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE data.bank`)
+
+	// The files of the backup are encrypted, and their key can be decrypted by
+	// either KMS, even if another one can't be reached.
+	sqlDB.ExpectErr(t, `file appears encrypted`, `SHOW BACKUP $1`, localFoo)
+	sqlDB.Exec(t, `SHOW BACKUP $1 WITH kms = $2`, localFoo, regionA)
+	sqlDB.Exec(t, `SHOW BACKUP $1 WITH kms = $2, kms = $3`, localFoo, unreachable, regionB)
+	sqlDB.ExpectErr(t, `backup key was not encrypted by master key`,
+		`SHOW BACKUP $1 WITH kms = $2`, localFoo, regionC)
+	sqlDB.ExpectErr(t, `backup is encrypted with a KMS -- try specifying "kms"`,
+		`SHOW BACKUP $1 WITH encryption_passphrase = 'abcdefg'`, localFoo)
+	sqlDB.ExpectErr(t, `unsupported KMS scheme: "aws"`,
+		`BACKUP DATABASE data TO $1 WITH kms = 'aws:///key'`, localFoo)
+	sqlDB.ExpectErr(t, `cannot specify both "encryption_passphrase" and "kms"`,
+		`BACKUP DATABASE data TO $1 WITH encryption_passphrase = 'abcdefg', kms = $2`,
+		localFoo, regionA)
+
+	// Adding a KMS to the backup requires one which can already decrypt its key.
+	sqlDB.ExpectErr(t, `backup key was not encrypted by master key`,
+		`ALTER BACKUP $1 ADD NEW_KMS = $2 WITH OLD_KMS = $2`, localFoo, regionC)
+	sqlDB.Exec(t, `ALTER BACKUP $1 ADD NEW_KMS = $2 WITH OLD_KMS = ($3, $4)`,
+		localFoo, regionC, unreachable, regionA)
+
+	sqlDB.Exec(t, `DROP DATABASE data CASCADE`)
+	sqlDB.Exec(t, `RESTORE DATABASE data FROM $1 WITH kms = $2`, localFoo, regionC)
+	sqlDB.CheckQueryResults(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE data.bank`, before)
+}
+
 func TestRestoredPrivileges(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
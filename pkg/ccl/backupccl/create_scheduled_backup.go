@@ -0,0 +1,268 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/gorhill/cronexpr"
+)
+
+const (
+	scheduleOptRetention = "retention"
+
+	// defaultFullBackupExpr is how often a full backup is taken by the
+	// schedules which run more often than daily and don't specify it.
+	defaultFullBackupExpr = "@daily"
+)
+
+var scheduledBackupOptionExpectValues = map[string]sql.KVStringOptValidate{
+	scheduleOptRetention: sql.KVStringOptRequireValue,
+}
+
+// createBackupScheduleHook implements PlanHookFn for CREATE SCHEDULE FOR
+// BACKUP.
+func createBackupScheduleHook(
+	_ context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	schedule, ok := stmt.(*tree.ScheduledBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	const op = "CREATE SCHEDULE FOR BACKUP"
+	nameFn := func() (string, error) { return "BACKUP", nil }
+	if schedule.ScheduleName != nil {
+		var err error
+		if nameFn, err = p.TypeAsString(schedule.ScheduleName, op); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+	recurrenceFn, err := p.TypeAsString(schedule.Recurrence, op)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	var fullBackupFn func() (string, error)
+	if schedule.FullBackup != nil && !schedule.FullBackup.AlwaysFull {
+		if fullBackupFn, err = p.TypeAsString(schedule.FullBackup.Recurrence, op); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+	toFn, err := p.TypeAsStringArray(tree.Exprs(schedule.To), op)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	kmsExprs, backupOptions := splitKMSOptions(schedule.BackupOptions)
+	kmsFn, err := typeAsKMSURIs(p, kmsExprs, op)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	backupOptsFn, err := p.TypeAsStringOpts(backupOptions, backupOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	scheduleOptsFn, err := p.TypeAsStringOpts(schedule.ScheduleOptions, scheduledBackupOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "schedule_id", Typ: types.Int},
+		{Name: "name", Typ: types.String},
+		{Name: "next_run", Typ: types.TimestampTZ},
+		{Name: "recurrence", Typ: types.String},
+		{Name: "full_backup_recurrence", Typ: types.String},
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(), op,
+		); err != nil {
+			return err
+		}
+
+		if err := p.RequireAdminRole(ctx, op); err != nil {
+			return err
+		}
+
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionScheduledJobs) {
+			return errors.Errorf("%s can only be used on a cluster that has been fully upgraded to version 20.2", op)
+		}
+
+		name, err := nameFn()
+		if err != nil {
+			return err
+		}
+		recurrence, err := recurrenceFn()
+		if err != nil {
+			return err
+		}
+		var fullBackupExpr string
+		if fullBackupFn != nil {
+			if fullBackupExpr, err = fullBackupFn(); err != nil {
+				return err
+			}
+		} else if schedule.FullBackup == nil {
+			if fullBackupExpr, err = defaultFullBackupRecurrence(recurrence); err != nil {
+				return err
+			}
+		}
+		if fullBackupExpr != "" {
+			if _, err := cronexpr.Parse(fullBackupExpr); err != nil {
+				return errors.Wrapf(err, "invalid full backup schedule %q", fullBackupExpr)
+			}
+		}
+		to, err := toFn()
+		if err != nil {
+			return err
+		}
+		kmsURIs, err := kmsFn()
+		if err != nil {
+			return err
+		}
+		backupOpts, err := backupOptsFn()
+		if err != nil {
+			return err
+		}
+		scheduleOpts, err := scheduleOptsFn()
+		if err != nil {
+			return err
+		}
+
+		var retention time.Duration
+		if r, ok := scheduleOpts[scheduleOptRetention]; ok {
+			interval, err := tree.ParseDInterval(r)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s %q", scheduleOptRetention, r)
+			}
+			now := timeutil.Now()
+			if retention = duration.Add(now, interval.Duration).Sub(now); retention <= 0 {
+				return errors.Errorf("%s must be positive, found %q", scheduleOptRetention, r)
+			}
+		}
+
+		// The destinations of the BACKUP statement are replaced with those of a
+		// collection every time it runs.
+		backupStmt := &tree.Backup{
+			Options: scheduledBackupOptions(backupOpts, kmsURIs),
+		}
+		if schedule.Targets != nil {
+			backupStmt.Targets = *schedule.Targets
+		} else {
+			backupStmt.DescriptorCoverage = tree.AllDescriptors
+		}
+
+		args, err := pbtypes.MarshalAny(&ScheduledBackupExecutionArgs{
+			BackupStatement: tree.AsStringWithFlags(backupStmt, tree.FmtParsable),
+			Destinations:    to,
+			FullBackupExpr:  fullBackupExpr,
+			RetentionNanos:  retention.Nanoseconds(),
+		})
+		if err != nil {
+			return err
+		}
+
+		sj := jobs.NewScheduledJob()
+		sj.SetScheduleName(name)
+		sj.SetOwner(p.User())
+		sj.SetExecutionDetails(scheduledBackupExecutorName, jobspb.ExecutionArguments{Args: args})
+		if err := sj.SetSchedule(recurrence); err != nil {
+			return err
+		}
+		if err := sj.Create(ctx, p.ExecCfg().InternalExecutor, p.ExtendedEvalContext().Txn); err != nil {
+			return err
+		}
+
+		telemetry.Count("backup.schedule.created")
+		if retention > 0 {
+			telemetry.Count("backup.schedule.retention")
+		}
+
+		fullBackup := tree.Datum(tree.DNull)
+		if fullBackupExpr != "" {
+			fullBackup = tree.NewDString(fullBackupExpr)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- tree.Datums{
+			tree.NewDInt(tree.DInt(sj.ScheduleID())),
+			tree.NewDString(name),
+			tree.MustMakeDTimestampTZ(sj.NextRun(), time.Microsecond),
+			tree.NewDString(recurrence),
+			fullBackup,
+		}:
+			return nil
+		}
+	}
+	return fn, header, nil, false, nil
+}
+
+// defaultFullBackupRecurrence returns how often a schedule running at the
+// given recurrence takes a full backup when it is not specified: daily if it
+// runs more often than daily, otherwise every backup is a full backup, which
+// is denoted by an empty expression.
+func defaultFullBackupRecurrence(recurrence string) (string, error) {
+	expr, err := cronexpr.Parse(recurrence)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid schedule %q", recurrence)
+	}
+	nextRuns := expr.NextN(timeutil.Now(), 2)
+	if len(nextRuns) == 2 && nextRuns[1].Sub(nextRuns[0]) < 24*time.Hour {
+		return defaultFullBackupExpr, nil
+	}
+	return "", nil
+}
+
+// scheduledBackupOptions returns the options of the BACKUP statement run by a
+// schedule. Unlike the options of a backup job description, the secrets of
+// the options are not redacted since the statement is executed.
+func scheduledBackupOptions(opts map[string]string, kmsURIs []string) tree.KVOptions {
+	opts[backupOptDetached] = ""
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvOpts := make(tree.KVOptions, 0, len(opts)+len(kmsURIs))
+	for _, k := range keys {
+		opt := tree.KVOption{Key: tree.Name(k)}
+		if v := opts[k]; v != "" {
+			opt.Value = tree.NewDString(v)
+		}
+		kvOpts = append(kvOpts, opt)
+	}
+	for _, uri := range kmsURIs {
+		kvOpts = append(kvOpts, tree.KVOption{Key: backupOptEncKMS, Value: tree.NewDString(uri)})
+	}
+	return kvOpts
+}
+
+func init() {
+	sql.AddPlanHook(createBackupScheduleHook)
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// failingBackupResumer fails the backup jobs it resumes.
+type failingBackupResumer struct {
+	jobs.Resumer
+}
+
+func (r *failingBackupResumer) Resume(
+	ctx context.Context, phs interface{}, resultsCh chan<- tree.Datums,
+) error {
+	return errors.New("injected backup failure")
+}
+
+func TestScheduledBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	ctx, tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+	registry := tc.Server(0).JobRegistry().(*jobs.Registry)
+
+	var failBackups int32
+	registry.TestingResumerCreationKnobs = map[jobspb.Type]func(raw jobs.Resumer) jobs.Resumer{
+		jobspb.TypeBackup: func(raw jobs.Resumer) jobs.Resumer {
+			if atomic.LoadInt32(&failBackups) == 1 {
+				return &failingBackupResumer{Resumer: raw}
+			}
+			return raw
+		},
+	}
+
+	// startSchedule forces the schedule to run and returns its backup job.
+	startSchedule := func(id int64) int64 {
+		t.Helper()
+		sqlDB.Exec(t,
+			`UPDATE system.scheduled_jobs SET next_run = now() - '1s'::INTERVAL WHERE schedule_id = $1`, id)
+		require.NoError(t, registry.ExecuteSchedulesForTesting(ctx))
+		var jobID int64
+		sqlDB.QueryRow(t, `SELECT job_id FROM [SHOW JOBS] WHERE job_type = 'BACKUP'
+ORDER BY created DESC LIMIT 1`).Scan(&jobID)
+		return jobID
+	}
+	// runSchedule forces the schedule to run and waits for its backup job.
+	runSchedule := func(id int64) {
+		t.Helper()
+		jobutils.WaitForJob(t, sqlDB, startSchedule(id))
+	}
+	collections := func(dest string) []string {
+		t.Helper()
+		var paths []string
+		for _, row := range sqlDB.QueryStr(t, `SHOW BACKUPS IN $1`, dest) {
+			paths = append(paths, row[0])
+		}
+		return paths
+	}
+
+	t.Run("incremental", func(t *testing.T) {
+		var id int64
+		var name, recurrence, fullBackup string
+		var nextRun time.Time
+		sqlDB.QueryRow(t,
+			`CREATE SCHEDULE 'data backup' FOR BACKUP DATABASE data TO $1 RECURRING '@hourly'`,
+			localFoo+"/inc",
+		).Scan(&id, &name, &nextRun, &recurrence, &fullBackup)
+		require.Equal(t, "data backup", name)
+		require.Equal(t, "@daily", fullBackup)
+
+		runSchedule(id)
+		sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1`)
+		runSchedule(id)
+
+		// Both backups belong to the same collection.
+		require.Len(t, collections(localFoo+"/inc"), 1)
+		res := sqlDB.QueryStr(t,
+			`SELECT DISTINCT start_time IS NULL FROM [SHOW BACKUP LATEST IN $1] ORDER BY 1`,
+			localFoo+"/inc")
+		require.Equal(t, [][]string{{"false"}, {"true"}}, res)
+
+		sqlDB.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT label, schedule_status, recurrence FROM [SHOW SCHEDULE %d]`, id),
+			[][]string{{"data backup", "ACTIVE", "@hourly"}})
+		sqlDB.Exec(t, `PAUSE SCHEDULE $1`, id)
+		sqlDB.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT schedule_status, next_run FROM [SHOW SCHEDULE %d]`, id),
+			[][]string{{"PAUSED", "NULL"}})
+		sqlDB.Exec(t, `RESUME SCHEDULE $1`, id)
+		sqlDB.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT schedule_status FROM [SHOW SCHEDULE %d]`, id), [][]string{{"ACTIVE"}})
+		sqlDB.Exec(t, `DROP SCHEDULE $1`, id)
+		sqlDB.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT count(*) FROM [SHOW SCHEDULES] WHERE id = %d`, id), [][]string{{"0"}})
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		var id int64
+		var name, recurrence string
+		var nextRun time.Time
+		var fullBackup interface{}
+		sqlDB.QueryRow(t,
+			`CREATE SCHEDULE FOR BACKUP data.bank TO $1 RECURRING '@hourly' FULL BACKUP ALWAYS
+WITH SCHEDULE OPTIONS retention = '1ms'`,
+			localFoo+"/retention",
+		).Scan(&id, &name, &nextRun, &recurrence, &fullBackup)
+		require.Nil(t, fullBackup)
+
+		dest := localFoo + "/retention"
+		runSchedule(id)
+		first := collections(dest)
+		require.Len(t, first, 1)
+
+		// A failed backup doesn't supersede the collection of the previous one,
+		// however old.
+		atomic.StoreInt32(&failBackups, 1)
+		failedJobID := startSchedule(id)
+		sqlDB.CheckQueryResultsRetry(t,
+			fmt.Sprintf(`SELECT status FROM system.jobs WHERE id = %d`, failedJobID),
+			[][]string{{string(jobs.StatusFailed)}})
+		time.Sleep(time.Millisecond)
+		require.Equal(t, first, collections(dest))
+
+		// Once a backup succeeds, the collections it superseded for longer than
+		// the retention are deleted.
+		atomic.StoreInt32(&failBackups, 0)
+		runSchedule(id)
+		remaining := collections(dest)
+		require.Len(t, remaining, 1)
+		require.NotEqual(t, first, remaining)
+	})
+}
+
+func TestExpireCollections(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	now := time.Unix(0, 1000)
+	args := &ScheduledBackupExecutionArgs{
+		Collections: []BackupCollection{
+			{Path: "/a", CreatedNanos: 100},
+			{Path: "/b", CreatedNanos: 500},
+			{Path: "/c", CreatedNanos: 900},
+		},
+	}
+	// Without retention, collections never expire.
+	require.Empty(t, expireCollections(args, now))
+	require.Len(t, args.Collections, 3)
+
+	args.RetentionNanos = 400
+	require.Equal(t, []BackupCollection{{Path: "/a", CreatedNanos: 100}}, expireCollections(args, now))
+	require.Len(t, args.Collections, 2)
+
+	// The latest collection never expires.
+	args.RetentionNanos = 1
+	require.Equal(t, []BackupCollection{{Path: "/b", CreatedNanos: 500}}, expireCollections(args, now))
+	require.Equal(t, []BackupCollection{{Path: "/c", CreatedNanos: 900}}, args.Collections)
+}
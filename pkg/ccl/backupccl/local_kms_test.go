@@ -0,0 +1,65 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/errors"
+)
+
+// localKMSScheme is the scheme of the URIs of localKMS, e.g.
+// localkms:///path/to/key, which name the file holding the master key.
+const localKMSScheme = "localkms"
+
+// localKMS is a stand-in for a KMS in tests, whose master key is read from a
+// local file.
+type localKMS struct {
+	path string
+	key  []byte
+}
+
+var _ cloud.KMS = &localKMS{}
+
+func makeLocalKMS(_ context.Context, uri string, _ *cluster.Settings) (cloud.KMS, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ioutil.ReadFile(parsed.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading master key")
+	}
+	return &localKMS{path: parsed.Path, key: key}, nil
+}
+
+func (k *localKMS) MasterKeyID() (string, error) {
+	return k.path, nil
+}
+
+func (k *localKMS) Encrypt(_ context.Context, data []byte) ([]byte, error) {
+	return storageccl.EncryptFile(data, k.key)
+}
+
+func (k *localKMS) Decrypt(_ context.Context, data []byte) ([]byte, error) {
+	return storageccl.DecryptFile(data, k.key)
+}
+
+func (k *localKMS) Close() error {
+	return nil
+}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(localKMSScheme, makeLocalKMS)
+}
@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"path"
 	"sort"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -133,7 +134,7 @@ func readBackupManifest(
 	if err := protoutil.Unmarshal(descBytes, &backupManifest); err != nil {
 		if encryption == nil && storageccl.AppearsEncrypted(descBytes) {
 			return BackupManifest{}, errors.Wrapf(
-				err, "file appears encrypted -- try specifying %q", backupOptEncPassphrase)
+				err, "file appears encrypted -- try specifying %q or %q", backupOptEncPassphrase, backupOptEncKMS)
 		}
 		return BackupManifest{}, err
 	}
@@ -315,6 +316,89 @@ func findPriorBackups(ctx context.Context, store cloud.ExternalStorage) ([]strin
 	return prev, nil
 }
 
+const (
+	// backupCollectionSubdirFormat is the format of the subdirectories of a
+	// collection in which BACKUP INTO writes its full backups. The incremental
+	// backups appended to a full backup are nested in its subdirectory.
+	backupCollectionSubdirFormat = "/2006/01/02-150405.00"
+	// latestBackupSubdir is the subdirectory which resolves to the latest full
+	// backup of a collection.
+	latestBackupSubdir = "LATEST"
+)
+
+// findBackupsInCollection returns the subdirectories of the full backups in a
+// collection, in chronological order. Like findPriorBackups, it searches for
+// the subdirectories matching the naming pattern rather than keeping a list.
+func findBackupsInCollection(ctx context.Context, store cloud.ExternalStorage) ([]string, error) {
+	backups, err := store.ListFiles(ctx, "[0-9]*/[0-9]*/[0-9]*-[0-9]*.[0-9][0-9]/"+BackupManifestName)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing backups in collection")
+	}
+	for i := range backups {
+		backups[i] = "/" + strings.TrimSuffix(backups[i], "/"+BackupManifestName)
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// resolveBackupSubdir returns the subdirectory of the full backup in the
+// collection which is referred to by subdir. The subdirectory LATEST resolves
+// to the latest full backup of the collection.
+func resolveBackupSubdir(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	collection []string,
+	subdir string,
+) (string, error) {
+	if !strings.EqualFold(subdir, latestBackupSubdir) {
+		if !strings.HasPrefix(subdir, "/") {
+			subdir = "/" + subdir
+		}
+		return subdir, nil
+	}
+	collectionURI, _, err := getURIsByLocalityKV(collection, "")
+	if err != nil {
+		return "", err
+	}
+	store, err := mkStore(ctx, collectionURI)
+	if err != nil {
+		return "", errors.Wrap(err, "opening backup collection")
+	}
+	defer store.Close()
+	backups, err := findBackupsInCollection(ctx, store)
+	if err != nil {
+		return "", err
+	}
+	if len(backups) == 0 {
+		return "", errors.New("no full backup found in collection")
+	}
+	return backups[len(backups)-1], nil
+}
+
+// appendPathToURI appends path to the path component of uri, preserving its
+// query parameters.
+func appendPathToURI(uri string, path string) (string, error) {
+	parsedURI, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	parsedURI.Path = parsedURI.Path + path
+	return parsedURI.String(), nil
+}
+
+// appendPathToURIs is like appendPathToURI for every URI of a partitioned
+// backup.
+func appendPathToURIs(uris []string, path string) ([]string, error) {
+	res := make([]string, len(uris))
+	for i, uri := range uris {
+		var err error
+		if res[i], err = appendPathToURI(uri, path); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 // resolveBackupManifests resolves a list of list of URIs that point to the
 // incremental layers (each of which can be partitioned) of backups into the
 // actual backup manifests and metadata required to RESTORE. If only one layer
@@ -593,6 +677,173 @@ func writeEncryptionOptions(
 	return nil
 }
 
+// backupEncryptionParams are the options with which the files of a backup are
+// encrypted: either a passphrase from which their key is derived, or the URIs
+// of KMSs which wrap their randomly generated key.
+type backupEncryptionParams struct {
+	passphrase []byte
+	kmsURIs    []string
+}
+
+func (e backupEncryptionParams) enabled() bool {
+	return e.passphrase != nil || len(e.kmsURIs) > 0
+}
+
+// makeEncryptionParams returns the encryption params specified by the options
+// of a statement.
+func makeEncryptionParams(
+	opts map[string]string, kmsURIs []string,
+) (backupEncryptionParams, error) {
+	var params backupEncryptionParams
+	if passphrase, ok := opts[backupOptEncPassphrase]; ok {
+		if len(kmsURIs) > 0 {
+			return params, errors.Errorf(
+				"cannot specify both %q and %q", backupOptEncPassphrase, backupOptEncKMS)
+		}
+		params.passphrase = []byte(passphrase)
+	}
+	params.kmsURIs = kmsURIs
+	return params, nil
+}
+
+// makeNewEncryption generates the key of a new encrypted backup, along with the
+// EncryptionInfo from which it can later be derived or unwrapped.
+func makeNewEncryption(
+	ctx context.Context, settings *cluster.Settings, params backupEncryptionParams,
+) (*roachpb.FileEncryptionOptions, *EncryptionInfo, error) {
+	if len(params.kmsURIs) > 0 {
+		key, err := storageccl.GenerateDataKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		encInfo := &EncryptionInfo{}
+		if err := wrapEncryptionKey(ctx, settings, encInfo, key, params.kmsURIs); err != nil {
+			return nil, nil, err
+		}
+		return &roachpb.FileEncryptionOptions{Key: key}, encInfo, nil
+	}
+	salt, err := storageccl.GenerateSalt()
+	if err != nil {
+		return nil, nil, err
+	}
+	key := storageccl.GenerateKey(params.passphrase, salt)
+	return &roachpb.FileEncryptionOptions{Key: key}, &EncryptionInfo{Salt: salt}, nil
+}
+
+// readEncryption reads the EncryptionInfo stored in src and returns the key of
+// the backup it describes, or nil if params are not enabled.
+func readEncryption(
+	ctx context.Context,
+	settings *cluster.Settings,
+	src cloud.ExternalStorage,
+	params backupEncryptionParams,
+) (*roachpb.FileEncryptionOptions, error) {
+	if !params.enabled() {
+		return nil, nil
+	}
+	encInfo, err := readEncryptionOptions(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.kmsURIs) > 0 {
+		if len(encInfo.EncryptedDataKeyByKMSMasterKeyID) == 0 {
+			return nil, errors.Errorf(
+				"backup is not encrypted with a KMS -- try specifying %q", backupOptEncPassphrase)
+		}
+		key, err := unwrapEncryptionKey(ctx, settings, encInfo, params.kmsURIs)
+		if err != nil {
+			return nil, err
+		}
+		return &roachpb.FileEncryptionOptions{Key: key}, nil
+	}
+	if len(encInfo.EncryptedDataKeyByKMSMasterKeyID) > 0 {
+		return nil, errors.Errorf(
+			"backup is encrypted with a KMS -- try specifying %q", backupOptEncKMS)
+	}
+	return &roachpb.FileEncryptionOptions{
+		Key: storageccl.GenerateKey(params.passphrase, encInfo.Salt),
+	}, nil
+}
+
+// redactedKMSURI returns the URI of a KMS with its secrets redacted, for use in
+// errors and job descriptions.
+func redactedKMSURI(uri string) string {
+	redacted, err := cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+	if err != nil {
+		return "<invalid URI>"
+	}
+	return redacted
+}
+
+// wrapEncryptionKey wraps key with the master key of each of kmsURIs and adds
+// it to the keys of encInfo.
+func wrapEncryptionKey(
+	ctx context.Context,
+	settings *cluster.Settings,
+	encInfo *EncryptionInfo,
+	key []byte,
+	kmsURIs []string,
+) error {
+	if encInfo.EncryptedDataKeyByKMSMasterKeyID == nil {
+		encInfo.EncryptedDataKeyByKMSMasterKeyID = make(map[string][]byte, len(kmsURIs))
+	}
+	for _, uri := range kmsURIs {
+		if err := func() error {
+			kms, err := cloud.KMSFromURI(ctx, uri, settings)
+			if err != nil {
+				return err
+			}
+			defer kms.Close()
+			id, err := kms.MasterKeyID()
+			if err != nil {
+				return err
+			}
+			wrapped, err := kms.Encrypt(ctx, key)
+			if err != nil {
+				return err
+			}
+			encInfo.EncryptedDataKeyByKMSMasterKeyID[id] = wrapped
+			return nil
+		}(); err != nil {
+			return errors.Wrapf(err, "encrypting backup key with KMS %s", redactedKMSURI(uri))
+		}
+	}
+	return nil
+}
+
+// unwrapEncryptionKey returns the key of encInfo unwrapped by the first of
+// kmsURIs whose master key wrapped it. Failures to reach a KMS are tolerated as
+// long as another one can unwrap the key.
+func unwrapEncryptionKey(
+	ctx context.Context, settings *cluster.Settings, encInfo *EncryptionInfo, kmsURIs []string,
+) ([]byte, error) {
+	var errs error
+	for _, uri := range kmsURIs {
+		key, err := func() ([]byte, error) {
+			kms, err := cloud.KMSFromURI(ctx, uri, settings)
+			if err != nil {
+				return nil, err
+			}
+			defer kms.Close()
+			id, err := kms.MasterKeyID()
+			if err != nil {
+				return nil, err
+			}
+			wrapped, ok := encInfo.EncryptedDataKeyByKMSMasterKeyID[id]
+			if !ok {
+				return nil, errors.Errorf("backup key was not encrypted by master key %q", id)
+			}
+			return kms.Decrypt(ctx, wrapped)
+		}()
+		if err == nil {
+			return key, nil
+		}
+		errs = errors.CombineErrors(errs,
+			errors.Wrapf(err, "decrypting backup key with KMS %s", redactedKMSURI(uri)))
+	}
+	return nil, errs
+}
+
 // VerifyUsableExportTarget ensures that the target location does not already
 // contain a BACKUP or checkpoint and writes an empty checkpoint, both verifying
 // that the location is writable and locking out accidental concurrent
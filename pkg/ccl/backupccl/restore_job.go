@@ -554,7 +554,8 @@ func rewriteBackupSpanKey(kr *storageccl.KeyRewriter, key roachpb.Key) (roachpb.
 }
 
 // restore imports a SQL table (or tables) from sets of non-overlapping sstable
-// files.
+// files. If verifyOnly is set, the files are read and their entries verified,
+// but nothing is imported.
 func restore(
 	restoreCtx context.Context,
 	db *kv.DB,
@@ -568,6 +569,7 @@ func restore(
 	spans []roachpb.Span,
 	job *jobs.Job,
 	encryption *roachpb.FileEncryptionOptions,
+	verifyOnly bool,
 ) (RowCount, error) {
 	// A note about contexts and spans in this method: the top-level context
 	// `restoreCtx` is used for orchestration logging. All operations that carry
@@ -665,6 +667,18 @@ func restore(
 	readyForImportCh := make(chan importEntry, presplitLeadLimit)
 	g.GoCtx(func(ctx context.Context) error {
 		defer close(readyForImportCh)
+		if verifyOnly {
+			// Nothing is ingested when verifying, so the spans are not presplit or
+			// scattered.
+			for _, importSpan := range importSpans {
+				select {
+				case readyForImportCh <- importSpan:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		}
 		return splitAndScatter(ctx, settings, db, kr, numClusterNodes, importSpans, readyForImportCh)
 	})
 
@@ -693,6 +707,7 @@ func restore(
 				EndTime:       endTime,
 				Rekeys:        rekeys,
 				Encryption:    encryption,
+				VerifyOnly:    verifyOnly,
 			}
 
 			log.VEventf(restoreCtx, 1, "importing %d of %d", idx, len(importSpans))
@@ -904,7 +919,22 @@ func createImportingTables(
 
 	// We get the spans of the restoring tables _as they appear in the backup_,
 	// that is, in the 'old' keyspace, before we reassign the table IDs.
-	spans := spansForAllTableIndexes(p.ExecCfg().Codec, tables, nil)
+	var spans []roachpb.Span
+	if details.SpanFilter != "" {
+		// The restore of a table with a span filter is limited to the span of its
+		// primary index selected by the filter. Planning checked that exactly one
+		// table is restored.
+		var err error
+		spans, err = spanFilterSpans(
+			p.ExecCfg().Codec, &p.ExtendedEvalContext().EvalContext, tables[0], details.SpanFilter,
+		)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		addSecondaryIndexMutations(tables[0])
+	} else {
+		spans = spansForAllTableIndexes(p.ExecCfg().Codec, tables, nil)
+	}
 
 	log.Eventf(ctx, "starting restore for %d tables", len(tables))
 
@@ -957,6 +987,10 @@ func (r *restoreResumer) Resume(
 		return err
 	}
 
+	if details.VerifyOnly {
+		return r.verifyBackup(ctx, p, backupManifests, sqlDescs, resultsCh)
+	}
+
 	databases, tables, oldTableIDs, spans, err := createImportingTables(ctx, p, sqlDescs, r)
 	if err != nil {
 		return err
@@ -993,6 +1027,7 @@ func (r *restoreResumer) Resume(
 		spans,
 		r.job,
 		details.Encryption,
+		false, /* verifyOnly */
 	)
 	if err != nil {
 		return err
@@ -1044,6 +1079,68 @@ func (r *restoreResumer) Resume(
 	return nil
 }
 
+// verifyBackup reads the data of the tables being restored through the same
+// Import requests as a restore, which verify the checksums of the files and of
+// their entries, but doesn't ingest it. The tables keep their IDs in the
+// backup, since no descriptor is written.
+func (r *restoreResumer) verifyBackup(
+	ctx context.Context,
+	p sql.PlanHookState,
+	backupManifests []BackupManifest,
+	sqlDescs []sqlbase.Descriptor,
+	resultsCh chan<- tree.Datums,
+) error {
+	details := r.job.Details().(jobspb.RestoreDetails)
+
+	var tables []*sqlbase.TableDescriptor
+	var tableIDs []sqlbase.ID
+	for _, desc := range sqlDescs {
+		if tableDesc := desc.Table(hlc.Timestamp{}); tableDesc != nil {
+			tables = append(tables, tableDesc)
+			tableIDs = append(tableIDs, tableDesc.ID)
+		}
+	}
+	if len(tables) == 0 {
+		log.Warning(ctx, "no tables to verify")
+		return nil
+	}
+	spans := spansForAllTableIndexes(p.ExecCfg().Codec, tables, nil)
+
+	numClusterNodes, err := clusterNodeCount(p.ExecCfg().Gossip)
+	if err != nil {
+		return err
+	}
+	res, err := restore(
+		ctx,
+		p.ExecCfg().DB,
+		numClusterNodes,
+		p.ExecCfg().Settings,
+		backupManifests,
+		details.BackupLocalityInfo,
+		details.EndTime,
+		tables,
+		tableIDs,
+		spans,
+		r.job,
+		details.Encryption,
+		true, /* verifyOnly */
+	)
+	if err != nil {
+		return err
+	}
+
+	telemetry.Count("restore.verify-only.succeeded")
+	resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(*r.job.ID())),
+		tree.NewDString(string(jobs.StatusSucceeded)),
+		tree.NewDFloat(tree.DFloat(1.0)),
+		tree.NewDInt(tree.DInt(res.Rows)),
+		tree.NewDInt(tree.DInt(res.IndexEntries)),
+		tree.NewDInt(tree.DInt(res.DataSize)),
+	}
+	return nil
+}
+
 // Insert stats re-inserts the table statistics stored in the backup manifest.
 func (r *restoreResumer) insertStats(ctx context.Context) error {
 	details := r.job.Details().(jobspb.RestoreDetails)
@@ -1075,7 +1172,9 @@ func (r *restoreResumer) publishTables(ctx context.Context) error {
 	}
 	log.Event(ctx, "making tables live")
 
+	var indexJobs []int64
 	err := r.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		indexJobs = nil
 		// Write the new TableDescriptors and flip state over to public so they can be
 		// accessed.
 		b := txn.NewBatch()
@@ -1083,6 +1182,13 @@ func (r *restoreResumer) publishTables(ctx context.Context) error {
 			tableDesc := *tbl
 			tableDesc.Version++
 			tableDesc.State = sqlbase.TableDescriptor_PUBLIC
+			if details.SpanFilter != "" && len(tableDesc.Mutations) > 0 {
+				jobID, err := r.createIndexBackfillJob(ctx, txn, &tableDesc)
+				if err != nil {
+					return errors.Wrap(err, "creating job to backfill secondary indexes")
+				}
+				indexJobs = append(indexJobs, jobID)
+			}
 			existingDescVal, err := sqlbase.ConditionalGetTableDescFromTxn(ctx, txn, r.execCfg.Codec, tbl)
 			if err != nil {
 				return errors.Wrap(err, "validating table descriptor has not changed")
@@ -1117,9 +1223,53 @@ func (r *restoreResumer) publishTables(ctx context.Context) error {
 		r.execCfg.StatsRefresher.NotifyMutation(r.tables[i].ID, math.MaxInt32 /* rowsAffected */)
 	}
 
+	// Wait for the secondary indexes of a table restored with a span_filter to
+	// be backfilled. The table is already public, so a failure to backfill them
+	// does not fail the restore: the schema change job records it.
+	if len(indexJobs) > 0 {
+		if err := r.execCfg.JobRegistry.Run(ctx, r.execCfg.InternalExecutor, indexJobs); err != nil {
+			log.Warningf(ctx, "failed to backfill secondary indexes of restored table: %+v", err)
+		}
+	}
+
 	return nil
 }
 
+// createIndexBackfillJob creates the schema change job which backfills the
+// secondary indexes of a table restored with a span_filter, which
+// addSecondaryIndexMutations turned into mutations, and records it in the
+// descriptor of the table.
+func (r *restoreResumer) createIndexBackfillJob(
+	ctx context.Context, txn *kv.Txn, tableDesc *sqlbase.TableDescriptor,
+) (int64, error) {
+	mutationID := tableDesc.Mutations[0].MutationID
+	span := tableDesc.PrimaryIndexSpan(r.execCfg.Codec)
+	spanList := make([]jobspb.ResumeSpanList, len(tableDesc.Mutations))
+	for i := range spanList {
+		spanList[i].ResumeSpans = []roachpb.Span{span}
+	}
+	record := jobs.Record{
+		Description:   fmt.Sprintf("backfilling secondary indexes of restored table %s", tableDesc.Name),
+		Username:      r.job.Payload().Username,
+		DescriptorIDs: sqlbase.IDs{tableDesc.ID},
+		Details: jobspb.SchemaChangeDetails{
+			TableID:        tableDesc.ID,
+			MutationID:     mutationID,
+			ResumeSpanList: spanList,
+			FormatVersion:  jobspb.JobResumerFormatVersion,
+		},
+		Progress: jobspb.SchemaChangeProgress{},
+	}
+	job, err := r.execCfg.JobRegistry.CreateJobWithTxn(ctx, record, txn)
+	if err != nil {
+		return 0, err
+	}
+	tableDesc.MutationJobs = append(tableDesc.MutationJobs, sqlbase.TableDescriptor_MutationJob{
+		MutationID: mutationID, JobID: *job.ID(),
+	})
+	return *job.ID(), nil
+}
+
 // OnFailOrCancel is part of the jobs.Resumer interface. Removes KV data that
 // has been committed from a restore that has failed or been canceled. It does
 // this by adding the table descriptors in DROP state, which causes the schema
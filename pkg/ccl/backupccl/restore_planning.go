@@ -12,7 +12,6 @@ import (
 	"context"
 	"sort"
 
-	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -45,6 +44,8 @@ const (
 	restoreOptSkipMissingFKs       = "skip_missing_foreign_keys"
 	restoreOptSkipMissingSequences = "skip_missing_sequences"
 	restoreOptSkipMissingViews     = "skip_missing_views"
+	restoreOptVerifyOnly           = "verify_only"
+	restoreOptSpanFilter           = "span_filter"
 
 	// The temporary database system tables will be restored into for full
 	// cluster backups.
@@ -56,6 +57,8 @@ var restoreOptionExpectValues = map[string]sql.KVStringOptValidate{
 	restoreOptSkipMissingFKs:       sql.KVStringOptRequireNoValue,
 	restoreOptSkipMissingSequences: sql.KVStringOptRequireNoValue,
 	restoreOptSkipMissingViews:     sql.KVStringOptRequireNoValue,
+	restoreOptVerifyOnly:           sql.KVStringOptRequireNoValue,
+	restoreOptSpanFilter:           sql.KVStringOptRequireValue,
 	backupOptEncPassphrase:         sql.KVStringOptRequireValue,
 }
 
@@ -567,7 +570,11 @@ func errOnMissingRange(span covering.Range, start, end hlc.Timestamp) error {
 }
 
 func restoreJobDescription(
-	p sql.PlanHookState, restore *tree.Restore, from [][]string, opts map[string]string,
+	p sql.PlanHookState,
+	restore *tree.Restore,
+	from [][]string,
+	opts map[string]string,
+	kmsURIs []string,
 ) (string, error) {
 	r := &tree.Restore{
 		AsOf:    restore.AsOf,
@@ -575,6 +582,11 @@ func restoreJobDescription(
 		Targets: restore.Targets,
 		From:    make([]tree.PartitionedBackup, len(restore.From)),
 	}
+	kmsOpts, err := kmsToKVOptions(kmsURIs)
+	if err != nil {
+		return "", err
+	}
+	r.Options = append(r.Options, kmsOpts...)
 
 	for i, backup := range from {
 		r.From[i] = make(tree.PartitionedBackup, len(backup))
@@ -619,7 +631,20 @@ func restorePlanHook(
 		fromFns[i] = fromFn
 	}
 
-	optsFn, err := p.TypeAsStringOpts(restoreStmt.Options, restoreOptionExpectValues)
+	var subdirFn func() (string, error)
+	if restoreStmt.Subdir != nil {
+		var err error
+		if subdirFn, err = p.TypeAsString(restoreStmt.Subdir, "RESTORE"); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	kmsExprs, options := splitKMSOptions(restoreStmt.Options)
+	kmsFn, err := typeAsKMSURIs(p, kmsExprs, "RESTORE")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	optsFn, err := p.TypeAsStringOpts(options, restoreOptionExpectValues)
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
@@ -650,6 +675,22 @@ func restorePlanHook(
 				return err
 			}
 		}
+		// RESTORE FROM <subdir> IN restores the full backup in the subdirectory
+		// of the collection, along with the incremental backups appended to it.
+		if subdirFn != nil {
+			subdir, err := subdirFn()
+			if err != nil {
+				return err
+			}
+			if subdir, err = resolveBackupSubdir(
+				ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, from[0], subdir,
+			); err != nil {
+				return err
+			}
+			if from[0], err = appendPathToURIs(from[0], subdir); err != nil {
+				return err
+			}
+		}
 		var endTime hlc.Timestamp
 		if restoreStmt.AsOf.Expr != nil {
 			var err error
@@ -663,7 +704,11 @@ func restorePlanHook(
 		if err != nil {
 			return err
 		}
-		return doRestorePlan(ctx, restoreStmt, p, from, endTime, opts, resultsCh)
+		kmsURIs, err := kmsFn()
+		if err != nil {
+			return err
+		}
+		return doRestorePlan(ctx, restoreStmt, p, from, endTime, opts, kmsURIs, resultsCh)
 	}
 	return fn, RestoreHeader, nil, false, nil
 }
@@ -675,6 +720,7 @@ func doRestorePlan(
 	from [][]string,
 	endTime hlc.Timestamp,
 	opts map[string]string,
+	kmsURIs []string,
 	resultsCh chan<- tree.Datums,
 ) error {
 	if len(from) < 1 || len(from[0]) < 1 {
@@ -690,14 +736,13 @@ func doRestorePlan(
 		baseStores[i] = store
 	}
 
-	var encryption *roachpb.FileEncryptionOptions
-	if passphrase, ok := opts[backupOptEncPassphrase]; ok {
-		opts, err := readEncryptionOptions(ctx, baseStores[0])
-		if err != nil {
-			return err
-		}
-		encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts.Salt)
-		encryption = &roachpb.FileEncryptionOptions{Key: encryptionKey}
+	encryptionParams, err := makeEncryptionParams(opts, kmsURIs)
+	if err != nil {
+		return err
+	}
+	encryption, err := readEncryption(ctx, p.ExecCfg().Settings, baseStores[0], encryptionParams)
+	if err != nil {
+		return err
 	}
 
 	defaultURIs, mainBackupManifests, localityInfo, err := resolveBackupManifests(
@@ -720,7 +765,8 @@ func doRestorePlan(
 	if err != nil {
 		return errors.Wrap(err, "looking up user descriptors during restore")
 	}
-	if descCount != 0 && restoreStmt.DescriptorCoverage == tree.AllDescriptors {
+	_, verifyOnly := opts[restoreOptVerifyOnly]
+	if descCount != 0 && restoreStmt.DescriptorCoverage == tree.AllDescriptors && !verifyOnly {
 		return errors.Errorf(
 			"full cluster restore can only be run on a cluster with no tables or databases but found %d descriptors",
 			descCount,
@@ -750,11 +796,45 @@ func doRestorePlan(
 	if err != nil {
 		return err
 	}
-	tableRewrites, err := allocateTableRewrites(ctx, p, databasesByID, filteredTablesByID, restoreDBs, restoreStmt.DescriptorCoverage, opts)
-	if err != nil {
-		return err
+	spanFilter, filtered := opts[restoreOptSpanFilter]
+	if filtered {
+		if verifyOnly {
+			return errors.Errorf("cannot use %q option with %q", restoreOptSpanFilter, restoreOptVerifyOnly)
+		}
+		if restoreStmt.DescriptorCoverage == tree.AllDescriptors || len(restoreDBs) > 0 ||
+			len(filteredTablesByID) != 1 {
+			return errors.Errorf("%q option can only be used when restoring a single table", restoreOptSpanFilter)
+		}
+		for _, table := range filteredTablesByID {
+			if err := checkSpanFilterTable(table); err != nil {
+				return err
+			}
+			if _, err := spanFilterSpans(
+				p.ExecCfg().Codec, &p.ExtendedEvalContext().EvalContext, table, spanFilter,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	var tableRewrites TableRewriteMap
+	if verifyOnly {
+		// A verification doesn't write any descriptor, so the descriptors keep
+		// their IDs.
+		tableRewrites = make(TableRewriteMap)
+		for id := range databasesByID {
+			tableRewrites[id] = &jobspb.RestoreDetails_TableRewrite{TableID: id}
+		}
+		for id, table := range filteredTablesByID {
+			tableRewrites[id] = &jobspb.RestoreDetails_TableRewrite{TableID: id, ParentID: table.ParentID}
+		}
+	} else {
+		tableRewrites, err = allocateTableRewrites(ctx, p, databasesByID, filteredTablesByID, restoreDBs, restoreStmt.DescriptorCoverage, opts)
+		if err != nil {
+			return err
+		}
 	}
-	description, err := restoreJobDescription(p, restoreStmt, from, opts)
+	description, err := restoreJobDescription(p, restoreStmt, from, opts, kmsURIs)
 	if err != nil {
 		return err
 	}
@@ -763,8 +843,15 @@ func doRestorePlan(
 	for _, desc := range filteredTablesByID {
 		tables = append(tables, desc)
 	}
-	if err := RewriteTableDescs(tables, tableRewrites, opts[restoreOptIntoDB]); err != nil {
-		return err
+	if !verifyOnly {
+		if err := RewriteTableDescs(tables, tableRewrites, opts[restoreOptIntoDB]); err != nil {
+			return err
+		}
+	}
+	if filtered {
+		for _, table := range tables {
+			addSecondaryIndexMutations(table)
+		}
 	}
 
 	// Collect telemetry.
@@ -773,12 +860,21 @@ func doRestorePlan(
 		if restoreStmt.DescriptorCoverage == tree.AllDescriptors {
 			telemetry.Count("restore.full-cluster")
 		}
+		if verifyOnly {
+			telemetry.Count("restore.verify-only")
+		}
+		if filtered {
+			telemetry.Count("restore.span-filter")
+		}
 	}
 
 	_, errCh, err := p.ExecCfg().JobRegistry.CreateAndStartJob(ctx, resultsCh, jobs.Record{
 		Description: description,
 		Username:    p.User(),
 		DescriptorIDs: func() (sqlDescIDs []sqlbase.ID) {
+			if verifyOnly {
+				return nil
+			}
 			for _, tableRewrite := range tableRewrites {
 				sqlDescIDs = append(sqlDescIDs, tableRewrite.TableID)
 			}
@@ -793,6 +889,8 @@ func doRestorePlan(
 			OverrideDB:         opts[restoreOptIntoDB],
 			DescriptorCoverage: restoreStmt.DescriptorCoverage,
 			Encryption:         encryption,
+			VerifyOnly:         verifyOnly,
+			SpanFilter:         spanFilter,
 		},
 		Progress: jobspb.RestoreProgress{},
 	})
@@ -0,0 +1,273 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/gorhill/cronexpr"
+)
+
+// scheduledBackupExecutorName is the executor type of the schedules created by
+// CREATE SCHEDULE FOR BACKUP.
+const scheduledBackupExecutorName = "scheduled-backup-executor"
+
+// scheduledBackupExecutor runs the BACKUP statement of a backup schedule.
+//
+// Every full backup is written to a new collection under the destinations of
+// the schedule, and the incremental backups are appended to the latest
+// collection. Once a collection has been superseded by a newer one for longer
+// than the retention of the schedule, its files are deleted by the next backup
+// job of the schedule which succeeds; see expireScheduledCollections.
+//
+// The collections are laid out like the full backups of BACKUP INTO, so the
+// backups of a schedule can be listed with SHOW BACKUPS IN its destinations
+// and restored with RESTORE FROM LATEST IN.
+type scheduledBackupExecutor struct{}
+
+var _ jobs.ScheduledJobExecutor = &scheduledBackupExecutor{}
+
+// ExecuteJob implements jobs.ScheduledJobExecutor.
+func (e *scheduledBackupExecutor) ExecuteJob(
+	ctx context.Context, env *jobs.ScheduledJobExecutorEnv, schedule *jobs.ScheduledJob, txn *kv.Txn,
+) error {
+	args := &ScheduledBackupExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(schedule.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "failed to decode arguments of backup schedule")
+	}
+	stmt, err := parser.ParseOne(args.BackupStatement)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse backup statement of schedule")
+	}
+	backupStmt, ok := stmt.AST.(*tree.Backup)
+	if !ok {
+		return errors.AssertionFailedf("expected BACKUP statement, found %s", stmt.AST.StatementTag())
+	}
+
+	now := timeutil.Now()
+	if needsFullBackup(args, now) {
+		args.Collections = append(args.Collections, BackupCollection{
+			Path:         now.Format(backupCollectionSubdirFormat),
+			CreatedNanos: now.UnixNano(),
+		})
+		if args.FullBackupExpr != "" {
+			nextFullBackup, err := nextCronTime(args.FullBackupExpr, now)
+			if err != nil {
+				return err
+			}
+			args.NextFullBackupNanos = nextFullBackup.UnixNano()
+		}
+	}
+
+	latest := args.Collections[len(args.Collections)-1]
+	backupStmt.To = nil
+	for _, dest := range args.Destinations {
+		uri, err := appendPathToURI(dest, latest.Path)
+		if err != nil {
+			return err
+		}
+		backupStmt.To = append(backupStmt.To, tree.NewDString(uri))
+	}
+
+	row, err := env.InternalExecutor.QueryRowEx(ctx, "scheduled-backup", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: schedule.Owner()},
+		tree.AsStringWithFlags(backupStmt, tree.FmtParsable),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to start backup of schedule %d", schedule.ScheduleID())
+	}
+	jobID := int64(tree.MustBeDInt(row[0]))
+	log.Infof(ctx, "schedule %d started backup job %d into %s",
+		schedule.ScheduleID(), jobID, latest.Path)
+
+	// The collections of the schedule are expired by the backup job once it
+	// succeeds, so that they're only deleted when a newer backup exists.
+	job, err := env.Registry.LoadJobWithTxn(ctx, jobID, txn)
+	if err != nil {
+		return err
+	}
+	details := job.Details().(jobspb.BackupDetails)
+	details.ScheduleID = schedule.ScheduleID()
+	if err := job.WithTxn(txn).SetDetails(ctx, details); err != nil {
+		return err
+	}
+
+	anyArgs, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return err
+	}
+	schedule.SetExecutionDetails(schedule.ExecutorType(), jobspb.ExecutionArguments{Args: anyArgs})
+	return nil
+}
+
+// needsFullBackup returns whether the backup of a schedule running at now is a
+// full backup.
+func needsFullBackup(args *ScheduledBackupExecutionArgs, now time.Time) bool {
+	return len(args.Collections) == 0 || args.FullBackupExpr == "" ||
+		now.UnixNano() >= args.NextFullBackupNanos
+}
+
+// expireCollections removes from args the collections whose retention has
+// expired at now, and returns them. A collection expires once the collection
+// which superseded it is older than the retention, since every time within
+// the retention can then be restored from the newer collections. The latest
+// collection never expires.
+func expireCollections(args *ScheduledBackupExecutionArgs, now time.Time) []BackupCollection {
+	if args.RetentionNanos == 0 {
+		return nil
+	}
+	var expired []BackupCollection
+	for len(args.Collections) > 1 &&
+		args.Collections[1].CreatedNanos+args.RetentionNanos <= now.UnixNano() {
+		expired = append(expired, args.Collections[0])
+		args.Collections = args.Collections[1:]
+	}
+	return expired
+}
+
+// expireScheduledCollections removes the collections whose retention has
+// expired from the backup schedule with the given ID, and deletes their files.
+// It's called by the backup jobs of the schedule once they succeed, outside of
+// any transaction, so that a failed backup never deletes the collections it
+// would have superseded, and the scheduler isn't held up by the deletion. Only
+// the collections superseded by the one the backup was written to, at
+// backupURI, can expire.
+func expireScheduledCollections(
+	ctx context.Context, execCfg *sql.ExecutorConfig, scheduleID int64, backupURI string,
+) error {
+	var args *ScheduledBackupExecutionArgs
+	var expired []BackupCollection
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		schedule, err := jobs.LoadScheduledJob(ctx, execCfg.InternalExecutor, scheduleID, txn)
+		if err != nil {
+			return err
+		}
+		args = &ScheduledBackupExecutionArgs{}
+		if err := pbtypes.UnmarshalAny(schedule.ExecutionArgs().Args, args); err != nil {
+			return errors.Wrap(err, "failed to decode arguments of backup schedule")
+		}
+		backupIdx := -1
+		for i, collection := range args.Collections {
+			uri, _, err := getURIsByLocalityKV(args.Destinations, collection.Path)
+			if err != nil {
+				return err
+			}
+			if uri == backupURI {
+				backupIdx = i
+				break
+			}
+		}
+		// Newer collections, whose backups may not have succeeded yet, are kept
+		// out of the expiration.
+		newer := append([]BackupCollection(nil), args.Collections[backupIdx+1:]...)
+		args.Collections = args.Collections[:backupIdx+1]
+		expired = expireCollections(args, timeutil.Now())
+		args.Collections = append(args.Collections, newer...)
+		if len(expired) == 0 {
+			return nil
+		}
+		anyArgs, err := pbtypes.MarshalAny(args)
+		if err != nil {
+			return err
+		}
+		schedule.SetExecutionDetails(schedule.ExecutorType(), jobspb.ExecutionArguments{Args: anyArgs})
+		return schedule.Update(ctx, execCfg.InternalExecutor, txn)
+	}); err != nil {
+		return errors.Wrapf(err, "failed to expire backups of schedule %d", scheduleID)
+	}
+	deleteCollections(ctx, execCfg.DistSQLSrv.ExternalStorageFromURI, args.Destinations, expired)
+	return nil
+}
+
+// deleteCollections deletes the files of the given collections. The deletion
+// is best effort: errors are logged, as the collections are not part of the
+// schedule anymore.
+func deleteCollections(
+	ctx context.Context,
+	makeCloudStorage cloud.ExternalStorageFromURIFactory,
+	destinations []string,
+	collections []BackupCollection,
+) {
+	for _, collection := range collections {
+		defaultURI, urisByLocalityKV, err := getURIsByLocalityKV(destinations, collection.Path)
+		if err != nil {
+			log.Warningf(ctx, "failed to delete expired backup collection %s: %v", collection.Path, err)
+			continue
+		}
+		uris := []string{defaultURI}
+		for _, uri := range urisByLocalityKV {
+			uris = append(uris, uri)
+		}
+		for _, uri := range uris {
+			if err := deleteCollectionFiles(ctx, makeCloudStorage, uri); err != nil {
+				log.Warningf(ctx, "failed to delete expired backup collection %s: %v", collection.Path, err)
+			}
+		}
+	}
+}
+
+// deleteCollectionFiles deletes the files of the collection stored at uri:
+// the files of its incremental layers, then its own files.
+func deleteCollectionFiles(
+	ctx context.Context, makeCloudStorage cloud.ExternalStorageFromURIFactory, uri string,
+) error {
+	store, err := makeCloudStorage(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	for _, pattern := range []string{"*/*/*", "*/*", "*"} {
+		files, err := store.ListFiles(ctx, pattern)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := store.Delete(ctx, file); err != nil {
+				return errors.Wrapf(err, "failed to delete %s", file)
+			}
+		}
+	}
+	return nil
+}
+
+// nextCronTime returns the next time after now matched by the cron expression
+// expr.
+func nextCronTime(expr string, now time.Time) (time.Time, error) {
+	cron, err := cronexpr.Parse(expr)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid schedule %q", expr)
+	}
+	next := cron.Next(now)
+	if next.IsZero() {
+		return time.Time{}, errors.Newf("schedule %q never runs", expr)
+	}
+	return next, nil
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		scheduledBackupExecutorName,
+		func() (jobs.ScheduledJobExecutor, error) {
+			return &scheduledBackupExecutor{}, nil
+		})
+}
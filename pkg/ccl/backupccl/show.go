@@ -10,13 +10,15 @@ package backupccl
 
 import (
 	"context"
+	"net/url"
+	"path"
 	"strings"
 	"time"
 
-	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -48,16 +50,36 @@ func showBackupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	if backup.Path == nil && backup.InCollection != nil {
+		return showBackupsInCollectionPlanHook(ctx, backup, p)
+	}
+
 	toFn, err := p.TypeAsString(backup.Path, "SHOW BACKUP")
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
+	var inColFn func() (string, error)
+	if backup.InCollection != nil {
+		if inColFn, err = p.TypeAsString(backup.InCollection, "SHOW BACKUP"); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+	localityFn, err := p.TypeAsStringArray(tree.Exprs(backup.LocalityURIs), "SHOW BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
 
 	expected := map[string]sql.KVStringOptValidate{
 		backupOptEncPassphrase:  sql.KVStringOptRequireValue,
 		backupOptWithPrivileges: sql.KVStringOptRequireNoValue,
+		backupOptCheckFiles:     sql.KVStringOptRequireNoValue,
+	}
+	kmsExprs, options := splitKMSOptions(backup.Options)
+	kmsFn, err := typeAsKMSURIs(p, kmsExprs, "SHOW BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
 	}
-	optsFn, err := p.TypeAsStringOpts(backup.Options, expected)
+	optsFn, err := p.TypeAsStringOpts(options, expected)
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
@@ -65,6 +87,14 @@ func showBackupPlanHook(
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
+	kmsURIs, err := kmsFn()
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	encryptionParams, err := makeEncryptionParams(opts, kmsURIs)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
 
 	var shower backupShower
 	switch backup.Details {
@@ -85,6 +115,29 @@ func showBackupPlanHook(
 		if err != nil {
 			return err
 		}
+		localityURIs, err := localityFn()
+		if err != nil {
+			return err
+		}
+		if inColFn != nil {
+			collection, err := inColFn()
+			if err != nil {
+				return err
+			}
+			subdir, err := resolveBackupSubdir(
+				ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
+				append([]string{collection}, localityURIs...), str,
+			)
+			if err != nil {
+				return err
+			}
+			if str, err = appendPathToURI(collection, subdir); err != nil {
+				return err
+			}
+			if localityURIs, err = appendPathToURIs(localityURIs, subdir); err != nil {
+				return err
+			}
+		}
 
 		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, str)
 		if err != nil {
@@ -92,14 +145,9 @@ func showBackupPlanHook(
 		}
 		defer store.Close()
 
-		var encryption *roachpb.FileEncryptionOptions
-		if passphrase, ok := opts[backupOptEncPassphrase]; ok {
-			opts, err := readEncryptionOptions(ctx, store)
-			if err != nil {
-				return err
-			}
-			encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts.Salt)
-			encryption = &roachpb.FileEncryptionOptions{Key: encryptionKey}
+		encryption, err := readEncryption(ctx, p.ExecCfg().Settings, store, encryptionParams)
+		if err != nil {
+			return err
 		}
 
 		incPaths, err := findPriorBackups(ctx, store)
@@ -130,6 +178,22 @@ func showBackupPlanHook(
 			manifests[i+1] = m
 		}
 
+		if _, ok := opts[backupOptCheckFiles]; ok {
+			uncheckedSizes, err := checkBackupFiles(
+				ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, append([]string{str}, localityURIs...),
+				store, manifests, incPaths, encryption,
+			)
+			if err != nil {
+				return err
+			}
+			if uncheckedSizes > 0 {
+				p.ExtendedEvalContext().ClientNoticeSender.SendClientNotice(ctx, pgnotice.Newf(
+					"the sizes of %d backup files could not be checked since the backup does not record them",
+					uncheckedSizes,
+				))
+			}
+		}
+
 		// If we are restoring a backup with old-style foreign keys, skip over the
 		// FKs for which we can't resolve the cross-table references. We can't
 		// display them anyway, because we don't have the referenced table names,
@@ -157,6 +221,139 @@ func showBackupPlanHook(
 	return fn, shower.header, nil, false, nil
 }
 
+// checkBackupFiles checks that every file of a backup exists and has the size
+// recorded in its manifest. manifests are the manifests of the backup in store
+// followed by those of the incremental backups appended to it, in incPaths.
+// The files of a backup partitioned by locality are looked up in the store of
+// their locality, which is found among uris, like RESTORE does. It returns the
+// number of files whose size isn't recorded and couldn't be checked.
+func checkBackupFiles(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	uris []string,
+	store cloud.ExternalStorage,
+	manifests []BackupManifest,
+	incPaths []string,
+	encryption *roachpb.FileEncryptionOptions,
+) (int, error) {
+	var stores []cloud.ExternalStorage
+	storesByURI := make(map[string]cloud.ExternalStorage)
+	defer func() {
+		for _, s := range storesByURI {
+			_ = s.Close()
+		}
+	}()
+	storeForURI := func(uri string) (cloud.ExternalStorage, error) {
+		if s, ok := storesByURI[uri]; ok {
+			return s, nil
+		}
+		s, err := mkStore(ctx, uri)
+		if err != nil {
+			return nil, errors.Wrapf(err, "export configuration")
+		}
+		storesByURI[uri] = s
+		return s, nil
+	}
+
+	var uncheckedSizes int
+	for i := range manifests {
+		var dir string
+		if i > 0 {
+			dir = path.Dir(incPaths[i-1])
+		}
+
+		// The files of each locality are in the same subdirectory of the
+		// locality's store as the manifest is in the default one.
+		var urisByLocalityKV map[string]string
+		if len(manifests[i].PartitionDescriptorFilenames) > 0 {
+			if stores == nil {
+				stores = make([]cloud.ExternalStorage, len(uris))
+				for j := range uris {
+					var err error
+					if stores[j], err = storeForURI(uris[j]); err != nil {
+						return 0, err
+					}
+				}
+			}
+			layerURIs := make([]string, len(uris))
+			for j := range uris {
+				u, err := url.Parse(uris[j])
+				if err != nil {
+					return 0, err
+				}
+				u.Path = path.Join(u.Path, dir)
+				layerURIs[j] = u.String()
+			}
+			info, err := getLocalityInfo(ctx, stores, layerURIs, manifests[i], encryption, dir)
+			if err != nil {
+				return 0, err
+			}
+			urisByLocalityKV = info.URIsByOriginalLocalityKV
+		}
+
+		for _, f := range manifests[i].Files {
+			fileStore, name := store, path.Join(dir, f.Path)
+			if uri, ok := urisByLocalityKV[f.LocalityKV]; ok {
+				var err error
+				if fileStore, err = storeForURI(uri); err != nil {
+					return 0, err
+				}
+				name = f.Path
+			}
+			size, err := fileStore.Size(ctx, name)
+			if err != nil {
+				return 0, errors.Wrapf(err, "checking backup file %s", name)
+			}
+			if f.FileSize == 0 {
+				uncheckedSizes++
+			} else if size != f.FileSize {
+				return 0, errors.Errorf("backup file %s has size %d, expected %d", name, size, f.FileSize)
+			}
+		}
+	}
+	return uncheckedSizes, nil
+}
+
+// showBackupsInCollectionPlanHook implements PlanHookFn for SHOW BACKUPS IN,
+// which lists the subdirectories of the full backups in a collection.
+func showBackupsInCollectionPlanHook(
+	_ context.Context, backup *tree.ShowBackup, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	collectionFn, err := p.TypeAsString(backup.InCollection, "SHOW BACKUPS")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, backup.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		collection, err := collectionFn()
+		if err != nil {
+			return err
+		}
+		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, collection)
+		if err != nil {
+			return errors.Wrapf(err, "make storage")
+		}
+		defer store.Close()
+
+		backups, err := findBackupsInCollection(ctx, store)
+		if err != nil {
+			return err
+		}
+		for _, subdir := range backups {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case resultsCh <- tree.Datums{tree.NewDString(subdir)}:
+			}
+		}
+		return nil
+	}
+	return fn, sqlbase.ResultColumns{{Name: "path", Typ: types.String}}, nil, false, nil
+}
+
 type backupShower struct {
 	header sqlbase.ResultColumns
 	fn     func([]BackupManifest) ([]tree.Datums, error)
@@ -0,0 +1,157 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/errors"
+)
+
+// spanFilterSpans returns the span of the primary index of table selected by
+// the span_filter option of RESTORE.
+//
+// The filter is either a single key, which selects the rows whose primary key
+// starts with it, or a start and an end key separated by a comma, which select
+// the rows whose primary key is in [start, end) in the order of the primary
+// index. A NULL start or end leaves that side of the range unbounded. A key is
+// a value or a tuple of values of the leading columns of the primary key, e.g.
+// (1, 'a') or 10, 20.
+func spanFilterSpans(
+	codec keys.SQLCodec, evalCtx *tree.EvalContext, table *sqlbase.TableDescriptor, filter string,
+) ([]roachpb.Span, error) {
+	exprs, err := parser.ParseExprs([]string{filter})
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s %q", restoreOptSpanFilter, filter)
+	}
+	indexSpan := table.PrimaryIndexSpan(codec)
+	switch len(exprs) {
+	case 1:
+		key, err := encodeSpanFilterKey(codec, evalCtx, table, exprs[0])
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			return []roachpb.Span{indexSpan}, nil
+		}
+		return []roachpb.Span{{Key: key, EndKey: key.PrefixEnd()}}, nil
+	case 2:
+		span := indexSpan
+		start, err := encodeSpanFilterKey(codec, evalCtx, table, exprs[0])
+		if err != nil {
+			return nil, err
+		}
+		if start != nil {
+			span.Key = start
+		}
+		end, err := encodeSpanFilterKey(codec, evalCtx, table, exprs[1])
+		if err != nil {
+			return nil, err
+		}
+		if end != nil {
+			span.EndKey = end
+		}
+		if bytes.Compare(span.Key, span.EndKey) >= 0 {
+			return nil, errors.Errorf("%s %q selects no rows: its start is not before its end",
+				restoreOptSpanFilter, filter)
+		}
+		return []roachpb.Span{span}, nil
+	default:
+		return nil, errors.Errorf("invalid %s %q: expected a key or a start and an end key",
+			restoreOptSpanFilter, filter)
+	}
+}
+
+// encodeSpanFilterKey encodes a key of a span_filter as a prefix of the keys of
+// the primary index of table. It returns nil for a NULL key.
+func encodeSpanFilterKey(
+	codec keys.SQLCodec, evalCtx *tree.EvalContext, table *sqlbase.TableDescriptor, expr tree.Expr,
+) (roachpb.Key, error) {
+	if expr == tree.DNull {
+		return nil, nil
+	}
+	elems := tree.Exprs{expr}
+	if tuple, ok := expr.(*tree.Tuple); ok {
+		elems = tuple.Exprs
+	}
+	index := &table.PrimaryIndex
+	if len(elems) > len(index.ColumnIDs) {
+		return nil, errors.Errorf("%s key %s has %d values, but the primary key of %s has %d columns",
+			restoreOptSpanFilter, expr, len(elems), table.Name, len(index.ColumnIDs))
+	}
+
+	semaCtx := tree.MakeSemaContext()
+	colMap := make(map[sqlbase.ColumnID]int, len(elems))
+	values := make([]tree.Datum, len(elems))
+	for i, elem := range elems {
+		col, err := table.FindColumnByID(index.ColumnIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		typedElem, err := tree.TypeCheckAndRequire(elem, &semaCtx, col.Type, restoreOptSpanFilter)
+		if err != nil {
+			return nil, err
+		}
+		if values[i], err = typedElem.Eval(evalCtx); err != nil {
+			return nil, err
+		}
+		if values[i] == tree.DNull {
+			return nil, errors.Errorf("%s key %s cannot contain NULL", restoreOptSpanFilter, expr)
+		}
+		colMap[col.ID] = i
+	}
+	key, _, err := sqlbase.EncodePartialIndexKey(
+		table, index, len(values), colMap, values, sqlbase.MakeIndexKeyPrefix(codec, table, index.ID),
+	)
+	return key, err
+}
+
+// checkSpanFilterTable returns an error if the restore of table cannot be
+// limited by a span_filter.
+func checkSpanFilterTable(table *sqlbase.TableDescriptor) error {
+	if table.IsInterleaved() {
+		return errors.Errorf("cannot use %s on interleaved table %s", restoreOptSpanFilter, table.Name)
+	}
+	if len(table.Mutations) > 0 {
+		return errors.Errorf("cannot use %s on table %s which has schema changes in progress",
+			restoreOptSpanFilter, table.Name)
+	}
+	if !table.IsPhysicalTable() || table.IsSequence() {
+		return errors.Errorf("cannot use %s on %s which is not a table", restoreOptSpanFilter, table.Name)
+	}
+	return nil
+}
+
+// addSecondaryIndexMutations turns the secondary indexes of a table restored
+// with a span_filter into mutations adding them back: only the rows of its
+// primary index in the span are restored, so the entries of its secondary
+// indexes are backfilled from these rows by a schema change once the table is
+// published. All the indexes are added by the same mutation.
+func addSecondaryIndexMutations(table *sqlbase.TableDescriptor) {
+	if len(table.Indexes) == 0 {
+		return
+	}
+	mutationID := table.NextMutationID
+	table.NextMutationID++
+	for i := range table.Indexes {
+		idx := table.Indexes[i]
+		table.Mutations = append(table.Mutations, sqlbase.DescriptorMutation{
+			Descriptor_: &sqlbase.DescriptorMutation_Index{Index: &idx},
+			State:       sqlbase.DescriptorMutation_DELETE_ONLY,
+			Direction:   sqlbase.DescriptorMutation_ADD,
+			MutationID:  mutationID,
+		})
+	}
+	table.Indexes = nil
+}
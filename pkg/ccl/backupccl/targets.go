@@ -60,6 +60,9 @@ type descriptorResolver struct {
 	dbsByName map[string]sqlbase.ID
 	// Map: dbID -> obj name -> obj ID
 	objsByName map[sqlbase.ID]map[string]sqlbase.ID
+	// Map: dbID -> name of a table in a user-defined schema of that database.
+	// Such tables cannot be backed up yet.
+	userSchemaObjs map[sqlbase.ID]string
 }
 
 // LookupSchema implements the tree.TableNameTargetResolver interface.
@@ -101,9 +104,10 @@ func (r *descriptorResolver) LookupObject(
 // known set of descriptors.
 func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, error) {
 	r := &descriptorResolver{
-		descByID:   make(map[sqlbase.ID]sqlbase.Descriptor),
-		dbsByName:  make(map[string]sqlbase.ID),
-		objsByName: make(map[sqlbase.ID]map[string]sqlbase.ID),
+		descByID:       make(map[sqlbase.ID]sqlbase.Descriptor),
+		dbsByName:      make(map[string]sqlbase.ID),
+		objsByName:     make(map[sqlbase.ID]map[string]sqlbase.ID),
+		userSchemaObjs: make(map[sqlbase.ID]string),
 	}
 
 	// Iterate to find the databases first. We need that because we also
@@ -131,6 +135,10 @@ func newDescriptorResolver(descs []sqlbase.Descriptor) (*descriptorResolver, err
 			if tbDesc.Dropped() {
 				continue
 			}
+			if schemaDesc, ok := r.descByID[tbDesc.GetParentSchemaID()]; ok && schemaDesc.GetSchema() != nil {
+				r.userSchemaObjs[tbDesc.ParentID] = tbDesc.Name
+				continue
+			}
 			parentDesc, ok := r.descByID[tbDesc.ParentID]
 			if !ok {
 				return nil, errors.Errorf("table %q has unknown ParentID %d", tbDesc.Name, tbDesc.ParentID)
@@ -175,6 +183,10 @@ func descriptorsMatchingTargets(
 
 	ret := descriptorsMatched{}
 
+	if len(targets.Schemas) > 0 {
+		return ret, errors.Errorf("cannot target schemas %s", tree.ErrString(&targets.Schemas))
+	}
+
 	resolver, err := newDescriptorResolver(descriptors)
 	if err != nil {
 		return ret, err
@@ -274,6 +286,11 @@ func descriptorsMatchingTargets(
 
 	// Then process the database expansions.
 	for dbID := range alreadyExpandedDBs {
+		if tblName, ok := resolver.userSchemaObjs[dbID]; ok {
+			return ret, errors.Errorf(
+				"cannot back up database %q: table %q is in a user-defined schema",
+				resolver.descByID[dbID].GetName(), tblName)
+		}
 		for _, tblID := range resolver.objsByName[dbID] {
 			desc := resolver.descByID[tblID]
 			table := desc.Table(hlc.Timestamp{})
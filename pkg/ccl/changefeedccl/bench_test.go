@@ -237,7 +237,8 @@ func createBenchmarkChangefeed(
 		s.LeaseManager().(*sql.LeaseManager), details, buf.Get)
 	sf := span.MakeFrontier(spans...)
 	tickFn := emitEntries(s.ClusterSettings(), details, hlc.Timestamp{}, sf,
-		encoder, sink, rowsFn, TestingKnobs{}, metrics)
+		encoder, sink, rowsFn, nil /* txns */, nil /* exactlyOnceSink */, TestingKnobs{},
+		metrics)
 
 	ctx, cancel := context.WithCancel(ctx)
 	go func() { _ = kvfeed.Run(ctx, kvfeedCfg) }()
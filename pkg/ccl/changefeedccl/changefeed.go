@@ -68,6 +68,7 @@ func kvsToRows(
 		output []emitEntry,
 		kv roachpb.KeyValue,
 		prevVal roachpb.Value,
+		txnID uuid.UUID,
 		schemaTimestamp hlc.Timestamp,
 		prevSchemaTimestamp hlc.Timestamp,
 		bufferGetTimestamp time.Time,
@@ -112,6 +113,7 @@ func kvsToRows(
 			r.row.datums = append(sqlbase.EncDatumRow(nil), r.row.datums...)
 			r.row.deleted = rf.RowIsDeleted()
 			r.row.updated = schemaTimestamp
+			r.row.txnID = txnID
 
 			// Assert that we don't get a second row from the row.Fetcher. We
 			// fed it a single KV, so that would be surprising.
@@ -199,7 +201,7 @@ func kvsToRows(
 					prevSchemaTimestamp = schemaTimestamp.Prev()
 				}
 				output, err = appendEmitEntryForKV(
-					ctx, output, kv, input.PrevValue(),
+					ctx, output, kv, input.PrevValue(), input.TxnID(),
 					schemaTimestamp, prevSchemaTimestamp,
 					input.BufferGetTimestamp())
 				if err != nil {
@@ -232,6 +234,8 @@ func emitEntries(
 	encoder Encoder,
 	sink Sink,
 	inputFn func(context.Context) ([]emitEntry, error),
+	txns *transactionTracker,
+	exactlyOnceSink *cloudStorageSink,
 	knobs TestingKnobs,
 	metrics *Metrics,
 ) func(context.Context) ([]jobspb.ResolvedSpan, error) {
@@ -271,6 +275,9 @@ func emitEntries(
 		); err != nil {
 			return err
 		}
+		if txns != nil {
+			txns.rowEmitted(row)
+		}
 		if log.V(3) {
 			log.Infof(ctx, `row %s: %s -> %s`, row.tableDesc.Name, keyCopy, valueCopy)
 		}
@@ -356,6 +363,16 @@ func emitEntries(
 		}
 		ret := append([]jobspb.ResolvedSpan(nil), resolvedSpans...)
 		resolvedSpans = resolvedSpans[:0]
+		if txns != nil {
+			// All rows emitted so far have been flushed, so the changeFrontier
+			// can count them towards their transaction end markers.
+			ret[0].Transactions = txns.drain()
+		}
+		if exactlyOnceSink != nil {
+			// The changeFrontier publishes the flushed data files with the
+			// manifests of their manifest timestamps.
+			ret[0].Files, ret[0].FileBounds = exactlyOnceSink.drainFlushedFiles()
+		}
 		return ret, nil
 	}
 }
@@ -11,7 +11,6 @@ package changefeedccl
 import (
 	"context"
 	"fmt"
-	"math"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -53,6 +52,9 @@ type changeAggregator struct {
 	errCh chan error
 	// kvFeedDoneCh is closed when the kvfeed exits.
 	kvFeedDoneCh chan struct{}
+	// memMon is the memory budget of the changefeed on this node, which
+	// kvFeedMemMon and the sink draw from.
+	memMon       *mon.BytesMonitor
 	kvFeedMemMon *mon.BytesMonitor
 
 	// encoder is the Encoder to use for key and value serialization.
@@ -174,44 +176,69 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 		return ctx
 	}
 
-	// This is the correct point to set up certain hooks depending on the sink
-	// type.
-	if b, ok := ca.sink.(*bufferSink); ok {
-		ca.changedRowBuf = &b.buf
-	}
-
-	// The job registry has a set of metrics used to monitor the various jobs it
-	// runs. They're all stored as the `metric.Struct` interface because of
-	// dependency cycles.
-	metrics := ca.flowCtx.Cfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
-	ca.sink = makeMetricsSink(metrics, ca.sink)
-	ca.sink = &errorWrapperSink{wrapped: ca.sink}
-
 	var knobs TestingKnobs
 	if cfKnobs, ok := ca.flowCtx.TestingKnobs().Changefeed.(*TestingKnobs); ok {
 		knobs = *cfKnobs
 	}
 
+	// The memory used to buffer changes, between the rangefeeds and the sink,
+	// is drawn from the SQL memory pool of the node, and limited per
+	// changefeed. Half of the budget is reserved for the sink, so that a full
+	// kvfeed buffer can't starve it.
+	//
 	// It seems like we should also be able to use `ca.ProcessorBase.MemMonitor`
-	// for the poller, but there is a race between the flow's MemoryMonitor
-	// getting Stopped and `changeAggregator.Close`, which causes panics. Not sure
-	// what to do about this yet.
-	kvFeedMemMonCapacity := kvfeed.MemBufferDefaultCapacity
+	// for this, but there is a race between the flow's MemoryMonitor getting
+	// Stopped and `changeAggregator.Close`, which causes panics. Not sure what to
+	// do about this yet.
+	memLimit := changefeedbase.PerChangefeedMemLimit.Get(&ca.flowCtx.Cfg.Settings.SV)
 	if knobs.MemBufferCapacity != 0 {
-		kvFeedMemMonCapacity = knobs.MemBufferCapacity
+		memLimit = knobs.MemBufferCapacity
 	}
-	kvFeedMemMon := mon.MakeMonitorInheritWithLimit("kvFeed", math.MaxInt64, ca.ProcessorBase.MemMonitor)
-	kvFeedMemMon.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(kvFeedMemMonCapacity))
+	pool := ca.flowCtx.Cfg.ParentMemoryMonitor
+	memMon := mon.MakeMonitorInheritWithLimit("changefeed", memLimit, pool)
+	memMon.Start(ctx, pool, mon.BoundAccount{})
+	ca.memMon = &memMon
+	kvFeedMemMon := mon.MakeMonitorInheritWithLimit("kvFeed", memLimit/2, ca.memMon)
+	kvFeedMemMon.Start(ctx, ca.memMon, mon.BoundAccount{})
 	ca.kvFeedMemMon = &kvFeedMemMon
 
+	// The job registry has a set of metrics used to monitor the various jobs it
+	// runs. They're all stored as the `metric.Struct` interface because of
+	// dependency cycles.
+	metrics := ca.flowCtx.Cfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
+
+	// This is the correct point to set up certain hooks depending on the sink
+	// type. The rows of a bufferSink are accounted for by the flow instead.
+	var exactlyOnceSink *cloudStorageSink
+	if s, ok := ca.sink.(*cloudStorageSink); ok && s.exactlyOnce {
+		exactlyOnceSink = s
+	}
+	if b, ok := ca.sink.(*bufferSink); ok {
+		ca.changedRowBuf = &b.buf
+	} else {
+		ca.sink = makeBudgetedSink(ca.memMon.MakeBoundAccount(), metrics, ca.sink)
+	}
+	ca.sink = makeMetricsSink(metrics, ca.sink)
+	ca.sink = &errorWrapperSink{wrapped: ca.sink}
+
 	buf := kvfeed.MakeChanBuffer()
 	leaseMgr := ca.flowCtx.Cfg.LeaseManager.(*sql.LeaseManager)
 	_, withDiff := ca.spec.Feed.Opts[changefeedbase.OptDiff]
 	kvfeedCfg := makeKVFeedCfg(ca.flowCtx.Cfg, leaseMgr, ca.kvFeedMemMon, ca.spec,
 		spans, withDiff, buf, metrics)
 	rowsFn := kvsToRows(ca.flowCtx.Codec(), leaseMgr, ca.spec.Feed, buf.Get)
+	// Apply the column lists and WHERE clauses of the targets, if any, to the
+	// decoded rows before they're encoded.
+	if p := makeRowProjector(ca.flowCtx.NewEvalCtx(), ca.spec.Feed.Targets); p != nil {
+		rowsFn = p.wrap(rowsFn)
+	}
+	var txns *transactionTracker
+	if _, ok := ca.spec.Feed.Opts[changefeedbase.OptTransactions]; ok {
+		txns = makeTransactionTracker()
+	}
 	ca.tickFn = emitEntries(ca.flowCtx.Cfg.Settings, ca.spec.Feed,
-		kvfeedCfg.InitialHighWater, sf, ca.encoder, ca.sink, rowsFn, knobs, metrics)
+		kvfeedCfg.InitialHighWater, sf, ca.encoder, ca.sink, rowsFn, txns, exactlyOnceSink, knobs,
+		metrics)
 	ca.startKVFeed(ctx, kvfeedCfg)
 
 	return ctx
@@ -342,6 +369,9 @@ func (ca *changeAggregator) close() {
 		if ca.kvFeedMemMon != nil {
 			ca.kvFeedMemMon.Stop(ca.Ctx)
 		}
+		if ca.memMon != nil {
+			ca.memMon.Stop(ca.Ctx)
+		}
 		ca.MemMonitor.Stop(ca.Ctx)
 	}
 }
@@ -437,6 +467,21 @@ type changeFrontier struct {
 	lastEmitResolved time.Time
 	// lastSlowSpanLog is the last time a slow span from `sf` was logged.
 	lastSlowSpanLog time.Time
+	// txns, if non-nil, accumulates the per-transaction row counts sent by the
+	// changeAggregators until the frontier passes them and the transaction end
+	// markers are emitted.
+	txns *transactionAccumulator
+	// manifestSink, if non-nil, is the cloud storage sink of an exactly_once
+	// changefeed, to which manifests are written instead of resolved
+	// timestamps.
+	manifestSink *cloudStorageSink
+	// pendingFiles are the data files sent by the changeAggregators that
+	// haven't been published by a manifest yet. It is only used with
+	// manifestSink.
+	pendingFiles []manifestFile
+	// lastManifest is the timestamp of the last manifest written, or the
+	// high-water at start. It is only used with manifestSink.
+	lastManifest hlc.Timestamp
 
 	// schemaChangeBoundary represents an hlc timestamp at which a schema change
 	// event occurred to a target watched by this frontier. If the changefeed is
@@ -523,6 +568,9 @@ func newChangeFrontierProcessor(
 	} else {
 		cf.freqEmitResolved = emitNoResolved
 	}
+	if _, ok := cf.spec.Feed.Opts[changefeedbase.OptTransactions]; ok {
+		cf.txns = makeTransactionAccumulator()
+	}
 
 	var err error
 	if cf.encoder, err = getEncoder(spec.Feed.Opts); err != nil {
@@ -564,6 +612,9 @@ func (cf *changeFrontier) Start(ctx context.Context) context.Context {
 	if b, ok := cf.sink.(*bufferSink); ok {
 		cf.resolvedBuf = &b.buf
 	}
+	if s, ok := cf.sink.(*cloudStorageSink); ok && s.exactlyOnce {
+		cf.manifestSink = s
+	}
 
 	// The job registry has a set of metrics used to monitor the various jobs it
 	// runs. They're all stored as the `metric.Struct` interface because of
@@ -584,9 +635,21 @@ func (cf *changeFrontier) Start(ctx context.Context) context.Context {
 		p := job.Progress()
 		if ts := p.GetHighWater(); ts != nil {
 			cf.highWaterAtStart.Forward(*ts)
+			// The previous job session may have checkpointed its last resolved
+			// timestamp without publishing its manifest.
+			if cf.manifestSink != nil && !ts.IsEmpty() {
+				if err := cf.manifestSink.recoverManifest(ctx, *ts); err != nil {
+					cf.MoveToDraining(MarkRetryableError(err))
+					return ctx
+				}
+			}
 		}
 	}
 
+	// Manifests are only written for timestamps above the high-water, which
+	// the rows of the initial scan are emitted at.
+	cf.lastManifest = cf.highWaterAtStart
+
 	cf.metrics.mu.Lock()
 	cf.metricsID = cf.metrics.mu.id
 	cf.metrics.mu.id++
@@ -736,6 +799,21 @@ func (cf *changeFrontier) noteResolvedSpan(d sqlbase.EncDatum) error {
 		cf.schemaChangeBoundary = hlc.Timestamp{}
 	}
 
+	if cf.txns != nil {
+		cf.txns.add(resolved.Transactions)
+	}
+	if cf.manifestSink != nil {
+		if len(resolved.Files) != len(resolved.FileBounds) {
+			return errors.AssertionFailedf(
+				"got %d data files with %d bounds", len(resolved.Files), len(resolved.FileBounds))
+		}
+		for i := range resolved.Files {
+			cf.pendingFiles = append(cf.pendingFiles, manifestFile{
+				path: resolved.Files[i], bound: resolved.FileBounds[i],
+			})
+		}
+	}
+
 	frontierChanged := cf.sf.Forward(resolved.Span, resolved.Timestamp)
 	isBehind := cf.maybeLogBehindSpan(frontierChanged)
 	if frontierChanged {
@@ -753,6 +831,18 @@ func (cf *changeFrontier) handleFrontierChanged(isBehind bool) error {
 		cf.metrics.mu.resolved[cf.metricsID] = newResolved
 	}
 	cf.metrics.mu.Unlock()
+	if cf.txns != nil {
+		// Emit the transaction end markers before checkpointing so that they
+		// aren't lost if the changefeed restarts in between.
+		if err := emitTransactionEnds(
+			cf.Ctx, cf.encoder, cf.sink, cf.txns, cf.spec.Feed.Targets, newResolved,
+		); err != nil {
+			return err
+		}
+	}
+	if cf.manifestSink != nil {
+		return cf.maybeWriteManifest(newResolved, isBehind)
+	}
 	if err := cf.checkpointResolvedTimestamp(newResolved, isBehind); err != nil {
 		return err
 	}
@@ -762,6 +852,46 @@ func (cf *changeFrontier) handleFrontierChanged(isBehind bool) error {
 	return nil
 }
 
+// manifestFile is a data file of an exactly_once changefeed, which is
+// published by the manifest of its bound.
+type manifestFile struct {
+	path  string
+	bound hlc.Timestamp
+}
+
+// maybeWriteManifest checkpoints the latest manifest timestamp at or below the
+// resolved timestamp of an exactly_once changefeed, along with the manifest of
+// the pending data files of the manifest timestamps up to it. The rows above
+// the checkpoint are all in the remaining pending data files, or haven't been
+// flushed yet, so they're all replayed into new data files if the changefeed
+// restarts from the checkpoint, and none of them are published twice.
+// Checkpointing without a manifest would lose the data files, which wouldn't
+// be replayed on restart.
+func (cf *changeFrontier) maybeWriteManifest(newResolved hlc.Timestamp, isBehind bool) error {
+	manifestTS := manifestFloor(newResolved, cf.manifestSink.manifestInterval)
+	if manifestTS.LessEq(cf.lastManifest) {
+		return nil
+	}
+	var files []string
+	remaining := cf.pendingFiles[:0]
+	for _, f := range cf.pendingFiles {
+		if f.bound.LessEq(manifestTS) {
+			files = append(files, f.path)
+		} else {
+			remaining = append(remaining, f)
+		}
+	}
+	if err := cf.manifestSink.writeManifest(cf.Ctx, manifestTS, files, func() error {
+		return cf.checkpointResolvedTimestamp(manifestTS, isBehind)
+	}); err != nil {
+		return err
+	}
+	cf.pendingFiles = remaining
+	cf.lastManifest = manifestTS
+	cf.lastEmitResolved = manifestTS.GoTime()
+	return nil
+}
+
 // checkpointResolvedTimestamp checkpoints a changefeed-level resolved timestamp
 // to the jobs record. It additionally manages the protected timestamp state
 // which is stored in the job progress details. It is only called if the new
@@ -861,12 +991,7 @@ func (cf *changeFrontier) maybeProtectTimestamp(
 }
 
 func (cf *changeFrontier) maybeEmitResolved(newResolved hlc.Timestamp) error {
-	if cf.freqEmitResolved == emitNoResolved {
-		return nil
-	}
-	sinceEmitted := newResolved.GoTime().Sub(cf.lastEmitResolved)
-	shouldEmit := sinceEmitted >= cf.freqEmitResolved || cf.schemaChangeBoundaryReached()
-	if !shouldEmit {
+	if !cf.shouldEmitResolved(newResolved) {
 		return nil
 	}
 	// Keeping this after the checkpointResolvedTimestamp call will avoid
@@ -878,6 +1003,16 @@ func (cf *changeFrontier) maybeEmitResolved(newResolved hlc.Timestamp) error {
 	return nil
 }
 
+// shouldEmitResolved returns whether the passed resolved timestamp should be
+// emitted, per the frequency configured with the resolved option.
+func (cf *changeFrontier) shouldEmitResolved(newResolved hlc.Timestamp) bool {
+	if cf.freqEmitResolved == emitNoResolved {
+		return false
+	}
+	sinceEmitted := newResolved.GoTime().Sub(cf.lastEmitResolved)
+	return sinceEmitted >= cf.freqEmitResolved || cf.schemaChangeBoundaryReached()
+}
+
 // Potentially log the most behind span in the frontier for debugging. The
 // returned boolean will be true if the resolved timestamp lags far behind the
 // present as defined by the current configuration.
@@ -11,6 +11,7 @@ package changefeedccl
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	"math/rand"
 	"net/url"
 	"sort"
@@ -137,10 +138,10 @@ func changefeedPlanHook(
 			statementTime = initialHighWater
 		}
 
-		// For now, disallow targeting a database or wildcard table selection.
-		// Getting it right as tables enter and leave the set over time is
-		// tricky.
-		if len(changefeedStmt.Targets.Databases) > 0 {
+		// For now, disallow targeting a database, a schema or wildcard table
+		// selection. Getting it right as tables enter and leave the set over
+		// time is tricky.
+		if len(changefeedStmt.Targets.Databases) > 0 || len(changefeedStmt.Targets.Schemas) > 0 {
 			return errors.Errorf(`CHANGEFEED cannot target %s`,
 				tree.AsString(&changefeedStmt.Targets))
 		}
@@ -160,17 +161,36 @@ func changefeedPlanHook(
 		if err != nil {
 			return err
 		}
+		if len(changefeedStmt.Columns) > 0 || changefeedStmt.Where != nil {
+			if len(changefeedStmt.Targets.Tables) != 1 {
+				return errors.Errorf(
+					`CHANGEFEED with a column list or WHERE clause must target exactly one table`)
+			}
+		}
 		targets := make(jobspb.ChangefeedTargets, len(targetDescs))
 		for _, desc := range targetDescs {
 			if tableDesc := desc.Table(hlc.Timestamp{}); tableDesc != nil {
-				targets[tableDesc.ID] = jobspb.ChangefeedTarget{
+				target := jobspb.ChangefeedTarget{
 					StatementTimeName: tableDesc.Name,
 				}
+				if target, err = projectChangefeedTarget(
+					&p.ExtendedEvalContext().EvalContext, target, tableDesc, changefeedStmt,
+				); err != nil {
+					return err
+				}
+				targets[tableDesc.ID] = target
 				if err := validateChangefeedTable(targets, tableDesc); err != nil {
 					return err
 				}
 			}
 		}
+		// Without the previous value of a row, an update that makes the row stop
+		// matching the WHERE clause can't be told apart from an update of a row
+		// that never matched it, so it couldn't be emitted as a deletion.
+		if _, ok := opts[changefeedbase.OptDiff]; changefeedStmt.Where != nil && !ok {
+			return errors.Errorf(`CHANGEFEED with a WHERE clause requires the %s option`,
+				changefeedbase.OptDiff)
+		}
 
 		details := jobspb.ChangefeedDetails{
 			Targets:       targets,
@@ -221,9 +241,31 @@ func changefeedPlanHook(
 		if _, err := getEncoder(details.Opts); err != nil {
 			return err
 		}
-		if isCloudStorageSink(parsedSink) {
+		if isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink) {
 			details.Opts[changefeedbase.OptKeyInValue] = ``
 		}
+		if changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) ==
+			changefeedbase.OptFormatParquet && !isCloudStorageSink(parsedSink) {
+			return errors.Errorf(`%s=%s is only supported by cloud storage sinks`,
+				changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+		}
+		if _, ok := details.Opts[changefeedbase.OptTransactions]; ok && isCloudStorageSink(parsedSink) {
+			// Cloud storage sinks name resolved timestamp files after the
+			// timestamp, so transaction end markers would overwrite each other.
+			return errors.Errorf(`%s is not supported by cloud storage sinks`,
+				changefeedbase.OptTransactions)
+		}
+		if _, ok := details.Opts[changefeedbase.OptExactlyOnce]; ok {
+			if !isCloudStorageSink(parsedSink) {
+				return errors.Errorf(`%s is only supported by cloud storage sinks`,
+					changefeedbase.OptExactlyOnce)
+			}
+			// Manifests are written at the frequency of resolved timestamps.
+			if _, ok := details.Opts[changefeedbase.OptResolvedTimestamps]; !ok {
+				return errors.Errorf(`%s requires the %s option`,
+					changefeedbase.OptExactlyOnce, changefeedbase.OptResolvedTimestamps)
+			}
+		}
 
 		// Feature telemetry
 		telemetrySink := parsedSink.Scheme
@@ -363,12 +405,16 @@ func changefeedPlanHook(
 func changefeedJobDescription(
 	p sql.PlanHookState, changefeed *tree.CreateChangefeed, sinkURI string, opts map[string]string,
 ) (string, error) {
-	cleanedSinkURI, err := cloud.SanitizeExternalStorageURI(sinkURI, []string{changefeedbase.SinkParamSASLPassword})
+	cleanedSinkURI, err := cloud.SanitizeExternalStorageURI(sinkURI, []string{
+		changefeedbase.SinkParamSASLPassword, changefeedbase.SinkParamCredentials,
+	})
 	if err != nil {
 		return "", err
 	}
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
+		Columns: changefeed.Columns,
+		Where:   changefeed.Where,
 		SinkURI: tree.NewDString(cleanedSinkURI),
 	}
 	for k, v := range opts {
@@ -455,16 +501,51 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		switch v := changefeedbase.FormatType(details.Opts[opt]); v {
 		case ``, changefeedbase.OptFormatJSON:
 			details.Opts[opt] = string(changefeedbase.OptFormatJSON)
-		case changefeedbase.OptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.OptFormatParquet:
 			// No-op.
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`unknown %s: %s`, opt, v)
 		}
 	}
+	for _, opt := range []string{
+		changefeedbase.OptAvroSubjectNameStrategy, changefeedbase.OptAvroSchemaCompatibility,
+	} {
+		_, ok := details.Opts[opt]
+		format := changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat])
+		if ok && format != changefeedbase.OptFormatAvro {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(`%s is only usable with %s=%s`,
+				opt, changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
+		}
+	}
 	return details, nil
 }
 
+// projectChangefeedTarget fills in the column list and filter of a target
+// from the statement, checking them against the table's descriptor.
+func projectChangefeedTarget(
+	evalCtx *tree.EvalContext,
+	target jobspb.ChangefeedTarget,
+	tableDesc *sqlbase.TableDescriptor,
+	changefeedStmt *tree.CreateChangefeed,
+) (jobspb.ChangefeedTarget, error) {
+	for _, name := range changefeedStmt.Columns {
+		col, err := tableDesc.FindActiveColumnByName(string(name))
+		if err != nil {
+			return jobspb.ChangefeedTarget{}, err
+		}
+		target.ColumnIDs = append(target.ColumnIDs, col.ID)
+	}
+	if changefeedStmt.Where != nil {
+		target.Filter = tree.Serialize(changefeedStmt.Where.Expr)
+		// Check the filter now rather than when the first row is emitted.
+		if _, err := makeChangefeedFilter(target.Filter, tableDesc, evalCtx); err != nil {
+			return jobspb.ChangefeedTarget{}, err
+		}
+	}
+	return target, nil
+}
+
 func validateChangefeedTable(
 	targets jobspb.ChangefeedTargets, tableDesc *sqlbase.TableDescriptor,
 ) error {
@@ -553,6 +634,18 @@ func (b *changefeedResumer) Resume(
 	details := b.job.Details().(jobspb.ChangefeedDetails)
 	progress := b.job.Progress()
 
+	if progress.RunningStatus != `` {
+		// Clear the status recorded when the changefeed was paused because of an
+		// incompatible schema, now that it has been resumed.
+		if err := b.job.RunningStatus(ctx, func(
+			context.Context, jobspb.Details,
+		) (jobs.RunningStatus, error) {
+			return ``, nil
+		}); err != nil {
+			log.Warningf(ctx, `CHANGEFEED job %d could not clear its status: %v`, jobID, err)
+		}
+	}
+
 	// We'd like to avoid failing a changefeed unnecessarily, so when an error
 	// bubbles up to this level, we'd like to "retry" the flow if possible. This
 	// could be because the sink is down or because a cockroach node has crashed
@@ -571,6 +664,9 @@ func (b *changefeedResumer) Resume(
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
+			if isIncompatibleSchemaError(err) {
+				return b.pauseOnIncompatibleSchema(ctx, execCfg, err)
+			}
 			log.Warningf(ctx, `CHANGEFEED job %d returning with error: %+v`, jobID, err)
 			return err
 		}
@@ -604,6 +700,33 @@ func (b *changefeedResumer) Resume(
 	return errors.Wrap(err, `ran out of retries`)
 }
 
+// pauseOnIncompatibleSchema pauses the changefeed, rather than failing it, when
+// it would have registered an Avro schema that is incompatible with the one its
+// consumers use. The error is recorded as the running status of the job so that
+// it shows up in SHOW JOBS, and the changefeed can be resumed once the schema
+// registry or the table has been fixed.
+func (b *changefeedResumer) pauseOnIncompatibleSchema(
+	ctx context.Context, execCfg *sql.ExecutorConfig, err error,
+) error {
+	jobID := *b.job.ID()
+	log.Warningf(ctx, `CHANGEFEED job %d pausing: %v`, jobID, err)
+	if statusErr := b.job.RunningStatus(ctx, func(
+		context.Context, jobspb.Details,
+	) (jobs.RunningStatus, error) {
+		return jobs.RunningStatus(fmt.Sprintf(`paused: %s`, err)), nil
+	}); statusErr != nil {
+		log.Warningf(ctx, `CHANGEFEED job %d could not record its status: %v`, jobID, statusErr)
+	}
+	if pauseErr := execCfg.JobRegistry.PauseRequested(ctx, nil /* txn */, jobID); pauseErr != nil {
+		log.Warningf(ctx, `CHANGEFEED job %d could not pause: %v`, jobID, pauseErr)
+		return err
+	}
+	// The registry cancels the context of the job once it notices the pause
+	// request. Returning an error before that would fail the job instead.
+	<-ctx.Done()
+	return ctx.Err()
+}
+
 // OnFailOrCancel is part of the jobs.Resumer interface.
 func (b *changefeedResumer) OnFailOrCancel(ctx context.Context, planHookState interface{}) error {
 	phs := planHookState.(sql.PlanHookState)
@@ -11,9 +11,13 @@ package changefeedccl
 import (
 	"context"
 	gosql "database/sql"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
@@ -153,6 +157,50 @@ func TestChangefeedDiff(t *testing.T) {
 	t.Run(`cloudstorage`, cloudStorageTest(testFn))
 }
 
+func TestChangefeedColumnsAndWhere(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT, d STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'skipped', 1, 'x'), (1, 'initial', 10, 'y')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo (b, c) WHERE c > 5 WITH diff`)
+		defer closeFeed(t, foo)
+
+		// The primary key is always emitted, since it's needed for the key.
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "initial", "c": 10}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'a', 3, 'z'), (3, 'b', 30, 'z')`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 20 WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "b", "c": 30}, "before": null}`,
+			`foo: [1]->{"after": {"a": 1, "b": "initial", "c": 20}, "before": {"a": 1, "b": "initial", "c": 10}}`,
+		})
+
+		// Deletions are always emitted, since the deleted row isn't known.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": null, "before": {"a": 2, "b": "a", "c": 3}}`,
+		})
+
+		// An update that moves a row out of the filter is emitted as a deletion,
+		// while an update of a row that didn't match before isn't emitted.
+		sqlDB.Exec(t, `UPDATE foo SET c = 2 WHERE a = 0`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 1 WHERE a = 3`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 40 WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": null, "before": {"a": 3, "b": "b", "c": 30}}`,
+			`foo: [0]->{"after": {"a": 0, "b": "skipped", "c": 40}, "before": {"a": 0, "b": "skipped", "c": 2}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedEnvelope(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1553,6 +1601,109 @@ func TestChangefeedRetryableError(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+// TestChangefeedExactlyOnceRestart verifies that the manifests of an
+// exactly_once changefeed publish every row exactly once, even though the
+// changefeed restarts after flushing data files that aren't published yet.
+func TestChangefeedExactlyOnceRestart(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer utilccl.TestingEnableEnterprise()()
+
+	ctx := context.Background()
+	dir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+
+	// failFlush makes the next sink flush of a changeAggregator fail with a
+	// retryable error, after the data files were written.
+	var failFlush int64
+	knobs := base.TestingKnobs{DistSQL: &execinfra.TestingKnobs{Changefeed: &TestingKnobs{
+		AfterSinkFlush: func() error {
+			if atomic.CompareAndSwapInt64(&failFlush, 1, 0) {
+				return MarkRetryableError(errors.New("synthetic retryable error"))
+			}
+			return nil
+		},
+	}}}
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		UseDatabase:   "d",
+		ExternalIODir: dir,
+		Knobs:         knobs,
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.experimental_poll_interval = '10ms'`)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 0)`)
+
+	var jobID int64
+	sqlDB.QueryRow(t, `CREATE CHANGEFEED FOR foo INTO 'experimental-nodelocal://0/feed' `+
+		`WITH exactly_once, resolved = '50ms'`).Scan(&jobID)
+	defer sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+
+	const numRestarts = 5
+	expected := []string{`{"after": {"a": 0, "b": 0}, "key": [0]}`}
+	for i := 1; i <= numRestarts; i++ {
+		sqlDB.Exec(t, `INSERT INTO foo VALUES ($1, 0)`, i)
+		sqlDB.Exec(t, `UPDATE foo SET b = $1 WHERE a = 0`, i)
+		expected = append(expected,
+			fmt.Sprintf(`{"after": {"a": %d, "b": 0}, "key": [%d]}`, i, i),
+			fmt.Sprintf(`{"after": {"a": 0, "b": %d}, "key": [0]}`, i),
+		)
+		atomic.StoreInt64(&failFlush, 1)
+		testutils.SucceedsSoon(t, func() error {
+			if atomic.LoadInt64(&failFlush) != 0 {
+				return errors.New("flush didn't fail yet")
+			}
+			return nil
+		})
+	}
+	retries := s.JobRegistry().(*jobs.Registry).MetricsStruct().Changefeed.(*Metrics).ErrorRetries
+	require.GreaterOrEqual(t, retries.Counter.Count(), int64(numRestarts))
+	sort.Strings(expected)
+
+	// readPublishedRows returns the rows of all the data files referenced by the
+	// published manifests.
+	feedDir := filepath.Join(dir, `feed`)
+	readPublishedRows := func() []string {
+		t.Helper()
+		var rows []string
+		require.NoError(t, filepath.Walk(feedDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !strings.HasSuffix(path, `.MANIFEST`) {
+				return err
+			}
+			manifestBytes, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var manifest cloudStorageManifest
+			if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+				return err
+			}
+			for _, file := range manifest.Files {
+				data, err := ioutil.ReadFile(filepath.Join(feedDir, file))
+				if err != nil {
+					return err
+				}
+				rows = append(rows, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+			}
+			return nil
+		}))
+		sort.Strings(rows)
+		return rows
+	}
+	testutils.SucceedsSoon(t, func() error {
+		if rows := readPublishedRows(); len(rows) < len(expected) {
+			return errors.Errorf("expected %d published rows, got %d", len(expected), len(rows))
+		}
+		return nil
+	})
+	// Every row is published exactly once, even though some of them were
+	// written to data files more than once.
+	require.Equal(t, expected, readPublishedRows())
+}
+
 // TestChangefeedDataTTL ensures that changefeeds fail with an error in the case
 // where the feed has fallen behind the GC TTL of the table data.
 func TestChangefeedDataTTL(t *testing.T) {
@@ -1811,6 +1962,27 @@ func TestChangefeedErrors(t *testing.T) {
 		changefeedbase.OptFormatAvro, `bar`,
 	)
 
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED with a column list or WHERE clause must target exactly one table`,
+		`CREATE CHANGEFEED FOR foo, rangefeed_off (a) INTO $1`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `column "nope" does not exist`,
+		`CREATE CHANGEFEED FOR foo (nope) INTO $1`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `invalid CHANGEFEED filter for table foo: argument of CHANGEFEED WHERE must be type bool, not type string`,
+		`CREATE CHANGEFEED FOR foo WHERE b INTO $1`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `invalid CHANGEFEED filter for table foo: subqueries are not allowed in CHANGEFEED`,
+		`CREATE CHANGEFEED FOR foo WHERE a IN (SELECT 1) INTO $1`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `CHANGEFEED with a WHERE clause requires the diff option`,
+		`CREATE CHANGEFEED FOR foo WHERE a > 1 INTO $1`, `kafka://nope`,
+	)
+
 	// Check that confluent_schema_registry is only accepted if format is avro.
 	sqlDB.ExpectErr(
 		t, `unknown sink query parameter: confluent_schema_registry`,
@@ -1911,6 +2083,24 @@ func TestChangefeedErrors(t *testing.T) {
 		`kafka://nope`,
 	)
 
+	// The schema registry options require the avro format, and are validated.
+	sqlDB.ExpectErr(
+		t, `avro_schema_compatibility is only usable with format=experimental_avro`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH avro_schema_compatibility='full'`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `unknown avro_subject_name_strategy: nope`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='experimental_avro', confluent_schema_registry=$2, `+
+			`avro_subject_name_strategy='nope'`,
+		`kafka://nope`, `schemareg-nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `unknown avro_schema_compatibility: nope`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='experimental_avro', confluent_schema_registry=$2, `+
+			`avro_schema_compatibility='nope'`,
+		`kafka://nope`, `schemareg-nope`,
+	)
+
 	// The cloudStorageSink is particular about the options it will work with.
 	sqlDB.ExpectErr(
 		t, `this sink is incompatible with format=experimental_avro`,
@@ -1943,6 +2133,28 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH diff, envelope='row'`, `kafka://nope`,
 	)
 
+	// WITH transactions requires envelope=wrapped and isn't supported by cloud
+	// storage sinks.
+	sqlDB.ExpectErr(
+		t, `transactions is only usable with envelope=wrapped`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH transactions, envelope='row'`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `transactions is not supported by cloud storage sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH transactions`, `experimental-nodelocal://0/bar`,
+	)
+
+	// WITH exactly_once is only supported by cloud storage sinks, and requires
+	// resolved timestamps.
+	sqlDB.ExpectErr(
+		t, `exactly_once is only supported by cloud storage sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH exactly_once, resolved`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `exactly_once requires the resolved option`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH exactly_once`, `experimental-nodelocal://0/bar`,
+	)
+
 	// WITH initial_scan and no_initial_scan disallowed
 	sqlDB.ExpectErr(
 		t, `cannot specify both initial_scan and no_initial_scan`,
@@ -2643,10 +2855,11 @@ func TestChangefeedMemBufferCapacity(t *testing.T) {
 			Changefeed.(*TestingKnobs)
 		// The RowContainer used internally by the memBuffer seems to request from
 		// the budget in 10240 chunks. Set this number high enough for one but not
-		// for a second. I'd love to be able to derive this from constants, but I
+		// for a second, keeping in mind that half of the budget is reserved for
+		// the sink. I'd love to be able to derive this from constants, but I
 		// don't see how to do that without a refactor.
-		knobs.MemBufferCapacity = 20000
-		beforeEmitRowCh := make(chan struct{}, 1)
+		knobs.MemBufferCapacity = 40000
+		beforeEmitRowCh := make(chan struct{})
 		knobs.BeforeEmitRow = func(ctx context.Context) error {
 			select {
 			case <-ctx.Done():
@@ -2655,7 +2868,6 @@ func TestChangefeedMemBufferCapacity(t *testing.T) {
 			}
 			return nil
 		}
-		defer close(beforeEmitRowCh)
 
 		sqlDB := sqlutils.MakeSQLRunner(db)
 		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
@@ -2670,12 +2882,17 @@ func TestChangefeedMemBufferCapacity(t *testing.T) {
 			`foo: [0]->{"after": {"a": 0, "b": "small"}}`,
 		})
 
-		// Put enough data in to overflow the buffer and verify that at some point
-		// we get the "memory budget exceeded" error.
-		sqlDB.Exec(t, `INSERT INTO foo SELECT i, 'foofoofoo' FROM generate_series(1, $1) AS g(i)`, 1000)
-		if _, err := foo.Next(); !testutils.IsError(err, `memory budget exceeded`) {
-			t.Fatalf(`expected "memory budget exceeded" error got: %v`, err)
+		// Put enough data in to overflow the buffer while the sink is blocked, and
+		// verify that the changefeed is pushed back on until the sink catches up,
+		// rather than running out of memory.
+		const numRows = 1000
+		sqlDB.Exec(t, `INSERT INTO foo SELECT i, 'foofoofoo' FROM generate_series(1, $1) AS g(i)`, numRows)
+		close(beforeEmitRowCh)
+		var expected []string
+		for i := 1; i <= numRows; i++ {
+			expected = append(expected, fmt.Sprintf(`foo: [%d]->{"after": {"a": %d, "b": "foofoofoo"}}`, i, i))
 		}
+		assertPayloads(t, foo, expected)
 	}
 
 	// The mem buffer is only used with RangeFeed.
@@ -24,6 +24,15 @@ type SchemaChangeEventClass string
 // change event which is a member of the changefeed's schema change events.
 type SchemaChangePolicy string
 
+// AvroSubjectNameStrategy defines the subjects under which the Avro schemas of
+// a changefeed are registered in the confluent schema registry.
+type AvroSubjectNameStrategy string
+
+// AvroSchemaCompatibility defines the compatibility level that the subjects of
+// the Avro schemas of a changefeed are given in the confluent schema registry,
+// which checks new schemas against it before they're registered.
+type AvroSchemaCompatibility string
+
 // Constants for the options.
 const (
 	OptConfluentSchemaRegistry  = `confluent_schema_registry`
@@ -38,6 +47,10 @@ const (
 	OptSchemaChangeEvents       = `schema_change_events`
 	OptSchemaChangePolicy       = `schema_change_policy`
 	OptProtectDataFromGCOnPause = `protect_data_from_gc_on_pause`
+	OptTransactions             = `transactions`
+	OptExactlyOnce              = `exactly_once`
+	OptAvroSubjectNameStrategy  = `avro_subject_name_strategy`
+	OptAvroSchemaCompatibility  = `avro_schema_compatibility`
 
 	// OptSchemaChangeEventClassColumnChange corresponds to all schema change
 	// events which add or remove any column.
@@ -58,6 +71,30 @@ const (
 	// the user could continue.
 	OptSchemaChangePolicyStop SchemaChangePolicy = `stop`
 
+	// OptAvroSubjectNameStrategyTopic registers the schemas of the keys and
+	// values of a topic under the `<topic>-key` and `<topic>-value` subjects.
+	OptAvroSubjectNameStrategyTopic AvroSubjectNameStrategy = `topic`
+	// OptAvroSubjectNameStrategyRecord registers each schema under the name of
+	// its record.
+	OptAvroSubjectNameStrategyRecord AvroSubjectNameStrategy = `record`
+	// OptAvroSubjectNameStrategyTopicRecord registers each schema under the
+	// `<topic>-<record>` subject.
+	OptAvroSubjectNameStrategyTopicRecord AvroSubjectNameStrategy = `topic_record`
+
+	// OptAvroSchemaCompatibilityNone disables the compatibility check, and
+	// leaves the compatibility level of the subjects as configured in the
+	// registry.
+	OptAvroSchemaCompatibilityNone AvroSchemaCompatibility = `none`
+	// OptAvroSchemaCompatibilityBackward checks that data written with the
+	// latest registered schema can be read with the new one.
+	OptAvroSchemaCompatibilityBackward AvroSchemaCompatibility = `backward`
+	// OptAvroSchemaCompatibilityForward checks that data written with the new
+	// schema can be read with the latest registered one.
+	OptAvroSchemaCompatibilityForward AvroSchemaCompatibility = `forward`
+	// OptAvroSchemaCompatibilityFull checks both the backward and forward
+	// compatibility.
+	OptAvroSchemaCompatibilityFull AvroSchemaCompatibility = `full`
+
 	// OptInitialScan enables an initial scan. This is the default when no
 	// cursor is specified, leading to an initial scan at the statement time of
 	// the creation of the changeffed. If used in conjunction with a cursor,
@@ -73,19 +110,23 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `experimental_avro`
+	OptFormatJSON    FormatType = `json`
+	OptFormatAvro    FormatType = `experimental_avro`
+	OptFormatParquet FormatType = `parquet`
 
 	SinkParamCACert           = `ca_cert`
 	SinkParamClientCert       = `client_cert`
 	SinkParamClientKey        = `client_key`
+	SinkParamCredentials      = `credentials`
 	SinkParamFileSize         = `file_size`
 	SinkParamSchemaTopic      = `schema_topic`
 	SinkParamTLSEnabled       = `tls_enabled`
 	SinkParamTopicPrefix      = `topic_prefix`
 	SinkSchemeBuffer          = ``
 	SinkSchemeExperimentalSQL = `experimental-sql`
+	SinkSchemeGCPubSub        = `gcpubsub`
 	SinkSchemeKafka           = `kafka`
+	SinkSchemeWebhookHTTPS    = `webhook-https`
 	SinkParamSASLEnabled      = `sasl_enabled`
 	SinkParamSASLHandshake    = `sasl_handshake`
 	SinkParamSASLUser         = `sasl_user`
@@ -109,4 +150,8 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptInitialScan:              sql.KVStringOptRequireNoValue,
 	OptNoInitialScan:            sql.KVStringOptRequireNoValue,
 	OptProtectDataFromGCOnPause: sql.KVStringOptRequireNoValue,
+	OptTransactions:             sql.KVStringOptRequireNoValue,
+	OptExactlyOnce:              sql.KVStringOptRequireNoValue,
+	OptAvroSubjectNameStrategy:  sql.KVStringOptRequireValue,
+	OptAvroSchemaCompatibility:  sql.KVStringOptRequireValue,
 }
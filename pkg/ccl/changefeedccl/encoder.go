@@ -13,9 +13,12 @@ import (
 	"context"
 	"encoding/binary"
 	gojson "encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -25,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
 
@@ -57,6 +61,9 @@ type encodeRow struct {
 	// prevTableDesc is a TableDescriptor for the table containing `prevDatums`.
 	// It's valid for interpreting the row at `updated.Prev()`.
 	prevTableDesc *sqlbase.TableDescriptor
+	// txnID is the ID of the transaction that wrote the row, if it's known. See
+	// kvfeed.Event.TxnID.
+	txnID uuid.UUID
 }
 
 // Encoder turns a row into a serialized changefeed key, value, or resolved
@@ -84,6 +91,8 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 		return makeJSONEncoder(opts)
 	case changefeedbase.OptFormatAvro:
 		return newConfluentAvroEncoder(opts)
+	case changefeedbase.OptFormatParquet:
+		return makeParquetEncoder(opts)
 	default:
 		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
@@ -94,7 +103,7 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
-	updatedField, beforeField, wrapped, keyOnly, keyInValue bool
+	updatedField, beforeField, wrapped, keyOnly, keyInValue, transactionField bool
 
 	alloc sqlbase.DatumAlloc
 	buf   bytes.Buffer
@@ -118,6 +127,11 @@ func makeJSONEncoder(opts map[string]string) (*jsonEncoder, error) {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptKeyInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
+	_, e.transactionField = opts[changefeedbase.OptTransactions]
+	if e.transactionField && !e.wrapped {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptTransactions, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
 	return e, nil
 }
 
@@ -220,6 +234,9 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 			}
 			jsonEntries[`key`] = keyEntries
 		}
+		if e.transactionField {
+			jsonEntries[`transaction`] = transactionJSON(row.txnID, row.updated)
+		}
 	} else {
 		jsonEntries = after
 	}
@@ -262,12 +279,82 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 	return gojson.Marshal(jsonEntries)
 }
 
+// parquetEncoder encodes changefeed values for the Parquet files written by
+// the cloud storage sink. Values are the value encoding of the row's columns
+// and changefeed metadata, which the sink decodes again to buffer them in a
+// columnar file (see parquet.go). Keys and resolved timestamp payloads are the
+// same as in the JSON format.
+type parquetEncoder struct {
+	json         *jsonEncoder
+	updatedField bool
+
+	alloc sqlbase.DatumAlloc
+	buf   []byte
+}
+
+var _ Encoder = &parquetEncoder{}
+
+func makeParquetEncoder(opts map[string]string) (*parquetEncoder, error) {
+	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+	case changefeedbase.OptEnvelopeWrapped:
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope],
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	if _, ok := opts[changefeedbase.OptDiff]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptDiff, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	if _, ok := opts[changefeedbase.OptTransactions]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTransactions, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	j, err := makeJSONEncoder(opts)
+	if err != nil {
+		return nil, err
+	}
+	e := &parquetEncoder{json: j}
+	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *parquetEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]byte, error) {
+	return e.json.EncodeKey(ctx, row)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *parquetEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
+	var err error
+	e.buf, err = encodeParquetRow(e.buf[:0], row, e.updatedField, &e.alloc)
+	return e.buf, err
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *parquetEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return e.json.EncodeResolvedTimestamp(ctx, topic, resolved)
+}
+
 // confluentAvroEncoder encodes changefeed entries as Avro's binary or textual
 // JSON format. Keys are the primary key columns in a record. Values are all
 // columns in a record.
+//
+// The schemas are registered in the confluent schema registry under the
+// subjects picked by the avro_subject_name_strategy option. If the
+// avro_schema_compatibility option is set, it's the compatibility level of
+// these subjects, and the registry first checks each schema against the latest
+// one registered under its subject, so that a schema change doesn't break the
+// consumers of the changefeed.
 type confluentAvroEncoder struct {
 	registryURL                        string
 	updatedField, beforeField, keyOnly bool
+	subjectNameStrategy                changefeedbase.AvroSubjectNameStrategy
+	compatibility                      changefeedbase.AvroSchemaCompatibility
+	// configuredSubjects are the subjects whose compatibility level was set.
+	configuredSubjects map[string]struct{}
 
 	keyCache      map[tableIDAndVersion]confluentRegisteredKeySchema
 	valueCache    map[tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
@@ -319,15 +406,38 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 		return nil, errors.Errorf(`%s is not supported with %s=%s`,
 			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
 	}
+	if _, ok := opts[changefeedbase.OptTransactions]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTransactions, changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
+	}
 
 	if len(e.registryURL) == 0 {
 		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
 			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
 	}
 
+	switch v := changefeedbase.AvroSubjectNameStrategy(opts[changefeedbase.OptAvroSubjectNameStrategy]); v {
+	case ``, changefeedbase.OptAvroSubjectNameStrategyTopic:
+		e.subjectNameStrategy = changefeedbase.OptAvroSubjectNameStrategyTopic
+	case changefeedbase.OptAvroSubjectNameStrategyRecord, changefeedbase.OptAvroSubjectNameStrategyTopicRecord:
+		e.subjectNameStrategy = v
+	default:
+		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptAvroSubjectNameStrategy, v)
+	}
+	switch v := changefeedbase.AvroSchemaCompatibility(opts[changefeedbase.OptAvroSchemaCompatibility]); v {
+	case ``, changefeedbase.OptAvroSchemaCompatibilityNone:
+		e.compatibility = changefeedbase.OptAvroSchemaCompatibilityNone
+	case changefeedbase.OptAvroSchemaCompatibilityBackward, changefeedbase.OptAvroSchemaCompatibilityForward,
+		changefeedbase.OptAvroSchemaCompatibilityFull:
+		e.compatibility = v
+	default:
+		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptAvroSchemaCompatibility, v)
+	}
+
 	e.keyCache = make(map[tableIDAndVersion]confluentRegisteredKeySchema)
 	e.valueCache = make(map[tableIDAndVersionPair]confluentRegisteredEnvelopeSchema)
 	e.resolvedCache = make(map[string]confluentRegisteredEnvelopeSchema)
+	e.configuredSubjects = make(map[string]struct{})
 	return e, nil
 }
 
@@ -342,9 +452,7 @@ func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(row.tableDesc.Name) + confluentSubjectSuffixKey
+		subject := e.subject(row.tableDesc.Name, &registered.schema.avroRecord, confluentSubjectSuffixKey)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -395,9 +503,7 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(row.tableDesc.Name) + confluentSubjectSuffixValue
+		subject := e.subject(row.tableDesc.Name, &registered.schema.avroRecord, confluentSubjectSuffixValue)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -440,9 +546,7 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		subject := e.subject(topic, &registered.schema.avroRecord, confluentSubjectSuffixValue)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -465,6 +569,122 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 	return registered.schema.BinaryFromRow(header, meta, nil /* beforeRow */, nil /* afterRow */)
 }
 
+// subject returns the subject under which the schema of the keys or values,
+// depending on suffix, of the topic is registered.
+func (e *confluentAvroEncoder) subject(topic string, schema *avroRecord, suffix string) string {
+	switch e.subjectNameStrategy {
+	case changefeedbase.OptAvroSubjectNameStrategyRecord:
+		return schema.Name
+	case changefeedbase.OptAvroSubjectNameStrategyTopicRecord:
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		return SQLNameToKafkaName(topic) + `-` + schema.Name
+	default:
+		return SQLNameToKafkaName(topic) + suffix
+	}
+}
+
+// checkCompatibility returns an incompatibleSchemaError if the schema registry
+// finds that the schema doesn't have the compatibility requested by the
+// avro_schema_compatibility option with the latest schema registered under the
+// subject, if any. The requested compatibility is first made the compatibility
+// level of the subject, so that the registry also enforces it on registration.
+func (e *confluentAvroEncoder) checkCompatibility(
+	ctx context.Context, schemaStr string, subject string,
+) error {
+	if e.compatibility == changefeedbase.OptAvroSchemaCompatibilityNone {
+		return nil
+	}
+	if _, ok := e.configuredSubjects[subject]; !ok {
+		if err := e.setCompatibilityLevel(ctx, subject); err != nil {
+			return err
+		}
+		e.configuredSubjects[subject] = struct{}{}
+	}
+
+	type confluentCompatibilityRequest struct {
+		Schema string `json:"schema"`
+	}
+	type confluentCompatibilityResponse struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}
+
+	url, err := url.Parse(e.registryURL)
+	if err != nil {
+		return err
+	}
+	url.Path = filepath.Join(url.EscapedPath(), `compatibility`, `subjects`, subject, `versions`, `latest`)
+	// Registries that support it explain why the schema isn't compatible.
+	url.RawQuery = `verbose=true`
+
+	var buf bytes.Buffer
+	if err := gojson.NewEncoder(&buf).Encode(confluentCompatibilityRequest{Schema: schemaStr}); err != nil {
+		return err
+	}
+	resp, err := httputil.Post(ctx, url.String(), confluentSchemaContentType, &buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		// Nothing was registered under the subject yet.
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf(`checking schema compatibility with %s %s: %s`, url.String(), resp.Status, body)
+	}
+	var res confluentCompatibilityResponse
+	if err := gojson.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return err
+	}
+	if !res.IsCompatible {
+		msg := fmt.Sprintf(`schema is not %s compatible with the latest version of subject %s`,
+			e.compatibility, subject)
+		if len(res.Messages) > 0 {
+			msg += `: ` + strings.Join(res.Messages, `; `)
+		}
+		return markIncompatibleSchemaError(errors.New(msg))
+	}
+	return nil
+}
+
+// setCompatibilityLevel sets the compatibility level of the subject in the
+// schema registry to the one requested by the avro_schema_compatibility option.
+func (e *confluentAvroEncoder) setCompatibilityLevel(ctx context.Context, subject string) error {
+	type confluentConfigRequest struct {
+		Compatibility string `json:"compatibility"`
+	}
+
+	url, err := url.Parse(e.registryURL)
+	if err != nil {
+		return err
+	}
+	url.Path = filepath.Join(url.EscapedPath(), `config`, subject)
+
+	var buf bytes.Buffer
+	req := confluentConfigRequest{Compatibility: strings.ToUpper(string(e.compatibility))}
+	if err := gojson.NewEncoder(&buf).Encode(req); err != nil {
+		return err
+	}
+	httpReq, err := httputil.NewRequestWithContext(ctx, http.MethodPut, url.String(), &buf)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set(`Content-Type`, confluentSchemaContentType)
+	resp, err := httputil.DefaultClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf(`setting compatibility level at %s %s: %s`, url.String(), resp.Status, body)
+	}
+	return nil
+}
+
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
 ) (int32, error) {
@@ -482,6 +702,9 @@ func (e *confluentAvroEncoder) register(
 	url.Path = filepath.Join(url.EscapedPath(), `subjects`, subject, `versions`)
 
 	schemaStr := schema.codec.Schema()
+	if err := e.checkCompatibility(ctx, schemaStr, subject); err != nil {
+		return 0, err
+	}
 	if log.V(1) {
 		log.Infof(context.TODO(), "registering avro schema %s %s", url, schemaStr)
 	}
@@ -499,6 +722,12 @@ func (e *confluentAvroEncoder) register(
 		return 0, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict && e.compatibility != changefeedbase.OptAvroSchemaCompatibilityNone {
+		// The schema was found incompatible at the level set for the subject.
+		body, _ := ioutil.ReadAll(resp.Body)
+		return 0, markIncompatibleSchemaError(errors.Errorf(
+			`registering schema to %s %s: %s`, url.String(), resp.Status, body))
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := ioutil.ReadAll(resp.Body)
 		return 0, errors.Errorf(`registering schema to %s %s: %s`, url.String(), resp.Status, body)
@@ -16,11 +16,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach-go/crdb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -261,12 +265,23 @@ type testSchemaRegistry struct {
 		syncutil.Mutex
 		idAlloc int32
 		schemas map[int32]string
+		// subjects contains the IDs of the versions registered under each
+		// subject, in order.
+		subjects map[string][]int32
+		// levels contains the compatibility levels set for subjects.
+		levels map[string]string
+		// incompatible returns why a schema isn't compatible at a level with the
+		// latest one registered under a subject, if it isn't. All schemas are
+		// compatible if it's nil.
+		incompatible func(level, schema, latest string) []string
 	}
 }
 
 func makeTestSchemaRegistry() *testSchemaRegistry {
 	r := &testSchemaRegistry{}
 	r.mu.schemas = make(map[int32]string)
+	r.mu.subjects = make(map[string][]int32)
+	r.mu.levels = make(map[string]string)
 	r.server = httptest.NewServer(http.HandlerFunc(r.Register))
 	return r
 }
@@ -275,6 +290,33 @@ func (r *testSchemaRegistry) Close() {
 	r.server.Close()
 }
 
+// Subjects returns the subjects that schemas were registered under.
+func (r *testSchemaRegistry) Subjects() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var subjects []string
+	for subject := range r.mu.subjects {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// CompatibilityLevel returns the compatibility level set for the subject.
+func (r *testSchemaRegistry) CompatibilityLevel(subject string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mu.levels[subject]
+}
+
+// SetIncompatible sets the function which decides whether schemas are
+// compatible with the latest ones registered under their subjects.
+func (r *testSchemaRegistry) SetIncompatible(fn func(level, schema, latest string) []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.incompatible = fn
+}
+
 func (r *testSchemaRegistry) Register(hw http.ResponseWriter, hr *http.Request) {
 	type confluentSchemaVersionRequest struct {
 		Schema string `json:"schema"`
@@ -282,6 +324,17 @@ func (r *testSchemaRegistry) Register(hw http.ResponseWriter, hr *http.Request)
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
 	}
+	switch {
+	case strings.HasPrefix(hr.URL.Path, `/config/`):
+		r.config(hw, hr, strings.TrimPrefix(hr.URL.Path, `/config/`))
+		return
+	case strings.HasPrefix(hr.URL.Path, `/compatibility/subjects/`):
+		subject := strings.TrimPrefix(hr.URL.Path, `/compatibility/subjects/`)
+		r.compatibility(hw, hr, strings.TrimSuffix(subject, `/versions/latest`))
+		return
+	}
+	subject := strings.TrimPrefix(hr.URL.Path, `/subjects/`)
+	subject = strings.TrimSuffix(subject, `/versions`)
 	if err := func() error {
 		defer hr.Body.Close()
 		var req confluentSchemaVersionRequest
@@ -293,6 +346,7 @@ func (r *testSchemaRegistry) Register(hw http.ResponseWriter, hr *http.Request)
 		id := r.mu.idAlloc
 		r.mu.idAlloc++
 		r.mu.schemas[id] = req.Schema
+		r.mu.subjects[subject] = append(r.mu.subjects[subject], id)
 		r.mu.Unlock()
 
 		res, err := gojson.Marshal(confluentSchemaVersionResponse{ID: id})
@@ -308,6 +362,60 @@ func (r *testSchemaRegistry) Register(hw http.ResponseWriter, hr *http.Request)
 	}
 }
 
+// config sets the compatibility level of the subject.
+func (r *testSchemaRegistry) config(hw http.ResponseWriter, hr *http.Request, subject string) {
+	type confluentConfigRequest struct {
+		Compatibility string `json:"compatibility"`
+	}
+	defer hr.Body.Close()
+	var req confluentConfigRequest
+	if err := gojson.NewDecoder(hr.Body).Decode(&req); err != nil {
+		http.Error(hw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	r.mu.Lock()
+	r.mu.levels[subject] = req.Compatibility
+	r.mu.Unlock()
+	hw.Header().Set(`Content-type`, `application/json`)
+	_ = gojson.NewEncoder(hw).Encode(req)
+}
+
+// compatibility checks the compatibility of the schema with the latest version
+// registered under the subject.
+func (r *testSchemaRegistry) compatibility(
+	hw http.ResponseWriter, hr *http.Request, subject string,
+) {
+	type confluentCompatibilityRequest struct {
+		Schema string `json:"schema"`
+	}
+	type confluentCompatibilityResponse struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages,omitempty"`
+	}
+	defer hr.Body.Close()
+	var req confluentCompatibilityRequest
+	if err := gojson.NewDecoder(hr.Body).Decode(&req); err != nil {
+		http.Error(hw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	r.mu.Lock()
+	versions := r.mu.subjects[subject]
+	var messages []string
+	if len(versions) > 0 && r.mu.incompatible != nil {
+		latest := r.mu.schemas[versions[len(versions)-1]]
+		messages = r.mu.incompatible(r.mu.levels[subject], req.Schema, latest)
+	}
+	r.mu.Unlock()
+	if len(versions) == 0 {
+		http.Error(hw, `subject not found`, http.StatusNotFound)
+		return
+	}
+	hw.Header().Set(`Content-type`, `application/json`)
+	_ = gojson.NewEncoder(hw).Encode(confluentCompatibilityResponse{
+		IsCompatible: len(messages) == 0, Messages: messages,
+	})
+}
+
 func (r *testSchemaRegistry) encodedAvroToNative(b []byte) (interface{}, error) {
 	if len(b) == 0 || b[0] != confluentAvroWireFormatMagic {
 		return ``, errors.Errorf(`bad magic byte`)
@@ -415,6 +523,68 @@ func TestAvroMigrateToUnsupportedColumn(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestAvroIncompatibleSchemaPause(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(i time.Duration) { jobs.DefaultAdoptInterval = i }(jobs.DefaultAdoptInterval)
+	jobs.DefaultAdoptInterval = 10 * time.Millisecond
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		reg := makeTestSchemaRegistry()
+		defer reg.Close()
+
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b DECIMAL(6,2))`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 1.5)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo `+
+			`WITH format=$1, confluent_schema_registry=$2, `+
+			`avro_subject_name_strategy=topic_record, avro_schema_compatibility=full`,
+			changefeedbase.OptFormatAvro, reg.server.URL).(*cdctest.TableFeed)
+		defer closeFeed(t, foo)
+		assertPayloadsAvro(t, reg, foo, []string{
+			`foo: {"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"bytes.decimal":"3/2"}}}}`,
+		})
+		require.Equal(t, []string{`foo-foo`, `foo-foo_envelope`}, reg.Subjects())
+		require.Equal(t, `FULL`, reg.CompatibilityLevel(`foo-foo`))
+		require.Equal(t, `FULL`, reg.CompatibilityLevel(`foo-foo_envelope`))
+
+		// Adding a column is compatible, since it's nullable.
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN c STRING`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 2.5, 'c')`)
+		assertPayloadsAvro(t, reg, foo, []string{
+			`foo: {"a":{"long":2}}->{"after":{"foo":{"a":{"long":2},"b":{"bytes.decimal":"5/2"},"c":{"string":"c"}}}}`,
+		})
+
+		// Changing the precision of a decimal isn't, so the changefeed pauses
+		// rather than registering the new schema.
+		reg.SetIncompatible(func(level, schema, latest string) []string {
+			if schema == latest {
+				return nil
+			}
+			return []string{`foo_envelope.after.b: decimal(6,2) can't be read as decimal(8,2)`}
+		})
+		sqlDB.Exec(t, `ALTER TABLE foo ALTER COLUMN b TYPE DECIMAL(8,2)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 3.5, 'c')`)
+		testutils.SucceedsSoon(t, func() error {
+			var status, runningStatus string
+			sqlDB.QueryRow(t,
+				`SELECT status, running_status FROM crdb_internal.jobs WHERE job_id = $1`, foo.JobID,
+			).Scan(&status, &runningStatus)
+			if jobs.Status(status) != jobs.StatusPaused {
+				return errors.Errorf(`expected job to be paused got %s`, status)
+			}
+			if !strings.Contains(runningStatus, `with the latest version of subject foo-foo_envelope: foo_envelope.after.b: decimal(6,2) can't be read as decimal(8,2)`) {
+				return errors.Errorf(`unexpected running status: %s`, runningStatus)
+			}
+			return nil
+		})
+	}
+
+	// Only the enterprise version uses jobs, and can be paused.
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestAvroLedger(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -10,6 +10,7 @@ package changefeedccl
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -77,3 +78,110 @@ func MaybeStripRetryableErrorMarker(err error) error {
 	}
 	return err
 }
+
+const incompatibleSchemaErrorString = "incompatible avro schema"
+
+// incompatibleSchemaError is returned when the Avro schema of a changefeed
+// doesn't have the compatibility requested with the avro_schema_compatibility
+// option with the schema registered in the schema registry.
+type incompatibleSchemaError struct {
+	wrapped error
+}
+
+// markIncompatibleSchemaError wraps the given error, marking it as the
+// detection of an incompatible schema.
+func markIncompatibleSchemaError(e error) error {
+	return &incompatibleSchemaError{wrapped: e}
+}
+
+// Error implements the error interface.
+func (e *incompatibleSchemaError) Error() string {
+	return fmt.Sprintf("%s: %s", incompatibleSchemaErrorString, e.wrapped.Error())
+}
+
+// Cause implements the github.com/pkg/errors.causer interface.
+func (e *incompatibleSchemaError) Cause() error { return e.wrapped }
+
+// Unwrap implements the github.com/golang/xerrors.Wrapper interface.
+func (e *incompatibleSchemaError) Unwrap() error { return e.wrapped }
+
+// isIncompatibleSchemaError returns true if the supplied error, or any of its
+// parent causes, is an incompatibleSchemaError.
+func isIncompatibleSchemaError(err error) bool {
+	for {
+		if err == nil {
+			return false
+		}
+		if _, ok := err.(*incompatibleSchemaError); ok {
+			return true
+		}
+		if strings.Contains(err.Error(), incompatibleSchemaErrorString) {
+			// See the comment in IsRetryableError.
+			return true
+		}
+		if e, ok := err.(interface{ Unwrap() error }); ok {
+			err = e.Unwrap()
+			continue
+		}
+		return false
+	}
+}
+
+const terminalSinkErrorString = "terminal sink error"
+
+// terminalSinkError is returned by sinks for errors which retrying can't fix,
+// such as a request rejected by the sink as invalid or unauthorized. Unlike
+// the other sink errors, it fails the changefeed.
+type terminalSinkError struct {
+	wrapped error
+}
+
+// markTerminalSinkError wraps the given error, marking it as a sink error
+// which is not to be retried.
+func markTerminalSinkError(e error) error {
+	return &terminalSinkError{wrapped: e}
+}
+
+// Error implements the error interface.
+func (e *terminalSinkError) Error() string {
+	return fmt.Sprintf("%s: %s", terminalSinkErrorString, e.wrapped.Error())
+}
+
+// Cause implements the github.com/pkg/errors.causer interface.
+func (e *terminalSinkError) Cause() error { return e.wrapped }
+
+// Unwrap implements the github.com/golang/xerrors.Wrapper interface.
+func (e *terminalSinkError) Unwrap() error { return e.wrapped }
+
+// isTerminalSinkError returns true if the supplied error, or any of its parent
+// causes, is a terminalSinkError.
+func isTerminalSinkError(err error) bool {
+	for {
+		if err == nil {
+			return false
+		}
+		if _, ok := err.(*terminalSinkError); ok {
+			return true
+		}
+		if strings.Contains(err.Error(), terminalSinkErrorString) {
+			// See the comment in IsRetryableError.
+			return true
+		}
+		if e, ok := err.(interface{ Unwrap() error }); ok {
+			err = e.Unwrap()
+			continue
+		}
+		return false
+	}
+}
+
+// isTerminalHTTPStatus returns whether a request rejected by an HTTP sink
+// with the given status would be rejected again if it were retried. This is
+// the case of client errors, such as an invalid payload or failed
+// authentication, except for request timeouts and rate limiting.
+func isTerminalHTTPStatus(status int) bool {
+	if status < http.StatusBadRequest || status >= http.StatusInternalServerError {
+		return false
+	}
+	return status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
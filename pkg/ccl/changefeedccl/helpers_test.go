@@ -13,6 +13,7 @@ import (
 	gosql "database/sql"
 	gojson "encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"sort"
@@ -30,8 +31,61 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 )
 
+// requestRecorder records the messages received by the fake HTTP endpoints
+// that the HTTP-based sinks are tested against, and fails a configurable
+// number of requests first so that the sinks' retries can be tested. Fakes
+// embed it and call maybeFail and record with the recorder locked.
+type requestRecorder struct {
+	syncutil.Mutex
+	records []string
+	// failures is the number of requests to reject before accepting any.
+	failures int
+	// failureStatus is the status of the rejected requests, which defaults to
+	// http.StatusServiceUnavailable.
+	failureStatus int
+}
+
+// setFailures makes the recorder reject the next n requests with status, or
+// with http.StatusServiceUnavailable if status is zero.
+func (r *requestRecorder) setFailures(n int, status int) {
+	r.Lock()
+	defer r.Unlock()
+	r.failures = n
+	r.failureStatus = status
+}
+
+// maybeFail rejects the request if requests are to be failed, and returns
+// whether it did.
+func (r *requestRecorder) maybeFail(w http.ResponseWriter) bool {
+	if r.failures == 0 {
+		return false
+	}
+	r.failures--
+	status := r.failureStatus
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+	http.Error(w, http.StatusText(status), status)
+	return true
+}
+
+// record records a received message.
+func (r *requestRecorder) record(msg string) {
+	r.records = append(r.records, msg)
+}
+
+// pop returns the messages received since the last call.
+func (r *requestRecorder) pop() []string {
+	r.Lock()
+	defer r.Unlock()
+	records := r.records
+	r.records = nil
+	return records
+}
+
 func waitForSchemaChange(
 	t testing.TB, sqlDB *sqlutils.SQLRunner, stmt string, arguments ...interface{},
 ) {
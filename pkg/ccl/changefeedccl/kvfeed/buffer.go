@@ -18,12 +18,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // EventBuffer is an interface for communicating kvfeed entries between processors.
@@ -40,7 +40,7 @@ type EventBufferReader interface {
 
 // EventBufferWriter is the write portion of the EventBuffer interface.
 type EventBufferWriter interface {
-	AddKV(ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, backfillTimestamp hlc.Timestamp) error
+	AddKV(ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, txnID uuid.UUID, backfillTimestamp hlc.Timestamp) error
 	AddResolved(ctx context.Context, span roachpb.Span, ts hlc.Timestamp, boundaryReached bool) error
 	Close(ctx context.Context)
 }
@@ -52,8 +52,8 @@ type EventBufferWriter interface {
 type EventType int
 
 const (
-	// KVEvent indicates that the KV, PrevValue, and BackfillTimestamp methods
-	// on the Event meaningful.
+	// KVEvent indicates that the KV, PrevValue, TxnID, and BackfillTimestamp
+	// methods on the Event meaningful.
 	KVEvent EventType = iota
 
 	// ResolvedEvent indicates that the Resolved method on the Event will be
@@ -66,6 +66,7 @@ const (
 type Event struct {
 	kv                 roachpb.KeyValue
 	prevVal            roachpb.Value
+	txnID              uuid.UUID
 	resolved           *jobspb.ResolvedSpan
 	backfillTimestamp  hlc.Timestamp
 	bufferGetTimestamp time.Time
@@ -95,6 +96,13 @@ func (b *Event) PrevValue() roachpb.Value {
 	return b.prevVal
 }
 
+// TxnID returns the ID of the transaction that wrote the KV, if this is a KV
+// event and the ID is known. It is only known for changes observed by the
+// rangefeed as they were committed, not for changes read by a scan.
+func (b *Event) TxnID() uuid.UUID {
+	return b.txnID
+}
+
 // Resolved will be non-nil if this is a resolved timestamp event (i.e. IsKV()
 // returns false).
 func (b *Event) Resolved() *jobspb.ResolvedSpan {
@@ -153,11 +161,16 @@ func MakeChanBuffer() EventBuffer {
 // AddKV inserts a changed KV into the buffer. Individual keys must be added in
 // increasing mvcc order.
 func (b *chanBuffer) AddKV(
-	ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, backfillTimestamp hlc.Timestamp,
+	ctx context.Context,
+	kv roachpb.KeyValue,
+	prevVal roachpb.Value,
+	txnID uuid.UUID,
+	backfillTimestamp hlc.Timestamp,
 ) error {
 	return b.addEvent(ctx, Event{
 		kv:                kv,
 		prevVal:           prevVal,
+		txnID:             txnID,
 		backfillTimestamp: backfillTimestamp,
 	})
 }
@@ -194,14 +207,6 @@ func (b *chanBuffer) Get(ctx context.Context) (Event, error) {
 	}
 }
 
-// MemBufferDefaultCapacity is the default capacity for a memBuffer for a single
-// changefeed.
-//
-// TODO(dan): It would be better if all changefeeds shared a single capacity
-// that was given by the operater at startup, like we do for RocksDB and SQL.
-var MemBufferDefaultCapacity = envutil.EnvOrDefaultBytes(
-	"COCKROACH_CHANGEFEED_BUFFER_CAPACITY", 1<<30) // 1GB
-
 var memBufferColTypes = []*types.T{
 	types.Bytes, // KV.Key
 	types.Bytes, // KV.Value
@@ -210,11 +215,14 @@ var memBufferColTypes = []*types.T{
 	types.Bytes, // span.EndKey
 	types.Int,   // ts.WallTime
 	types.Int,   // ts.Logical
+	types.Bytes, // txnID
 }
 
 // memBuffer is an in-memory buffer for changed KV and Resolved timestamp
 // events. It's size is limited only by the BoundAccount passed to the
-// constructor. memBuffer is only for use with single-producer single-consumer.
+// constructor: when the budget of the account is exhausted, writes to the
+// buffer block until entries are read from it. memBuffer is only for use with
+// single-producer single-consumer.
 type memBuffer struct {
 	metrics *Metrics
 
@@ -225,6 +233,9 @@ type memBuffer struct {
 	// signalCh can be selected on to learn when an entry is written to
 	// mu.entries.
 	signalCh chan struct{}
+	// consumedCh can be selected on to learn when an entry is read from
+	// mu.entries.
+	consumedCh chan struct{}
 
 	allocMu struct {
 		syncutil.Mutex
@@ -234,8 +245,9 @@ type memBuffer struct {
 
 func makeMemBuffer(acc mon.BoundAccount, metrics *Metrics) *memBuffer {
 	b := &memBuffer{
-		metrics:  metrics,
-		signalCh: make(chan struct{}, 1),
+		metrics:    metrics,
+		signalCh:   make(chan struct{}, 1),
+		consumedCh: make(chan struct{}, 1),
 	}
 	b.mu.entries.Init(acc, sqlbase.ColTypeInfoFromColTypes(memBufferColTypes), 0 /* rowCapacity */)
 	return b
@@ -250,13 +262,21 @@ func (b *memBuffer) Close(ctx context.Context) {
 // AddKV inserts a changed KV into the buffer. Individual keys must be added in
 // increasing mvcc order.
 func (b *memBuffer) AddKV(
-	ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, backfillTimestamp hlc.Timestamp,
+	ctx context.Context,
+	kv roachpb.KeyValue,
+	prevVal roachpb.Value,
+	txnID uuid.UUID,
+	backfillTimestamp hlc.Timestamp,
 ) error {
 	b.allocMu.Lock()
 	prevValDatum := tree.DNull
 	if prevVal.IsPresent() {
 		prevValDatum = b.allocMu.a.NewDBytes(tree.DBytes(prevVal.RawBytes))
 	}
+	txnIDDatum := tree.DNull
+	if txnID != uuid.Nil {
+		txnIDDatum = b.allocMu.a.NewDBytes(tree.DBytes(txnID.GetBytes()))
+	}
 	row := tree.Datums{
 		b.allocMu.a.NewDBytes(tree.DBytes(kv.Key)),
 		b.allocMu.a.NewDBytes(tree.DBytes(kv.Value.RawBytes)),
@@ -265,6 +285,7 @@ func (b *memBuffer) AddKV(
 		tree.DNull,
 		b.allocMu.a.NewDInt(tree.DInt(kv.Value.Timestamp.WallTime)),
 		b.allocMu.a.NewDInt(tree.DInt(kv.Value.Timestamp.Logical)),
+		txnIDDatum,
 	}
 	b.allocMu.Unlock()
 	return b.addRow(ctx, row)
@@ -283,6 +304,7 @@ func (b *memBuffer) AddResolved(
 		b.allocMu.a.NewDBytes(tree.DBytes(span.EndKey)),
 		b.allocMu.a.NewDInt(tree.DInt(ts.WallTime)),
 		b.allocMu.a.NewDInt(tree.DInt(ts.Logical)),
+		tree.DNull,
 	}
 	b.allocMu.Unlock()
 	return b.addRow(ctx, row)
@@ -305,6 +327,9 @@ func (b *memBuffer) Get(ctx context.Context) (Event, error) {
 			RawBytes: []byte(*row[2].(*tree.DBytes)),
 		}
 	}
+	if row[7] != tree.DNull {
+		e.txnID = uuid.FromBytesOrNil([]byte(*row[7].(*tree.DBytes)))
+	}
 	if row[0] != tree.DNull {
 		e.kv = roachpb.KeyValue{
 			Key: []byte(*row[0].(*tree.DBytes)),
@@ -326,16 +351,40 @@ func (b *memBuffer) Get(ctx context.Context) (Event, error) {
 }
 
 func (b *memBuffer) addRow(ctx context.Context, row tree.Datums) error {
-	b.mu.Lock()
-	_, err := b.mu.entries.AddRow(ctx, row)
-	b.mu.Unlock()
+	for {
+		b.mu.Lock()
+		_, err := b.mu.entries.AddRow(ctx, row)
+		empty := b.mu.entries.Len() == 0
+		if err != nil && empty {
+			// The memory of the entries which were read is released by chunks,
+			// so release it all before giving up.
+			b.mu.entries.Clear(ctx)
+			_, err = b.mu.entries.AddRow(ctx, row)
+		}
+		b.mu.Unlock()
+		if err == nil {
+			break
+		}
+		if empty || !sqlbase.IsOutOfMemoryError(err) {
+			return err
+		}
+		// The buffer is full: push back on the producer until the consumer
+		// catches up, rather than failing the changefeed.
+		start := timeutil.Now()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.consumedCh:
+		}
+		b.metrics.BufferPushbackNanos.Inc(timeutil.Since(start).Nanoseconds())
+	}
 	b.metrics.BufferEntriesIn.Inc(1)
 	select {
 	case b.signalCh <- struct{}{}:
 	default:
 		// Already signaled, don't need to signal again.
 	}
-	return err
+	return nil
 }
 
 func (b *memBuffer) getRow(ctx context.Context) (tree.Datums, error) {
@@ -349,6 +398,11 @@ func (b *memBuffer) getRow(ctx context.Context) (tree.Datums, error) {
 		b.mu.Unlock()
 		if row != nil {
 			b.metrics.BufferEntriesOut.Inc(1)
+			select {
+			case b.consumedCh <- struct{}{}:
+			default:
+				// Already signaled, don't need to signal again.
+			}
 			return row, nil
 		}
 
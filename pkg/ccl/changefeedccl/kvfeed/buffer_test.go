@@ -0,0 +1,82 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package kvfeed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemBufferPushback(t *testing.T) {
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	mm := mon.MakeMonitorWithLimit(
+		"test", mon.MemoryResource, 1<<15 /* limit */, nil /* curCount */, nil, /* maxHist */
+		1 /* increment */, math.MaxInt64 /* noteworthy */, st,
+	)
+	mm.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer mm.Stop(ctx)
+	metrics := MakeMetrics(time.Minute)
+	buf := makeMemBuffer(mm.MakeBoundAccount(), &metrics)
+	defer buf.Close(ctx)
+
+	kv := func(i int) roachpb.KeyValue {
+		return roachpb.KeyValue{
+			Key: roachpb.Key(fmt.Sprintf("key-%05d", i)),
+			Value: roachpb.Value{
+				RawBytes:  []byte(fmt.Sprintf("value-%05d", i)),
+				Timestamp: hlc.Timestamp{WallTime: int64(i + 1)},
+			},
+		}
+	}
+
+	// Fill the buffer. Once its budget is exhausted, adding to it blocks until
+	// the context is canceled, rather than failing.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	var n int
+	for ; ; n++ {
+		require.Less(t, n, 10000, "buffer never filled up")
+		err := buf.AddKV(canceledCtx, kv(n), roachpb.Value{}, uuid.Nil, hlc.Timestamp{})
+		if err != nil {
+			require.Equal(t, context.Canceled, err)
+			break
+		}
+	}
+	require.Greater(t, n, 0)
+
+	// Reading from the full buffer unblocks its writer.
+	total := 3 * n
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		for i := n; i < total; i++ {
+			if err := buf.AddKV(ctx, kv(i), roachpb.Value{}, uuid.Nil, hlc.Timestamp{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	for i := 0; i < total; i++ {
+		e, err := buf.Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, kv(i), e.KV())
+	}
+	require.NoError(t, g.Wait())
+}
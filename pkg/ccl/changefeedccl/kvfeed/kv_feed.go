@@ -357,7 +357,7 @@ func copyFromSourceToSinkUntilTableEvent(
 		addEntry = func(e Event) error {
 			switch e.Type() {
 			case KVEvent:
-				return sink.AddKV(ctx, e.KV(), e.PrevValue(), e.BackfillTimestamp())
+				return sink.AddKV(ctx, e.KV(), e.PrevValue(), e.TxnID(), e.BackfillTimestamp())
 			case ResolvedEvent:
 				// TODO(ajwerner): technically this doesn't need to happen for most
 				// events - we just need to make sure we forward for events which are
@@ -91,7 +91,7 @@ func (p *rangefeed) addEventsToBuffer(ctx context.Context) error {
 				if p.cfg.WithDiff {
 					prevVal = t.PrevValue
 				}
-				if err := p.memBuf.AddKV(ctx, kv, prevVal, backfillTimestamp); err != nil {
+				if err := p.memBuf.AddKV(ctx, kv, prevVal, t.TxnID, backfillTimestamp); err != nil {
 					return err
 				}
 			case *roachpb.RangeFeedCheckpoint:
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -219,7 +220,7 @@ func slurpScanResponse(
 				// change. This is handled in kvsToRows.
 				prevVal = kv.Value
 			}
-			if err = sink.AddKV(ctx, kv, prevVal, ts); err != nil {
+			if err = sink.AddKV(ctx, kv, prevVal, uuid.Nil, ts); err != nil {
 				return errors.Wrapf(err, `buffering changes for %s`, span)
 			}
 		}
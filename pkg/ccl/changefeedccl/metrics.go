@@ -40,9 +40,11 @@ func (s *metricsSink) EmitRow(
 	start := timeutil.Now()
 	err := s.wrapped.EmitRow(ctx, table, key, value, updated)
 	if err == nil {
+		emitNanos := timeutil.Since(start).Nanoseconds()
 		s.metrics.EmittedMessages.Inc(1)
 		s.metrics.EmittedBytes.Inc(int64(len(key) + len(value)))
-		s.metrics.EmitNanos.Inc(timeutil.Since(start).Nanoseconds())
+		s.metrics.EmitNanos.Inc(emitNanos)
+		s.metrics.EmitHistNanos.RecordValue(emitNanos)
 	}
 	return err
 }
@@ -66,8 +68,10 @@ func (s *metricsSink) Flush(ctx context.Context) error {
 	start := timeutil.Now()
 	err := s.wrapped.Flush(ctx)
 	if err == nil {
+		flushNanos := timeutil.Since(start).Nanoseconds()
 		s.metrics.Flushes.Inc(1)
-		s.metrics.FlushNanos.Inc(timeutil.Since(start).Nanoseconds())
+		s.metrics.FlushNanos.Inc(flushNanos)
+		s.metrics.FlushHistNanos.RecordValue(flushNanos)
 	}
 	return err
 }
@@ -76,6 +80,10 @@ func (s *metricsSink) Close() error {
 	return s.wrapped.Close()
 }
 
+// sinkQueueDepthHistMax is the maximum value tracked by the sink queue depth
+// histogram.
+const sinkQueueDepthHistMax = 1 << 20
+
 var (
 	metaChangefeedEmittedMessages = metric.Metadata{
 		Name:        "changefeed.emitted_messages",
@@ -126,6 +134,30 @@ var (
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaChangefeedEmitHistNanos = metric.Metadata{
+		Name:        "changefeed.emit_hist_nanos",
+		Help:        "Time blocked emitting a message to a sink, including flushes forced by the memory budget of the feed",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaChangefeedFlushHistNanos = metric.Metadata{
+		Name:        "changefeed.flush_hist_nanos",
+		Help:        "Time blocked flushing a sink",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaChangefeedSinkQueueDepth = metric.Metadata{
+		Name:        "changefeed.sink_queue_depth",
+		Help:        "Messages emitted to a sink and not yet flushed, sampled on every emit",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaChangefeedSinkBufferedBytes = metric.Metadata{
+		Name:        "changefeed.sink_buffered_bytes",
+		Help:        "Bytes of the messages emitted to all sinks and not yet flushed",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
 
 	// TODO(dan): This was intended to be a measure of the minimum distance of
 	// any changefeed ahead of its gc ttl threshold, but keeping that correct in
@@ -152,6 +184,11 @@ type Metrics struct {
 	EmitNanos          *metric.Counter
 	FlushNanos         *metric.Counter
 
+	EmitHistNanos     *metric.Histogram
+	FlushHistNanos    *metric.Histogram
+	SinkQueueDepth    *metric.Histogram
+	SinkBufferedBytes *metric.Gauge
+
 	mu struct {
 		syncutil.Mutex
 		id       int
@@ -176,6 +213,12 @@ func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 		TableMetadataNanos: metric.NewCounter(metaChangefeedTableMetadataNanos),
 		EmitNanos:          metric.NewCounter(metaChangefeedEmitNanos),
 		FlushNanos:         metric.NewCounter(metaChangefeedFlushNanos),
+
+		EmitHistNanos:  metric.NewLatency(metaChangefeedEmitHistNanos, histogramWindow),
+		FlushHistNanos: metric.NewLatency(metaChangefeedFlushHistNanos, histogramWindow),
+		SinkQueueDepth: metric.NewHistogram(
+			metaChangefeedSinkQueueDepth, histogramWindow, sinkQueueDepthHistMax, 1 /* sigFigs */),
+		SinkBufferedBytes: metric.NewGauge(metaChangefeedSinkBufferedBytes),
 	}
 	m.mu.resolved = make(map[int]hlc.Timestamp)
 
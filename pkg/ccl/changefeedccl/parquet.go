@@ -0,0 +1,132 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/errors"
+)
+
+// Parquet is a columnar file format, so unlike the other formats a single row
+// can't be turned into a self-contained message. Instead, the parquetEncoder
+// value-encodes every column of the row (see encodeParquetRow) and the cloud
+// storage sink, which is the only sink that supports the format, decodes it
+// again and buffers the datums in a parquet.Writer, writing out one Parquet
+// file per table version.
+//
+// Every file has one column per table column, with the same name, followed by
+// the changefeed metadata columns:
+//
+//   - __crdb__deleted is true for deletions. Only the primary key columns of a
+//     deletion are set, all others are NULL.
+//   - __crdb__updated is the row's update timestamp, with the same string
+//     format as the `updated` field of the JSON format. It is only present if
+//     the `updated` option is set.
+
+const (
+	parquetColumnDeleted = `__crdb__deleted`
+	parquetColumnUpdated = `__crdb__updated`
+)
+
+// parquetSchemaForTable returns the schema of the Parquet files written for
+// rows of the given table version.
+func parquetSchemaForTable(
+	tableDesc *sqlbase.TableDescriptor, withUpdated bool,
+) (*parquet.Schema, error) {
+	names := make([]string, 0, len(tableDesc.Columns)+2)
+	typs := make([]*types.T, 0, len(tableDesc.Columns)+2)
+	for i := range tableDesc.Columns {
+		col := &tableDesc.Columns[i]
+		names = append(names, col.Name)
+		typs = append(typs, col.Type)
+	}
+	names = append(names, parquetColumnDeleted)
+	typs = append(typs, types.Bool)
+	if withUpdated {
+		names = append(names, parquetColumnUpdated)
+		typs = append(typs, types.String)
+	}
+	return parquet.NewSchema(names, typs)
+}
+
+// encodeParquetRow appends the value encoding of every column of the row,
+// followed by the deleted flag and, if requested, the updated timestamp.
+func encodeParquetRow(
+	appendTo []byte, row encodeRow, withUpdated bool, alloc *sqlbase.DatumAlloc,
+) ([]byte, error) {
+	columns := row.tableDesc.Columns
+	if len(row.datums) != len(columns) {
+		return nil, errors.AssertionFailedf(`expected %d datums got %d`, len(columns), len(row.datums))
+	}
+	var err error
+	for i := range columns {
+		col := &columns[i]
+		if row.deleted && !row.tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
+			appendTo = encoding.EncodeNullValue(appendTo, encoding.NoColumnID)
+			continue
+		}
+		if appendTo, err = row.datums[i].Encode(
+			col.Type, alloc, sqlbase.DatumEncoding_VALUE, appendTo,
+		); err != nil {
+			return nil, err
+		}
+	}
+	appendTo = encoding.EncodeBoolValue(appendTo, encoding.NoColumnID, row.deleted)
+	if withUpdated {
+		updated := tree.TimestampToDecimal(row.updated).Decimal.String()
+		appendTo = encoding.EncodeBytesValue(appendTo, encoding.NoColumnID, []byte(updated))
+	}
+	return appendTo, nil
+}
+
+// decodeParquetRow decodes a row encoded by encodeParquetRow into datums,
+// which is reused if it has enough capacity.
+func decodeParquetRow(
+	tableDesc *sqlbase.TableDescriptor,
+	withUpdated bool,
+	value []byte,
+	alloc *sqlbase.DatumAlloc,
+	datums tree.Datums,
+) (tree.Datums, error) {
+	datums = datums[:0]
+	decode := func(typ *types.T) error {
+		if len(value) == 0 {
+			return errors.AssertionFailedf(`truncated parquet row for table %s`, tableDesc.Name)
+		}
+		var d tree.Datum
+		var err error
+		if d, value, err = sqlbase.DecodeTableValue(alloc, typ, value); err != nil {
+			return err
+		}
+		datums = append(datums, d)
+		return nil
+	}
+	for i := range tableDesc.Columns {
+		if err := decode(tableDesc.Columns[i].Type); err != nil {
+			return nil, err
+		}
+	}
+	if err := decode(types.Bool); err != nil {
+		return nil, err
+	}
+	if withUpdated {
+		if err := decode(types.String); err != nil {
+			return nil, err
+		}
+	}
+	if len(value) != 0 {
+		return nil, errors.AssertionFailedf(`%d trailing bytes in parquet row for table %s`,
+			len(value), tableDesc.Name)
+	}
+	return datums, nil
+}
@@ -0,0 +1,275 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// rowProjector applies the column list and WHERE clause of a changefeed's
+// targets to the rows decoded by kvsToRows, so that only the matching rows and
+// requested columns reach the encoder and sink.
+//
+// Deletions are always emitted, since only the primary key of a deleted row is
+// known. An update that makes a row stop matching the filter is emitted as a
+// deletion of the row if its previous value matched the filter, which is why a
+// WHERE clause requires the diff option.
+type rowProjector struct {
+	evalCtx *tree.EvalContext
+	targets jobspb.ChangefeedTargets
+	// tables caches the projection of the table descriptor versions seen
+	// recently. Like the rowFetcherCache, it relies on descriptors being reused
+	// for all rows of a table version, and is cleared once it holds
+	// maxCachedTableVersions entries.
+	tables map[*sqlbase.TableDescriptor]*projectedTable
+
+	alloc sqlbase.DatumAlloc
+}
+
+// projectedTable is the projection of one table descriptor version.
+type projectedTable struct {
+	// desc is the descriptor rows are emitted with. It only has the projected
+	// columns, or is the original descriptor if there's no column list.
+	desc *sqlbase.TableDescriptor
+	// colIdxs maps every column of desc to its index in the decoded row. It's
+	// nil if there's no column list.
+	colIdxs []int
+	// filter is nil if there's no WHERE clause.
+	filter tree.TypedExpr
+	ivars  filterContainer
+}
+
+// makeRowProjector returns a rowProjector for the targets of a changefeed, or
+// nil if none of them have a column list or WHERE clause.
+func makeRowProjector(evalCtx *tree.EvalContext, targets jobspb.ChangefeedTargets) *rowProjector {
+	for _, t := range targets {
+		if len(t.ColumnIDs) > 0 || t.Filter != `` {
+			return &rowProjector{
+				evalCtx: evalCtx,
+				targets: targets,
+				tables:  make(map[*sqlbase.TableDescriptor]*projectedTable),
+			}
+		}
+	}
+	return nil
+}
+
+// wrap returns a closure that calls inputFn and applies the projection to the
+// rows it returns.
+func (p *rowProjector) wrap(
+	inputFn func(context.Context) ([]emitEntry, error),
+) func(context.Context) ([]emitEntry, error) {
+	return func(ctx context.Context) ([]emitEntry, error) {
+		entries, err := inputFn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Filter in place; inputFn reuses the slice on every call anyway.
+		output := entries[:0]
+		for _, e := range entries {
+			if e.row.datums != nil {
+				var keep bool
+				if e.row, keep, err = p.project(e.row); err != nil {
+					return nil, err
+				}
+				if !keep {
+					continue
+				}
+			}
+			output = append(output, e)
+		}
+		return output, nil
+	}
+}
+
+// project applies the projection of the row's table to it. It returns false if
+// neither the row nor its previous value, if known, match the table's filter.
+// A row that doesn't match the filter but whose previous value does is turned
+// into a deletion.
+func (p *rowProjector) project(row encodeRow) (encodeRow, bool, error) {
+	pt, err := p.projectedTableFor(row.tableDesc)
+	if err != nil {
+		return encodeRow{}, false, err
+	}
+	if pt.filter != nil && !row.deleted {
+		match, err := p.evalFilter(pt, row.tableDesc, row.datums)
+		if err != nil {
+			return encodeRow{}, false, err
+		}
+		if !match {
+			if row.prevDatums == nil || row.prevDeleted {
+				return encodeRow{}, false, nil
+			}
+			prev, err := p.projectedTableFor(row.prevTableDesc)
+			if err != nil {
+				return encodeRow{}, false, err
+			}
+			if prev.filter != nil {
+				prevMatch, err := p.evalFilter(prev, row.prevTableDesc, row.prevDatums)
+				if err != nil {
+					return encodeRow{}, false, err
+				}
+				if !prevMatch {
+					return encodeRow{}, false, nil
+				}
+			}
+			// The row moved out of the filter, which is a deletion as far as
+			// the consumers of the changefeed are concerned.
+			row.deleted = true
+		}
+	}
+	row.datums = projectDatums(pt, row.datums)
+	row.tableDesc = pt.desc
+	if row.prevDatums != nil {
+		prev, err := p.projectedTableFor(row.prevTableDesc)
+		if err != nil {
+			return encodeRow{}, false, err
+		}
+		row.prevDatums = projectDatums(prev, row.prevDatums)
+		row.prevTableDesc = prev.desc
+	}
+	return row, true, nil
+}
+
+func (p *rowProjector) projectedTableFor(
+	tableDesc *sqlbase.TableDescriptor,
+) (*projectedTable, error) {
+	if pt, ok := p.tables[tableDesc]; ok {
+		return pt, nil
+	}
+	if len(p.tables) >= maxCachedTableVersions {
+		p.tables = make(map[*sqlbase.TableDescriptor]*projectedTable)
+	}
+	target := p.targets[tableDesc.ID]
+	pt := &projectedTable{desc: tableDesc}
+	if len(target.ColumnIDs) > 0 {
+		pt.desc, pt.colIdxs = projectTableDesc(tableDesc, target.ColumnIDs)
+	}
+	if target.Filter != `` {
+		var err error
+		if pt.filter, err = makeChangefeedFilter(target.Filter, tableDesc, p.evalCtx); err != nil {
+			return nil, err
+		}
+		pt.ivars.cols = tableDesc.Columns
+		pt.ivars.row = make(tree.Datums, len(tableDesc.Columns))
+	}
+	p.tables[tableDesc] = pt
+	return pt, nil
+}
+
+func (p *rowProjector) evalFilter(
+	pt *projectedTable, tableDesc *sqlbase.TableDescriptor, datums sqlbase.EncDatumRow,
+) (bool, error) {
+	for i := range datums {
+		if err := datums[i].EnsureDecoded(tableDesc.Columns[i].Type, &p.alloc); err != nil {
+			return false, err
+		}
+		pt.ivars.row[i] = datums[i].Datum
+	}
+	p.evalCtx.PushIVarContainer(&pt.ivars)
+	defer p.evalCtx.PopIVarContainer()
+	return sqlbase.RunFilter(pt.filter, p.evalCtx)
+}
+
+func projectDatums(pt *projectedTable, datums sqlbase.EncDatumRow) sqlbase.EncDatumRow {
+	if pt.colIdxs == nil {
+		return datums
+	}
+	projected := make(sqlbase.EncDatumRow, len(pt.colIdxs))
+	for i, idx := range pt.colIdxs {
+		projected[i] = datums[idx]
+	}
+	return projected
+}
+
+// projectTableDesc returns a copy of tableDesc that only has the given columns
+// and the primary key columns, which are needed to encode the key of every
+// row, in the order of tableDesc's columns. It also returns the index in
+// tableDesc's columns of each of the returned descriptor's columns. Columns
+// that no longer exist in tableDesc are skipped.
+func projectTableDesc(
+	tableDesc *sqlbase.TableDescriptor, columnIDs []sqlbase.ColumnID,
+) (*sqlbase.TableDescriptor, []int) {
+	projected := *tableDesc
+	projected.Columns = nil
+	var colIdxs []int
+	for i := range tableDesc.Columns {
+		col := &tableDesc.Columns[i]
+		keep := tableDesc.PrimaryIndex.ContainsColumnID(col.ID)
+		for _, id := range columnIDs {
+			keep = keep || id == col.ID
+		}
+		if keep {
+			projected.Columns = append(projected.Columns, *col)
+			colIdxs = append(colIdxs, i)
+		}
+	}
+	return &projected, colIdxs
+}
+
+// makeChangefeedFilter parses and type checks the filter of a changefeed
+// target against the columns of the given table descriptor version.
+func makeChangefeedFilter(
+	filter string, tableDesc *sqlbase.TableDescriptor, evalCtx *tree.EvalContext,
+) (tree.TypedExpr, error) {
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return nil, err
+	}
+	iv := &filterContainer{cols: tableDesc.Columns}
+	ivarHelper := tree.MakeIndexedVarHelper(iv, len(tableDesc.Columns))
+	tn := tree.MakeUnqualifiedTableName(tree.Name(tableDesc.Name))
+	source := sqlbase.NewSourceInfoForSingleTable(tn, sqlbase.ResultColumnsFromColDescs(tableDesc.Columns))
+	if expr, _, err = sqlbase.ResolveNames(
+		expr, source, ivarHelper, evalCtx.SessionData.SearchPath,
+	); err != nil {
+		return nil, errors.Wrapf(err, `invalid CHANGEFEED filter for table %s`, tableDesc.Name)
+	}
+
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = iv
+	semaCtx.Properties.Require(`CHANGEFEED`, tree.RejectSpecial|tree.RejectSubqueries)
+	typedExpr, err := tree.TypeCheckAndRequire(expr, &semaCtx, types.Bool, `CHANGEFEED WHERE`)
+	if err != nil {
+		return nil, errors.Wrapf(err, `invalid CHANGEFEED filter for table %s`, tableDesc.Name)
+	}
+	return typedExpr, nil
+}
+
+// filterContainer is a tree.IndexedVarContainer for evaluating changefeed
+// filters, whose IndexedVars are the columns of a table.
+type filterContainer struct {
+	cols []sqlbase.ColumnDescriptor
+	row  tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &filterContainer{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *filterContainer) IndexedVarEval(idx int, _ *tree.EvalContext) (tree.Datum, error) {
+	return c.row[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *filterContainer) IndexedVarResolvedType(idx int) *types.T {
+	return c.cols[idx].Type
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *filterContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(c.cols[idx].Name)
+	return &n
+}
@@ -21,6 +21,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
+// maxCachedTableVersions is the number of table descriptor versions the
+// rowFetcherCache and rowProjector keep state for before starting over. A
+// changefeed normally only sees one or two versions of each of its tables at a
+// time, so the caches are only cleared when many schema changes are being
+// processed.
+const maxCachedTableVersions = 64
+
 // rowFetcherCache maintains a cache of single table RowFetchers. Given a key
 // with an mvcc timestamp, it retrieves the correct TableDescriptor for that key
 // and returns a Fetcher initialized with that table. This Fetcher's
@@ -108,6 +115,8 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 		c.codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&c.a,
@@ -123,9 +132,11 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 	); err != nil {
 		return nil, err
 	}
-	// TODO(dan): Bound the size of the cache. Resolved notifications will let
-	// us evict anything for timestamps entirely before the notification. Then
-	// probably an LRU just in case?
+	// Resolved notifications would let us evict anything for timestamps
+	// entirely before the notification, but starting over is good enough.
+	if len(c.fetchers) >= maxCachedTableVersions {
+		c.fetchers = make(map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher)
+	}
 	c.fetchers[tableDesc] = &rf
 	return &rf, nil
 }
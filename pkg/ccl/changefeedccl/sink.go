@@ -18,6 +18,7 @@ import (
 	"hash"
 	"hash/fnv"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -39,6 +40,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/logtags"
@@ -195,6 +198,71 @@ func getSink(
 				opts, timestampOracle, makeExternalStorageFromURI,
 			)
 		}
+	case isWebhookSink(u):
+		cfg := webhookSinkConfig{
+			retryOpts: retry.Options{
+				InitialBackoff: 500 * time.Millisecond,
+				MaxBackoff:     30 * time.Second,
+				MaxRetries:     webhookSinkMaxRetries,
+			},
+		}
+		if caCertHex := q.Get(changefeedbase.SinkParamCACert); caCertHex != `` {
+			if cfg.caCert, err = base64.StdEncoding.DecodeString(caCertHex); err != nil {
+				return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, changefeedbase.SinkParamCACert, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamCACert)
+		if clientCertHex := q.Get(changefeedbase.SinkParamClientCert); clientCertHex != `` {
+			if cfg.clientCert, err = base64.StdEncoding.DecodeString(clientCertHex); err != nil {
+				return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, changefeedbase.SinkParamClientCert, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamClientCert)
+		if clientKeyHex := q.Get(changefeedbase.SinkParamClientKey); clientKeyHex != `` {
+			if cfg.clientKey, err = base64.StdEncoding.DecodeString(clientKeyHex); err != nil {
+				return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, changefeedbase.SinkParamClientKey, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamClientKey)
+		// Everything else is passed through to the endpoint untouched.
+		u.RawQuery = q.Encode()
+		q = url.Values{}
+		topics := make(map[string]struct{}, len(targets))
+		for _, t := range targets {
+			topics[t.StatementTimeName] = struct{}{}
+		}
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(cfg, u, opts, topics)
+		}
+	case isPubsubSink(u):
+		cfg := pubsubSinkConfig{
+			project:     u.Host,
+			topicPrefix: q.Get(changefeedbase.SinkParamTopicPrefix),
+			endpoint:    pubsubEndpoint,
+			retryOpts: retry.Options{
+				InitialBackoff: 500 * time.Millisecond,
+				MaxBackoff:     30 * time.Second,
+				MaxRetries:     pubsubSinkMaxRetries,
+			},
+		}
+		q.Del(changefeedbase.SinkParamTopicPrefix)
+		if cfg.project == `` {
+			return nil, errors.Errorf(`%s sink requires a project: %s://<project>`,
+				changefeedbase.SinkSchemeGCPubSub, changefeedbase.SinkSchemeGCPubSub)
+		}
+		if credentials := q.Get(changefeedbase.SinkParamCredentials); credentials != `` {
+			if cfg.credentials, err = decodePubsubCredentials(credentials); err != nil {
+				return nil, err
+			}
+		}
+		q.Del(changefeedbase.SinkParamCredentials)
+		if emulatorHost := os.Getenv(pubsubEmulatorHostEnv); emulatorHost != `` {
+			cfg.endpoint = `http://` + emulatorHost
+			cfg.emulator = true
+		}
+		makeSink = func() (Sink, error) {
+			return makePubsubSink(ctx, cfg, opts, targets)
+		}
 	case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 		// Swap the changefeed prefix for the sql connection one that sqlSink
 		// expects.
@@ -228,16 +296,25 @@ func getSink(
 // errorWrapperSink delegates to another sink and marks all returned errors as
 // retryable. During changefeed setup, we use the sink once without this to
 // verify configuration, but in the steady state, no sink error should be
-// terminal.
+// terminal, except for the ones the sink itself marks as terminal.
 type errorWrapperSink struct {
 	wrapped Sink
 }
 
+// markSinkErrorRetryable marks err as retryable, unless the sink marked it as
+// terminal.
+func markSinkErrorRetryable(err error) error {
+	if isTerminalSinkError(err) {
+		return err
+	}
+	return MarkRetryableError(err)
+}
+
 func (s errorWrapperSink) EmitRow(
 	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, updated hlc.Timestamp,
 ) error {
 	if err := s.wrapped.EmitRow(ctx, table, key, value, updated); err != nil {
-		return MarkRetryableError(err)
+		return markSinkErrorRetryable(err)
 	}
 	return nil
 }
@@ -246,25 +323,91 @@ func (s errorWrapperSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
 ) error {
 	if err := s.wrapped.EmitResolvedTimestamp(ctx, encoder, resolved); err != nil {
-		return MarkRetryableError(err)
+		return markSinkErrorRetryable(err)
 	}
 	return nil
 }
 
 func (s errorWrapperSink) Flush(ctx context.Context) error {
 	if err := s.wrapped.Flush(ctx); err != nil {
-		return MarkRetryableError(err)
+		return markSinkErrorRetryable(err)
 	}
 	return nil
 }
 
 func (s errorWrapperSink) Close() error {
 	if err := s.wrapped.Close(); err != nil {
-		return MarkRetryableError(err)
+		return markSinkErrorRetryable(err)
+	}
+	return nil
+}
+
+// budgetedSink delegates to another sink and accounts for the memory of the
+// messages emitted to it until they are flushed, against the memory budget of
+// the changefeed. When the budget is exhausted, the wrapped sink is flushed,
+// which pushes back on the changefeed until the sink catches up, instead of
+// letting the messages of a slow sink pile up.
+type budgetedSink struct {
+	wrapped Sink
+	metrics *Metrics
+	acc     mon.BoundAccount
+	// pending is the number of messages emitted since the last flush.
+	pending int64
+}
+
+func makeBudgetedSink(acc mon.BoundAccount, metrics *Metrics, s Sink) *budgetedSink {
+	return &budgetedSink{wrapped: s, metrics: metrics, acc: acc}
+}
+
+func (s *budgetedSink) EmitRow(
+	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, updated hlc.Timestamp,
+) error {
+	size := int64(len(key) + len(value))
+	if err := s.acc.Grow(ctx, size); err != nil {
+		if !sqlbase.IsOutOfMemoryError(err) || s.pending == 0 {
+			return err
+		}
+		if err := s.Flush(ctx); err != nil {
+			return err
+		}
+		if err := s.acc.Grow(ctx, size); err != nil {
+			return err
+		}
 	}
+	s.metrics.SinkBufferedBytes.Inc(size)
+	s.pending++
+	s.metrics.SinkQueueDepth.RecordValue(s.pending)
+	return s.wrapped.EmitRow(ctx, table, key, value, updated)
+}
+
+func (s *budgetedSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	return s.wrapped.EmitResolvedTimestamp(ctx, encoder, resolved)
+}
+
+func (s *budgetedSink) Flush(ctx context.Context) error {
+	if err := s.wrapped.Flush(ctx); err != nil {
+		return err
+	}
+	s.release(ctx)
 	return nil
 }
 
+func (s *budgetedSink) Close() error {
+	// The context is only used for logging when releasing memory.
+	ctx := context.TODO()
+	s.release(ctx)
+	s.acc.Close(ctx)
+	return s.wrapped.Close()
+}
+
+func (s *budgetedSink) release(ctx context.Context) {
+	s.metrics.SinkBufferedBytes.Dec(s.acc.Used())
+	s.acc.Clear(ctx)
+	s.pending = 0
+}
+
 type kafkaLogAdapter struct {
 	ctx context.Context
 }
@@ -12,20 +12,26 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	gojson "encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
 	"github.com/cockroachdb/errors"
 	"github.com/google/btree"
 )
@@ -33,7 +39,7 @@ import (
 func isCloudStorageSink(u *url.URL) bool {
 	switch u.Scheme {
 	case `experimental-s3`, `experimental-gs`, `experimental-nodelocal`, `experimental-http`,
-		`experimental-https`, `experimental-azure`:
+		`experimental-https`, `experimental-azure`, `experimental-sftp`:
 		return true
 	default:
 		return false
@@ -55,6 +61,9 @@ type cloudStorageSinkFile struct {
 	codec   io.WriteCloser
 	rawSize int
 	buf     bytes.Buffer
+	// parquet buffers the rows of the file when format=parquet, in which case
+	// buf only holds the row groups that have been written out so far.
+	parquet *parquet.Writer
 }
 
 var _ io.Writer = &cloudStorageSinkFile{}
@@ -160,9 +169,9 @@ func (f *cloudStorageSinkFile) Write(p []byte) (int, error) {
 // by a given `<sink_id>` and <session_id> is a unique identifying string for the job
 // session running the `changeAggregator` that owns this sink.
 //
-// `<ext>` implies the format of the file: currently the only option is
-// `ndjson`, which means a text file conforming to the "Newline Delimited JSON"
-// spec.
+// `<ext>` implies the format of the file: `ndjson`, which means a text file
+// conforming to the "Newline Delimited JSON" spec, or `parquet`, which means an
+// Apache Parquet file (see parquet.go for its columns).
 //
 // This naming convention of data files is carefully chosen in order to preserve
 // the external ordering guarantees of CDC. Naming output files in this fashion
@@ -184,6 +193,33 @@ func (f *cloudStorageSinkFile) Write(p []byte) (int, error) {
 // deleted, included in hive queries, etc). A typical user of cloudStorageSink
 // would periodically do exactly this.
 //
+// With the `exactly_once` option, the sink instead offers that every row is
+// published exactly once. Cloud storage has no atomic rename, but every file is
+// written in full by a single request, so a data file written by the sink is
+// only published once it's referenced by a manifest, and must be ignored by
+// external users until then. No RESOLVED files are written. Instead, manifests
+// are written for the multiples of the resolved timestamp frequency (see
+// manifestInterval), which all changeAggregators and the changeFrontier agree
+// on. The sink buffers the rows between two consecutive manifest timestamps in
+// their own data files, so that no data file holds rows on both sides of a
+// manifest timestamp, and the data files of a manifest timestamp are only
+// flushed once the local frontier has reached it (unless they get too large).
+// Whenever the changeFrontier's resolved timestamp passes a manifest timestamp
+// T, it writes a manifest named `<T>.MANIFEST` listing exactly the data files
+// for manifest timestamps at or below T flushed by the changeAggregators of its
+// flow since the previous manifest, which contain every row at or below T that
+// isn't referenced by an earlier manifest, and no row above T. The manifest is
+// first written under the temporary name `<T>.MANIFEST.tmp`, then T is
+// checkpointed in the job, and only then is the manifest published. If the
+// changefeed restarts in between, the new job session publishes the temporary
+// manifest of the checkpointed timestamp before emitting anything. The next job
+// session replays the rows above the checkpoint, none of which are referenced
+// by a published manifest, into new data files, and the data files of the
+// previous job session that aren't referenced by a manifest are never
+// published. Within a job session, the sink drops the rows it has already
+// emitted at the same timestamp, which the underlying system may emit again,
+// e.g. when a rangefeed is restarted.
+//
 // Still TODO is writing out data schemas, Avro support, bounding memory usage.
 //
 // Now what follows is a proof of why the above is correct even in the presence
@@ -270,11 +306,18 @@ type cloudStorageSink struct {
 	settings          *cluster.Settings
 	partitionFormat   string
 
+	format        changefeedbase.FormatType
 	ext           string
 	recordDelimFn func(io.Writer) error
 
 	compression string
 
+	// These are only used when format=parquet.
+	parquetCompression parquet.CompressionCodec
+	parquetUpdated     bool
+	alloc              sqlbase.DatumAlloc
+	datums             tree.Datums
+
 	es cloud.ExternalStorage
 
 	// These are fields to track information needed to output files based on the naming
@@ -290,6 +333,28 @@ type cloudStorageSink struct {
 	dataFileTs        string
 	dataFilePartition string
 	prevFilename      string
+
+	// exactlyOnce is set with the exactly_once option, in which case the data
+	// files are only published by the manifests written by the changeFrontier.
+	exactlyOnce bool
+	// manifestInterval is the interval between manifest timestamps. It is only
+	// used with exactlyOnce.
+	manifestInterval time.Duration
+	// flushedFiles and flushedFileBounds are the paths and manifest timestamps
+	// of the data files flushed since the last call to drainFlushedFiles. They
+	// are only used with exactlyOnce.
+	flushedFiles      []string
+	flushedFileBounds []hlc.Timestamp
+	// emitted contains the rows emitted above the local frontier as of the
+	// last Flush call, to drop them if they're emitted again. It is only used
+	// with exactlyOnce.
+	emitted map[cloudStorageEmittedRow]struct{}
+}
+
+// cloudStorageEmittedRow identifies a row emitted by a cloudStorageSink.
+type cloudStorageEmittedRow struct {
+	key     string
+	updated hlc.Timestamp
 }
 
 const sinkCompressionGzip = "gzip"
@@ -327,7 +392,8 @@ func makeCloudStorageSink(
 		s.dataFilePartition = timestampOracle.inclusiveLowerBoundTS().GoTime().Format(s.partitionFormat)
 	}
 
-	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	s.format = changefeedbase.FormatType(opts[changefeedbase.OptFormat])
+	switch s.format {
 	case changefeedbase.OptFormatJSON:
 		// TODO(dan): It seems like these should be on the encoder, but that
 		// would require a bit of refactoring.
@@ -336,6 +402,9 @@ func makeCloudStorageSink(
 			_, err := w.Write([]byte{'\n'})
 			return err
 		}
+	case changefeedbase.OptFormatParquet:
+		s.ext = `.parquet`
+		_, s.parquetUpdated = opts[changefeedbase.OptUpdatedTimestamps]
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
@@ -354,13 +423,28 @@ func makeCloudStorageSink(
 
 	if codec, ok := opts[changefeedbase.OptCompression]; ok && codec != "" {
 		if strings.EqualFold(codec, "gzip") {
-			s.compression = sinkCompressionGzip
-			s.ext = s.ext + ".gz"
+			if s.format == changefeedbase.OptFormatParquet {
+				// Parquet files are compressed page by page, so that they can still
+				// be read by any Parquet reader.
+				s.parquetCompression = parquet.CompressionGzip
+			} else {
+				s.compression = sinkCompressionGzip
+				s.ext = s.ext + ".gz"
+			}
 		} else {
 			return nil, errors.Errorf(`unsupported compression codec %q`, codec)
 		}
 	}
 
+	if _, ok := opts[changefeedbase.OptExactlyOnce]; ok {
+		s.exactlyOnce = true
+		s.emitted = make(map[cloudStorageEmittedRow]struct{})
+		var err error
+		if s.manifestInterval, err = manifestInterval(opts); err != nil {
+			return nil, err
+		}
+	}
+
 	var err error
 	if s.es, err = makeExternalStorageFromURI(ctx, baseURI); err != nil {
 		return nil, err
@@ -370,9 +454,9 @@ func makeCloudStorageSink(
 }
 
 func (s *cloudStorageSink) getOrCreateFile(
-	topic string, schemaID sqlbase.DescriptorVersion,
+	topic string, schemaID sqlbase.DescriptorVersion, bound hlc.Timestamp,
 ) *cloudStorageSinkFile {
-	key := cloudStorageSinkKey{topic, schemaID}
+	key := cloudStorageSinkKey{topic, schemaID, bound}
 	if item := s.files.Get(key); item != nil {
 		return item.(*cloudStorageSinkFile)
 	}
@@ -389,23 +473,45 @@ func (s *cloudStorageSink) getOrCreateFile(
 
 // EmitRow implements the Sink interface.
 func (s *cloudStorageSink) EmitRow(
-	ctx context.Context, table *sqlbase.TableDescriptor, _, value []byte, updated hlc.Timestamp,
+	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, updated hlc.Timestamp,
 ) error {
 	if s.files == nil {
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
+	if s.emitted != nil {
+		row := cloudStorageEmittedRow{key: string(key), updated: updated}
+		if _, ok := s.emitted[row]; ok {
+			return nil
+		}
+		s.emitted[row] = struct{}{}
+	}
 
-	file := s.getOrCreateFile(table.Name, table.Version)
+	var bound hlc.Timestamp
+	if s.exactlyOnce {
+		bound = manifestCeil(updated, s.manifestInterval)
+	}
+	file := s.getOrCreateFile(table.Name, table.Version, bound)
 
 	// TODO(dan): Memory monitoring for this
-	if _, err := file.Write(value); err != nil {
-		return err
-	}
-	if err := s.recordDelimFn(file); err != nil {
-		return err
+	var fileSize int
+	if s.format == changefeedbase.OptFormatParquet {
+		if err := s.addParquetRow(file, table, value); err != nil {
+			return err
+		}
+		// Most of the file is buffered by the parquet writer until it's closed,
+		// so use the size of the encoded rows as an estimate.
+		fileSize = file.rawSize
+	} else {
+		if _, err := file.Write(value); err != nil {
+			return err
+		}
+		if err := s.recordDelimFn(file); err != nil {
+			return err
+		}
+		fileSize = file.buf.Len()
 	}
 
-	if int64(file.buf.Len()) > s.targetMaxFileSize {
+	if int64(fileSize) > s.targetMaxFileSize {
 		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
 			return err
 		}
@@ -413,6 +519,31 @@ func (s *cloudStorageSink) EmitRow(
 	return nil
 }
 
+// addParquetRow decodes a value encoded by the parquetEncoder and adds it to
+// the file's parquet writer, creating the writer if needed.
+func (s *cloudStorageSink) addParquetRow(
+	file *cloudStorageSinkFile, table *sqlbase.TableDescriptor, value []byte,
+) error {
+	if file.parquet == nil {
+		sch, err := parquetSchemaForTable(table, s.parquetUpdated)
+		if err != nil {
+			return err
+		}
+		file.parquet = parquet.NewWriter(sch, &file.buf, parquet.WriterOptions{
+			Compression: s.parquetCompression,
+		})
+	}
+	var err error
+	if s.datums, err = decodeParquetRow(table, s.parquetUpdated, value, &s.alloc, s.datums); err != nil {
+		return err
+	}
+	if err := file.parquet.AddRow(s.datums); err != nil {
+		return err
+	}
+	file.rawSize += len(value)
+	return nil
+}
+
 // EmitResolvedTimestamp implements the Sink interface.
 func (s *cloudStorageSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -455,19 +586,19 @@ func (s *cloudStorageSink) EmitResolvedTimestamp(
 func (s *cloudStorageSink) flushTopicVersions(
 	ctx context.Context, topic string, maxVersionToFlush sqlbase.DescriptorVersion,
 ) (err error) {
-	var toRemoveAlloc [2]sqlbase.DescriptorVersion // generally avoid allocating
-	toRemove := toRemoveAlloc[:0]                  // schemaIDs of flushed files
+	var toRemoveAlloc [2]cloudStorageSinkKey // generally avoid allocating
+	toRemove := toRemoveAlloc[:0]            // keys of flushed files
 	gte := cloudStorageSinkKey{topic: topic}
 	lt := cloudStorageSinkKey{topic: topic, schemaID: maxVersionToFlush + 1}
 	s.files.AscendRange(gte, lt, func(i btree.Item) (wantMore bool) {
 		f := i.(*cloudStorageSinkFile)
 		if err = s.flushFile(ctx, f); err == nil {
-			toRemove = append(toRemove, f.schemaID)
+			toRemove = append(toRemove, f.cloudStorageSinkKey)
 		}
 		return err == nil
 	})
-	for _, v := range toRemove {
-		s.files.Delete(cloudStorageSinkKey{topic: topic, schemaID: v})
+	for _, k := range toRemove {
+		s.files.Delete(k)
 	}
 	return err
 }
@@ -479,20 +610,54 @@ func (s *cloudStorageSink) Flush(ctx context.Context) error {
 	}
 
 	var err error
-	s.files.Ascend(func(i btree.Item) (wantMore bool) {
-		err = s.flushFile(ctx, i.(*cloudStorageSinkFile))
-		return err == nil
-	})
-	if err != nil {
-		return err
+	if s.exactlyOnce {
+		// Only flush the files of the manifest timestamps the local frontier
+		// has reached. More rows may still be emitted into the other files.
+		var toRemove []cloudStorageSinkKey
+		lowerBound := s.timestampOracle.inclusiveLowerBoundTS()
+		s.files.Ascend(func(i btree.Item) (wantMore bool) {
+			f := i.(*cloudStorageSinkFile)
+			if !f.bound.Less(lowerBound) {
+				return true
+			}
+			if err = s.flushFile(ctx, f); err == nil {
+				toRemove = append(toRemove, f.cloudStorageSinkKey)
+			}
+			return err == nil
+		})
+		for _, k := range toRemove {
+			s.files.Delete(k)
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		s.files.Ascend(func(i btree.Item) (wantMore bool) {
+			err = s.flushFile(ctx, i.(*cloudStorageSinkFile))
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		s.files.Clear(true /* addNodesToFreeList */)
 	}
-	s.files.Clear(true /* addNodesToFreeList */)
 
 	// Record the least resolved timestamp being tracked in the frontier as of this point,
 	// to use for naming files until the next `Flush()`. See comment on cloudStorageSink
 	// for an overview of the naming convention and proof of correctness.
 	s.dataFileTs = cloudStorageFormatTime(s.timestampOracle.inclusiveLowerBoundTS())
 	s.dataFilePartition = s.timestampOracle.inclusiveLowerBoundTS().GoTime().Format(s.partitionFormat)
+
+	// Rows at or below the local frontier are never emitted again, so they
+	// don't need to be remembered anymore.
+	if s.emitted != nil {
+		lowerBound := s.timestampOracle.inclusiveLowerBoundTS()
+		for row := range s.emitted {
+			if row.updated.Less(lowerBound) {
+				delete(s.emitted, row)
+			}
+		}
+	}
 	return nil
 }
 
@@ -504,6 +669,13 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 		return nil
 	}
 
+	// Closing the parquet writer writes out the buffered rows and the footer.
+	if file.parquet != nil {
+		if err := file.parquet.Close(); err != nil {
+			return err
+		}
+	}
+
 	// If the file is written via compression codec, close the codec to ensure it
 	// has flushed to the underlying buffer.
 	if file.codec != nil {
@@ -527,7 +699,132 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 			"precedes a file emitted before: %s", filename, s.prevFilename)
 	}
 	s.prevFilename = filename
-	return s.es.WriteFile(ctx, filepath.Join(s.dataFilePartition, filename), bytes.NewReader(file.buf.Bytes()))
+	path := filepath.Join(s.dataFilePartition, filename)
+	if err := s.es.WriteFile(ctx, path, bytes.NewReader(file.buf.Bytes())); err != nil {
+		return err
+	}
+	if s.exactlyOnce {
+		s.flushedFiles = append(s.flushedFiles, path)
+		s.flushedFileBounds = append(s.flushedFileBounds, file.bound)
+	}
+	return nil
+}
+
+// drainFlushedFiles returns the paths and manifest timestamps of the data files
+// flushed since the last call to drainFlushedFiles. It must only be called
+// after the sink has been flushed.
+func (s *cloudStorageSink) drainFlushedFiles() ([]string, []hlc.Timestamp) {
+	files, bounds := s.flushedFiles, s.flushedFileBounds
+	s.flushedFiles, s.flushedFileBounds = nil, nil
+	return files, bounds
+}
+
+// defaultManifestInterval is the interval between manifest timestamps of
+// exactly_once changefeeds whose resolved option doesn't specify a frequency.
+const defaultManifestInterval = time.Second
+
+// manifestInterval returns the interval between the manifest timestamps of an
+// exactly_once changefeed, which is the frequency of its resolved timestamps.
+func manifestInterval(opts map[string]string) (time.Duration, error) {
+	r := opts[changefeedbase.OptResolvedTimestamps]
+	if r == `` {
+		return defaultManifestInterval, nil
+	}
+	d, err := time.ParseDuration(r)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return defaultManifestInterval, nil
+	}
+	return d, nil
+}
+
+// manifestFloor returns the latest manifest timestamp at or below ts.
+func manifestFloor(ts hlc.Timestamp, interval time.Duration) hlc.Timestamp {
+	return hlc.Timestamp{WallTime: ts.WallTime - ts.WallTime%interval.Nanoseconds()}
+}
+
+// manifestCeil returns the earliest manifest timestamp at or above ts, which
+// is the timestamp of the manifest that publishes a row updated at ts.
+func manifestCeil(ts hlc.Timestamp, interval time.Duration) hlc.Timestamp {
+	floor := manifestFloor(ts, interval)
+	if floor == ts {
+		return floor
+	}
+	return hlc.Timestamp{WallTime: floor.WallTime + interval.Nanoseconds()}
+}
+
+// cloudStorageManifest is the content of a manifest file, which publishes the
+// data files of an exactly_once changefeed.
+type cloudStorageManifest struct {
+	// Resolved is the resolved timestamp, as a decimal, at or below which
+	// every row is in the data files of this manifest or an earlier one.
+	Resolved string `json:"resolved"`
+	// Files are the paths of the data files, relative to the sink URI.
+	Files []string `json:"files"`
+}
+
+// manifestPaths returns the path of the manifest file of a resolved timestamp,
+// and the temporary path it's first written to.
+func (s *cloudStorageSink) manifestPaths(resolved hlc.Timestamp) (path, tmpPath string) {
+	part := resolved.GoTime().Format(s.partitionFormat)
+	path = filepath.Join(part, fmt.Sprintf(`%s.MANIFEST`, cloudStorageFormatTime(resolved)))
+	return path, path + `.tmp`
+}
+
+// writeManifest publishes the passed data files with a manifest for the
+// resolved timestamp, which is checkpointed by checkpointFn. The manifest is
+// written under a temporary name before the checkpoint, so that it can be
+// published by recoverManifest if the changefeed restarts before it is.
+func (s *cloudStorageSink) writeManifest(
+	ctx context.Context, resolved hlc.Timestamp, files []string, checkpointFn func() error,
+) error {
+	sort.Strings(files)
+	manifest, err := gojson.Marshal(cloudStorageManifest{
+		Resolved: tree.TimestampToDecimal(resolved).Decimal.String(),
+		Files:    append([]string{}, files...),
+	})
+	if err != nil {
+		return err
+	}
+	path, tmpPath := s.manifestPaths(resolved)
+	if err := s.es.WriteFile(ctx, tmpPath, bytes.NewReader(manifest)); err != nil {
+		return err
+	}
+	if err := checkpointFn(); err != nil {
+		return err
+	}
+	if log.V(1) {
+		log.Infof(ctx, "writing manifest %s of %d files", path, len(files))
+	}
+	return s.es.WriteFile(ctx, path, bytes.NewReader(manifest))
+}
+
+// recoverManifest publishes the manifest of the passed resolved timestamp,
+// which was checkpointed, if it was written under its temporary name. Writing
+// it again is harmless if it was already published.
+func (s *cloudStorageSink) recoverManifest(ctx context.Context, resolved hlc.Timestamp) error {
+	path, tmpPath := s.manifestPaths(resolved)
+	matches, err := s.es.ListFiles(ctx, tmpPath)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		// The checkpoint wasn't made with a manifest, e.g. it's the cursor.
+		return nil
+	}
+	r, err := s.es.ReadFile(ctx, tmpPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	manifest, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	log.Infof(ctx, "publishing manifest %s of checkpointed timestamp", path)
+	return s.es.WriteFile(ctx, path, bytes.NewReader(manifest))
 }
 
 // Close implements the Sink interface.
@@ -539,6 +836,9 @@ func (s *cloudStorageSink) Close() error {
 type cloudStorageSinkKey struct {
 	topic    string
 	schemaID sqlbase.DescriptorVersion
+	// bound is the manifest timestamp of the rows of the file. It is only set
+	// with the exactly_once option.
+	bound hlc.Timestamp
 }
 
 func (k cloudStorageSinkKey) Less(other btree.Item) bool {
@@ -554,6 +854,9 @@ func (k cloudStorageSinkKey) Less(other btree.Item) bool {
 
 func keyLess(a, b cloudStorageSinkKey) bool {
 	if a.topic == b.topic {
+		if a.schemaID == b.schemaID {
+			return a.bound.Less(b.bound)
+		}
 		return a.schemaID < b.schemaID
 	}
 	return a.topic < b.topic
@@ -26,12 +26,15 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -527,4 +530,166 @@ func TestCloudStorageSink(t *testing.T) {
 			"w1\n",
 		}, slurpDir(t, dir))
 	})
+	t.Run(`parquet`, func(t *testing.T) {
+		parquetOpts := map[string]string{
+			changefeedbase.OptFormat:      string(changefeedbase.OptFormatParquet),
+			changefeedbase.OptEnvelope:    string(changefeedbase.OptEnvelopeWrapped),
+			changefeedbase.OptKeyInValue:  ``,
+			changefeedbase.OptCompression: `gzip`,
+		}
+		pe, err := makeParquetEncoder(parquetOpts)
+		require.NoError(t, err)
+		t1 := &sqlbase.TableDescriptor{
+			Name: `t1`,
+			Columns: []sqlbase.ColumnDescriptor{
+				{ID: 1, Name: `a`, Type: types.Int},
+				{ID: 2, Name: `b`, Type: types.String, Nullable: true},
+			},
+			PrimaryIndex: sqlbase.IndexDescriptor{ColumnIDs: []sqlbase.ColumnID{1}},
+		}
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf := span.MakeFrontier(testSpan)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+		dir := `parquet`
+		s, err := makeCloudStorageSink(
+			ctx, `nodelocal://0/`+dir, 1, unlimitedFileSize,
+			settings, parquetOpts, timestampOracle, externalStorageFromURI,
+		)
+		require.NoError(t, err)
+
+		emit := func(a int, b tree.Datum, deleted bool) {
+			row := encodeRow{
+				datums: sqlbase.EncDatumRow{
+					sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(a))),
+					sqlbase.DatumToEncDatum(types.String, b),
+				},
+				deleted:   deleted,
+				tableDesc: t1,
+			}
+			value, err := pe.EncodeValue(ctx, row)
+			require.NoError(t, err)
+			require.NoError(t, s.EmitRow(ctx, t1, noKey, value, ts(1)))
+		}
+		emit(1, tree.NewDString(`x`), false)
+		emit(2, tree.DNull, false)
+		emit(1, tree.NewDString(`x`), true)
+		require.NoError(t, s.Flush(ctx))
+
+		// Compressed Parquet files are compressed page by page, so the file is
+		// still a valid Parquet file without a .gz extension.
+		var names []string
+		require.NoError(t, filepath.Walk(filepath.Join(settings.ExternalIODir, dir), func(
+			path string, info os.FileInfo, err error,
+		) error {
+			if err == nil && !info.IsDir() {
+				names = append(names, path)
+			}
+			return err
+		}))
+		require.Len(t, names, 1)
+		require.True(t, strings.HasSuffix(names[0], `-t1-0.parquet`), names[0])
+		files := slurpDir(t, dir)
+		require.Len(t, files, 1)
+		require.True(t, strings.HasPrefix(files[0], `PAR1`))
+		require.True(t, strings.HasSuffix(files[0], `PAR1`))
+		require.Contains(t, files[0], parquetColumnDeleted)
+
+		_, err = makeParquetEncoder(map[string]string{
+			changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeKeyOnly),
+		})
+		require.EqualError(t, err, `envelope=key_only is not supported with format=parquet`)
+	})
+	t.Run(`exactly-once`, func(t *testing.T) {
+		exactlyOnceOpts := make(map[string]string)
+		for k, v := range opts {
+			exactlyOnceOpts[k] = v
+		}
+		exactlyOnceOpts[changefeedbase.OptExactlyOnce] = ``
+		exactlyOnceOpts[changefeedbase.OptResolvedTimestamps] = `2ns`
+		t1 := &sqlbase.TableDescriptor{Name: `t1`}
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf := span.MakeFrontier(testSpan)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+		dir := `exactly-once`
+		s, err := makeCloudStorageSink(
+			ctx, `nodelocal://0/`+dir, 1, unlimitedFileSize,
+			settings, exactlyOnceOpts, timestampOracle, externalStorageFromURI,
+		)
+		require.NoError(t, err)
+		sink := s.(*cloudStorageSink)
+		var nilOracle timestampLowerBoundOracle
+		f, err := makeCloudStorageSink(
+			ctx, `nodelocal://0/`+dir, 2, unlimitedFileSize,
+			settings, exactlyOnceOpts, nilOracle, externalStorageFromURI,
+		)
+		require.NoError(t, err)
+		frontierSink := f.(*cloudStorageSink)
+
+		// Manifests are written every 2ns, so rows at ts 2 are published by the
+		// manifest at 2 and rows at 3 and 4 by the one at 4.
+		require.Equal(t, ts(2), manifestFloor(ts(3), sink.manifestInterval))
+		require.Equal(t, ts(4), manifestCeil(ts(3), sink.manifestInterval))
+		require.Equal(t, ts(4), manifestCeil(ts(4), sink.manifestInterval))
+		require.Equal(t, ts(6), manifestCeil(ts(4).Next(), sink.manifestInterval))
+
+		// A row emitted again at the same timestamp is dropped. Only the files of
+		// the manifest timestamps at or below the local frontier are flushed, so
+		// that no file has rows on both sides of a manifest timestamp.
+		require.NoError(t, s.EmitRow(ctx, t1, []byte(`k1`), []byte(`v1`), ts(2)))
+		require.NoError(t, s.EmitRow(ctx, t1, []byte(`k1`), []byte(`v1`), ts(2)))
+		require.NoError(t, s.EmitRow(ctx, t1, []byte(`k2`), []byte(`v2`), ts(3)))
+		require.NoError(t, s.EmitRow(ctx, t1, []byte(`k1`), []byte(`v3`), ts(3)))
+		sf.Forward(testSpan, ts(2))
+		require.NoError(t, s.Flush(ctx))
+		files, bounds := sink.drainFlushedFiles()
+		require.Len(t, files, 1)
+		require.Equal(t, []hlc.Timestamp{ts(2)}, bounds)
+		noFiles, _ := sink.drainFlushedFiles()
+		require.Empty(t, noFiles)
+		require.Equal(t, []string{"v1\n"}, slurpDir(t, dir))
+
+		// Rows at or below the local frontier are forgotten on Flush.
+		sf.Forward(testSpan, ts(3))
+		require.NoError(t, s.EmitRow(ctx, t1, []byte(`k1`), []byte(`v4`), ts(4)))
+		require.NoError(t, s.Flush(ctx))
+		require.Len(t, sink.emitted, 1)
+		noFiles, _ = sink.drainFlushedFiles()
+		require.Empty(t, noFiles)
+		sf.Forward(testSpan, ts(4))
+		require.NoError(t, s.Flush(ctx))
+		laterFiles, laterBounds := sink.drainFlushedFiles()
+		require.Len(t, laterFiles, 1)
+		require.Equal(t, []hlc.Timestamp{ts(4)}, laterBounds)
+		require.Equal(t, []string{"v1\n", "v2\nv3\nv4\n"}, slurpDir(t, dir))
+
+		manifestPath, tmpManifestPath := frontierSink.manifestPaths(ts(2))
+		readManifest := func(path string) string {
+			t.Helper()
+			manifest, err := ioutil.ReadFile(filepath.Join(settings.ExternalIODir, dir, path))
+			require.NoError(t, err)
+			return string(manifest)
+		}
+		expected := fmt.Sprintf(`{"resolved":"2.0000000000","files":["%s"]}`, files[0])
+
+		// If the checkpoint fails, the manifest isn't published.
+		require.EqualError(t, frontierSink.writeManifest(ctx, ts(2), files, func() error {
+			return errors.New(`boom`)
+		}), `boom`)
+		require.Equal(t, expected, readManifest(tmpManifestPath))
+		_, err = os.Stat(filepath.Join(settings.ExternalIODir, dir, manifestPath))
+		require.True(t, os.IsNotExist(err), err)
+
+		// Once the checkpoint was made, the manifest can be recovered.
+		require.NoError(t, frontierSink.recoverManifest(ctx, ts(2)))
+		require.Equal(t, expected, readManifest(manifestPath))
+		require.NoError(t, frontierSink.recoverManifest(ctx, ts(1)))
+		emptyManifestPath, _ := frontierSink.manifestPaths(ts(1))
+		_, err = os.Stat(filepath.Join(settings.ExternalIODir, dir, emptyManifestPath))
+		require.True(t, os.IsNotExist(err), err)
+
+		require.NoError(t, frontierSink.writeManifest(ctx, ts(4), laterFiles, func() error { return nil }))
+		manifestPath, _ = frontierSink.manifestPaths(ts(4))
+		require.Equal(t, fmt.Sprintf(`{"resolved":"4.0000000000","files":["%s"]}`, laterFiles[0]),
+			readManifest(manifestPath))
+	})
 }
@@ -0,0 +1,378 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// pubsubSinkDefaultBatchSize is the number of messages buffered for a topic
+	// before the sink publishes them without waiting for a Flush. Pub/Sub
+	// rejects publish requests with more than 1000 messages.
+	pubsubSinkDefaultBatchSize = 500
+	// pubsubSinkMaxBatchBytes bounds the size of the messages buffered for a
+	// topic. Pub/Sub rejects publish requests larger than 10MB, and the message
+	// data grows by a third when it's base64 encoded in the request.
+	pubsubSinkMaxBatchBytes = 6 << 20
+	// pubsubSinkMaxOrderingKeyBytes is the largest ordering key Pub/Sub accepts.
+	pubsubSinkMaxOrderingKeyBytes = 1024
+	// pubsubSinkTimeout bounds each individual request.
+	pubsubSinkTimeout = 30 * time.Second
+	// pubsubSinkMaxRetries bounds the number of attempts for a single publish
+	// before the error is returned (and the changefeed retried from its last
+	// checkpoint).
+	pubsubSinkMaxRetries = 8
+
+	// pubsubEndpoint is the Pub/Sub REST API endpoint used unless the emulator
+	// is configured.
+	pubsubEndpoint = `https://pubsub.googleapis.com`
+	// pubsubEmulatorHostEnv is the environment variable the Google Cloud client
+	// libraries use to find a Pub/Sub emulator. If it is set, the sink talks to
+	// the emulator over plain HTTP and without credentials.
+	pubsubEmulatorHostEnv = `PUBSUB_EMULATOR_HOST`
+	pubsubScope           = `https://www.googleapis.com/auth/pubsub`
+)
+
+func isPubsubSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeGCPubSub
+}
+
+type pubsubSinkConfig struct {
+	project     string
+	topicPrefix string
+	// credentials is the JSON key of a service account. If it's empty, the
+	// application default credentials are used.
+	credentials []byte
+	// endpoint is the base URL of the Pub/Sub REST API.
+	endpoint string
+	// emulator disables authentication, which the Pub/Sub emulator doesn't
+	// support.
+	emulator  bool
+	batchSize int
+	retryOpts retry.Options
+}
+
+// pubsubSink emits to Google Cloud Pub/Sub. Like the kafka sink, every table
+// is published to its own topic, named by the sink's topic_prefix followed by
+// the escaped table name (see SQLNameToKafkaName). The topics must already
+// exist.
+//
+// Every row is published as a message whose data is the encoded value and
+// whose ordering key is the encoded primary key, so that subscriptions with
+// message ordering enabled receive the changes to a row in order. Resolved
+// timestamps are published, without an ordering key, to every topic after
+// all rows buffered before them have been acknowledged.
+//
+// Messages are published through the Pub/Sub REST API. Publishes are
+// synchronous and retried with backoff, so by the time Flush returns every
+// message emitted before it has been acknowledged. Like the other sinks,
+// pubsubSink is not concurrency-safe.
+type pubsubSink struct {
+	cfg    pubsubSinkConfig
+	client *httputil.Client
+	// topics maps the statement time name of every target table to the topic
+	// its rows are published to. sortedTopics has the same topics sorted by
+	// name, which is the order resolved timestamps are published in.
+	topics       map[string]*pubsubTopic
+	sortedTopics []*pubsubTopic
+}
+
+type pubsubTopic struct {
+	name string
+	// batch holds the messages that have been emitted but not yet published.
+	batch      []pubsubMessage
+	batchBytes int
+}
+
+// pubsubMessage is a PubsubMessage of the Pub/Sub REST API. Data is base64
+// encoded by encoding/json, as the API expects.
+type pubsubMessage struct {
+	Data        []byte `json:"data"`
+	OrderingKey string `json:"orderingKey,omitempty"`
+}
+
+type pubsubPublishRequest struct {
+	Messages []pubsubMessage `json:"messages"`
+}
+
+func makePubsubSink(
+	ctx context.Context,
+	cfg pubsubSinkConfig,
+	opts map[string]string,
+	targets jobspb.ChangefeedTargets,
+) (Sink, error) {
+	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	case changefeedbase.OptFormatJSON:
+	default:
+		// Ordering keys are strings, which the binary avro keys are not.
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
+	}
+
+	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+	case changefeedbase.OptEnvelopeWrapped:
+	default:
+		// Pub/Sub rejects messages without data, which is what the other
+		// envelopes produce for deletions.
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
+	}
+
+	if cfg.batchSize <= 0 {
+		cfg.batchSize = pubsubSinkDefaultBatchSize
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		DialContext: (&net.Dialer{Timeout: pubsubSinkTimeout}).DialContext,
+	}
+	if !cfg.emulator {
+		var ts oauth2.TokenSource
+		if cfg.credentials != nil {
+			jwtConfig, err := google.JWTConfigFromJSON(cfg.credentials, pubsubScope)
+			if err != nil {
+				return nil, errors.Wrap(err, `creating pubsub oauth token source`)
+			}
+			ts = jwtConfig.TokenSource(ctx)
+		} else {
+			var err error
+			if ts, err = google.DefaultTokenSource(ctx, pubsubScope); err != nil {
+				return nil, errors.Wrap(err, `finding default google credentials`)
+			}
+		}
+		transport = &oauth2.Transport{Source: ts, Base: transport}
+	}
+
+	s := &pubsubSink{
+		cfg: cfg,
+		client: &httputil.Client{Client: &http.Client{
+			Timeout:   pubsubSinkTimeout,
+			Transport: transport,
+		}},
+		topics: make(map[string]*pubsubTopic, len(targets)),
+	}
+	for _, t := range targets {
+		topic := &pubsubTopic{name: cfg.topicPrefix + SQLNameToKafkaName(t.StatementTimeName)}
+		s.topics[t.StatementTimeName] = topic
+		s.sortedTopics = append(s.sortedTopics, topic)
+	}
+	sort.Slice(s.sortedTopics, func(i, j int) bool {
+		return s.sortedTopics[i].name < s.sortedTopics[j].name
+	})
+
+	// Fail fast if a topic is missing, instead of retrying every publish to it.
+	for _, topic := range s.sortedTopics {
+		if err := s.checkTopic(ctx, topic); err != nil {
+			s.client.CloseIdleConnections()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *pubsubSink) EmitRow(
+	ctx context.Context, table *sqlbase.TableDescriptor, key, value []byte, _ hlc.Timestamp,
+) error {
+	if s.client == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	topic, ok := s.topics[table.Name]
+	if !ok {
+		return errors.Errorf(`cannot emit to undeclared topic: %s`, table.Name)
+	}
+	if len(key) > pubsubSinkMaxOrderingKeyBytes {
+		return errors.Errorf(`primary key of a row in %s is %d bytes when encoded, `+
+			`larger than the maximum pubsub ordering key of %d bytes`,
+			table.Name, len(key), pubsubSinkMaxOrderingKeyBytes)
+	}
+
+	// The encoder reuses its buffers, so hold on to a copy.
+	topic.batch = append(topic.batch, pubsubMessage{
+		Data:        append([]byte(nil), value...),
+		OrderingKey: string(key),
+	})
+	topic.batchBytes += len(key) + len(value)
+	if len(topic.batch) >= s.cfg.batchSize || topic.batchBytes >= pubsubSinkMaxBatchBytes {
+		return s.flushTopic(ctx, topic)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *pubsubSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	if s.client == nil {
+		return errors.New(`cannot EmitResolvedTimestamp on a closed sink`)
+	}
+	// A resolved timestamp promises that every row before it has been emitted,
+	// so anything still buffered has to go out first.
+	if err := s.flushAll(ctx); err != nil {
+		return err
+	}
+	for _, topic := range s.sortedTopics {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic.name, resolved)
+		if err != nil {
+			return err
+		}
+		if err := s.publish(ctx, topic, []pubsubMessage{{Data: payload}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *pubsubSink) Flush(ctx context.Context) error {
+	if s.client == nil {
+		return errors.New(`cannot Flush on a closed sink`)
+	}
+	return s.flushAll(ctx)
+}
+
+// Close implements the Sink interface.
+func (s *pubsubSink) Close() error {
+	if s.client != nil {
+		s.client.CloseIdleConnections()
+	}
+	s.client = nil
+	for _, topic := range s.sortedTopics {
+		topic.batch, topic.batchBytes = nil, 0
+	}
+	return nil
+}
+
+func (s *pubsubSink) flushAll(ctx context.Context) error {
+	for _, topic := range s.sortedTopics {
+		if err := s.flushTopic(ctx, topic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *pubsubSink) flushTopic(ctx context.Context, topic *pubsubTopic) error {
+	if len(topic.batch) == 0 {
+		return nil
+	}
+	if err := s.publish(ctx, topic, topic.batch); err != nil {
+		return err
+	}
+	topic.batch, topic.batchBytes = topic.batch[:0], 0
+	return nil
+}
+
+func (s *pubsubSink) topicURL(topic *pubsubTopic) string {
+	return fmt.Sprintf(`%s/v1/projects/%s/topics/%s`,
+		s.cfg.endpoint, url.PathEscape(s.cfg.project), url.PathEscape(topic.name))
+}
+
+// checkTopic returns an error if the topic doesn't exist.
+func (s *pubsubSink) checkTopic(ctx context.Context, topic *pubsubTopic) error {
+	resp, err := s.client.Get(ctx, s.topicURL(topic))
+	if err != nil {
+		return errors.Wrapf(err, `looking up pubsub topic %s`, topic.name)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.Errorf(`pubsub topic %s does not exist in project %s`, topic.name, s.cfg.project)
+	}
+	if err := pubsubResponseError(resp); err != nil {
+		return errors.Wrapf(err, `looking up pubsub topic %s`, topic.name)
+	}
+	return nil
+}
+
+// publish sends messages to the topic, retrying with backoff until they are
+// acknowledged, they are rejected with a terminal error or the retries are
+// exhausted.
+func (s *pubsubSink) publish(
+	ctx context.Context, topic *pubsubTopic, messages []pubsubMessage,
+) error {
+	body, err := json.Marshal(pubsubPublishRequest{Messages: messages})
+	if err != nil {
+		return err
+	}
+	publishURL := s.topicURL(topic) + `:publish`
+	for r := retry.StartWithCtx(ctx, s.cfg.retryOpts); r.Next(); {
+		if err = s.publishOnce(ctx, publishURL, body); err == nil {
+			return nil
+		}
+		if isTerminalSinkError(err) {
+			break
+		}
+		if log.V(1) {
+			log.Infof(ctx, "retrying pubsub publish to %s after error: %v", topic.name, err)
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.Wrapf(err, `publishing to pubsub topic %s`, topic.name)
+}
+
+func (s *pubsubSink) publishOnce(ctx context.Context, publishURL string, body []byte) error {
+	resp, err := s.client.Post(ctx, publishURL, `application/json`, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return pubsubResponseError(resp)
+}
+
+// pubsubResponseError returns an error for non-2xx responses, marked as
+// terminal for client errors which retrying can't fix. It consumes the
+// response body either way, so the connection can be reused.
+func pubsubResponseError(resp *http.Response) error {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := errors.Errorf(`%s: %s`, resp.Status, strings.TrimSpace(string(msg)))
+		if isTerminalHTTPStatus(resp.StatusCode) {
+			return markTerminalSinkError(err)
+		}
+		return err
+	}
+	_, err := io.Copy(ioutil.Discard, resp.Body)
+	return err
+}
+
+// decodePubsubCredentials decodes the base64 encoded JSON key passed in the
+// sink's credentials parameter.
+func decodePubsubCredentials(encoded string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Errorf(`param %s must be base 64 encoded: %s`,
+			changefeedbase.SinkParamCredentials, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,320 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePubsub is an in-process stand-in for the Pub/Sub emulator. It serves the
+// topic lookups and publishes done by pubsubSink and records every published
+// message.
+type fakePubsub struct {
+	// requestRecorder records a "<topic> <ordering key> <data>" entry for every
+	// published message, and fails publishes on demand.
+	requestRecorder
+	project string
+	topics  map[string]struct{}
+}
+
+func (f *fakePubsub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	prefix := fmt.Sprintf(`/v1/projects/%s/topics/`, f.project)
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		http.NotFound(w, req)
+		return
+	}
+	topic := strings.TrimPrefix(req.URL.Path, prefix)
+	publish := strings.HasSuffix(topic, `:publish`)
+	topic = strings.TrimSuffix(topic, `:publish`)
+	if _, ok := f.topics[topic]; !ok {
+		http.NotFound(w, req)
+		return
+	}
+	switch {
+	case req.Method == http.MethodGet && !publish:
+		fmt.Fprintf(w, `{"name":"projects/%s/topics/%s"}`, f.project, topic)
+	case req.Method == http.MethodPost && publish:
+		if f.maybeFail(w) {
+			return
+		}
+		var body pubsubPublishRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, m := range body.Messages {
+			f.record(fmt.Sprintf(`%s %s %s`, topic, m.OrderingKey, m.Data))
+		}
+		fmt.Fprint(w, `{"messageIds":[]}`)
+	default:
+		http.Error(w, `unexpected request`, http.StatusBadRequest)
+	}
+}
+
+// setPubsubEmulatorHost points pubsub sinks at host and returns a function
+// that restores the previous value.
+func setPubsubEmulatorHost(t *testing.T, host string) func() {
+	prev, ok := os.LookupEnv(pubsubEmulatorHostEnv)
+	require.NoError(t, os.Setenv(pubsubEmulatorHostEnv, host))
+	return func() {
+		if ok {
+			require.NoError(t, os.Setenv(pubsubEmulatorHostEnv, prev))
+		} else {
+			require.NoError(t, os.Unsetenv(pubsubEmulatorHostEnv))
+		}
+	}
+}
+
+func TestPubsubSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	table := func(name string) *sqlbase.TableDescriptor {
+		return &sqlbase.TableDescriptor{Name: name}
+	}
+
+	ctx := context.Background()
+	fake := &fakePubsub{
+		project: `proj`,
+		topics:  map[string]struct{}{`p.foo`: {}, `p.bar_u2603_`: {}},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	defer setPubsubEmulatorHost(t, serverURL.Host)()
+
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	targets := jobspb.ChangefeedTargets{
+		0: jobspb.ChangefeedTarget{StatementTimeName: `foo`},
+		1: jobspb.ChangefeedTarget{StatementTimeName: `bar☃`},
+	}
+	sink, err := getSink(ctx, `gcpubsub://proj?topic_prefix=p.`, 0 /* nodeID */, opts, targets,
+		nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sink.Close()) }()
+	s := sink.(*pubsubSink)
+	s.cfg.batchSize = 3
+	s.cfg.retryOpts.InitialBackoff = time.Millisecond
+	s.cfg.retryOpts.MaxBackoff = time.Millisecond
+
+	// Empty
+	require.NoError(t, sink.Flush(ctx))
+	require.Empty(t, fake.pop())
+
+	// Undeclared topic
+	require.EqualError(t,
+		sink.EmitRow(ctx, table(`nope`), nil, nil, zeroTS), `cannot emit to undeclared topic: nope`)
+
+	// Nothing is published until Flush is called. Every row is published to its
+	// table's topic with its key as the ordering key.
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+	require.NoError(t, sink.EmitRow(ctx, table(`bar☃`), []byte(`[2]`), []byte(`{"b":2}`), zeroTS))
+	require.Empty(t, fake.pop())
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{
+		`p.bar_u2603_ [2] {"b":2}`,
+		`p.foo [1] {"a":1}`,
+	}, fake.pop())
+
+	// A topic's rows are published without a Flush once its batch is full.
+	for i := 2; i <= 5; i++ {
+		key, value := fmt.Sprintf(`[%d]`, i), fmt.Sprintf(`{"a":%d}`, i)
+		require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(key), []byte(value), zeroTS))
+	}
+	require.Equal(t, []string{
+		`p.foo [2] {"a":2}`,
+		`p.foo [3] {"a":3}`,
+		`p.foo [4] {"a":4}`,
+	}, fake.pop())
+
+	// A resolved timestamp publishes buffered rows before itself and is fanned
+	// out to every topic without an ordering key.
+	var e testEncoder
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, e, zeroTS))
+	require.Equal(t, []string{
+		`p.foo [5] {"a":5}`,
+		`p.bar_u2603_  ` + zeroTS.String(),
+		`p.foo  ` + zeroTS.String(),
+	}, fake.pop())
+
+	// Ordering keys are limited in size.
+	bigKey := bytes.Repeat([]byte(`a`), pubsubSinkMaxOrderingKeyBytes+1)
+	require.EqualError(t, sink.EmitRow(ctx, table(`foo`), bigKey, []byte(`{}`), zeroTS),
+		`primary key of a row in foo is 1025 bytes when encoded, larger than the maximum `+
+			`pubsub ordering key of 1024 bytes`)
+
+	// Transient errors are retried and Flush only returns once the batch has
+	// been acknowledged.
+	fake.setFailures(2, 0 /* status */)
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`[6]`), []byte(`{"a":6}`), zeroTS))
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`p.foo [6] {"a":6}`}, fake.pop())
+
+	// Once the retries are exhausted, the error is returned and the rows are
+	// kept for the next attempt.
+	fake.setFailures(s.cfg.retryOpts.MaxRetries+1, 0 /* status */)
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`[7]`), []byte(`{"a":7}`), zeroTS))
+	require.Error(t, sink.Flush(ctx))
+	require.Empty(t, fake.pop())
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`p.foo [7] {"a":7}`}, fake.pop())
+
+	// Client errors are not retried and fail the changefeed, since the publish
+	// would be rejected again.
+	fake.setFailures(1, http.StatusForbidden)
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`[8]`), []byte(`{"a":8}`), zeroTS))
+	err = errorWrapperSink{wrapped: sink}.Flush(ctx)
+	require.Error(t, err)
+	require.True(t, isTerminalSinkError(err), "expected terminal error, got %v", err)
+	require.False(t, IsRetryableError(err))
+	require.Empty(t, fake.pop())
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`p.foo [8] {"a":8}`}, fake.pop())
+
+	// Except for request timeouts and rate limiting, which are retried.
+	for _, status := range []int{http.StatusRequestTimeout, http.StatusTooManyRequests} {
+		fake.setFailures(2, status)
+		require.NoError(t, sink.EmitRow(ctx, table(`foo`), []byte(`[9]`), []byte(`{"a":9}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, []string{`p.foo [9] {"a":9}`}, fake.pop())
+	}
+}
+
+func TestPubsubSinkConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	fake := &fakePubsub{project: `proj`, topics: map[string]struct{}{`foo`: {}}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	defer setPubsubEmulatorHost(t, serverURL.Host)()
+
+	targets := jobspb.ChangefeedTargets{
+		0: jobspb.ChangefeedTarget{StatementTimeName: `foo`},
+	}
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatAvro),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	getPubsubSink := func(uri string) error {
+		sink, err := getSink(ctx, uri, 0 /* nodeID */, opts, targets,
+			nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */)
+		if err == nil {
+			require.NoError(t, sink.Close())
+		}
+		return err
+	}
+	require.EqualError(t, getPubsubSink(`gcpubsub://proj`),
+		`this sink is incompatible with format=experimental_avro`)
+
+	opts[changefeedbase.OptFormat] = string(changefeedbase.OptFormatJSON)
+	opts[changefeedbase.OptEnvelope] = string(changefeedbase.OptEnvelopeKeyOnly)
+	require.EqualError(t, getPubsubSink(`gcpubsub://proj`),
+		`this sink is incompatible with envelope=key_only`)
+
+	opts[changefeedbase.OptEnvelope] = string(changefeedbase.OptEnvelopeWrapped)
+	require.EqualError(t, getPubsubSink(`gcpubsub://`),
+		`gcpubsub sink requires a project: gcpubsub://<project>`)
+	require.EqualError(t, getPubsubSink(`gcpubsub://proj?credentials=!`),
+		`param credentials must be base 64 encoded: illegal base64 data at input byte 0`)
+	require.EqualError(t, getPubsubSink(`gcpubsub://proj?topic_prefix=missing_`),
+		`pubsub topic missing_foo does not exist in project proj`)
+	require.EqualError(t, getPubsubSink(`gcpubsub://proj?nope=1`),
+		`unknown sink query parameter: nope`)
+	require.NoError(t, getPubsubSink(`gcpubsub://proj`))
+}
+
+// TestPubsubSinkEmulator runs a changefeed sink against a real Pub/Sub
+// emulator, which is only done if PUBSUB_EMULATOR_HOST is set. The emulator
+// can be started with:
+//
+//	gcloud beta emulators pubsub start --host-port=localhost:8085
+//	export PUBSUB_EMULATOR_HOST=localhost:8085
+func TestPubsubSinkEmulator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	host := os.Getenv(pubsubEmulatorHostEnv)
+	if host == `` {
+		t.Skipf(`%s is not set`, pubsubEmulatorHostEnv)
+	}
+
+	ctx := context.Background()
+	project := fmt.Sprintf(`crdb-test-%d`, time.Now().UnixNano())
+	base := fmt.Sprintf(`http://%s/v1/projects/%s`, host, project)
+	put := func(path, body string) {
+		req, err := http.NewRequest(http.MethodPut, base+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set(`Content-Type`, `application/json`)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.NoError(t, pubsubResponseError(resp))
+	}
+	put(`/topics/foo`, `{}`)
+	put(`/subscriptions/foo`, fmt.Sprintf(
+		`{"topic":"projects/%s/topics/foo","enableMessageOrdering":true}`, project))
+
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	targets := jobspb.ChangefeedTargets{
+		0: jobspb.ChangefeedTarget{StatementTimeName: `foo`},
+	}
+	sink, err := getSink(ctx, `gcpubsub://`+project, 0 /* nodeID */, opts, targets,
+		nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	table := &sqlbase.TableDescriptor{Name: `foo`}
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":2}`), zeroTS))
+	require.NoError(t, sink.Flush(ctx))
+
+	var pulled struct {
+		ReceivedMessages []struct {
+			Message pubsubMessage `json:"message"`
+		} `json:"receivedMessages"`
+	}
+	var messages []string
+	for len(messages) < 2 {
+		resp, err := http.Post(base+`/subscriptions/foo:pull`, `application/json`,
+			strings.NewReader(`{"maxMessages":10}`))
+		require.NoError(t, err)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pulled))
+		require.NoError(t, resp.Body.Close())
+		for _, m := range pulled.ReceivedMessages {
+			messages = append(messages, m.Message.OrderingKey+` `+string(m.Message.Data))
+		}
+	}
+	require.Equal(t, []string{`[1] {"a":1}`, `[1] {"a":2}`}, messages)
+}
@@ -0,0 +1,264 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/pkg/errors"
+)
+
+const (
+	// webhookSinkDefaultBatchSize is the number of rows buffered before the sink
+	// sends them out without waiting for a Flush.
+	webhookSinkDefaultBatchSize = 500
+	// webhookSinkTimeout bounds each individual POST attempt.
+	webhookSinkTimeout = 10 * time.Second
+	// webhookSinkMaxRetries bounds the number of attempts for a single POST
+	// before the error is returned (and the changefeed retried from its last
+	// checkpoint).
+	webhookSinkMaxRetries = 8
+)
+
+func isWebhookSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeWebhookHTTPS
+}
+
+type webhookSinkConfig struct {
+	caCert     []byte
+	clientCert []byte
+	clientKey  []byte
+	batchSize  int
+	retryOpts  retry.Options
+}
+
+// webhookSink emits to an HTTPS endpoint. Rows are buffered and sent as a
+// single JSON POST body of the form:
+//
+//	{"payload": [<row>, <row>, ...], "length": <n>}
+//
+// where each row is the JSON encoded value produced by the changefeed's
+// encoder. Resolved timestamps are sent in their own POST, after all rows
+// buffered before them, with the body produced by the encoder unchanged.
+//
+// Requests are sent synchronously and retried with backoff, so by the time
+// Flush returns every row and resolved timestamp emitted before it has been
+// acknowledged with a 2xx response. Like the other sinks, webhookSink is not
+// concurrency-safe.
+type webhookSink struct {
+	url    string
+	cfg    webhookSinkConfig
+	client *httputil.Client
+	topics map[string]struct{}
+
+	// batch holds the JSON encoded rows that have been emitted but not yet
+	// sent.
+	batch [][]byte
+}
+
+func makeWebhookSink(
+	cfg webhookSinkConfig, u *url.URL, opts map[string]string, topics map[string]struct{},
+) (Sink, error) {
+	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	case changefeedbase.OptFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
+	}
+
+	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+	case changefeedbase.OptEnvelopeWrapped:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
+	}
+
+	if _, ok := opts[changefeedbase.OptKeyInValue]; !ok {
+		return nil, errors.Errorf(`this sink requires the WITH %s option`, changefeedbase.OptKeyInValue)
+	}
+
+	tlsConfig := &tls.Config{}
+	if cfg.caCert != nil {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(cfg.caCert) {
+			return nil, errors.Errorf(`param %s does not contain a valid PEM certificate`,
+				changefeedbase.SinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if cfg.clientCert != nil {
+		if cfg.clientKey == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+		}
+		cert, err := tls.X509KeyPair(cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			return nil, errors.Errorf(`invalid client certificate data provided: %s`, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if cfg.clientKey != nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+
+	if cfg.batchSize <= 0 {
+		cfg.batchSize = webhookSinkDefaultBatchSize
+	}
+
+	// Swap the changefeed prefix for the one the http client expects.
+	sinkURL := *u
+	sinkURL.Scheme = strings.TrimPrefix(sinkURL.Scheme, `webhook-`)
+
+	s := &webhookSink{
+		url: sinkURL.String(),
+		cfg: cfg,
+		client: &httputil.Client{Client: &http.Client{
+			Timeout: webhookSinkTimeout,
+			Transport: &http.Transport{
+				DialContext:     (&net.Dialer{Timeout: webhookSinkTimeout}).DialContext,
+				TLSClientConfig: tlsConfig,
+			},
+		}},
+		topics: topics,
+	}
+	return s, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context, table *sqlbase.TableDescriptor, _, value []byte, _ hlc.Timestamp,
+) error {
+	if s.client == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	if _, ok := s.topics[table.Name]; !ok {
+		return errors.Errorf(`cannot emit to undeclared topic: %s`, table.Name)
+	}
+
+	// The encoder reuses its buffers, so hold on to a copy.
+	s.batch = append(s.batch, append([]byte(nil), value...))
+	if len(s.batch) >= s.cfg.batchSize {
+		return s.flushBatch(ctx)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	if s.client == nil {
+		return errors.New(`cannot EmitResolvedTimestamp on a closed sink`)
+	}
+	// A resolved timestamp promises that every row before it has been emitted,
+	// so anything still buffered has to go out first.
+	if err := s.flushBatch(ctx); err != nil {
+		return err
+	}
+	var noTopic string
+	payload, err := encoder.EncodeResolvedTimestamp(ctx, noTopic, resolved)
+	if err != nil {
+		return err
+	}
+	return s.post(ctx, payload)
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	if s.client == nil {
+		return errors.New(`cannot Flush on a closed sink`)
+	}
+	return s.flushBatch(ctx)
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	if s.client != nil {
+		s.client.CloseIdleConnections()
+	}
+	s.client = nil
+	s.batch = nil
+	return nil
+}
+
+func (s *webhookSink) flushBatch(ctx context.Context) error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`{"payload":[`)
+	for i, row := range s.batch {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(row)
+	}
+	fmt.Fprintf(&buf, `],"length":%d}`, len(s.batch))
+	if err := s.post(ctx, buf.Bytes()); err != nil {
+		return err
+	}
+	s.batch = s.batch[:0]
+	return nil
+}
+
+// post sends body to the webhook endpoint, retrying with backoff until it is
+// acknowledged, it is rejected with a terminal error or the retries are
+// exhausted.
+func (s *webhookSink) post(ctx context.Context, body []byte) error {
+	var err error
+	for r := retry.StartWithCtx(ctx, s.cfg.retryOpts); r.Next(); {
+		if err = s.postOnce(ctx, body); err == nil {
+			return nil
+		}
+		if isTerminalSinkError(err) {
+			break
+		}
+		if log.V(1) {
+			log.Infof(ctx, "retrying webhook sink request after error: %v", err)
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.Wrapf(err, `sending to webhook sink`)
+}
+
+func (s *webhookSink) postOnce(ctx context.Context, body []byte) error {
+	resp, err := s.client.Post(ctx, s.url, `application/json`, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := errors.Errorf(`%s: %s`, resp.Status, strings.TrimSpace(string(msg)))
+		if isTerminalHTTPStatus(resp.StatusCode) {
+			return markTerminalSinkError(err)
+		}
+		return err
+	}
+	// Drain the body so the connection can be reused.
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	return err
+}
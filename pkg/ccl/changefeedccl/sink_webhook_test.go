@@ -0,0 +1,174 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookRecorder struct {
+	requestRecorder
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if r.maybeFail(w) {
+		return
+	}
+	r.record(string(body))
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	table := func(name string) *sqlbase.TableDescriptor {
+		return &sqlbase.TableDescriptor{Name: name}
+	}
+
+	ctx := context.Background()
+	rec := &webhookRecorder{}
+	server := httptest.NewTLSServer(rec)
+	defer server.Close()
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: server.Certificate().Raw})
+	sinkURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	sinkURL.Scheme = changefeedbase.SinkSchemeWebhookHTTPS
+	sinkURL.RawQuery = url.Values{
+		changefeedbase.SinkParamCACert: {base64.StdEncoding.EncodeToString(caCert)},
+	}.Encode()
+
+	opts := map[string]string{
+		changefeedbase.OptFormat:     string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope:   string(changefeedbase.OptEnvelopeWrapped),
+		changefeedbase.OptKeyInValue: ``,
+	}
+	targets := jobspb.ChangefeedTargets{
+		0: jobspb.ChangefeedTarget{StatementTimeName: `foo`},
+	}
+	sink, err := getSink(ctx, sinkURL.String(), 0 /* nodeID */, opts, targets,
+		nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sink.Close()) }()
+	s := sink.(*webhookSink)
+	s.cfg.batchSize = 3
+	s.cfg.retryOpts.InitialBackoff = time.Millisecond
+	s.cfg.retryOpts.MaxBackoff = time.Millisecond
+
+	// Empty
+	require.NoError(t, sink.Flush(ctx))
+	require.Empty(t, rec.pop())
+
+	// Undeclared topic
+	require.EqualError(t,
+		sink.EmitRow(ctx, table(`nope`), nil, nil, zeroTS), `cannot emit to undeclared topic: nope`)
+
+	// With one row, nothing is sent until Flush is called.
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), nil, []byte(`{"a":1}`), zeroTS))
+	require.Empty(t, rec.pop())
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`{"payload":[{"a":1}],"length":1}`}, rec.pop())
+
+	// Rows are sent without a Flush once the batch is full.
+	for _, v := range []string{`{"a":2}`, `{"a":3}`, `{"a":4}`, `{"a":5}`} {
+		require.NoError(t, sink.EmitRow(ctx, table(`foo`), nil, []byte(v), zeroTS))
+	}
+	require.Equal(t, []string{`{"payload":[{"a":2},{"a":3},{"a":4}],"length":3}`}, rec.pop())
+
+	// A resolved timestamp sends out buffered rows before itself.
+	var e testEncoder
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, e, zeroTS))
+	require.Equal(t, []string{
+		`{"payload":[{"a":5}],"length":1}`,
+		zeroTS.String(),
+	}, rec.pop())
+
+	// Transient errors are retried and Flush only returns once the batch has
+	// been acknowledged.
+	rec.setFailures(2, 0 /* status */)
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), nil, []byte(`{"a":6}`), zeroTS))
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`{"payload":[{"a":6}],"length":1}`}, rec.pop())
+
+	// Once the retries are exhausted, the error is returned and the rows are
+	// kept for the next attempt.
+	rec.setFailures(s.cfg.retryOpts.MaxRetries+1, 0 /* status */)
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), nil, []byte(`{"a":7}`), zeroTS))
+	require.Error(t, sink.Flush(ctx))
+	require.Empty(t, rec.pop())
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`{"payload":[{"a":7}],"length":1}`}, rec.pop())
+
+	// Client errors are not retried and fail the changefeed, since the request
+	// would be rejected again.
+	rec.setFailures(1, http.StatusBadRequest)
+	require.NoError(t, sink.EmitRow(ctx, table(`foo`), nil, []byte(`{"a":8}`), zeroTS))
+	err = errorWrapperSink{wrapped: sink}.Flush(ctx)
+	require.Error(t, err)
+	require.True(t, isTerminalSinkError(err), "expected terminal error, got %v", err)
+	require.False(t, IsRetryableError(err))
+	require.Empty(t, rec.pop())
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`{"payload":[{"a":8}],"length":1}`}, rec.pop())
+
+	// Except for request timeouts and rate limiting, which are retried.
+	for _, status := range []int{http.StatusRequestTimeout, http.StatusTooManyRequests} {
+		rec.setFailures(2, status)
+		require.NoError(t, sink.EmitRow(ctx, table(`foo`), nil, []byte(`{"a":9}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, []string{`{"payload":[{"a":9}],"length":1}`}, rec.pop())
+	}
+}
+
+func TestWebhookSinkConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	targets := jobspb.ChangefeedTargets{
+		0: jobspb.ChangefeedTarget{StatementTimeName: `foo`},
+	}
+	opts := map[string]string{
+		changefeedbase.OptFormat:     string(changefeedbase.OptFormatAvro),
+		changefeedbase.OptEnvelope:   string(changefeedbase.OptEnvelopeWrapped),
+		changefeedbase.OptKeyInValue: ``,
+	}
+	_, err := getSink(ctx, `webhook-https://localhost:1234`, 0 /* nodeID */, opts, targets,
+		nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */)
+	require.EqualError(t, err, `this sink is incompatible with format=experimental_avro`)
+
+	opts[changefeedbase.OptFormat] = string(changefeedbase.OptFormatJSON)
+	_, err = getSink(ctx, `webhook-https://localhost:1234?ca_cert=!`, 0 /* nodeID */, opts, targets,
+		nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */)
+	require.EqualError(t, err,
+		`param ca_cert must be base 64 encoded: illegal base64 data at input byte 0`)
+
+	_, err = getSink(ctx, `webhook-https://localhost:1234?client_key=Zm9v`, 0 /* nodeID */, opts,
+		targets, nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */)
+	require.EqualError(t, err, `client_key requires client_cert to be set`)
+}
@@ -17,7 +17,8 @@ type TestingKnobs struct {
 	// AfterSinkFlush is called after a sink flush operation has returned without
 	// error.
 	AfterSinkFlush func() error
-	// MemBufferCapacity, if non-zero, overrides memBufferDefaultCapacity.
+	// MemBufferCapacity, if non-zero, overrides the
+	// changefeed.memory.per_changefeed_limit setting.
 	MemBufferCapacity int64
 }
 
@@ -0,0 +1,233 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	gojson "encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// The `transactions` option tags every row emitted by a changefeed with the ID
+// and commit timestamp of the transaction that wrote it and, once all of a
+// transaction's rows have been flushed to the sink and the changefeed-level
+// resolved timestamp has passed the commit timestamp, emits a "transaction
+// end" marker listing how many rows the transaction wrote to each watched
+// table. Like resolved timestamps, markers are sent to every topic and
+// partition. Consumers can buffer rows by transaction ID and apply them
+// atomically when the marker arrives.
+//
+// The transaction ID is plumbed through rangefeeds from the committed intent
+// (or the 1PC batch) that wrote the value. It's not known for rows emitted by
+// initial scans, schema change backfills or the catch-up scans that run when a
+// rangefeed is restarted. Those rows are tagged with a null ID and are not
+// included in any marker. Like everything else in a changefeed, rows and
+// markers are delivered at-least-once, so a marker can be repeated after a
+// restart, possibly with counts that don't include rows which were emitted
+// before it.
+//
+// The bookkeeping works as follows. Each changeAggregator counts the rows it
+// has emitted per transaction and, after flushing the sink, attaches the counts
+// to the next resolved span it sends to the changeFrontier. The changeFrontier
+// accumulates the counts from every aggregator and, whenever the frontier
+// advances, emits the markers for all transactions at or below it. This works
+// because an aggregator only sends a resolved span after it has flushed every
+// row at or below that span's timestamp.
+
+// transactionJSON returns the `transaction` field of a wrapped JSON row.
+func transactionJSON(id uuid.UUID, ts hlc.Timestamp) map[string]interface{} {
+	var jsonID interface{}
+	if id != uuid.Nil {
+		jsonID = id.String()
+	}
+	return map[string]interface{}{
+		`id`:        jsonID,
+		`timestamp`: ts.AsOfSystemTime(),
+	}
+}
+
+type transactionKey struct {
+	id      uuid.UUID
+	ts      hlc.Timestamp
+	tableID sqlbase.ID
+}
+
+// transactionCounts counts the rows in each transaction, per table.
+type transactionCounts map[transactionKey]int64
+
+func (c transactionCounts) add(txn jobspb.ChangefeedTransaction) {
+	c[transactionKey{id: txn.ID, ts: txn.Timestamp, tableID: txn.TableID}] += txn.Rows
+}
+
+// sorted returns the counts ordered by timestamp, transaction ID and table.
+func (c transactionCounts) sorted() []jobspb.ChangefeedTransaction {
+	txns := make([]jobspb.ChangefeedTransaction, 0, len(c))
+	for k, rows := range c {
+		txns = append(txns, jobspb.ChangefeedTransaction{
+			ID: k.id, Timestamp: k.ts, TableID: k.tableID, Rows: rows,
+		})
+	}
+	sort.Slice(txns, func(i, j int) bool {
+		if !txns[i].Timestamp.Equal(txns[j].Timestamp) {
+			return txns[i].Timestamp.Less(txns[j].Timestamp)
+		}
+		if c := bytes.Compare(txns[i].ID.GetBytes(), txns[j].ID.GetBytes()); c != 0 {
+			return c < 0
+		}
+		return txns[i].TableID < txns[j].TableID
+	})
+	return txns
+}
+
+// transactionTracker is used by a changeAggregator to count the rows it has
+// emitted for each transaction since the last flush.
+type transactionTracker struct {
+	counts transactionCounts
+}
+
+func makeTransactionTracker() *transactionTracker {
+	return &transactionTracker{counts: make(transactionCounts)}
+}
+
+// rowEmitted records that a row was written to the sink. Rows without a known
+// transaction ID are ignored.
+func (t *transactionTracker) rowEmitted(row encodeRow) {
+	if row.txnID == uuid.Nil {
+		return
+	}
+	t.counts.add(jobspb.ChangefeedTransaction{
+		ID: row.txnID, Timestamp: row.updated, TableID: row.tableDesc.ID, Rows: 1,
+	})
+}
+
+// drain returns the counts of all rows emitted since the last call to drain.
+// It must only be called after the sink has been flushed.
+func (t *transactionTracker) drain() []jobspb.ChangefeedTransaction {
+	if len(t.counts) == 0 {
+		return nil
+	}
+	txns := t.counts.sorted()
+	t.counts = make(transactionCounts)
+	return txns
+}
+
+// transactionAccumulator is used by the changeFrontier to combine the row
+// counts sent by every changeAggregator until the frontier passes them.
+type transactionAccumulator struct {
+	counts transactionCounts
+}
+
+func makeTransactionAccumulator() *transactionAccumulator {
+	return &transactionAccumulator{counts: make(transactionCounts)}
+}
+
+func (a *transactionAccumulator) add(txns []jobspb.ChangefeedTransaction) {
+	for _, txn := range txns {
+		a.counts.add(txn)
+	}
+}
+
+// pop removes and returns the end markers of all transactions that committed
+// at or before the given resolved timestamp, ordered by timestamp and then
+// transaction ID.
+func (a *transactionAccumulator) pop(resolved hlc.Timestamp) []transactionEnd {
+	done := make(transactionCounts)
+	for k, rows := range a.counts {
+		if k.ts.LessEq(resolved) {
+			done[k] = rows
+			delete(a.counts, k)
+		}
+	}
+	var ends []transactionEnd
+	for _, txn := range done.sorted() {
+		if n := len(ends); n == 0 || ends[n-1].id != txn.ID || !ends[n-1].ts.Equal(txn.Timestamp) {
+			ends = append(ends, transactionEnd{
+				id: txn.ID, ts: txn.Timestamp, rows: make(map[sqlbase.ID]int64),
+			})
+		}
+		ends[len(ends)-1].rows[txn.TableID] += txn.Rows
+	}
+	return ends
+}
+
+// transactionEnd is the marker emitted once all rows of a transaction have
+// been flushed.
+type transactionEnd struct {
+	id   uuid.UUID
+	ts   hlc.Timestamp
+	rows map[sqlbase.ID]int64
+}
+
+// encodeTransactionEnd encodes a transaction end marker payload. Row counts
+// are keyed by the statement time name of each table.
+func encodeTransactionEnd(end transactionEnd, targets jobspb.ChangefeedTargets) ([]byte, error) {
+	rows := make(map[string]int64, len(end.rows))
+	for tableID, n := range end.rows {
+		name := targets[tableID].StatementTimeName
+		if name == `` {
+			// The table isn't a target anymore, which shouldn't happen, but the
+			// marker is still useful so fall back to the ID.
+			name = strconv.FormatUint(uint64(tableID), 10)
+		}
+		rows[name] += n
+	}
+	marker := map[string]interface{}{
+		`transaction_end`: map[string]interface{}{
+			`id`:        end.id.String(),
+			`timestamp`: end.ts.AsOfSystemTime(),
+			`rows`:      rows,
+		},
+	}
+	return gojson.Marshal(marker)
+}
+
+// transactionEndEncoder wraps an Encoder so that transaction end markers can be
+// written with Sink.EmitResolvedTimestamp, which fans them out to every topic
+// and partition the same way as resolved timestamps.
+type transactionEndEncoder struct {
+	Encoder
+	payload []byte
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *transactionEndEncoder) EncodeResolvedTimestamp(
+	context.Context, string, hlc.Timestamp,
+) ([]byte, error) {
+	return e.payload, nil
+}
+
+// emitTransactionEnds emits the end markers of all transactions that
+// committed at or before the given resolved timestamp.
+func emitTransactionEnds(
+	ctx context.Context,
+	encoder Encoder,
+	sink Sink,
+	txns *transactionAccumulator,
+	targets jobspb.ChangefeedTargets,
+	resolved hlc.Timestamp,
+) error {
+	for _, end := range txns.pop(resolved) {
+		payload, err := encodeTransactionEnd(end, targets)
+		if err != nil {
+			return err
+		}
+		e := &transactionEndEncoder{Encoder: encoder, payload: payload}
+		if err := sink.EmitResolvedTimestamp(ctx, e, end.ts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
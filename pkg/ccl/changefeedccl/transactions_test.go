@@ -0,0 +1,186 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gosql "database/sql"
+	gojson "encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	row := encodeRow{
+		datums: sqlbase.EncDatumRow{
+			sqlbase.EncDatum{Datum: tree.NewDInt(1)},
+			sqlbase.EncDatum{Datum: tree.NewDString(`bar`)},
+		},
+		updated:   hlc.Timestamp{WallTime: 1, Logical: 2},
+		tableDesc: tableDesc,
+		txnID:     uuid.FromStringOrNil(`00000000-0000-0000-0000-000000000007`),
+	}
+
+	e, err := getEncoder(map[string]string{
+		changefeedbase.OptEnvelope:     string(changefeedbase.OptEnvelopeWrapped),
+		changefeedbase.OptTransactions: ``,
+	})
+	require.NoError(t, err)
+	value, err := e.EncodeValue(context.TODO(), row)
+	require.NoError(t, err)
+	require.Equal(t, `{"after": {"a": 1, "b": "bar"}, "transaction": `+
+		`{"id": "00000000-0000-0000-0000-000000000007", "timestamp": "1.0000000002"}}`, string(value))
+
+	row.txnID = uuid.Nil
+	value, err = e.EncodeValue(context.TODO(), row)
+	require.NoError(t, err)
+	require.Equal(t, `{"after": {"a": 1, "b": "bar"}, "transaction": `+
+		`{"id": null, "timestamp": "1.0000000002"}}`, string(value))
+
+	_, err = getEncoder(map[string]string{
+		changefeedbase.OptEnvelope:     string(changefeedbase.OptEnvelopeRow),
+		changefeedbase.OptTransactions: ``,
+	})
+	require.EqualError(t, err, `transactions is only usable with envelope=wrapped`)
+
+	_, err = getEncoder(map[string]string{
+		changefeedbase.OptFormat:       string(changefeedbase.OptFormatAvro),
+		changefeedbase.OptEnvelope:     string(changefeedbase.OptEnvelopeWrapped),
+		changefeedbase.OptTransactions: ``,
+	})
+	require.EqualError(t, err, `transactions is not supported with format=experimental_avro`)
+}
+
+func TestTransactionAccumulator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fooDesc := &sqlbase.TableDescriptor{ID: 52, Name: `foo`}
+	barDesc := &sqlbase.TableDescriptor{ID: 53, Name: `bar`}
+	targets := jobspb.ChangefeedTargets{
+		52: {StatementTimeName: `foo`},
+		53: {StatementTimeName: `bar`},
+	}
+	txn1 := uuid.FromStringOrNil(`00000000-0000-0000-0000-000000000001`)
+	txn2 := uuid.FromStringOrNil(`00000000-0000-0000-0000-000000000002`)
+	ts1, ts2 := hlc.Timestamp{WallTime: 1}, hlc.Timestamp{WallTime: 2}
+
+	// Two aggregators each see part of txn1 and one of them sees all of txn2.
+	agg1, agg2 := makeTransactionTracker(), makeTransactionTracker()
+	agg1.rowEmitted(encodeRow{txnID: txn1, updated: ts1, tableDesc: fooDesc})
+	agg1.rowEmitted(encodeRow{txnID: txn1, updated: ts1, tableDesc: fooDesc})
+	agg1.rowEmitted(encodeRow{txnID: txn2, updated: ts2, tableDesc: fooDesc})
+	agg1.rowEmitted(encodeRow{txnID: uuid.Nil, updated: ts1, tableDesc: fooDesc})
+	agg2.rowEmitted(encodeRow{txnID: txn1, updated: ts1, tableDesc: barDesc})
+
+	acc := makeTransactionAccumulator()
+	acc.add(agg1.drain())
+	acc.add(agg2.drain())
+	require.Nil(t, agg1.drain())
+
+	require.Empty(t, acc.pop(hlc.Timestamp{}))
+
+	encode := func(ends []transactionEnd) []string {
+		var ret []string
+		for _, end := range ends {
+			payload, err := encodeTransactionEnd(end, targets)
+			require.NoError(t, err)
+			ret = append(ret, string(payload))
+		}
+		return ret
+	}
+	require.Equal(t, []string{
+		`{"transaction_end":{"id":"00000000-0000-0000-0000-000000000001",` +
+			`"rows":{"bar":1,"foo":2},"timestamp":"1.0000000000"}}`,
+	}, encode(acc.pop(ts1)))
+	require.Equal(t, []string{
+		`{"transaction_end":{"id":"00000000-0000-0000-0000-000000000002",` +
+			`"rows":{"foo":1},"timestamp":"2.0000000000"}}`,
+	}, encode(acc.pop(ts2)))
+	require.Empty(t, acc.pop(ts2))
+}
+
+func TestChangefeedTransactions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo, bar WITH transactions`)
+		defer closeFeed(t, foo)
+
+		type txnJSON struct {
+			ID        *string `json:"id"`
+			Timestamp string  `json:"timestamp"`
+		}
+		type rowJSON struct {
+			Transaction txnJSON `json:"transaction"`
+		}
+		type endJSON struct {
+			TransactionEnd *struct {
+				txnJSON
+				Rows map[string]int64 `json:"rows"`
+			} `json:"transaction_end"`
+		}
+
+		// Rows from the initial scan aren't tagged with a transaction ID.
+		m, err := foo.Next()
+		require.NoError(t, err)
+		var initial rowJSON
+		require.NoError(t, gojson.Unmarshal(m.Value, &initial))
+		require.Nil(t, initial.Transaction.ID)
+
+		sqlDB.Exec(t, `BEGIN; INSERT INTO foo VALUES (1, 'a'), (2, 'b'); `+
+			`INSERT INTO bar VALUES (1); COMMIT`)
+
+		// Every row of the transaction is tagged with the same ID and timestamp
+		// and the transaction end marker counts all of them.
+		var rows []rowJSON
+		var end endJSON
+		for len(rows) < 3 || end.TransactionEnd == nil {
+			m, err := foo.Next()
+			require.NoError(t, err)
+			if m.Resolved == nil {
+				var row rowJSON
+				require.NoError(t, gojson.Unmarshal(m.Value, &row))
+				rows = append(rows, row)
+			} else if end.TransactionEnd == nil {
+				require.NoError(t, gojson.Unmarshal(m.Resolved, &end))
+			}
+		}
+		require.Len(t, rows, 3)
+		for _, row := range rows {
+			require.NotNil(t, row.Transaction.ID)
+			require.Equal(t, *rows[0].Transaction.ID, *row.Transaction.ID)
+			require.Equal(t, rows[0].Transaction.Timestamp, row.Transaction.Timestamp)
+		}
+		require.Equal(t, *rows[0].Transaction.ID, *end.TransactionEnd.ID)
+		require.Equal(t, rows[0].Transaction.Timestamp, end.TransactionEnd.Timestamp)
+		require.Equal(t, map[string]int64{`foo`: 2, `bar`: 1}, end.TransactionEnd.Rows)
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
@@ -0,0 +1,216 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
+
+func newParquetWriterProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ParquetWriterSpec,
+	input execinfra.RowSource,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+
+	if err := utilccl.CheckEnterpriseEnabled(
+		flowCtx.Cfg.Settings,
+		flowCtx.Cfg.ClusterID.Get(),
+		sql.ClusterOrganization.Get(&flowCtx.Cfg.Settings.SV),
+		"EXPORT",
+	); err != nil {
+		return nil, err
+	}
+
+	c := &parquetWriter{
+		flowCtx:     flowCtx,
+		processorID: processorID,
+		spec:        spec,
+		input:       input,
+		output:      output,
+	}
+	if err := c.out.Init(&execinfrapb.PostProcessSpec{}, c.OutputTypes(), flowCtx.NewEvalCtx(), output); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parquetWriter is a processor that writes its input rows to Parquet files,
+// one file per spec.ChunkRows rows. The files are compressed page by page, so
+// unlike CSV files they keep the .parquet extension when compressed.
+type parquetWriter struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.ParquetWriterSpec
+	input       execinfra.RowSource
+	out         execinfra.ProcOutputHelper
+	output      execinfra.RowReceiver
+}
+
+var _ execinfra.Processor = &parquetWriter{}
+
+func (sp *parquetWriter) OutputTypes() []*types.T {
+	res := make([]*types.T, len(sqlbase.ExportColumns))
+	for i := range res {
+		res[i] = sqlbase.ExportColumns[i].Typ
+	}
+	return res
+}
+
+func (sp *parquetWriter) fileName(part string) string {
+	pattern := exportParquetFilePatternDefault
+	if sp.spec.NamePattern != "" {
+		pattern = sp.spec.NamePattern
+	}
+	return strings.Replace(pattern, exportFilePatternPart, part, -1)
+}
+
+func (sp *parquetWriter) Run(ctx context.Context) {
+	ctx, span := tracing.ChildSpan(ctx, "parquetWriter")
+	defer tracing.FinishSpan(span)
+
+	err := func() error {
+		typs := sp.input.OutputTypes()
+		if len(typs) != len(sp.spec.ColumnNames) {
+			return errors.AssertionFailedf("expected %d column names, got %d",
+				len(typs), len(sp.spec.ColumnNames))
+		}
+		sch, err := parquet.NewSchema(sp.spec.ColumnNames, typs)
+		if err != nil {
+			return err
+		}
+		opts := parquet.WriterOptions{}
+		if sp.spec.CompressionCodec == execinfrapb.FileCompression_Gzip {
+			opts.Compression = parquet.CompressionGzip
+		}
+
+		sp.input.Start(ctx)
+		input := execinfra.MakeNoMetadataRowSource(sp.input, sp.output)
+
+		alloc := &sqlbase.DatumAlloc{}
+		datums := make(tree.Datums, len(typs))
+		var buf bytes.Buffer
+
+		chunk := 0
+		done := false
+		for {
+			var rows int64
+			buf.Reset()
+			writer := parquet.NewWriter(sch, &buf, opts)
+			for {
+				if sp.spec.ChunkRows > 0 && rows >= sp.spec.ChunkRows {
+					break
+				}
+				row, err := input.NextRow()
+				if err != nil {
+					return err
+				}
+				if row == nil {
+					done = true
+					break
+				}
+				rows++
+
+				for i, ed := range row {
+					if err := ed.EnsureDecoded(typs[i], alloc); err != nil {
+						return err
+					}
+					datums[i] = ed.Datum
+				}
+				if err := writer.AddRow(datums); err != nil {
+					return err
+				}
+			}
+			if rows < 1 {
+				break
+			}
+			// Close writer to ensure the buffered rows and the footer are written.
+			if err := writer.Close(); err != nil {
+				return errors.Wrap(err, "failed to close parquet writer")
+			}
+
+			conf, err := cloud.ExternalStorageConfFromURI(sp.spec.Destination)
+			if err != nil {
+				return err
+			}
+			es, err := sp.flowCtx.Cfg.ExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+
+			nodeID, err := sp.flowCtx.EvalCtx.NodeID.OptionalNodeIDErr(47970)
+			if err != nil {
+				return err
+			}
+
+			part := fmt.Sprintf("n%d.%d", nodeID, chunk)
+			chunk++
+			filename := sp.fileName(part)
+			size := buf.Len()
+
+			if err := es.WriteFile(ctx, filename, bytes.NewReader(buf.Bytes())); err != nil {
+				return err
+			}
+			res := sqlbase.EncDatumRow{
+				sqlbase.DatumToEncDatum(
+					types.String,
+					tree.NewDString(filename),
+				),
+				sqlbase.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(rows)),
+				),
+				sqlbase.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(size)),
+				),
+			}
+
+			cs, err := sp.out.EmitRow(ctx, res)
+			if err != nil {
+				return err
+			}
+			if cs != execinfra.NeedMoreRows {
+				return errors.New("unexpected closure of consumer")
+			}
+			if done {
+				break
+			}
+		}
+
+		return nil
+	}()
+
+	execinfra.DrainAndClose(
+		ctx, sp.output, err, func(context.Context) {} /* pushTrailingMeta */, sp.input)
+}
+
+func init() {
+	rowexec.NewParquetWriterProcessor = newParquetWriterProcessor
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestExportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE foo (
+		i INT PRIMARY KEY, d DECIMAL(10, 2), ts TIMESTAMPTZ, j JSONB, a STRING[]
+	)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES
+		(1, 1.25, '2020-01-01 00:00:00+00', '{"a": 1}', ARRAY['x', NULL]),
+		(2, NULL, NULL, NULL, NULL),
+		(3, -3.5, '2020-01-02 00:00:00+00', '[]', ARRAY[])`)
+
+	for _, tc := range []struct {
+		name    string
+		options string
+		files   []string
+	}{
+		{name: `default`, files: []string{`n1.0.parquet`}},
+		{name: `chunked`, options: `WITH chunk_rows = 2`, files: []string{`n1.0.parquet`, `n1.1.parquet`}},
+		{name: `gzip`, options: `WITH compression = gzip`, files: []string{`n1.0.parquet`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var files []string
+			var rows int
+			for _, r := range sqlDB.QueryStr(t,
+				`EXPORT INTO PARQUET 'nodelocal://0/`+tc.name+`' `+tc.options+` FROM SELECT * FROM foo ORDER BY i`,
+			) {
+				files = append(files, r[0])
+				n, err := strconv.Atoi(r[1])
+				if err != nil {
+					t.Fatal(err)
+				}
+				rows += n
+			}
+			if len(files) != len(tc.files) {
+				t.Fatalf("expected files %v, got %v", tc.files, files)
+			}
+			for i, name := range tc.files {
+				if files[i] != name {
+					t.Fatalf("expected files %v, got %v", tc.files, files)
+				}
+				content, err := ioutil.ReadFile(filepath.Join(dir, tc.name, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.HasPrefix(content, []byte("PAR1")) || !bytes.HasSuffix(content, []byte("PAR1")) {
+					t.Fatalf("%s is not a parquet file", name)
+				}
+			}
+			if rows != 3 {
+				t.Fatalf("expected 3 rows, got %d", rows)
+			}
+		})
+	}
+
+	sqlDB.ExpectErr(t, `delimiter option is not supported with PARQUET format`,
+		`EXPORT INTO PARQUET 'nodelocal://0/bad' WITH delimiter = '|' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `column "t": type tuple{int, string} is not supported by the parquet format`,
+		`EXPORT INTO PARQUET 'nodelocal://0/bad' FROM SELECT (1, 'a') AS t`)
+}
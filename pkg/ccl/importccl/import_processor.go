@@ -155,6 +155,14 @@ func makeInputConverter(
 		return newAvroInputReader(
 			kvCh, singleTable, spec.Format.Avro, spec.WalltimeNanos,
 			int(spec.ReaderParallelism), evalCtx)
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetInputReader(
+			kvCh, singleTable, spec.Format.Parquet, spec.WalltimeNanos,
+			int(spec.ReaderParallelism), evalCtx), nil
+	case roachpb.IOFileFormat_NDJSON:
+		return newNDJSONInputReader(
+			kvCh, singleTable, spec.Format.NDJSON, spec.WalltimeNanos,
+			int(spec.ReaderParallelism), evalCtx), nil
 	default:
 		return nil, errors.Errorf(
 			"Requested IMPORT format (%d) not supported by this node", spec.Format.Format)
@@ -169,6 +177,7 @@ func ingestKvs(
 	spec *execinfrapb.ReadImportDataSpec,
 	progCh chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
 	kvCh <-chan row.KVBatch,
+	rejected *rejectedRows,
 ) (*roachpb.BulkOpSummary, error) {
 	ctx, span := tracing.ChildSpan(ctx, "ingestKVs")
 	defer tracing.FinishSpan(span)
@@ -273,6 +282,7 @@ func ingestKvs(
 			}
 			prog.CompletedFraction[file] = math.Float32frombits(atomic.LoadUint32(&writtenFraction[offset]))
 		}
+		prog.RejectedRows = rejected.counts(prog.ResumePos)
 		progCh <- prog
 	}
 
@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
@@ -38,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -67,12 +69,16 @@ const (
 	importOptionDisableGlobMatch = "disable_glob_matching"
 	importOptionSaveRejected     = "experimental_save_rejected"
 
+	importOptionRejectedRowsLimit       = "rejected_rows_limit"
+	importOptionRejectedRowsDestination = "rejected_rows_destination"
+
 	pgCopyDelimiter = "delimiter"
 	pgCopyNull      = "nullif"
 
 	optMaxRowSize = "max_row_size"
 
-	// Turn on strict validation when importing avro records.
+	// Turn on strict validation when importing avro records, parquet files or
+	// ndjson records.
 	avroStrict = "strict_validation"
 	// Default input format is assumed to be OCF (object container file).
 	// This default can be changed by specified either of these options.
@@ -103,6 +109,9 @@ var importOptionExpectValues = map[string]sql.KVStringOptValidate{
 	importOptionOversample:   sql.KVStringOptRequireValue,
 	importOptionSaveRejected: sql.KVStringOptRequireNoValue,
 
+	importOptionRejectedRowsLimit:       sql.KVStringOptRequireValue,
+	importOptionRejectedRowsDestination: sql.KVStringOptRequireValue,
+
 	importOptionSkipFKs:          sql.KVStringOptRequireNoValue,
 	importOptionDisableGlobMatch: sql.KVStringOptRequireNoValue,
 
@@ -127,7 +136,8 @@ func makeStringSet(opts ...string) map[string]struct{} {
 // Options common to all formats.
 var allowedCommonOptions = makeStringSet(
 	importOptionSSTSize, importOptionDecompress, importOptionOversample,
-	importOptionSaveRejected, importOptionDisableGlobMatch)
+	importOptionSaveRejected, importOptionDisableGlobMatch,
+	importOptionRejectedRowsLimit, importOptionRejectedRowsDestination)
 
 // Format specific allowed options.
 var avroAllowedOptions = makeStringSet(
@@ -144,6 +154,8 @@ var mysqlOutAllowedOptions = makeStringSet(
 var mysqlDumpAllowedOptions = makeStringSet(importOptionSkipFKs)
 var pgCopyAllowedOptions = makeStringSet(pgCopyDelimiter, pgCopyNull, optMaxRowSize)
 var pgDumpAllowedOptions = makeStringSet(optMaxRowSize, importOptionSkipFKs)
+var parquetAllowedOptions = makeStringSet(avroStrict)
+var ndjsonAllowedOptions = makeStringSet(avroStrict, optMaxRowSize)
 
 func validateFormatOptions(
 	format string, specified map[string]string, formatAllowed map[string]struct{},
@@ -183,6 +195,13 @@ func importJobDescription(
 		val := importOptionExpectValues[k] == sql.KVStringOptRequireValue
 		val = val || (importOptionExpectValues[k] == sql.KVStringOptAny && len(v) > 0)
 		if val {
+			if k == importOptionRejectedRowsDestination {
+				clean, err := cloud.SanitizeExternalStorageURI(v, nil /* extraParams */)
+				if err != nil {
+					return "", err
+				}
+				v = clean
+			}
 			opt.Value = tree.NewDString(v)
 		}
 		stmt.Options = append(stmt.Options, opt)
@@ -490,6 +509,31 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			telemetry.Count("import.format.parquet")
+			format.Format = roachpb.IOFileFormat_Parquet
+			_, format.Parquet.StrictMode = opts[avroStrict]
+		case "NDJSON":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, ndjsonAllowedOptions); err != nil {
+				return err
+			}
+			telemetry.Count("import.format.ndjson")
+			format.Format = roachpb.IOFileFormat_NDJSON
+			_, format.NDJSON.StrictMode = opts[avroStrict]
+			format.NDJSON.MaxRecordSize = int32(defaultScanBuffer)
+			if override, ok := opts[optMaxRowSize]; ok {
+				sz, err := humanizeutil.ParseBytes(override)
+				if err != nil {
+					return err
+				}
+				if sz < 1 || sz > math.MaxInt32 {
+					return errors.Errorf("%s out of range: %d", override, sz)
+				}
+				format.NDJSON.MaxRecordSize = int32(sz)
+			}
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -533,6 +577,29 @@ func importPlanHook(
 			}
 		}
 
+		if override, ok := opts[importOptionRejectedRowsLimit]; ok {
+			limit, err := strconv.ParseInt(override, 10, 64)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid %s value", importOptionRejectedRowsLimit)
+			}
+			if limit < 1 {
+				return pgerror.Newf(pgcode.Syntax, "%s must be > 0", importOptionRejectedRowsLimit)
+			}
+			telemetry.Count("import.rejected_rows_limit")
+			format.RejectedRowsLimit = limit
+		}
+		if dest, ok := opts[importOptionRejectedRowsDestination]; ok {
+			if _, err := cloud.ExternalStorageConfFromURI(dest); err != nil {
+				return pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+					"invalid %s value", importOptionRejectedRowsDestination)
+			}
+			telemetry.Count("import.rejected_rows_destination")
+			format.RejectedRowsDestination = dest
+		}
+		if format.RejectedRowsLimit == 0 && (format.SaveRejected || format.RejectedRowsDestination != "") {
+			format.RejectedRowsLimit = defaultRejectedRowsLimit
+		}
+
 		var tableDetails []jobspb.ImportDetails_Table
 		jobDesc, err := importJobDescription(p, importStmt, nil, filenamePatterns, opts)
 		if err != nil {
@@ -1103,6 +1170,11 @@ func (r *importResumer) Resume(
 
 	res, err := sql.DistIngest(ctx, p, r.job, tables, files, format, walltime, r.testingKnobs.alwaysFlushJobProgress)
 	if err != nil {
+		// The ingestion fails when one of its nodes does, but the job can resume
+		// from its progress, so it's retried.
+		if grpcutil.IsClosedConnection(err) || errors.HasType(err, (*roachpb.NodeUnavailableError)(nil)) {
+			return jobs.NewRetryJobError(err.Error())
+		}
 		return err
 	}
 	pkIDs := make(map[uint64]struct{}, len(details.Tables))
@@ -1405,4 +1477,12 @@ func init() {
 			}
 		},
 	)
+	// Imports are retried after node failures, from their progress. An import
+	// which keeps failing is given up on rather than holding on to its offline
+	// tables.
+	jobs.RegisterRetryPolicy(jobspb.TypeImport, jobs.RetryPolicy{
+		MaxRetries:     10,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+	})
 }
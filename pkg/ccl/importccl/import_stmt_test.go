@@ -10,8 +10,10 @@ package importccl
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	gosql "database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,6 +22,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -3800,6 +3803,194 @@ func TestImportAvro(t *testing.T) {
 	}
 }
 
+func TestImportParquetAndNDJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	baseDir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	tc := testcluster.StartTestCluster(
+		t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{ExternalIODir: baseDir}})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.Conns[0])
+
+	sqlDB.Exec(t, `CREATE TABLE src (
+		i INT PRIMARY KEY, d DECIMAL(10, 2), ts TIMESTAMPTZ, j JSONB, a STRING[], s STRING
+	)`)
+	sqlDB.Exec(t, `INSERT INTO src VALUES
+		(1, 1.25, '2020-01-01 00:00:00+00', '{"a": {"b": [1, 2]}}', ARRAY['x', NULL], 'one'),
+		(2, NULL, NULL, NULL, NULL, NULL),
+		(3, -3.5, '2020-01-02 00:00:00+00', '[]', ARRAY[], 'three')`)
+	expected := sqlDB.QueryStr(t, `SELECT * FROM src ORDER BY i`)
+
+	t.Run("parquet", func(t *testing.T) {
+		sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/parquet' WITH chunk_rows = 2 FROM SELECT * FROM src`)
+		sqlDB.Exec(t, `IMPORT TABLE pq (
+			i INT PRIMARY KEY, d DECIMAL(10, 2), ts TIMESTAMPTZ, j JSONB, a STRING[], s STRING
+		) PARQUET DATA ('nodelocal://0/parquet/n1.0.parquet', 'nodelocal://0/parquet/n1.1.parquet')`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM pq ORDER BY i`, expected)
+
+		// Columns are matched by name, and the file's values are converted to the
+		// type of the table's columns.
+		sqlDB.Exec(t, `CREATE TABLE pq_into (s STRING, i STRING PRIMARY KEY, extra INT)`)
+		sqlDB.Exec(t, `IMPORT INTO pq_into PARQUET DATA ('nodelocal://0/parquet/n1.*.parquet')`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM pq_into ORDER BY i`,
+			[][]string{{"one", "1", "NULL"}, {"NULL", "2", "NULL"}, {"three", "3", "NULL"}})
+
+		sqlDB.ExpectErr(t, `could not find column for parquet column d`,
+			`IMPORT TABLE pq_strict (i INT PRIMARY KEY) PARQUET DATA ('nodelocal://0/parquet/n1.0.parquet')
+			WITH strict_validation`)
+		sqlDB.ExpectErr(t, `invalid option "delimiter" specified for PARQUET import format`,
+			`IMPORT TABLE pq_bad (i INT PRIMARY KEY) PARQUET DATA ('nodelocal://0/parquet/n1.0.parquet')
+			WITH delimiter = '|'`)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(`{"i": 1, "d": 1.25, "ts": "2020-01-01 00:00:00+00", "j": {"a": {"b": [1, 2]}}, "a": ["x", null], "s": "one"}
+{"i": 2}
+
+{"i": "3", "d": "-3.5", "ts": "2020-01-02T00:00:00Z", "j": [], "a": [], "s": "three", "ignored": {"x": 1}}
+`))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		require.NoError(t, ioutil.WriteFile(filepath.Join(baseDir, "data.ndjson.gz"), buf.Bytes(), 0644))
+
+		sqlDB.Exec(t, `IMPORT TABLE nd (
+			i INT PRIMARY KEY, d DECIMAL(10, 2), ts TIMESTAMPTZ, j JSONB, a STRING[], s STRING
+		) NDJSON DATA ('nodelocal://0/data.ndjson.gz')`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM nd ORDER BY i`, expected)
+
+		// Both the second and the third line violate strict validation, and
+		// either can be read first.
+		sqlDB.ExpectErr(t, `field d was not set in the ndjson import|could not find column for record field ignored`,
+			`IMPORT TABLE nd_strict (
+				i INT PRIMARY KEY, d DECIMAL(10, 2), ts TIMESTAMPTZ, j JSONB, a STRING[], s STRING
+			) NDJSON DATA ('nodelocal://0/data.ndjson.gz') WITH strict_validation`)
+		sqlDB.ExpectErr(t, `line longer than max_row_size`,
+			`IMPORT TABLE nd_small (i INT PRIMARY KEY) NDJSON DATA ('nodelocal://0/data.ndjson.gz')
+			WITH max_row_size = '16B'`)
+	})
+}
+
+func TestImportRejectedRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	baseDir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	tc := testcluster.StartTestCluster(
+		t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{ExternalIODir: baseDir}})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.Conns[0])
+
+	tests := []struct {
+		typ, file, data, with string
+		rejected              []rejectedRow
+	}{
+		{
+			typ:  "CSV",
+			file: "data.csv",
+			data: "1,a\nnot_int,b\n2,c\n3,d,extra\n4,e\"f\n",
+			with: "strict_quotes",
+			rejected: []rejectedRow{
+				{Row: 2, Data: "not_int,b", Error: `parse "i" as INT8: could not parse "not_int" as type int`},
+				{Row: 4, Data: "3,d,extra", Error: "expected 2 fields, got 3"},
+				{Row: 5, Data: `4,e"f`, Error: `reading CSV record: parse error on line 5, column 3: bare " in non-quoted-field`},
+			},
+		},
+		{
+			typ:  "PGCOPY",
+			file: "data.copy",
+			data: "1\ta\nnot_int\tb\n2\tc\n3\td\textra\n",
+			rejected: []rejectedRow{
+				{Row: 2, Data: "not_int\tb", Error: `could not parse "not_int" as type int`},
+				{Row: 4, Data: "3\td\textra", Error: "expected 2 values, got 3"},
+			},
+		},
+		{
+			typ:  "NDJSON",
+			file: "data.ndjson",
+			data: `{"i": 1, "s": "a"}
+{"i": "not_int", "s": "b"}
+{"i": 2, "s": "c"}
+[3, "d"]
+`,
+			rejected: []rejectedRow{
+				{Row: 2, Data: `{"i": "not_int", "s": "b"}`, Error: `could not parse "not_int" as type int`},
+				{Row: 4, Data: `[3, "d"]`, Error: "record is not a JSON object"},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.typ, func(t *testing.T) {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(baseDir, test.file), []byte(test.data), 0644))
+			fileURI := fmt.Sprintf("nodelocal://0/%s", test.file)
+			with := func(opts ...string) string {
+				if test.with != "" {
+					opts = append(opts, test.with)
+				}
+				if len(opts) == 0 {
+					return ""
+				}
+				return "WITH " + strings.Join(opts, ", ")
+			}
+
+			// Without a limit, the first bad row fails the import.
+			sqlDB.ExpectErr(t, `row [245]`, fmt.Sprintf(
+				`IMPORT TABLE t%d_strict (i INT PRIMARY KEY, s STRING) %s DATA ($1) %s`,
+				i, test.typ, with()), fileURI)
+
+			// Going over the limit fails the import too.
+			sqlDB.ExpectErr(t, `too many parsing errors \(2\) encountered`, fmt.Sprintf(
+				`IMPORT TABLE t%d_limit (i INT PRIMARY KEY, s STRING) %s DATA ($1) %s`,
+				i, test.typ, with(`rejected_rows_limit = '1'`)), fileURI)
+
+			// The limit applies to the rows rejected from all the files of the
+			// import, not to each file.
+			limit := fmt.Sprintf(`rejected_rows_limit = '%d'`, len(test.rejected))
+			sqlDB.ExpectErr(t, fmt.Sprintf(`too many parsing errors \(%d\) encountered`, len(test.rejected)+1),
+				fmt.Sprintf(`IMPORT TABLE t%d_files (i INT, s STRING) %s DATA ($1, $1) %s`,
+					i, test.typ, with(limit)), fileURI)
+
+			dest := fmt.Sprintf("rejected-%d", i)
+			sqlDB.Exec(t, fmt.Sprintf(
+				`IMPORT TABLE t%d (i INT PRIMARY KEY, s STRING) %s DATA ($1) %s`,
+				i, test.typ, with(limit, `rejected_rows_destination = $2`)),
+				fileURI, "nodelocal://0/"+dest)
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT * FROM t%d ORDER BY i`, i),
+				[][]string{{"1", "a"}, {"2", "c"}})
+
+			// Each rejected row is saved to a file of its own.
+			files, err := ioutil.ReadDir(filepath.Join(baseDir, dest))
+			require.NoError(t, err)
+			var rejected []rejectedRow
+			for _, f := range files {
+				content, err := ioutil.ReadFile(filepath.Join(baseDir, dest, f.Name()))
+				require.NoError(t, err)
+				var r rejectedRow
+				require.NoError(t, json.Unmarshal(content, &r))
+				require.Equal(t, fmt.Sprintf("0-%s.%d.rejected.ndjson", test.file, r.Row), f.Name())
+				rejected = append(rejected, r)
+			}
+			// Rows are rejected by parallel workers, in no particular order.
+			sort.Slice(rejected, func(i, j int) bool { return rejected[i].Row < rejected[j].Row })
+			require.Len(t, rejected, len(test.rejected))
+			for j, expected := range test.rejected {
+				require.Equal(t, fileURI, rejected[j].File)
+				require.Equal(t, expected.Row, rejected[j].Row)
+				require.Equal(t, expected.Data, rejected[j].Data)
+				require.Contains(t, rejected[j].Error, expected.Error)
+			}
+		})
+	}
+
+	sqlDB.ExpectErr(t, `rejected_rows_limit must be > 0`,
+		`IMPORT TABLE bad (i INT) CSV DATA ('nodelocal://0/data.csv') WITH rejected_rows_limit = '0'`)
+	sqlDB.ExpectErr(t, `invalid rejected_rows_destination value`,
+		`IMPORT TABLE bad (i INT) CSV DATA ('nodelocal://0/data.csv') WITH rejected_rows_destination = 'nope://x'`)
+}
+
 // TestImportClientDisconnect ensures that an import job can complete even if
 // the client connection which started it closes. This test uses a helper
 // subprocess to force a closed client connection without needing to rely
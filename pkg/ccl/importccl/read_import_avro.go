@@ -473,12 +473,13 @@ func (a *avroInputReader) readFiles(
 	resumePos map[int32]int64,
 	format roachpb.IOFileFormat,
 	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
 ) error {
-	return readInputFiles(ctx, dataFiles, resumePos, format, a.readFile, makeExternalStorage)
+	return readInputFiles(ctx, dataFiles, resumePos, format, a.readFile, makeExternalStorage, rejected)
 }
 
 func (a *avroInputReader) readFile(
-	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected rejectRowFunc,
 ) error {
 	producer, consumer, err := newImportAvroPipeline(a, input)
 	if err != nil {
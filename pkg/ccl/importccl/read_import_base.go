@@ -13,11 +13,13 @@ import (
 	"compress/bzip2"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math"
 	"net/url"
+	"path"
 	"runtime"
 	"strings"
 	"sync/atomic"
@@ -34,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
 )
@@ -51,6 +54,17 @@ func runImport(
 		return nil, err
 	}
 
+	// Rows that can't be imported are skipped if there's a limit on how many of
+	// them can be rejected.
+	var rejected *rejectedRows
+	if spec.Format.RejectedRowsLimit > 0 {
+		rejected, err = newRejectedRows(ctx, spec.Format, flowCtx.Cfg.ExternalStorage)
+		if err != nil {
+			return nil, err
+		}
+		defer rejected.close()
+	}
+
 	// This group holds the go routines that are responsible for producing KV batches.
 	// After this group is done, we need to close kvCh.
 	// Depending on the import implementation both conv.start and conv.readFiles can
@@ -74,7 +88,8 @@ func runImport(
 			inputs = spec.Uri
 		}
 
-		return conv.readFiles(ctx, inputs, spec.ResumePos, spec.Format, flowCtx.Cfg.ExternalStorage)
+		return conv.readFiles(
+			ctx, inputs, spec.ResumePos, spec.Format, flowCtx.Cfg.ExternalStorage, rejected)
 	})
 
 	// This group links together the producers (via producerGroup) and the KV ingester.
@@ -88,7 +103,7 @@ func runImport(
 	// at the end is one row containing an encoded BulkOpSummary.
 	var summary *roachpb.BulkOpSummary
 	group.GoCtx(func(ctx context.Context) error {
-		summary, err = ingestKvs(ctx, flowCtx, spec, progCh, kvCh)
+		summary, err = ingestKvs(ctx, flowCtx, spec, progCh, kvCh, rejected)
 		if err != nil {
 			return err
 		}
@@ -99,6 +114,7 @@ func runImport(
 			prog.CompletedFraction[i] = 1.0
 			prog.ResumePos[i] = math.MaxInt64
 		}
+		prog.RejectedRows = rejected.counts(prog.ResumePos)
 		progCh <- prog
 		return nil
 	})
@@ -110,7 +126,7 @@ func runImport(
 	return summary, nil
 }
 
-type readFileFunc func(context.Context, *fileReader, int32, int64, chan string) error
+type readFileFunc func(context.Context, *fileReader, int32, int64, rejectRowFunc) error
 
 // readInputFile reads each of the passed dataFiles using the passed func. The
 // key part of dataFiles is the unique index of the data file among all files in
@@ -127,6 +143,7 @@ func readInputFiles(
 	format roachpb.IOFileFormat,
 	fileFunc readFileFunc,
 	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
 ) error {
 	done := ctx.Done()
 
@@ -174,7 +191,12 @@ func readInputFiles(
 			}
 			defer raw.Close()
 
-			src := &fileReader{total: fileSizes[dataFileIndex], counter: byteCounter{r: raw}}
+			src := &fileReader{
+				total:       fileSizes[dataFileIndex],
+				counter:     byteCounter{r: raw},
+				store:       es,
+				compression: guessCompressionFromName(dataFile, format.Compression),
+			}
 			decompressed, err := decompressingReader(&src.counter, dataFile, format.Compression)
 			if err != nil {
 				return err
@@ -182,67 +204,18 @@ func readInputFiles(
 			defer decompressed.Close()
 			src.Reader = decompressed
 
-			var rejected chan string
-			if (format.Format == roachpb.IOFileFormat_CSV && format.SaveRejected) ||
-				(format.Format == roachpb.IOFileFormat_MysqlOutfile && format.SaveRejected) {
-				rejected = make(chan string)
-			}
+			var rejectRow rejectRowFunc
 			if rejected != nil {
-				grp := ctxgroup.WithContext(ctx)
-				grp.GoCtx(func(ctx context.Context) error {
-					var buf []byte
-					var countRejected int64
-					for s := range rejected {
-						countRejected++
-						if countRejected > 1000 { // TODO(spaskob): turn the magic constant into an option
-							return pgerror.Newf(
-								pgcode.DataCorrupted,
-								"too many parsing errors (%d) encountered for file %s",
-								countRejected,
-								dataFile,
-							)
-						}
-						buf = append(buf, s...)
-					}
-					if countRejected == 0 {
-						// no rejected rows
-						return nil
-					}
-					rejFn, err := rejectedFilename(dataFile)
-					if err != nil {
-						return err
-					}
-					conf, err := cloud.ExternalStorageConfFromURI(rejFn)
-					if err != nil {
-						return err
-					}
-					rejectedStorage, err := makeExternalStorage(ctx, conf)
-					if err != nil {
-						return err
-					}
-					defer rejectedStorage.Close()
-					if err := rejectedStorage.WriteFile(ctx, "", bytes.NewReader(buf)); err != nil {
-						return err
-					}
-					return nil
-				})
-
-				grp.GoCtx(func(ctx context.Context) error {
-					defer close(rejected)
-					if err := fileFunc(ctx, src, dataFileIndex, resumePos[dataFileIndex], rejected); err != nil {
-						return err
-					}
-					return nil
-				})
-
-				if err := grp.Wait(); err != nil {
-					return errors.Wrap(err, dataFile)
-				}
-			} else {
-				if err := fileFunc(ctx, src, dataFileIndex, resumePos[dataFileIndex], nil /* rejected */); err != nil {
-					return errors.Wrap(err, dataFile)
+				rejectRow = func(ctx context.Context, rowErr *importRowError) error {
+					return rejected.reject(ctx, dataFile, dataFileIndex, rowErr)
 				}
 			}
+			if err := fileFunc(ctx, src, dataFileIndex, resumePos[dataFileIndex], rejectRow); err != nil {
+				return errors.Wrap(err, dataFile)
+			}
+			if err := rejected.saveRaw(ctx, dataFile, dataFileIndex); err != nil {
+				return errors.Wrap(err, dataFile)
+			}
 			return nil
 		}(); err != nil {
 			return err
@@ -251,6 +224,191 @@ func readInputFiles(
 	return nil
 }
 
+// defaultRejectedRowsLimit is the number of rows that can be rejected by an
+// import when rejected rows are saved but no rejected_rows_limit is given.
+const defaultRejectedRowsLimit = 1000
+
+// rejectRowFunc is called with each row rejected while reading an input file.
+// It returns an error if the row can't be skipped.
+type rejectRowFunc func(context.Context, *importRowError) error
+
+// rejectedRow is the record written to the rejected_rows_destination for each
+// rejected row.
+type rejectedRow struct {
+	File  string `json:"file"`
+	Row   int64  `json:"row"`
+	Error string `json:"error"`
+	Data  string `json:"data"`
+}
+
+// rejectedRows saves and counts the rows rejected by the readers of an import
+// processor.
+//
+// A rejected row is written to the rejected_rows_destination before the reader
+// moves past it, so the resume position of an input file never passes a row
+// whose record hasn't been saved. Each record is written to a file of its own,
+// named after the row, so a row that is rejected again after the import
+// resumes overwrites the record saved for it before.
+type rejectedRows struct {
+	format              roachpb.IOFileFormat
+	makeExternalStorage cloud.ExternalStorageFactory
+	// dest is the rejected_rows_destination, or nil if none was given.
+	dest cloud.ExternalStorage
+
+	mu struct {
+		syncutil.Mutex
+		// count is the number of rows rejected by the processor.
+		count int64
+		// rows holds the numbers of the rows rejected from each input file.
+		rows map[int32][]int64
+		// raw holds the raw data of the rows rejected from each input file,
+		// which is written next to the file once it has been read if
+		// format.SaveRejected is set.
+		raw map[int32][]byte
+	}
+}
+
+func newRejectedRows(
+	ctx context.Context,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+) (*rejectedRows, error) {
+	r := &rejectedRows{format: format, makeExternalStorage: makeExternalStorage}
+	r.mu.rows = make(map[int32][]int64)
+	r.mu.raw = make(map[int32][]byte)
+	if format.RejectedRowsDestination != "" {
+		conf, err := cloud.ExternalStorageConfFromURI(format.RejectedRowsDestination)
+		if err != nil {
+			return nil, err
+		}
+		if r.dest, err = makeExternalStorage(ctx, conf); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *rejectedRows) close() {
+	if r.dest != nil {
+		r.dest.Close()
+	}
+}
+
+// reject records that the given row of dataFile was rejected, failing once the
+// processor has rejected more rows than the rejected_rows_limit allows. The
+// limit applies to all the files of the import, which the coordinator of the
+// import enforces too, since the processor only counts the rows it rejected.
+func (r *rejectedRows) reject(
+	ctx context.Context, dataFile string, dataFileIndex int32, rowErr *importRowError,
+) error {
+	if err := func() error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.mu.count++
+		if r.mu.count > r.format.RejectedRowsLimit {
+			return pgerror.Wrapf(rowErr, pgcode.DataCorrupted,
+				"too many parsing errors (%d) encountered", r.mu.count)
+		}
+		r.mu.rows[dataFileIndex] = append(r.mu.rows[dataFileIndex], rowErr.rowNum)
+		if r.format.SaveRejected {
+			r.mu.raw[dataFileIndex] = append(r.mu.raw[dataFileIndex], rowErr.row...)
+			r.mu.raw[dataFileIndex] = append(r.mu.raw[dataFileIndex], '\n')
+		}
+		return nil
+	}(); err != nil {
+		return err
+	}
+	if r.dest == nil {
+		return nil
+	}
+
+	// Don't leak credentials of the data file into rejected rows.
+	sanitizedFile, err := cloud.SanitizeExternalStorageURI(dataFile, nil /* extraParams */)
+	if err != nil {
+		return err
+	}
+	record, err := json.Marshal(rejectedRow{
+		File: sanitizedFile, Row: rowErr.rowNum, Error: rowErr.err.Error(), Data: rowErr.row,
+	})
+	if err != nil {
+		return err
+	}
+	name, err := rejectedRowFilename(dataFile, dataFileIndex, rowErr.rowNum)
+	if err != nil {
+		return err
+	}
+	return r.dest.WriteFile(ctx, name, bytes.NewReader(append(record, '\n')))
+}
+
+// saveRaw writes the raw data of the rows rejected from dataFile next to it if
+// format.SaveRejected is set. If the import resumed in the middle of the file,
+// only the rows rejected after the resume position are written.
+func (r *rejectedRows) saveRaw(ctx context.Context, dataFile string, dataFileIndex int32) error {
+	if r == nil || !r.format.SaveRejected {
+		return nil
+	}
+	r.mu.Lock()
+	raw := r.mu.raw[dataFileIndex]
+	delete(r.mu.raw, dataFileIndex)
+	r.mu.Unlock()
+	if len(raw) == 0 {
+		return nil
+	}
+
+	rejFn, err := rejectedFilename(dataFile)
+	if err != nil {
+		return err
+	}
+	conf, err := cloud.ExternalStorageConfFromURI(rejFn)
+	if err != nil {
+		return err
+	}
+	rejectedStorage, err := r.makeExternalStorage(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer rejectedStorage.Close()
+	return rejectedStorage.WriteFile(ctx, "", bytes.NewReader(raw))
+}
+
+// counts returns the number of rows rejected from each of the input files in
+// resumePos before its resume position, which are the rows that won't be read
+// again if the import resumes.
+func (r *rejectedRows) counts(resumePos map[int32]int64) map[int32]int64 {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[int32]int64, len(resumePos))
+	for file, pos := range resumePos {
+		var count int64
+		for _, row := range r.mu.rows[file] {
+			if row <= pos {
+				count++
+			}
+		}
+		counts[file] = count
+	}
+	return counts
+}
+
+// rejectedRowFilename returns the name of the file in the
+// rejected_rows_destination to which the given rejected row of the data file
+// is written. The index of the data file in the import keeps the names of
+// files with the same base name apart.
+func rejectedRowFilename(datafile string, dataFileIndex int32, rowNum int64) (string, error) {
+	parsedURI, err := url.Parse(datafile)
+	if err != nil {
+		return "", err
+	}
+	base := path.Base(parsedURI.Path)
+	if base == "." || base == "/" {
+		base = "data"
+	}
+	return fmt.Sprintf("%d-%s.%d.rejected.ndjson", dataFileIndex, base, rowNum), nil
+}
+
 func rejectedFilename(datafile string) (string, error) {
 	parsedURI, err := url.Parse(datafile)
 	if err != nil {
@@ -307,6 +465,12 @@ type fileReader struct {
 	io.Reader
 	total   int64
 	counter byteCounter
+	// store is the storage the file is read from, and compression the
+	// compression of the file. They let formats which need random access to the
+	// file read ranges of it from store instead of streaming it, if it isn't
+	// compressed.
+	store       cloud.ExternalStorage
+	compression roachpb.IOFileFormat_Compression
 }
 
 func (f fileReader) ReadFraction() float32 {
@@ -324,6 +488,7 @@ type inputConverter interface {
 		resumePos map[int32]int64,
 		format roachpb.IOFileFormat,
 		makeExternalStorage cloud.ExternalStorageFactory,
+		rejected *rejectedRows,
 	) error
 }
 
@@ -385,9 +550,9 @@ type parallelImportContext struct {
 
 // importFileContext describes state specific to a file being imported.
 type importFileContext struct {
-	source   int32       // Source is where the row data in the batch came from.
-	skip     int64       // Number of records to skip
-	rejected chan string // Channel for reporting corrupt "rows"
+	source   int32         // Source is where the row data in the batch came from.
+	skip     int64         // Number of records to skip
+	rejected rejectRowFunc // Called with each corrupt "row"
 }
 
 // handleCorruptRow reports an error encountered while processing a row
@@ -396,8 +561,7 @@ func handleCorruptRow(ctx context.Context, fileCtx *importFileContext, err error
 	log.Errorf(ctx, "%v", err)
 
 	if rowErr, isRowErr := err.(*importRowError); isRowErr && fileCtx.rejected != nil {
-		fileCtx.rejected <- rowErr.row + "\n"
-		return nil
+		return fileCtx.rejected(ctx, rowErr)
 	}
 
 	return err
@@ -615,7 +779,15 @@ func (p *parallelImporter) importWorker(
 		conv.KvBatch.Progress = batch.progress
 		for batchIdx, record := range batch.data {
 			rowNum = batch.startPos + int64(batchIdx)
+			// Consumers only fill in the datums of the values present in the
+			// record, so don't let values of the previous row leak into this one.
+			for i := range conv.Datums {
+				conv.Datums[i] = nil
+			}
 			if err := consumer.FillDatums(record, rowNum, conv); err != nil {
+				if _, isRowErr := err.(*importRowError); !isRowErr && !errors.IsAssertionFailure(err) {
+					err = newImportRowError(err, fmt.Sprintf("%v", record), rowNum)
+				}
 				if err = handleCorruptRow(ctx, fileCtx, err); err != nil {
 					return err
 				}
@@ -624,7 +796,13 @@ func (p *parallelImporter) importWorker(
 
 			rowIndex := int64(timestamp) + rowNum
 			if err := conv.Row(ctx, conv.KvBatch.Source, rowIndex); err != nil {
-				return newImportRowError(err, fmt.Sprintf("%v", record), rowNum)
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				err = newImportRowError(err, fmt.Sprintf("%v", record), rowNum)
+				if err = handleCorruptRow(ctx, fileCtx, err); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -42,3 +42,26 @@ func TestRejectedFilename(t *testing.T) {
 		}
 	}
 }
+
+func TestRejectedRowFilename(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tests := []struct {
+		fname    string
+		idx      int32
+		row      int64
+		filename string
+	}{
+		{fname: "http://127.0.0.1", idx: 0, row: 1, filename: "0-data.1.rejected.ndjson"},
+		{fname: "nodelocal://0/a/file.csv", idx: 3, row: 20, filename: "3-file.csv.20.rejected.ndjson"},
+		{fname: "s3://bucket/file.csv.gz?AWS_SECRET_ACCESS_KEY=x", idx: 1, row: 5, filename: "1-file.csv.gz.5.rejected.ndjson"},
+	}
+	for _, tc := range tests {
+		filename, err := rejectedRowFilename(tc.fname, tc.idx, tc.row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.filename != filename {
+			t.Errorf("expected:\n%v\ngot:\n%v\n", tc.filename, filename)
+		}
+	}
+}
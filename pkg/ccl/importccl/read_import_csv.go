@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -26,6 +27,10 @@ import (
 type csvInputReader struct {
 	importCtx *parallelImportContext
 	opts      roachpb.CSVOptions
+	// rejectMalformed is set if records which can't be parsed as CSV at all are
+	// rejected like any other bad row. With experimental_save_rejected, they
+	// still fail the import.
+	rejectMalformed bool
 }
 
 var _ inputConverter = &csvInputReader{}
@@ -61,14 +66,16 @@ func (c *csvInputReader) readFiles(
 	resumePos map[int32]int64,
 	format roachpb.IOFileFormat,
 	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
 ) error {
-	return readInputFiles(ctx, dataFiles, resumePos, format, c.readFile, makeExternalStorage)
+	c.rejectMalformed = !format.SaveRejected
+	return readInputFiles(ctx, dataFiles, resumePos, format, c.readFile, makeExternalStorage, rejected)
 }
 
 func (c *csvInputReader) readFile(
-	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected rejectRowFunc,
 ) error {
-	producer, consumer := newCSVPipeline(c, input)
+	producer, consumer := newCSVPipeline(c, input, rejected != nil && c.rejectMalformed)
 
 	if resumePos < int64(c.opts.Skip) {
 		resumePos = int64(c.opts.Skip)
@@ -92,6 +99,15 @@ type csvRowProducer struct {
 	record          []string
 	progress        func() float32
 	expectedColumns tree.NameList
+	// raw, if set, keeps the raw data of the current record so that a record
+	// which can't be parsed can be rejected. Otherwise such a record fails the
+	// import.
+	raw *rawRecordReader
+	// parseErr is set if the current record is malformed. Unlike err, it only
+	// affects the current record, which is reported as a corrupt row along with
+	// its raw data in rawRecord.
+	parseErr  error
+	rawRecord string
 }
 
 var _ importRowProducer = &csvRowProducer{}
@@ -99,12 +115,24 @@ var _ importRowProducer = &csvRowProducer{}
 // Scan() implements importRowProducer interface.
 func (p *csvRowProducer) Scan() bool {
 	p.record, p.err = p.csv.Read()
+	p.parseErr = nil
+	var raw []byte
+	if p.raw != nil {
+		raw = p.raw.next(p.csv.InputOffset())
+	}
 
 	if p.err == io.EOF {
 		p.err = nil
 		return false
 	}
 
+	// The reader carries on with the next record after a parse error.
+	var parseErr *csv.ParseError
+	if errors.As(p.err, &parseErr) {
+		p.parseErr, p.err = p.err, nil
+		p.rawRecord = strings.Trim(string(raw), "\r\n")
+	}
+
 	return p.err == nil
 }
 
@@ -130,6 +158,16 @@ func strRecord(record []string, sep rune) string {
 // Row() implements importRowProducer interface.
 func (p *csvRowProducer) Row() (interface{}, error) {
 	p.rowNum++
+	if p.parseErr != nil {
+		if p.raw == nil {
+			return nil, wrapRowErr(p.parseErr, "", p.rowNum, pgcode.Uncategorized, "reading CSV record")
+		}
+		return nil, newImportRowError(
+			errors.Wrap(p.parseErr, "reading CSV record"),
+			p.rawRecord,
+			p.rowNum)
+	}
+
 	expectedColsLen := len(p.expectedColumns)
 	if expectedColsLen == 0 {
 		expectedColsLen = len(p.importCtx.tableDesc.VisibleColumns())
@@ -195,8 +233,44 @@ func (c *csvRowConsumer) FillDatums(
 	return nil
 }
 
-func newCSVPipeline(c *csvInputReader, input *fileReader) (*csvRowProducer, *csvRowConsumer) {
-	cr := csv.NewReader(input)
+// rawRecordReader is an io.Reader which keeps the data read through it from
+// the start of the current CSV record on.
+type rawRecordReader struct {
+	r   io.Reader
+	buf []byte
+	// offset is the input offset of the first byte in buf.
+	offset int64
+}
+
+var _ io.Reader = &rawRecordReader{}
+
+// Read implements the io.Reader interface.
+func (r *rawRecordReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.buf = append(r.buf, p[:n]...)
+	return n, err
+}
+
+// next returns the data between the start of the current record and the given
+// input offset, which becomes the start of the next record.
+func (r *rawRecordReader) next(offset int64) []byte {
+	n := offset - r.offset
+	raw := r.buf[:n:n]
+	r.buf = r.buf[n:]
+	r.offset = offset
+	return raw
+}
+
+func newCSVPipeline(
+	c *csvInputReader, input *fileReader, rejectMalformed bool,
+) (*csvRowProducer, *csvRowConsumer) {
+	var raw *rawRecordReader
+	var r io.Reader = input
+	if rejectMalformed {
+		raw = &rawRecordReader{r: input}
+		r = raw
+	}
+	cr := csv.NewReader(r)
 	if c.opts.Comma != 0 {
 		cr.Comma = c.opts.Comma
 	}
@@ -210,6 +284,7 @@ func newCSVPipeline(c *csvInputReader, input *fileReader) (*csvRowProducer, *csv
 		csv:             cr,
 		progress:        func() float32 { return input.ReadFraction() },
 		expectedColumns: c.importCtx.targetCols,
+		raw:             raw,
 	}
 	consumer := &csvRowConsumer{
 		importCtx: c.importCtx,
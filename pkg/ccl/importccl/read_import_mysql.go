@@ -85,12 +85,13 @@ func (m *mysqldumpReader) readFiles(
 	resumePos map[int32]int64,
 	format roachpb.IOFileFormat,
 	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
 ) error {
-	return readInputFiles(ctx, dataFiles, resumePos, format, m.readFile, makeExternalStorage)
+	return readInputFiles(ctx, dataFiles, resumePos, format, m.readFile, makeExternalStorage, rejected)
 }
 
 func (m *mysqldumpReader) readFile(
-	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected rejectRowFunc,
 ) error {
 	var inserts, count int64
 	r := bufio.NewReaderSize(input, 1024*64)
@@ -104,6 +105,7 @@ func (m *mysqldumpReader) readFile(
 			return count
 		}
 	}
+	fileCtx := &importFileContext{source: inputIdx, skip: resumePos, rejected: rejected}
 
 	for {
 		stmt, err := mysql.ParseNextStrictDDL(tokens)
@@ -141,19 +143,17 @@ func (m *mysqldumpReader) readFile(
 				if count <= resumePos {
 					continue
 				}
-				if expected, got := len(conv.VisibleCols), len(inputRow); expected != got {
-					return errors.Errorf("expected %d values, got %d: %v", expected, got, inputRow)
-				}
-				for i, raw := range inputRow {
-					converted, err := mysqlValueToDatum(raw, conv.VisibleColTypes[i], conv.EvalCtx)
-					if err != nil {
-						return errors.Wrapf(err, "reading row %d (%d in insert statement %d)",
-							count, count-startingCount, inserts)
+				if err := importMysqlRow(
+					ctx, conv, inputRow, inputIdx, count, count-startingCount, inserts,
+				); err != nil {
+					if rejected == nil || ctx.Err() != nil {
+						return err
 					}
-					conv.Datums[i] = converted
-				}
-				if err := conv.Row(ctx, inputIdx, count); err != nil {
-					return err
+					rowErr := newImportRowError(err, mysql.String(inputRow), count)
+					if err := handleCorruptRow(ctx, fileCtx, rowErr); err != nil {
+						return err
+					}
+					continue
 				}
 				if m.debugRow != nil {
 					m.debugRow(conv.Datums)
@@ -174,6 +174,29 @@ func (m *mysqldumpReader) readFile(
 	return nil
 }
 
+// importMysqlRow converts the values of a row of an INSERT statement and adds
+// it to the current batch of conv.
+func importMysqlRow(
+	ctx context.Context,
+	conv *row.DatumRowConverter,
+	inputRow mysql.ValTuple,
+	inputIdx int32,
+	count, rowInInsert, inserts int64,
+) error {
+	if expected, got := len(conv.VisibleCols), len(inputRow); expected != got {
+		return errors.Errorf("expected %d values, got %d: %v", expected, got, inputRow)
+	}
+	for i, raw := range inputRow {
+		converted, err := mysqlValueToDatum(raw, conv.VisibleColTypes[i], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "reading row %d (%d in insert statement %d)",
+				count, rowInInsert, inserts)
+		}
+		conv.Datums[i] = converted
+	}
+	return conv.Row(ctx, inputIdx, count)
+}
+
 const (
 	zeroDate = "0000-00-00"
 	zeroYear = "0000"
@@ -0,0 +1,268 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// jsonToDatum converts a JSON value to a datum of the target type.
+//
+// JSONB columns take any value as is, which is how nested objects and arrays
+// are imported. Arrays can also be imported into ARRAY columns as long as
+// their elements can be converted to the element type. Any other value is
+// converted from its text form, so that e.g. both 1 and "1" can be imported
+// into an INT column.
+func jsonToDatum(j json.JSON, targetT *types.T, evalCtx *tree.EvalContext) (tree.Datum, error) {
+	if j.Type() == json.NullJSONType {
+		return tree.DNull, nil
+	}
+	if targetT.Family() == types.JsonFamily {
+		return tree.NewDJSON(j), nil
+	}
+
+	switch j.Type() {
+	case json.ObjectJSONType:
+		return nil, errors.Errorf("cannot convert object to non-JSON type %s", targetT)
+	case json.ArrayJSONType:
+		if targetT.Family() != types.ArrayFamily {
+			return nil, errors.Errorf("cannot convert array to non-array type %s", targetT)
+		}
+		arr := tree.NewDArray(targetT.ArrayContents())
+		for i := 0; ; i++ {
+			elt, err := j.FetchValIdx(i)
+			if err != nil {
+				return nil, err
+			}
+			if elt == nil {
+				break
+			}
+			eltDatum, err := jsonToDatum(elt, targetT.ArrayContents(), evalCtx)
+			if err == nil {
+				err = arr.Append(eltDatum)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+
+	s, err := j.AsText()
+	if err != nil {
+		return nil, err
+	}
+	return parseTextAs(targetT, *s, evalCtx)
+}
+
+// parseTextAs parses the text form of a value read from a JSON or Parquet
+// file as the target type. Unlike sqlbase.ParseDatumStringAs, which expects
+// the ARRAY[...] syntax written by EXPORT, arrays are parsed from their
+// Postgres text form ('{a,b}'), which is what other systems produce.
+func parseTextAs(targetT *types.T, s string, evalCtx *tree.EvalContext) (tree.Datum, error) {
+	if targetT.Family() == types.ArrayFamily {
+		return tree.ParseDArrayFromString(evalCtx, s, targetT.ArrayContents())
+	}
+	return sqlbase.ParseDatumStringAs(targetT, s, evalCtx)
+}
+
+// ndjsonConsumer implements importRowConsumer interface.
+type ndjsonConsumer struct {
+	fieldNameToIdx map[string]int
+	strict         bool
+}
+
+var _ importRowConsumer = &ndjsonConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (n *ndjsonConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	line, ok := native.(string)
+	if !ok {
+		return errors.AssertionFailedf("unexpected native type; expected string found %T instead", native)
+	}
+	record, err := json.ParseJSON(line)
+	if err != nil {
+		return err
+	}
+	it, err := record.ObjectIter()
+	if err != nil {
+		return err
+	}
+	if it == nil {
+		return errors.New("record is not a JSON object")
+	}
+
+	for it.Next() {
+		field := lex.NormalizeName(it.Key())
+		idx, ok := n.fieldNameToIdx[field]
+		if !ok {
+			if n.strict {
+				return errors.Errorf("could not find column for record field %s", field)
+			}
+			continue
+		}
+		datum, err := jsonToDatum(it.Value(), conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "field %s", field)
+		}
+		conv.Datums[idx] = datum
+	}
+
+	// Set any nil datums to DNull, in case the record didn't have the field.
+	for i := range conv.Datums {
+		if _, isTargetCol := conv.IsTargetCol[i]; isTargetCol && conv.Datums[i] == nil {
+			if n.strict {
+				return errors.Errorf("field %s was not set in the ndjson import", conv.VisibleCols[i].Name)
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// ndjsonStream implements importRowProducer interface over the lines of the
+// input. Blank lines are skipped.
+type ndjsonStream struct {
+	input *fileReader
+	s     *bufio.Scanner
+	line  string
+}
+
+var _ importRowProducer = &ndjsonStream{}
+
+// Scan implements importRowProducer interface.
+func (n *ndjsonStream) Scan() bool {
+	for n.s.Scan() {
+		if line := bytes.TrimSpace(n.s.Bytes()); len(line) > 0 {
+			n.line = string(line)
+			return true
+		}
+	}
+	return false
+}
+
+// Err implements importRowProducer interface.
+func (n *ndjsonStream) Err() error {
+	if err := n.s.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return errors.Wrapf(err, "line longer than %s", optMaxRowSize)
+		}
+		return err
+	}
+	return nil
+}
+
+// Skip implements importRowProducer interface.
+func (n *ndjsonStream) Skip() error {
+	n.line = ""
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (n *ndjsonStream) Row() (interface{}, error) {
+	return n.line, nil
+}
+
+// Progress implements importRowProducer interface.
+func (n *ndjsonStream) Progress() float32 {
+	return n.input.ReadFraction()
+}
+
+func newImportNDJSONPipeline(
+	n *ndjsonInputReader, input *fileReader,
+) (importRowProducer, importRowConsumer) {
+	fieldIdxByName := make(map[string]int)
+	for idx, col := range n.importContext.tableDesc.VisibleColumns() {
+		fieldIdxByName[col.Name] = idx
+	}
+	consumer := &ndjsonConsumer{
+		fieldNameToIdx: fieldIdxByName,
+		strict:         n.opts.StrictMode,
+	}
+
+	maxRecordSize := int(n.opts.MaxRecordSize)
+	if maxRecordSize <= 0 {
+		maxRecordSize = defaultScanBuffer
+	}
+	initialSize := 64 << 10
+	if initialSize > maxRecordSize {
+		initialSize = maxRecordSize
+	}
+	s := bufio.NewScanner(input)
+	s.Buffer(make([]byte, 0, initialSize), maxRecordSize)
+	producer := &ndjsonStream{input: input, s: s}
+	return producer, consumer
+}
+
+type ndjsonInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.NDJSONOptions
+}
+
+var _ inputConverter = &ndjsonInputReader{}
+
+func newNDJSONInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *sqlbase.TableDescriptor,
+	opts roachpb.NDJSONOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) *ndjsonInputReader {
+	return &ndjsonInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+		},
+		opts: opts,
+	}
+}
+
+func (n *ndjsonInputReader) start(group ctxgroup.Group) {}
+
+func (n *ndjsonInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, n.readFile, makeExternalStorage, rejected)
+}
+
+func (n *ndjsonInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected rejectRowFunc,
+) error {
+	producer, consumer := newImportNDJSONPipeline(n, input)
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+	return runParallelImport(ctx, n.importContext, fileCtx, producer, consumer)
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// readPipelineRows runs the rows of a producer through a consumer and returns
+// the resulting datums, formatted as tuples. Rows that fail to convert are
+// returned as their error instead.
+func readPipelineRows(
+	t *testing.T,
+	desc *sqlbase.TableDescriptor,
+	producer importRowProducer,
+	consumer importRowConsumer,
+) []string {
+	t.Helper()
+	// Ensure datum converter doesn't flush (since we're using nil kv channel
+	// for this test).
+	defer row.TestingSetDatumRowConverterBatchSize(1 << 20)()
+
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	conv, err := row.NewDatumRowConverter(context.Background(), desc, nil, &evalCtx, nil)
+	require.NoError(t, err)
+
+	var rows []string
+	for rowNum := int64(0); producer.Scan(); rowNum++ {
+		native, err := producer.Row()
+		require.NoError(t, err)
+		for i := range conv.Datums {
+			conv.Datums[i] = nil
+		}
+		if err := consumer.FillDatums(native, rowNum, conv); err != nil {
+			rows = append(rows, err.Error())
+			continue
+		}
+		datums := tree.Datums(conv.Datums)
+		rows = append(rows, datums.String())
+	}
+	require.NoError(t, producer.Err())
+	return rows
+}
+
+func TestReadsNDJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := descForTable(t,
+		`CREATE TABLE t (id INT PRIMARY KEY, name STRING, tags STRING[], doc JSONB, amount DECIMAL)`,
+		10, 20, NoFKs)
+	data := strings.Join([]string{
+		`{"id": 1, "name": "a", "tags": ["x", "y"], "doc": {"nested": [1, {"b": null}]}, "amount": 1.5}`,
+		``,
+		`{"ID": "2", "Name": null, "doc": "str", "amount": "2.25", "extra": true}`,
+		`  {"id": 3, "doc": 7, "tags": []}  `,
+		`{"id": 4, "name": {"not": "a string"}}`,
+		`[1, 2]`,
+		`{"id": 5, "tags": "{a,b}"}`,
+	}, "\n")
+
+	n := newNDJSONInputReader(nil, desc, roachpb.NDJSONOptions{}, 0, 1, nil)
+	producer, consumer := newImportNDJSONPipeline(n, &fileReader{Reader: strings.NewReader(data)})
+	require.Equal(t, []string{
+		`(1, 'a', ARRAY['x','y'], '{"nested": [1, {"b": null}]}', 1.5)`,
+		`(2, NULL, NULL, '"str"', 2.25)`,
+		`(3, NULL, ARRAY[], '7', NULL)`,
+		`field name: cannot convert object to non-JSON type string`,
+		`record is not a JSON object`,
+		`(5, NULL, ARRAY['a','b'], NULL, NULL)`,
+	}, readPipelineRows(t, desc, producer, consumer))
+
+	// In strict mode, records must have exactly the fields of the table.
+	n = newNDJSONInputReader(nil, desc, roachpb.NDJSONOptions{StrictMode: true}, 0, 1, nil)
+	producer, consumer = newImportNDJSONPipeline(n, &fileReader{Reader: strings.NewReader(data)})
+	rows := readPipelineRows(t, desc, producer, consumer)
+	require.Equal(t, `(1, 'a', ARRAY['x','y'], '{"nested": [1, {"b": null}]}', 1.5)`, rows[0])
+	require.Equal(t, `could not find column for record field extra`, rows[1])
+	require.Equal(t, `field name was not set in the ndjson import`, rows[2])
+
+	// Lines longer than the maximum record size are rejected.
+	n = newNDJSONInputReader(nil, desc, roachpb.NDJSONOptions{MaxRecordSize: 16}, 0, 1, nil)
+	producer, _ = newImportNDJSONPipeline(n, &fileReader{Reader: strings.NewReader(data)})
+	require.False(t, producer.Scan())
+	require.EqualError(t, producer.Err(), `line longer than max_row_size: bufio.Scanner: token too long`)
+}
@@ -0,0 +1,313 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/errors"
+)
+
+// parquetToDatum converts a datum read from a Parquet file to the type of the
+// column it is imported into.
+func parquetToDatum(
+	d tree.Datum, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if d == tree.DNull || targetT.Equivalent(d.ResolvedType()) {
+		return d, nil
+	}
+	switch v := d.(type) {
+	case *tree.DString:
+		// Like in every other format, strings can be imported into any column
+		// as long as they can be parsed as the target type.
+		return parseTextAs(targetT, string(*v), evalCtx)
+	case *tree.DBytes:
+		// Byte arrays without a logical type are often strings written by tools
+		// which don't annotate them, so parse them like strings.
+		return parseTextAs(targetT, string(*v), evalCtx)
+	case *tree.DArray:
+		if targetT.Family() != types.ArrayFamily {
+			return nil, errors.Errorf("cannot convert array to non-array type %s", targetT)
+		}
+		arr := tree.NewDArray(targetT.ArrayContents())
+		for _, elt := range v.Array {
+			eltDatum, err := parquetToDatum(elt, targetT.ArrayContents(), evalCtx)
+			if err == nil {
+				err = arr.Append(eltDatum)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	res, err := tree.PerformCast(evalCtx, d, targetT)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot convert type %s to %s", d.ResolvedType(), targetT)
+	}
+	return res, nil
+}
+
+// parquetConsumer implements importRowConsumer interface.
+type parquetConsumer struct {
+	// colIdx maps the position of each value in the rows of the producer to
+	// the index of its column in the table.
+	colIdx []int
+	strict bool
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (p *parquetConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	datums, ok := native.(tree.Datums)
+	if !ok {
+		return errors.AssertionFailedf("unexpected native type; expected tree.Datums found %T instead", native)
+	}
+	for i, d := range datums {
+		idx := p.colIdx[i]
+		datum, err := parquetToDatum(d, conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "column %s", conv.VisibleCols[idx].Name)
+		}
+		conv.Datums[idx] = datum
+	}
+
+	// Set any nil datums to DNull, in case the file didn't have the column.
+	for i := range conv.Datums {
+		if _, isTargetCol := conv.IsTargetCol[i]; isTargetCol && conv.Datums[i] == nil {
+			if p.strict {
+				return errors.Errorf("column %s was not found in the parquet file", conv.VisibleCols[i].Name)
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// parquetStream implements importRowProducer interface over the rows of a
+// Parquet file.
+type parquetStream struct {
+	r   *parquet.Reader
+	row tree.Datums
+	err error
+	// acc accounts for the file when it's read into memory.
+	acc mon.BoundAccount
+}
+
+var _ importRowProducer = &parquetStream{}
+
+// Scan implements importRowProducer interface.
+func (p *parquetStream) Scan() bool {
+	p.row, p.err = p.r.Next()
+	if p.err == io.EOF {
+		p.err = nil
+		return false
+	}
+	return p.err == nil
+}
+
+// Err implements importRowProducer interface.
+func (p *parquetStream) Err() error {
+	return p.err
+}
+
+// Skip implements importRowProducer interface.
+func (p *parquetStream) Skip() error {
+	p.row = nil
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (p *parquetStream) Row() (interface{}, error) {
+	res := p.row
+	p.row = nil
+	return res, nil
+}
+
+// Progress implements importRowProducer interface.
+func (p *parquetStream) Progress() float32 {
+	if n := p.r.NumRows(); n > 0 {
+		return float32(p.r.RowsRead()) / float32(n)
+	}
+	return 0
+}
+
+func (p *parquetStream) close(ctx context.Context) {
+	p.acc.Close(ctx)
+}
+
+// storageReaderAt implements io.ReaderAt over a file of an ExternalStorage,
+// with a ranged read per call.
+type storageReaderAt struct {
+	ctx   context.Context
+	store cloud.ExternalStorage
+}
+
+func (s *storageReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r, _, err := s.store.ReadFileAt(s.ctx, "", off)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return io.ReadFull(r, p)
+}
+
+// newImportParquetPipeline returns the producer and consumer of the rows of a
+// Parquet file. The producer must be closed once it's not used anymore.
+func newImportParquetPipeline(
+	ctx context.Context, pq *parquetInputReader, input *fileReader,
+) (*parquetStream, importRowConsumer, error) {
+	producer := &parquetStream{}
+	if pq.importContext.evalCtx != nil && pq.importContext.evalCtx.Mon != nil {
+		producer.acc = pq.importContext.evalCtx.Mon.MakeBoundAccount()
+	}
+	r, err := newParquetReader(ctx, input, &producer.acc)
+	if err != nil {
+		producer.close(ctx)
+		return nil, nil, err
+	}
+	producer.r = r
+
+	colIdxByName := make(map[string]int)
+	for idx, col := range pq.importContext.tableDesc.VisibleColumns() {
+		colIdxByName[col.Name] = idx
+	}
+	var selected, colIdx []int
+	for i, name := range r.Columns() {
+		idx, ok := colIdxByName[lex.NormalizeName(name)]
+		if !ok {
+			if pq.opts.StrictMode {
+				return nil, nil, errors.Errorf("could not find column for parquet column %s", name)
+			}
+			continue
+		}
+		selected = append(selected, i)
+		colIdx = append(colIdx, idx)
+	}
+	if err := r.Select(selected); err != nil {
+		producer.close(ctx)
+		return nil, nil, err
+	}
+
+	consumer := &parquetConsumer{colIdx: colIdx, strict: pq.opts.StrictMode}
+	return producer, consumer, nil
+}
+
+// newParquetReader returns a reader of the Parquet file of input. The metadata
+// of a Parquet file is at its end, so there is no way to stream it. Instead,
+// uncompressed files are read with ranged reads of their footer and of the
+// column chunks that are imported, and compressed files are decompressed into
+// memory, which is accounted for in acc.
+func newParquetReader(
+	ctx context.Context, input *fileReader, acc *mon.BoundAccount,
+) (*parquet.Reader, error) {
+	if input.store != nil && input.compression == roachpb.IOFileFormat_None {
+		size := input.total
+		if size <= 0 {
+			var err error
+			if size, err = input.store.Size(ctx, ""); err != nil {
+				return nil, err
+			}
+		}
+		return parquet.NewReaderAt(&storageReaderAt{ctx: ctx, store: input.store}, size)
+	}
+
+	var buf bytes.Buffer
+	var accounted int64
+	chunk := make([]byte, 64<<10)
+	for {
+		n, err := input.Read(chunk)
+		buf.Write(chunk[:n])
+		if c := int64(buf.Cap()); c > accounted && acc.Monitor() != nil {
+			if err := acc.Grow(ctx, c-accounted); err != nil {
+				return nil, errors.Wrap(err, "reading parquet file into memory")
+			}
+			accounted = c
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parquet.NewReader(buf.Bytes())
+}
+
+type parquetInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ParquetOptions
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *sqlbase.TableDescriptor,
+	opts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) *parquetInputReader {
+	return &parquetInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+		},
+		opts: opts,
+	}
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, p.readFile, makeExternalStorage, rejected)
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected rejectRowFunc,
+) error {
+	producer, consumer, err := newImportParquetPipeline(ctx, p, input)
+	if err != nil {
+		return err
+	}
+	defer producer.close(ctx)
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
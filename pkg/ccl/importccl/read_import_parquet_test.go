@@ -0,0 +1,110 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadsParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	sch, err := parquet.NewSchema(
+		[]string{`ID`, `name`, `ts`, `amount`, `tags`, `doc`, `extra`},
+		[]*types.T{
+			types.Int, types.String, types.TimestampTZ, types.Float, types.MakeArray(types.Int),
+			types.String, types.Bool,
+		})
+	require.NoError(t, err)
+	ints := tree.NewDArray(types.Int)
+	require.NoError(t, ints.Append(tree.NewDInt(1)))
+	require.NoError(t, ints.Append(tree.NewDInt(2)))
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := parquet.NewWriter(sch, &buf, parquet.WriterOptions{Compression: parquet.CompressionGzip})
+	require.NoError(t, w.AddRow(tree.Datums{
+		tree.NewDInt(1), tree.NewDString(`a`), tree.MustMakeDTimestampTZ(ts, time.Microsecond),
+		tree.NewDFloat(1.5), ints, tree.NewDString(`{"a": [1, 2]}`), tree.DBoolTrue,
+	}))
+	require.NoError(t, w.AddRow(tree.Datums{
+		tree.NewDInt(2), tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull,
+	}))
+	require.NoError(t, w.AddRow(tree.Datums{
+		tree.NewDInt(3), tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.NewDString(`{`),
+		tree.DNull,
+	}))
+	require.NoError(t, w.Close())
+
+	// The file has no column for z, and the table has none for extra.
+	desc := descForTable(t,
+		`CREATE TABLE t (id INT PRIMARY KEY, name STRING, ts TIMESTAMP, amount DECIMAL, tags STRING[], doc JSONB, z INT)`,
+		10, 20, NoFKs)
+
+	pq := newParquetInputReader(nil, desc, roachpb.ParquetOptions{}, 0, 1, nil)
+	producer, consumer, err := newImportParquetPipeline(ctx, pq, &fileReader{Reader: bytes.NewReader(buf.Bytes())})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`(1, 'a', '2020-01-02 03:04:05+00:00', 1.5, ARRAY['1','2'], '{"a": [1, 2]}', NULL)`,
+		`(2, NULL, NULL, NULL, NULL, NULL, NULL)`,
+		`column doc: could not parse JSON: unable to decode JSON: unexpected EOF`,
+	}, readPipelineRows(t, desc, producer, consumer))
+	require.Equal(t, float32(1), producer.Progress())
+
+	// In strict mode, the columns of the file must match the table.
+	pq = newParquetInputReader(nil, desc, roachpb.ParquetOptions{StrictMode: true}, 0, 1, nil)
+	_, _, err = newImportParquetPipeline(ctx, pq, &fileReader{Reader: bytes.NewReader(buf.Bytes())})
+	require.EqualError(t, err, `could not find column for parquet column extra`)
+
+	desc = descForTable(t,
+		`CREATE TABLE t (id INT PRIMARY KEY, name STRING, ts TIMESTAMP, amount DECIMAL, tags STRING[], doc JSONB, extra BOOL, z INT)`,
+		10, 20, NoFKs)
+	pq = newParquetInputReader(nil, desc, roachpb.ParquetOptions{StrictMode: true}, 0, 1, nil)
+	producer, consumer, err = newImportParquetPipeline(ctx, pq, &fileReader{Reader: bytes.NewReader(buf.Bytes())})
+	require.NoError(t, err)
+	rows := readPipelineRows(t, desc, producer, consumer)
+	require.Equal(t, `column z was not found in the parquet file`, rows[0])
+
+	_, _, err = newImportParquetPipeline(ctx, pq, &fileReader{Reader: bytes.NewReader([]byte(`{"id": 1}`))})
+	require.EqualError(t, err, `parquet: not a parquet file`)
+
+	// Files which are read into memory are accounted for in the memory monitor
+	// of the import.
+	st := cluster.MakeTestingClusterSettings()
+	monitor := mon.MakeMonitorWithLimit(
+		"test-monitor",
+		mon.MemoryResource,
+		10,            /* limit */
+		nil,           /* curCount */
+		nil,           /* maxHist */
+		1,             /* increment */
+		math.MaxInt64, /* noteworthy */
+		st,
+	)
+	evalCtx := tree.MakeTestingEvalContextWithMon(st, &monitor)
+	defer evalCtx.Stop(ctx)
+	pq = newParquetInputReader(nil, desc, roachpb.ParquetOptions{}, 0, 1, &evalCtx)
+	_, _, err = newImportParquetPipeline(ctx, pq, &fileReader{Reader: bytes.NewReader(buf.Bytes())})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `memory budget exceeded`)
+}
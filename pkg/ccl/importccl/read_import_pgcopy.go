@@ -64,8 +64,9 @@ func (d *pgCopyReader) readFiles(
 	resumePos map[int32]int64,
 	format roachpb.IOFileFormat,
 	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
 ) error {
-	return readInputFiles(ctx, dataFiles, resumePos, format, d.readFile, makeExternalStorage)
+	return readInputFiles(ctx, dataFiles, resumePos, format, d.readFile, makeExternalStorage, rejected)
 }
 
 type postgreStreamCopy struct {
@@ -255,7 +256,7 @@ func (c copyData) String() string {
 }
 
 func (d *pgCopyReader) readFile(
-	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected rejectRowFunc,
 ) error {
 	s := bufio.NewScanner(input)
 	s.Split(bufio.ScanLines)
@@ -272,12 +273,18 @@ func (d *pgCopyReader) readFile(
 		return count
 	}
 
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
 	for ; ; count++ {
 		row, err := c.Next()
 		if err == io.EOF {
 			break
 		}
-		if err != nil {
+		if err != nil && (err == errCopyDone || s.Err() != nil) {
+			// The scanner can't make progress past this point.
 			return wrapRowErr(err, "", count, pgcode.Uncategorized, "")
 		}
 
@@ -285,27 +292,49 @@ func (d *pgCopyReader) readFile(
 			continue
 		}
 
-		if len(row) != len(d.conv.VisibleColTypes) {
-			return makeRowErr("", count, pgcode.Syntax,
-				"expected %d values, got %d", len(d.conv.VisibleColTypes), len(row))
+		if err != nil {
+			err = wrapRowErr(err, "", count, pgcode.Uncategorized, "")
+		} else {
+			err = importCopyRow(ctx, &d.conv, row, inputIdx, count)
 		}
-		for i, s := range row {
-			if s == nil {
-				d.conv.Datums[i] = tree.DNull
-			} else {
-				d.conv.Datums[i], err = sqlbase.ParseDatumStringAs(d.conv.VisibleColTypes[i], *s, d.conv.EvalCtx)
-				if err != nil {
-					col := d.conv.VisibleCols[i]
-					return wrapRowErr(err, "", count, pgcode.Syntax,
-						"parse %q as %s", col.Name, col.Type.SQLString())
-				}
+		if err != nil {
+			if rejected == nil || ctx.Err() != nil {
+				return err
+			}
+			err = newImportRowError(err, s.Text(), count)
+			if err := handleCorruptRow(ctx, fileCtx, err); err != nil {
+				return err
 			}
-		}
-
-		if err := d.conv.Row(ctx, inputIdx, count); err != nil {
-			return wrapRowErr(err, "", count, pgcode.Uncategorized, "")
 		}
 	}
 
 	return d.conv.SendBatch(ctx)
 }
+
+// importCopyRow converts a row of COPY data and adds it to the current batch
+// of conv.
+func importCopyRow(
+	ctx context.Context, conv *row.DatumRowConverter, data copyData, inputIdx int32, count int64,
+) error {
+	if len(data) != len(conv.VisibleColTypes) {
+		return makeRowErr("", count, pgcode.Syntax,
+			"expected %d values, got %d", len(conv.VisibleColTypes), len(data))
+	}
+	for i, s := range data {
+		if s == nil {
+			conv.Datums[i] = tree.DNull
+		} else {
+			var err error
+			conv.Datums[i], err = sqlbase.ParseDatumStringAs(conv.VisibleColTypes[i], *s, conv.EvalCtx)
+			if err != nil {
+				col := conv.VisibleCols[i]
+				return wrapRowErr(err, "", count, pgcode.Syntax,
+					"parse %q as %s", col.Name, col.Type.SQLString())
+			}
+		}
+	}
+	if err := conv.Row(ctx, inputIdx, count); err != nil {
+		return wrapRowErr(err, "", count, pgcode.Uncategorized, "")
+	}
+	return nil
+}
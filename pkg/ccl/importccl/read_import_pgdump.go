@@ -445,12 +445,13 @@ func (m *pgDumpReader) readFiles(
 	resumePos map[int32]int64,
 	format roachpb.IOFileFormat,
 	makeExternalStorage cloud.ExternalStorageFactory,
+	rejected *rejectedRows,
 ) error {
-	return readInputFiles(ctx, dataFiles, resumePos, format, m.readFile, makeExternalStorage)
+	return readInputFiles(ctx, dataFiles, resumePos, format, m.readFile, makeExternalStorage, rejected)
 }
 
 func (m *pgDumpReader) readFile(
-	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected rejectRowFunc,
 ) error {
 	var inserts, count int64
 	ps := newPostgreStream(input, int(m.opts.MaxRowSize))
@@ -462,6 +463,15 @@ func (m *pgDumpReader) readFile(
 			return count
 		}
 	}
+	fileCtx := &importFileContext{source: inputIdx, skip: resumePos, rejected: rejected}
+	// rejectRow fails the import with err, unless rejected rows are being
+	// saved, in which case the row is skipped.
+	rejectRow := func(err error, data string) error {
+		if rejected == nil || ctx.Err() != nil {
+			return err
+		}
+		return handleCorruptRow(ctx, fileCtx, newImportRowError(err, data, count))
+	}
 
 	for {
 		stmt, err := ps.Next()
@@ -500,24 +510,12 @@ func (m *pgDumpReader) readFile(
 				if count <= resumePos {
 					continue
 				}
-				if expected, got := len(conv.VisibleCols), len(tuple); expected != got {
-					return errors.Errorf("expected %d values, got %d: %v", expected, got, tuple)
-				}
-				for i, expr := range tuple {
-					typed, err := expr.TypeCheck(semaCtx, conv.VisibleColTypes[i])
-					if err != nil {
-						return errors.Wrapf(err, "reading row %d (%d in insert statement %d)",
-							count, count-startingCount, inserts)
-					}
-					converted, err := typed.Eval(conv.EvalCtx)
-					if err != nil {
-						return errors.Wrapf(err, "reading row %d (%d in insert statement %d)",
-							count, count-startingCount, inserts)
+				if err := importInsertRow(
+					ctx, conv, semaCtx, tuple, inputIdx, count, count-startingCount, inserts,
+				); err != nil {
+					if err := rejectRow(err, tree.AsString(&tuple)); err != nil {
+						return err
 					}
-					conv.Datums[i] = converted
-				}
-				if err := conv.Row(ctx, inputIdx, count); err != nil {
-					return err
 				}
 			}
 		case *tree.CopyFrom:
@@ -564,25 +562,11 @@ func (m *pgDumpReader) readFile(
 				}
 				switch row := row.(type) {
 				case copyData:
-					if expected, got := len(conv.VisibleCols), len(row); expected != got {
-						return makeRowErr("", count, pgcode.Syntax,
-							"expected %d values, got %d", expected, got)
-					}
-					for i, s := range row {
-						if s == nil {
-							conv.Datums[i] = tree.DNull
-						} else {
-							conv.Datums[i], err = sqlbase.ParseDatumStringAs(conv.VisibleColTypes[i], *s, conv.EvalCtx)
-							if err != nil {
-								col := conv.VisibleCols[i]
-								return wrapRowErr(err, "", count, pgcode.Syntax,
-									"parse %q as %s", col.Name, col.Type.SQLString())
-							}
+					if err := importCopyRow(ctx, conv, row, inputIdx, count); err != nil {
+						if err := rejectRow(err, row.String()); err != nil {
+							return err
 						}
 					}
-					if err := conv.Row(ctx, inputIdx, count); err != nil {
-						return err
-					}
 				default:
 					return makeRowErr("", count, pgcode.Uncategorized,
 						"unexpected: %v", row)
@@ -658,3 +642,32 @@ func (m *pgDumpReader) readFile(
 	}
 	return nil
 }
+
+// importInsertRow evaluates the values of a row of an INSERT statement and adds
+// it to the current batch of conv.
+func importInsertRow(
+	ctx context.Context,
+	conv *row.DatumRowConverter,
+	semaCtx *tree.SemaContext,
+	tuple tree.Exprs,
+	inputIdx int32,
+	count, rowInInsert, inserts int64,
+) error {
+	if expected, got := len(conv.VisibleCols), len(tuple); expected != got {
+		return errors.Errorf("expected %d values, got %d: %v", expected, got, tuple)
+	}
+	for i, expr := range tuple {
+		typed, err := expr.TypeCheck(semaCtx, conv.VisibleColTypes[i])
+		if err != nil {
+			return errors.Wrapf(err, "reading row %d (%d in insert statement %d)",
+				count, rowInInsert, inserts)
+		}
+		converted, err := typed.Eval(conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "reading row %d (%d in insert statement %d)",
+				count, rowInInsert, inserts)
+		}
+		conv.Datums[i] = converted
+	}
+	return conv.Row(ctx, inputIdx, count)
+}
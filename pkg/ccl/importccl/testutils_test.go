@@ -250,6 +250,12 @@ func (es *generatorExternalStorage) ReadFile(
 	return es.gen.Open()
 }
 
+func (es *generatorExternalStorage) ReadFileAt(
+	ctx context.Context, basename string, offset int64,
+) (io.ReadCloser, int64, error) {
+	return nil, 0, errors.New("unsupported")
+}
+
 func (es *generatorExternalStorage) Close() error {
 	return nil
 }
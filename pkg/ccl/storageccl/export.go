@@ -209,9 +209,20 @@ func evalExport(
 			// Create a unique int differently.
 			nodeID := cArgs.EvalCtx.NodeID()
 			exported.Path = fmt.Sprintf("%d.sst", builtins.GenerateUniqueInt(base.SQLInstanceID(nodeID)))
-			if err := exportStore.WriteFile(ctx, exported.Path, bytes.NewReader(data)); err != nil {
+			// The upload is finished here, where the SST is. Uploading in parts
+			// lets a transient failure be retried without resending the whole
+			// SST; an upload which fails altogether is aborted, so that its parts
+			// don't linger in the storage, and restarted with the request.
+			var upload roachpb.ExternalStorageUpload
+			if err := cloud.WriteFileResumable(
+				ctx, exportStore, exported.Path, bytes.NewReader(data), &upload, nil, /* checkpoint */
+			); err != nil {
+				if abortErr := cloud.AbortResumableUpload(ctx, exportStore, &upload); abortErr != nil {
+					log.Warningf(ctx, "failed to abort upload of %s: %v", exported.Path, abortErr)
+				}
 				return result.Result{}, err
 			}
+			exported.FileSize = int64(len(data))
 		}
 		if args.ReturnSST {
 			exported.SST = data
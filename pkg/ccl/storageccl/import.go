@@ -198,11 +198,17 @@ func evalImport(ctx context.Context, cArgs batcheval.CommandArgs) (*roachpb.Impo
 		iters = append(iters, iter)
 	}
 
-	batcher, err := bulk.MakeSSTBatcher(ctx, db, cArgs.EvalCtx.ClusterSettings(), func() int64 { return MaxImportBatchSize(cArgs.EvalCtx.ClusterSettings()) })
-	if err != nil {
-		return nil, err
+	// When only verifying the files, the entries are counted rather than added
+	// to a batch for ingestion.
+	var batcher *bulk.SSTBatcher
+	var verified storage.RowCounter
+	if !args.VerifyOnly {
+		batcher, err = bulk.MakeSSTBatcher(ctx, db, cArgs.EvalCtx.ClusterSettings(), func() int64 { return MaxImportBatchSize(cArgs.EvalCtx.ClusterSettings()) })
+		if err != nil {
+			return nil, err
+		}
+		defer batcher.Close()
 	}
-	defer batcher.Close()
 
 	startKeyMVCC, endKeyMVCC := storage.MVCCKey{Key: args.DataSpan.Key}, storage.MVCCKey{Key: args.DataSpan.EndKey}
 	iter := storage.MakeMultiIterator(iters)
@@ -242,6 +248,14 @@ func evalImport(ctx context.Context, cArgs batcheval.CommandArgs) (*roachpb.Impo
 		value := roachpb.Value{RawBytes: valueScratch}
 		iter.NextKey()
 
+		if args.VerifyOnly {
+			// The checksum of a value is computed with the key it was written at,
+			// so it is verified before the key is rewritten.
+			if err := value.Verify(key.Key); err != nil {
+				return nil, err
+			}
+		}
+
 		key.Key, ok, err = kr.RewriteKey(key.Key, false /* isFromSpan */)
 		if err != nil {
 			return nil, err
@@ -255,6 +269,14 @@ func evalImport(ctx context.Context, cArgs batcheval.CommandArgs) (*roachpb.Impo
 			continue
 		}
 
+		if args.VerifyOnly {
+			if err := verified.Count(key.Key); err != nil {
+				return nil, err
+			}
+			verified.DataSize += int64(len(key.Key) + len(value.RawBytes))
+			continue
+		}
+
 		// Rewriting the key means the checksum needs to be updated.
 		value.ClearChecksum()
 		value.InitChecksum(key.Key)
@@ -266,6 +288,10 @@ func evalImport(ctx context.Context, cArgs batcheval.CommandArgs) (*roachpb.Impo
 			return nil, errors.Wrapf(err, "adding to batch: %s -> %s", key, value.PrettyPrint())
 		}
 	}
+	if args.VerifyOnly {
+		log.Event(ctx, "done verifying")
+		return &roachpb.ImportResponse{Imported: verified.BulkOpSummary}, nil
+	}
 	// Flush out the last batch.
 	if err := batcher.Flush(ctx); err != nil {
 		return nil, err
@@ -0,0 +1,125 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingccl
+
+import (
+	"context"
+	gosql "database/sql"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	// Imported to allow connecting to the source cluster over pgwire.
+	_ "github.com/lib/pq"
+)
+
+// streamEvent is an event of a replication stream: either a write to the
+// keyspace being replicated, or a resolved timestamp, below which all the
+// writes of the stream have been emitted.
+type streamEvent struct {
+	kv       roachpb.KeyValue
+	resolved hlc.Timestamp
+}
+
+// streamClient consumes the replication stream of a tenant.
+type streamClient interface {
+	// ConsumeStream sends the events of the replication stream of the tenant,
+	// starting after startTime, to eventCh. If startTime is empty, the stream
+	// starts with the current contents of the keyspace of the tenant. It
+	// returns when ctx is canceled or the stream fails.
+	ConsumeStream(
+		ctx context.Context, tenantID uint64, startTime hlc.Timestamp, eventCh chan<- streamEvent,
+	) error
+}
+
+// sqlStreamClient is a streamClient that reads the stream from
+// crdb_internal.replication_stream on the source cluster, over a SQL
+// connection.
+type sqlStreamClient struct {
+	pgURL string
+}
+
+var _ streamClient = &sqlStreamClient{}
+
+// newStreamClient returns a streamClient for the source cluster at the passed
+// address, a postgres URL.
+func newStreamClient(streamAddress string) (streamClient, error) {
+	u, err := url.Parse(streamAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing stream address")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, errors.Errorf("unsupported stream address scheme %q", u.Scheme)
+	}
+	// Disable the buffering of results by the source, so that events are
+	// received as soon as they are emitted.
+	q := u.Query()
+	q.Set("results_buffer_size", "0")
+	u.RawQuery = q.Encode()
+	return &sqlStreamClient{pgURL: u.String()}, nil
+}
+
+// ConsumeStream implements the streamClient interface.
+func (c *sqlStreamClient) ConsumeStream(
+	ctx context.Context, tenantID uint64, startTime hlc.Timestamp, eventCh chan<- streamEvent,
+) error {
+	db, err := gosql.Open("postgres", c.pgURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT key, value, timestamp, resolved FROM crdb_internal.replication_stream($1, $2::DECIMAL)`,
+		int64(tenantID), tree.TimestampToDecimal(startTime).String(),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value []byte
+		var ts, resolved gosql.NullString
+		if err := rows.Scan(&key, &value, &ts, &resolved); err != nil {
+			return err
+		}
+		var ev streamEvent
+		if resolved.Valid {
+			if ev.resolved, err = parseDecimalTimestamp(resolved.String); err != nil {
+				return err
+			}
+		} else {
+			ev.kv.Key = key
+			ev.kv.Value.RawBytes = value
+			if ev.kv.Value.Timestamp, err = parseDecimalTimestamp(ts.String); err != nil {
+				return err
+			}
+		}
+		select {
+		case eventCh <- ev:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return errors.New("replication stream ended unexpectedly")
+}
+
+func parseDecimalTimestamp(s string) (hlc.Timestamp, error) {
+	d, err := tree.ParseDDecimal(s)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	return tree.DecimalToHLC(&d.Decimal)
+}
@@ -0,0 +1,316 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingccl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/bulk"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// cutoverPollInterval controls how often a replication job checks whether its
+// cutover was requested, when its stream doesn't make progress.
+var cutoverPollInterval = settings.RegisterNonNegativeDurationSetting(
+	"stream_replication.cutover_poll_interval",
+	"the interval at which replication jobs check whether a cutover was requested",
+	10*time.Second,
+)
+
+// ingestionBufferSize is the size of the writes buffered by a replication job
+// before they are ingested, even if no resolved timestamp was received.
+const ingestionBufferSize = 16 << 20
+
+// errCutoverRequested is returned by the goroutines of a replication job when
+// they notice that the cutover of the job was requested.
+var errCutoverRequested = errors.New("cutover requested")
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeStreamIngestion,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &streamIngestionResumer{job: job}
+		},
+	)
+	// Replication jobs are retried for as long as the source cluster is
+	// unavailable.
+	jobs.RegisterRetryPolicy(jobspb.TypeStreamIngestion, jobs.RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	})
+	builtins.CompleteStreamIngestion = completeStreamIngestion
+}
+
+// streamIngestionResumer implements jobs.Resumer for replication jobs, which
+// ingest the replication stream of a tenant from a source cluster until their
+// cutover is requested.
+type streamIngestionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &streamIngestionResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *streamIngestionResumer) Resume(
+	ctx context.Context, planHookState interface{}, _ chan<- tree.Datums,
+) error {
+	execCfg := planHookState.(sql.PlanHookState).ExecCfg()
+	details := r.job.Details().(jobspb.StreamIngestionDetails)
+	progress := r.job.Progress()
+
+	if progress.GetStreamIngestion().CutoverTime.IsEmpty() {
+		var startTime hlc.Timestamp
+		if hw := progress.GetHighWater(); hw != nil {
+			startTime = *hw
+		}
+		err := r.ingest(ctx, execCfg, details, startTime)
+		if !errors.Is(err, errCutoverRequested) {
+			return err
+		}
+		// Reload the job to get the requested cutover time.
+		job, err := execCfg.JobRegistry.LoadJob(ctx, *r.job.ID())
+		if err != nil {
+			return err
+		}
+		progress = job.Progress()
+	}
+
+	cutoverTime := progress.GetStreamIngestion().CutoverTime
+	log.Infof(ctx, "reverting tenant %d to cutover time %s", details.TenantID, cutoverTime)
+	return sql.RevertSpans(
+		ctx, execCfg.DB, []roachpb.Span{tenantSpan(details.TenantID)}, cutoverTime,
+		sql.RevertTableDefaultBatchSize,
+	)
+}
+
+// ingest consumes the replication stream, starting after startTime, until the
+// cutover of the job is requested, in which case it returns
+// errCutoverRequested.
+func (r *streamIngestionResumer) ingest(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	details jobspb.StreamIngestionDetails,
+	startTime hlc.Timestamp,
+) error {
+	client, err := newStreamClient(details.StreamAddress)
+	if err != nil {
+		return err
+	}
+
+	eventCh := make(chan streamEvent)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		err := client.ConsumeStream(ctx, details.TenantID, startTime, eventCh)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// The source cluster may be temporarily unavailable, so retry the job
+		// from its high-water rather than failing it.
+		return jobs.NewRetryJobError(errors.Wrap(err, "consuming replication stream").Error())
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		ing := ingester{db: execCfg.DB, settings: execCfg.Settings}
+		for {
+			var ev streamEvent
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ev = <-eventCh:
+			}
+			if ev.kv.Key != nil {
+				if err := ing.add(ctx, ev.kv); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := ing.flush(ctx); err != nil {
+				return err
+			}
+			if err := r.job.HighWaterProgressed(ctx, func(
+				ctx context.Context, txn *kv.Txn, details jobspb.ProgressDetails,
+			) (hlc.Timestamp, error) {
+				p := details.(*jobspb.Progress_StreamIngestion).StreamIngestion
+				if !p.CutoverTime.IsEmpty() {
+					return hlc.Timestamp{}, errCutoverRequested
+				}
+				return ev.resolved, nil
+			}); err != nil {
+				return err
+			}
+		}
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(cutoverPollInterval.Get(&execCfg.Settings.SV)):
+			}
+			job, err := execCfg.JobRegistry.LoadJob(ctx, *r.job.ID())
+			if err != nil {
+				return err
+			}
+			if !job.Progress().GetStreamIngestion().CutoverTime.IsEmpty() {
+				return errCutoverRequested
+			}
+		}
+	})
+	return g.Wait()
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. It clears the keyspace
+// of the tenant, which is not consistent.
+func (r *streamIngestionResumer) OnFailOrCancel(
+	ctx context.Context, planHookState interface{},
+) error {
+	execCfg := planHookState.(sql.PlanHookState).ExecCfg()
+	details := r.job.Details().(jobspb.StreamIngestionDetails)
+	sp := tenantSpan(details.TenantID)
+
+	// ClearRange cannot be run in a transaction, so create a
+	// non-transactional batch to send the request.
+	b := &kv.Batch{}
+	b.AddRawRequest(&roachpb.ClearRangeRequest{
+		RequestHeader: roachpb.RequestHeader{
+			Key:    sp.Key,
+			EndKey: sp.EndKey,
+		},
+	})
+	return execCfg.DB.Run(ctx, b)
+}
+
+// ingester buffers the writes of a replication stream, and ingests them with
+// AddSSTable, preserving their MVCC timestamps.
+type ingester struct {
+	db       *kv.DB
+	settings *cluster.Settings
+
+	buf     []storage.MVCCKeyValue
+	bufSize int
+}
+
+func (i *ingester) add(ctx context.Context, keyValue roachpb.KeyValue) error {
+	i.buf = append(i.buf, storage.MVCCKeyValue{
+		Key:   storage.MVCCKey{Key: keyValue.Key, Timestamp: keyValue.Value.Timestamp},
+		Value: keyValue.Value.RawBytes,
+	})
+	i.bufSize += len(keyValue.Key) + len(keyValue.Value.RawBytes)
+	if i.bufSize < ingestionBufferSize {
+		return nil
+	}
+	return i.flush(ctx)
+}
+
+// flush ingests the buffered writes.
+func (i *ingester) flush(ctx context.Context) error {
+	if len(i.buf) == 0 {
+		return nil
+	}
+	sort.Slice(i.buf, func(a, b int) bool { return i.buf[a].Key.Less(i.buf[b].Key) })
+
+	sstFile := &storage.MemFile{}
+	sst := storage.MakeIngestionSSTWriter(sstFile)
+	defer sst.Close()
+	for j, mvccKV := range i.buf {
+		// The stream can emit the same write more than once, e.g. when a
+		// rangefeed is restarted.
+		if j > 0 && mvccKV.Key.Equal(i.buf[j-1].Key) {
+			continue
+		}
+		if err := sst.Put(mvccKV.Key, mvccKV.Value); err != nil {
+			return err
+		}
+	}
+	if err := sst.Finish(); err != nil {
+		return err
+	}
+
+	start, end := i.buf[0].Key.Key, i.buf[len(i.buf)-1].Key.Key.Next()
+	if _, err := bulk.AddSSTable(
+		ctx, i.db, start, end, sstFile.Data(), false /* disallowShadowing */, enginepb.MVCCStats{}, i.settings,
+	); err != nil {
+		return err
+	}
+	i.buf, i.bufSize = i.buf[:0], 0
+	return nil
+}
+
+// completeStreamIngestion implements crdb_internal.complete_stream_ingestion_job.
+// It records the cutover time of the job, which is its high-water, and is
+// noticed by the running job.
+func completeStreamIngestion(evalCtx *tree.EvalContext, jobID int64) (hlc.Timestamp, error) {
+	p, ok := evalCtx.Planner.(sql.PlanHookState)
+	if !ok {
+		return hlc.Timestamp{}, errors.AssertionFailedf("completing replication jobs requires a planner")
+	}
+	ctx := evalCtx.Context
+	if err := p.RequireAdminRole(ctx, "complete replication job"); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	execCfg := p.ExecCfg()
+	if err := utilccl.CheckEnterpriseEnabled(
+		execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "REPLICATION STREAM",
+	); err != nil {
+		return hlc.Timestamp{}, err
+	}
+
+	job, err := execCfg.JobRegistry.LoadJobWithTxn(ctx, jobID, evalCtx.Txn)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	var cutoverTime hlc.Timestamp
+	if err := job.WithTxn(evalCtx.Txn).Update(ctx, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		if typ := md.Payload.Type(); typ != jobspb.TypeStreamIngestion {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"job %d is not a replication job but %s", jobID, typ)
+		}
+		if md.Status.Terminal() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"replication job %d is %s", jobID, md.Status)
+		}
+		progress := md.Progress.GetStreamIngestion()
+		if !progress.CutoverTime.IsEmpty() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cutover of replication job %d was already requested", jobID)
+		}
+		hw := md.Progress.GetHighWater()
+		if hw == nil || hw.IsEmpty() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"replication job %d has not resolved any timestamp yet", jobID)
+		}
+		cutoverTime = *hw
+		progress.CutoverTime = cutoverTime
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	return cutoverTime, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingccl
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+func init() {
+	sql.AddPlanHook(streamIngestionJobPlanHook)
+}
+
+// streamIngestionJobPlanHook implements sql.PlanHookFn for RESTORE TENANT ...
+// FROM REPLICATION STREAM.
+func streamIngestionJobPlanHook(
+	_ context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	ingestionStmt, ok := stmt.(*tree.StreamIngestion)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	streamAddressFn, err := p.TypeAsString(ingestionStmt.StreamAddress, "RESTORE TENANT")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		execCfg := p.ExecCfg()
+		if err := utilccl.CheckEnterpriseEnabled(
+			execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "REPLICATION STREAM",
+		); err != nil {
+			return err
+		}
+		if err := p.RequireAdminRole(ctx, "RESTORE TENANT"); err != nil {
+			return err
+		}
+
+		if ingestionStmt.TenantID <= roachpb.SystemTenantID.ToUint64() {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid tenant ID %d: system tenant replication not supported", ingestionStmt.TenantID)
+		}
+		streamAddress, err := streamAddressFn()
+		if err != nil {
+			return err
+		}
+		if _, err := newStreamClient(streamAddress); err != nil {
+			return err
+		}
+
+		// The stream is ingested with AddSSTable, which doesn't play well with
+		// existing data, so the keyspace of the tenant must be empty.
+		sp := tenantSpan(ingestionStmt.TenantID)
+		kvs, err := execCfg.DB.Scan(ctx, sp.Key, sp.EndKey, 1)
+		if err != nil {
+			return err
+		}
+		if len(kvs) > 0 {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"keyspace of tenant %d is not empty", ingestionStmt.TenantID)
+		}
+
+		description, err := streamIngestionJobDescription(p, ingestionStmt, streamAddress)
+		if err != nil {
+			return err
+		}
+		job, _, err := execCfg.JobRegistry.CreateAndStartJob(ctx, nil /* resultsCh */, jobs.Record{
+			Description: description,
+			Username:    p.User(),
+			Details: jobspb.StreamIngestionDetails{
+				StreamAddress: streamAddress,
+				TenantID:      ingestionStmt.TenantID,
+			},
+			Progress: jobspb.StreamIngestionProgress{},
+		})
+		if err != nil {
+			return err
+		}
+		resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(*job.ID()))}
+		return nil
+	}
+	header := sqlbase.ResultColumns{{Name: "job_id", Typ: types.Int}}
+	return fn, header, nil, false, nil
+}
+
+// streamIngestionJobDescription returns the description of a replication job,
+// with the secrets of its stream address redacted.
+func streamIngestionJobDescription(
+	p sql.PlanHookState, ingestionStmt *tree.StreamIngestion, streamAddress string,
+) (string, error) {
+	cleanedAddress, err := cloud.SanitizeExternalStorageURI(streamAddress, []string{"password"})
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(cleanedAddress)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "redacted")
+	}
+	r := &tree.StreamIngestion{
+		TenantID:      ingestionStmt.TenantID,
+		StreamAddress: tree.NewDString(u.String()),
+	}
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(r, ann), nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingccl
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamIngestion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	source := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{})
+	defer source.Stopper().Stop(ctx)
+	dest := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{})
+	defer dest.Stopper().Stop(ctx)
+
+	sourceSQL := sqlutils.MakeSQLRunner(source.ServerConn(0))
+	sourceSQL.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sourceSQL.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	sourceSQL.Exec(t, `SET CLUSTER SETTING stream_replication.min_checkpoint_frequency = '10ms'`)
+	destSQL := sqlutils.MakeSQLRunner(dest.ServerConn(0))
+	destSQL.Exec(t, `SET CLUSTER SETTING stream_replication.cutover_poll_interval = '10ms'`)
+
+	const tenantID = 10
+	prefix := keys.MakeTenantPrefix(roachpb.MakeTenantID(tenantID))
+	key := func(s string) roachpb.Key {
+		return append(prefix[:len(prefix):len(prefix)], s...)
+	}
+	sourceDB, destDB := source.Server(0).DB(), dest.Server(0).DB()
+	// scan returns the contents of the keyspace of the tenant as of ts, or as
+	// of now if ts is empty.
+	scan := func(db *kv.DB, ts hlc.Timestamp) []string {
+		var res []string
+		require.NoError(t, db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			if !ts.IsEmpty() {
+				txn.SetFixedTimestamp(ctx, ts)
+			}
+			kvs, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0 /* maxRows */)
+			if err != nil {
+				return err
+			}
+			res = res[:0]
+			for _, row := range kvs {
+				res = append(res, fmt.Sprintf("%s=%s", row.Key, row.ValueBytes()))
+			}
+			return nil
+		}))
+		return res
+	}
+	waitForResolved := func(jobID int64, ts hlc.Timestamp) {
+		testutils.SucceedsSoon(t, func() error {
+			var highWater gosql.NullString
+			destSQL.QueryRow(t,
+				`SELECT high_water_timestamp FROM crdb_internal.jobs WHERE job_id = $1`, jobID,
+			).Scan(&highWater)
+			if !highWater.Valid {
+				return errors.New("no resolved timestamp yet")
+			}
+			resolved, err := parseDecimalTimestamp(highWater.String)
+			if err != nil {
+				return err
+			}
+			if resolved.Less(ts) {
+				return errors.Errorf("resolved timestamp %s is before %s", resolved, ts)
+			}
+			return nil
+		})
+	}
+
+	// Writes made before the stream is started are replicated by its initial
+	// scan.
+	for _, k := range []string{"a", "b", "c"} {
+		require.NoError(t, sourceDB.Put(ctx, key(k), "v1"))
+	}
+
+	pgURL, cleanup := sqlutils.PGUrl(
+		t, source.Server(0).ServingSQLAddr(), t.Name(), url.User(security.RootUser),
+	)
+	defer cleanup()
+	var jobID int64
+	destSQL.QueryRow(t,
+		`RESTORE TENANT 10 FROM REPLICATION STREAM FROM $1`, pgURL.String(),
+	).Scan(&jobID)
+
+	destSQL.ExpectErr(t, `system tenant replication not supported`,
+		`RESTORE TENANT 1 FROM REPLICATION STREAM FROM $1`, pgURL.String())
+
+	// Writes made after the stream is started are replicated by its rangefeed.
+	require.NoError(t, sourceDB.Put(ctx, key("b"), "v2"))
+	require.NoError(t, sourceDB.Del(ctx, key("c")))
+	require.NoError(t, sourceDB.Put(ctx, key("d"), "v1"))
+	waitForResolved(jobID, source.Server(0).Clock().Now())
+	require.Equal(t, scan(sourceDB, hlc.Timestamp{}), scan(destDB, hlc.Timestamp{}))
+	destSQL.ExpectErr(t, `keyspace of tenant 10 is not empty`,
+		`RESTORE TENANT 10 FROM REPLICATION STREAM FROM $1`, pgURL.String())
+
+	// After the cutover, the keyspace of the tenant is consistent as of the
+	// cutover time, regardless of the writes ingested after it.
+	require.NoError(t, sourceDB.Put(ctx, key("a"), "v2"))
+	require.NoError(t, sourceDB.Put(ctx, key("e"), "v1"))
+	var cutoverStr string
+	destSQL.QueryRow(t,
+		`SELECT crdb_internal.complete_stream_ingestion_job($1)`, jobID,
+	).Scan(&cutoverStr)
+	cutoverTime, err := parseDecimalTimestamp(cutoverStr)
+	require.NoError(t, err)
+	destSQL.ExpectErr(t, `cutover of replication job \d+ was already requested`,
+		`SELECT crdb_internal.complete_stream_ingestion_job($1)`, jobID)
+	jobutils.WaitForJob(t, destSQL, jobID)
+	require.Equal(t, scan(sourceDB, cutoverTime), scan(destDB, hlc.Timestamp{}))
+}
@@ -0,0 +1,258 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingccl
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// minCheckpointFrequency controls how often the resolved timestamp of a
+// replication stream is emitted.
+var minCheckpointFrequency = settings.RegisterNonNegativeDurationSetting(
+	"stream_replication.min_checkpoint_frequency",
+	"controls minimum frequency the stream replication source cluster sends "+
+		"resolved timestamps to the destination",
+	10*time.Second,
+)
+
+// initialScanPageSize is the number of keys read per request by the initial
+// scan of a replication stream.
+const initialScanPageSize = 10000
+
+func init() {
+	builtins.MakeReplicationStreamGenerator = makeReplicationStreamGenerator
+}
+
+// tenantSpan returns the span of the keyspace of the passed tenant.
+func tenantSpan(tenantID uint64) roachpb.Span {
+	prefix := keys.MakeTenantPrefix(roachpb.MakeTenantID(tenantID))
+	return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+}
+
+// replicationStreamGenerator implements crdb_internal.replication_stream. It
+// emits the contents of the keyspace of a tenant, followed by all the writes
+// made to it as reported by a rangefeed, along with the resolved timestamps
+// of the rangefeed.
+type replicationStreamGenerator struct {
+	p         sql.PlanHookState
+	span      roachpb.Span
+	startTime hlc.Timestamp
+
+	cancel  func()
+	group   ctxgroup.Group
+	eventCh chan streamEvent
+	cur     streamEvent
+}
+
+var _ tree.ValueGenerator = &replicationStreamGenerator{}
+
+func makeReplicationStreamGenerator(
+	evalCtx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	p, ok := evalCtx.Planner.(sql.PlanHookState)
+	if !ok {
+		return nil, errors.AssertionFailedf("replication streams require a planner")
+	}
+	if err := p.RequireAdminRole(evalCtx.Context, "REPLICATION STREAM"); err != nil {
+		return nil, err
+	}
+	execCfg := p.ExecCfg()
+	if err := utilccl.CheckEnterpriseEnabled(
+		execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "REPLICATION STREAM",
+	); err != nil {
+		return nil, err
+	}
+
+	tenantID := int64(tree.MustBeDInt(args[0]))
+	if tenantID <= int64(roachpb.SystemTenantID.ToUint64()) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"invalid tenant ID %d: system tenant replication not supported", tenantID)
+	}
+	startTimeDecimal := tree.MustBeDDecimal(args[1])
+	startTime, err := tree.DecimalToHLC(&startTimeDecimal.Decimal)
+	if err != nil {
+		return nil, err
+	}
+	return &replicationStreamGenerator{
+		p:         p,
+		span:      tenantSpan(uint64(tenantID)),
+		startTime: startTime,
+	}, nil
+}
+
+// ResolvedType is part of the tree.ValueGenerator interface.
+func (g *replicationStreamGenerator) ResolvedType() *types.T {
+	return builtins.ReplicationStreamGeneratorType
+}
+
+// Start is part of the tree.ValueGenerator interface.
+func (g *replicationStreamGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	ctx, g.cancel = context.WithCancel(ctx)
+	g.eventCh = make(chan streamEvent)
+	g.group = ctxgroup.WithContext(ctx)
+	g.group.GoCtx(func(ctx context.Context) error {
+		defer close(g.eventCh)
+		return g.run(ctx)
+	})
+	return nil
+}
+
+// run emits the events of the stream until ctx is canceled or the rangefeed
+// fails.
+func (g *replicationStreamGenerator) run(ctx context.Context) error {
+	execCfg := g.p.ExecCfg()
+	startTime := g.startTime
+	if startTime.IsEmpty() {
+		startTime = execCfg.Clock.Now()
+		if err := g.initialScan(ctx, startTime); err != nil {
+			return err
+		}
+		if err := g.emit(ctx, streamEvent{resolved: startTime}); err != nil {
+			return err
+		}
+	}
+
+	rangefeedCh := make(chan *roachpb.RangeFeedEvent)
+	group := ctxgroup.WithContext(ctx)
+	group.GoCtx(func(ctx context.Context) error {
+		return execCfg.DistSender.RangeFeed(ctx, g.span, startTime, false /* withDiff */, rangefeedCh)
+	})
+	group.GoCtx(func(ctx context.Context) error {
+		frontier := span.MakeFrontier(g.span)
+		lastResolved := startTime
+		var lastEmitted time.Time
+		for {
+			var ev *roachpb.RangeFeedEvent
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ev = <-rangefeedCh:
+			}
+			switch t := ev.GetValue().(type) {
+			case *roachpb.RangeFeedValue:
+				streamEv := streamEvent{kv: roachpb.KeyValue{Key: t.Key, Value: t.Value}}
+				if err := g.emit(ctx, streamEv); err != nil {
+					return err
+				}
+			case *roachpb.RangeFeedCheckpoint:
+				if !frontier.Forward(t.Span, t.ResolvedTS) {
+					continue
+				}
+				resolved := frontier.Frontier()
+				if resolved.LessEq(lastResolved) ||
+					timeutil.Since(lastEmitted) < minCheckpointFrequency.Get(&execCfg.Settings.SV) {
+					continue
+				}
+				if err := g.emit(ctx, streamEvent{resolved: resolved}); err != nil {
+					return err
+				}
+				lastResolved, lastEmitted = resolved, timeutil.Now()
+			default:
+				return errors.AssertionFailedf("unexpected rangefeed event %v", ev)
+			}
+		}
+	})
+	return group.Wait()
+}
+
+// initialScan emits the contents of the span of the stream as of ts.
+func (g *replicationStreamGenerator) initialScan(ctx context.Context, ts hlc.Timestamp) error {
+	db := g.p.ExecCfg().DB
+	resume := g.span.Key
+	for resume != nil {
+		var kvs []kv.KeyValue
+		if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			txn.SetFixedTimestamp(ctx, ts)
+			var err error
+			kvs, err = txn.Scan(ctx, resume, g.span.EndKey, initialScanPageSize)
+			return err
+		}); err != nil {
+			return err
+		}
+		for _, row := range kvs {
+			ev := streamEvent{kv: roachpb.KeyValue{Key: row.Key, Value: *row.Value}}
+			if err := g.emit(ctx, ev); err != nil {
+				return err
+			}
+		}
+		resume = nil
+		if len(kvs) == initialScanPageSize {
+			resume = kvs[len(kvs)-1].Key.Next()
+		}
+	}
+	return nil
+}
+
+func (g *replicationStreamGenerator) emit(ctx context.Context, ev streamEvent) error {
+	select {
+	case g.eventCh <- ev:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Next is part of the tree.ValueGenerator interface.
+func (g *replicationStreamGenerator) Next(ctx context.Context) (bool, error) {
+	select {
+	case ev, ok := <-g.eventCh:
+		if !ok {
+			return false, g.group.Wait()
+		}
+		g.cur = ev
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// Values is part of the tree.ValueGenerator interface.
+func (g *replicationStreamGenerator) Values() (tree.Datums, error) {
+	if g.cur.kv.Key == nil {
+		return tree.Datums{
+			tree.DNull, tree.DNull, tree.DNull, tree.TimestampToDecimal(g.cur.resolved),
+		}, nil
+	}
+	value := tree.DNull
+	if g.cur.kv.Value.IsPresent() {
+		value = tree.NewDBytes(tree.DBytes(g.cur.kv.Value.RawBytes))
+	}
+	return tree.Datums{
+		tree.NewDBytes(tree.DBytes(g.cur.kv.Key)),
+		value,
+		tree.TimestampToDecimal(g.cur.kv.Value.Timestamp),
+		tree.DNull,
+	}, nil
+}
+
+// Close is part of the tree.ValueGenerator interface.
+func (g *replicationStreamGenerator) Close() {
+	if g.cancel != nil {
+		g.cancel()
+		_ = g.group.Wait()
+	}
+}
@@ -62,6 +62,10 @@ const (
 	Version20_1
 	VersionStart20_2
 	VersionGeospatialType
+	VersionScheduledJobs
+	VersionJobHistory
+	VersionAdvisoryLocks
+	VersionUserDefinedSchemas
 
 	// Add new versions here (step one of two).
 )
@@ -474,6 +478,30 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionGeospatialType,
 		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 2},
 	},
+	{
+		// VersionScheduledJobs adds the system.scheduled_jobs table and enables
+		// the job scheduler.
+		Key:     VersionScheduledJobs,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 3},
+	},
+	{
+		// VersionJobHistory adds the system.job_history table, to which the
+		// events of jobs are recorded.
+		Key:     VersionJobHistory,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 4},
+	},
+	{
+		// VersionAdvisoryLocks adds the system.advisory_locks table, which stores
+		// the advisory locks held by sessions.
+		Key:     VersionAdvisoryLocks,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 5},
+	},
+	{
+		// VersionUserDefinedSchemas adds schema descriptors and allows users to
+		// create schemas other than public within a database.
+		Key:     VersionUserDefinedSchemas,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 6},
+	},
 
 	// Add new versions here (step two of two).
 
@@ -26,6 +26,14 @@ func ResetConstructors() func() {
 	return func() { constructors = old }
 }
 
+func ResetRetryPolicies() func() {
+	old := make(map[jobspb.Type]RetryPolicy)
+	for k, v := range retryPolicies {
+		old[k] = v
+	}
+	return func() { retryPolicies = old }
+}
+
 // FakeResumer calls optional callbacks during the job lifecycle.
 type FakeResumer struct {
 	OnResume     func(context.Context, chan<- tree.Datums) error
@@ -0,0 +1,136 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// HistoryEventType is the type of an event recorded to the history of a job
+// in system.job_history.
+type HistoryEventType string
+
+const (
+	// HistoryEventStatusChange is recorded when a job is created and when its
+	// status changes. The error of the event is the error of the job if it is
+	// reverting or failed.
+	HistoryEventStatusChange HistoryEventType = "status_change"
+	// HistoryEventAdoption is recorded when a node adopts a job.
+	HistoryEventAdoption HistoryEventType = "adoption"
+	// HistoryEventProgress is a periodic sample of the progress of a job.
+	HistoryEventProgress HistoryEventType = "progress"
+	// HistoryEventResumeError is recorded when the resumer of a job returns an
+	// error with which the job is retried.
+	HistoryEventResumeError HistoryEventType = "resume_error"
+	// HistoryEventCleanupError is recorded when the cleanup of a job which
+	// failed or was canceled returns an error with which it is retried.
+	HistoryEventCleanupError HistoryEventType = "cleanup_error"
+)
+
+var historyProgressSampleInterval = settings.RegisterNonNegativeDurationSetting(
+	"jobs.history.progress_sample_interval",
+	"the minimum delay between two samples of the progress of a job recorded to its history "+
+		"(0 = do not sample the progress)",
+	time.Minute,
+)
+
+// historyEvent is an event to record to the history of a job.
+type historyEvent struct {
+	typ HistoryEventType
+	err string
+}
+
+// shouldSampleProgress returns whether an update of the progress of the job
+// made at now should be recorded to its history.
+func (j *Job) shouldSampleProgress(now time.Time) bool {
+	interval := historyProgressSampleInterval.Get(&j.registry.settings.SV)
+	if interval == 0 {
+		return false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.mu.lastProgressSample.IsZero() || now.Sub(j.mu.lastProgressSample) >= interval
+}
+
+// writeHistory records events to the history of the job with the given ID in
+// txn. Every event records the state of the job after the transaction: its
+// status and its progress, and the node which recorded it.
+func (r *Registry) writeHistory(
+	ctx context.Context,
+	txn *kv.Txn,
+	id int64,
+	status Status,
+	progress *jobspb.Progress,
+	events []historyEvent,
+) error {
+	if len(events) == 0 || !r.settings.Version.IsActive(ctx, clusterversion.VersionJobHistory) {
+		return nil
+	}
+	nodeID, runningStatus, fractionCompleted, highWater := tree.DNull, tree.DNull, tree.DNull, tree.DNull
+	if instanceID := r.nodeID.SQLInstanceID(); instanceID != 0 {
+		nodeID = tree.NewDInt(tree.DInt(instanceID))
+	}
+	if progress.RunningStatus != "" {
+		runningStatus = tree.NewDString(progress.RunningStatus)
+	}
+	if hw := progress.GetHighWater(); hw != nil {
+		highWater = tree.TimestampToDecimal(*hw)
+	} else {
+		fractionCompleted = tree.NewDFloat(tree.DFloat(progress.GetFractionCompleted()))
+	}
+	const stmt = `INSERT INTO system.job_history
+  (job_id, event_type, node_id, status, running_status, fraction_completed, high_water, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	for _, ev := range events {
+		errStr := tree.DNull
+		if ev.err != "" {
+			errStr = tree.NewDString(ev.err)
+		}
+		if _, err := r.ex.Exec(ctx, "job-history", txn, stmt,
+			id, string(ev.typ), nodeID, string(status), runningStatus, fractionCompleted, highWater, errStr,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordHistoryError records to the history of the job an error with which it
+// is retried. Recording the error is best effort.
+func (r *Registry) recordHistoryError(
+	ctx context.Context, job *Job, typ HistoryEventType, jobErr error,
+) {
+	if err := job.Update(ctx, func(_ *kv.Txn, _ JobMetadata, ju *JobUpdater) error {
+		ju.recordHistoryEvent(typ, jobErr.Error())
+		return nil
+	}); err != nil {
+		log.Warningf(ctx, "job %d: could not record %s to its history: %v", *job.ID(), typ, err)
+	}
+}
+
+// deleteHistory deletes the history of the jobs whose IDs are in the array ids
+// using the given txn.
+func (r *Registry) deleteHistory(ctx context.Context, txn *kv.Txn, ids *tree.DArray) error {
+	if !r.settings.Version.IsActive(ctx, clusterversion.VersionJobHistory) {
+		return nil
+	}
+	const stmt = `DELETE FROM system.job_history WHERE job_id = ANY($1)`
+	_, err := r.ex.Exec(ctx, "gc-job-history", txn, stmt, ids)
+	return err
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	schedulerEnabledSetting = settings.RegisterBoolSetting(
+		"jobs.scheduler.enabled",
+		"enable/disable the execution of the schedules in system.scheduled_jobs",
+		true,
+	)
+	schedulerPaceSetting = settings.RegisterValidatedDurationSetting(
+		"jobs.scheduler.pace",
+		"how often to scan system.scheduled_jobs for schedules that are due to run",
+		time.Minute,
+		func(v time.Duration) error {
+			if v <= 0 {
+				return errors.Errorf("cannot set jobs.scheduler.pace to a non-positive duration: %s", v)
+			}
+			return nil
+		},
+	)
+	schedulerMaxJobsPerIterationSetting = settings.RegisterNonNegativeIntSetting(
+		"jobs.scheduler.max_jobs_per_iteration",
+		"how many schedules to execute per scan of system.scheduled_jobs; 0 means no limit",
+		10,
+	)
+)
+
+// startJobScheduler starts the daemon which periodically executes the
+// schedules of system.scheduled_jobs which are due to run.
+//
+// Every node runs the scheduler; a schedule is executed in a transaction
+// which re-reads it and advances its next run, so that concurrent schedulers
+// execute every run of a schedule only once.
+func (r *Registry) startJobScheduler(ctx context.Context, stopper *stop.Stopper) {
+	env := r.scheduledJobExecutorEnv()
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case <-stopper.ShouldStop():
+				return
+			case <-time.After(schedulerPaceSetting.Get(&r.settings.SV)):
+				if !schedulerEnabledSetting.Get(&r.settings.SV) ||
+					!r.settings.Version.IsActive(ctx, clusterversion.VersionScheduledJobs) {
+					continue
+				}
+				maxSchedules := schedulerMaxJobsPerIterationSetting.Get(&r.settings.SV)
+				if err := r.executeSchedules(ctx, env, maxSchedules); err != nil {
+					log.Errorf(ctx, "error executing schedules: %v", err)
+				}
+			}
+		}
+	})
+}
+
+func (r *Registry) scheduledJobExecutorEnv() *ScheduledJobExecutorEnv {
+	return &ScheduledJobExecutorEnv{
+		Settings:         r.settings,
+		DB:               r.db,
+		InternalExecutor: r.ex,
+		Registry:         r,
+		PlanHookMaker:    r.planFn,
+	}
+}
+
+// ExecuteSchedulesForTesting executes all the schedules that are due to run,
+// regardless of the scheduler settings.
+func (r *Registry) ExecuteSchedulesForTesting(ctx context.Context) error {
+	return r.executeSchedules(ctx, r.scheduledJobExecutorEnv(), 0 /* maxSchedules */)
+}
+
+// executeSchedules executes up to maxSchedules (if non-zero) of the schedules
+// that are due to run.
+func (r *Registry) executeSchedules(
+	ctx context.Context, env *ScheduledJobExecutorEnv, maxSchedules int64,
+) error {
+	limit := ""
+	if maxSchedules > 0 {
+		limit = fmt.Sprintf("LIMIT %d", maxSchedules)
+	}
+	rows, err := r.ex.QueryEx(ctx, "find-scheduled-jobs", nil, /* txn */
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		fmt.Sprintf(`SELECT schedule_id FROM system.scheduled_jobs
+WHERE next_run <= now() ORDER BY next_run %s`, limit),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to find schedules to execute")
+	}
+
+	var executed int
+	for _, row := range rows {
+		id := int64(tree.MustBeDInt(row[0]))
+		var ran bool
+		if err := r.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			var err error
+			ran, err = r.maybeExecuteSchedule(ctx, env, id, txn)
+			return err
+		}); err != nil {
+			log.Warningf(ctx, "error executing schedule %d: %v", id, err)
+			// Record the failure and schedule the next run anyway, so that a
+			// failing schedule is not retried in a loop.
+			if err := r.recordScheduleFailure(ctx, id, err); err != nil {
+				log.Errorf(ctx, "error recording failure of schedule %d: %v", id, err)
+			}
+			continue
+		}
+		if ran {
+			executed++
+		}
+	}
+	if executed > 0 {
+		// Nudge the registry into adopting the jobs which were just created.
+		select {
+		case r.adoptionCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// maybeExecuteSchedule executes the schedule with the given ID in txn if it
+// is still due to run, and schedules its next run. It returns whether the
+// schedule was executed.
+func (r *Registry) maybeExecuteSchedule(
+	ctx context.Context, env *ScheduledJobExecutorEnv, id int64, txn *kv.Txn,
+) (bool, error) {
+	schedule, err := LoadScheduledJob(ctx, r.ex, id, txn)
+	if err != nil {
+		return false, err
+	}
+	// Another node may have executed the schedule since it was found due.
+	if schedule.IsPaused() || schedule.NextRun().After(txn.ReadTimestamp().GoTime()) {
+		return false, nil
+	}
+
+	executor, err := NewScheduledJobExecutor(schedule.ExecutorType())
+	if err != nil {
+		return false, err
+	}
+	if err := executor.ExecuteJob(ctx, env, schedule, txn); err != nil {
+		return false, err
+	}
+	schedule.SetScheduleStatus("executed at %s", timeutil.Now().Format(time.RFC3339))
+	if err := schedule.ScheduleNextRun(); err != nil {
+		return false, err
+	}
+	return true, schedule.Update(ctx, r.ex, txn)
+}
+
+// recordScheduleFailure records execErr in the state of the schedule with the
+// given ID and schedules its next run.
+func (r *Registry) recordScheduleFailure(ctx context.Context, id int64, execErr error) error {
+	return r.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		schedule, err := LoadScheduledJob(ctx, r.ex, id, txn)
+		if err != nil {
+			return err
+		}
+		schedule.SetScheduleStatus("failed at %s: %v", timeutil.Now().Format(time.RFC3339), execErr)
+		if err := schedule.ScheduleNextRun(); err != nil {
+			return err
+		}
+		return schedule.Update(ctx, r.ex, txn)
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -44,6 +45,9 @@ type Job struct {
 		syncutil.Mutex
 		payload  jobspb.Payload
 		progress jobspb.Progress
+		// lastProgressSample is the time at which the progress of the job was
+		// last recorded to its history by this Job.
+		lastProgressSample time.Time
 	}
 }
 
@@ -729,8 +733,13 @@ func (j *Job) insert(ctx context.Context, id int64, lease *jobspb.Lease) error {
 		}
 
 		const stmt = "INSERT INTO system.jobs (id, status, payload, progress) VALUES ($1, $2, $3, $4)"
-		_, err = j.registry.ex.Exec(ctx, "job-insert", txn, stmt, id, StatusRunning, payloadBytes, progressBytes)
-		return err
+		if _, err := j.registry.ex.Exec(
+			ctx, "job-insert", txn, stmt, id, StatusRunning, payloadBytes, progressBytes,
+		); err != nil {
+			return err
+		}
+		return j.registry.writeHistory(ctx, txn, id, StatusRunning, &j.mu.progress,
+			[]historyEvent{{typ: HistoryEventStatusChange}})
 	}); err != nil {
 		return err
 	}
@@ -749,6 +758,7 @@ func (j *Job) adopt(ctx context.Context, oldLease *jobspb.Lease) error {
 			md.Payload.StartedMicros = timeutil.ToUnixMicros(j.registry.clock.Now().GoTime())
 		}
 		ju.UpdatePayload(md.Payload)
+		ju.recordHistoryEvent(HistoryEventAdoption, "")
 		return nil
 	})
 }
@@ -2314,3 +2314,150 @@ func TestUnmigratedSchemaChangeJobs(t *testing.T) {
 		}
 	})
 }
+
+func TestJobRetryPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer jobs.ResetConstructors()()
+	defer jobs.ResetRetryPolicies()()
+	defer func(oldInterval time.Duration) {
+		jobs.DefaultAdoptInterval = oldInterval
+	}(jobs.DefaultAdoptInterval)
+	jobs.DefaultAdoptInterval = 10 * time.Millisecond
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	registry := s.JobRegistry().(*jobs.Registry)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	var resumes int32
+	jobs.RegisterConstructor(jobspb.TypeImport, func(_ *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+		return jobs.FakeResumer{
+			OnResume: func(context.Context, chan<- tree.Datums) error {
+				atomic.AddInt32(&resumes, 1)
+				return jobs.NewRetryJobError("boom")
+			},
+		}
+	})
+	jobs.RegisterRetryPolicy(jobspb.TypeImport, jobs.RetryPolicy{MaxRetries: 2})
+
+	j, _, err := registry.CreateAndStartJob(ctx, nil, jobs.Record{
+		Details:  jobspb.ImportDetails{},
+		Progress: jobspb.ImportProgress{},
+	})
+	require.NoError(t, err)
+
+	// The job is retried twice, then fails.
+	testutils.SucceedsSoon(t, func() error {
+		var status string
+		sqlDB.QueryRow(t, `SELECT status FROM system.jobs WHERE id = $1`, *j.ID()).Scan(&status)
+		if jobs.Status(status) != jobs.StatusFailed {
+			return errors.Errorf("expected job to fail, found status %s", status)
+		}
+		return nil
+	})
+	require.Equal(t, int32(3), atomic.LoadInt32(&resumes))
+	var jobErr string
+	sqlDB.QueryRow(t, `SELECT error FROM [SHOW JOBS] WHERE job_id = $1`, *j.ID()).Scan(&jobErr)
+	require.Regexp(t, "job exceeded the maximum of 2 retries: boom", jobErr)
+}
+
+func TestJobMaxRunning(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer jobs.ResetConstructors()()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	registry := s.JobRegistry().(*jobs.Registry)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING jobs.registry.max_running.import = 1`)
+
+	resumed := make(chan int64)
+	done := make(chan struct{})
+	jobs.RegisterConstructor(jobspb.TypeImport, func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+		return jobs.FakeResumer{
+			OnResume: func(ctx context.Context, _ chan<- tree.Datums) error {
+				resumed <- *job.ID()
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-done:
+					return nil
+				}
+			},
+		}
+	})
+	record := jobs.Record{Details: jobspb.ImportDetails{}, Progress: jobspb.ImportProgress{}}
+
+	first, firstErrCh, err := registry.CreateAndStartJob(ctx, nil, record)
+	require.NoError(t, err)
+	require.Equal(t, *first.ID(), <-resumed)
+
+	// The second job is queued while the first one runs.
+	second, secondErrCh, err := registry.CreateAndStartJob(ctx, nil, record)
+	require.NoError(t, err)
+	testutils.SucceedsSoon(t, func() error {
+		var runningStatus gosql.NullString
+		sqlDB.QueryRow(t, `SELECT running_status FROM [SHOW JOBS] WHERE job_id = $1`,
+			*second.ID()).Scan(&runningStatus)
+		if runningStatus.String != string(jobs.RunningStatusQueued) {
+			return errors.Errorf("expected job to be queued, found running status %q", runningStatus.String)
+		}
+		return nil
+	})
+	select {
+	case id := <-resumed:
+		t.Fatalf("job %d resumed while the limit is reached", id)
+	default:
+	}
+
+	// The second job runs once the first one completed.
+	done <- struct{}{}
+	require.NoError(t, <-firstErrCh)
+	require.Equal(t, *second.ID(), <-resumed)
+	close(done)
+	require.NoError(t, <-secondErrCh)
+}
+
+func TestJobHistory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer jobs.ResetConstructors()()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	registry := s.JobRegistry().(*jobs.Registry)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	// Only the first update of the progress of the job is sampled.
+	sqlDB.Exec(t, `SET CLUSTER SETTING jobs.history.progress_sample_interval = '1h'`)
+
+	jobs.RegisterConstructor(jobspb.TypeImport, func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+		return jobs.FakeResumer{
+			OnResume: func(ctx context.Context, _ chan<- tree.Datums) error {
+				if err := job.FractionProgressed(ctx, jobs.FractionUpdater(0.5)); err != nil {
+					return err
+				}
+				return job.FractionProgressed(ctx, jobs.FractionUpdater(0.75))
+			},
+		}
+	})
+	record := jobs.Record{Details: jobspb.ImportDetails{}, Progress: jobspb.ImportProgress{}}
+	job, errCh, err := registry.CreateAndStartJob(ctx, nil, record)
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+
+	sqlDB.CheckQueryResults(t, fmt.Sprintf(
+		`SELECT event_type, status, fraction_completed, node_id IS NOT NULL, error
+		   FROM [SHOW JOB %d WITH HISTORY]`, *job.ID()),
+		[][]string{
+			{"status_change", "running", "0", "true", "NULL"},
+			{"progress", "running", "0.5", "true", "NULL"},
+			{"status_change", "succeeded", "1", "true", "NULL"},
+		},
+	)
+	sqlDB.CheckQueryResults(t, fmt.Sprintf(
+		`SELECT count(*) FROM crdb_internal.job_history WHERE job_id = %d`, *job.ID()),
+		[][]string{{"3"}},
+	)
+}
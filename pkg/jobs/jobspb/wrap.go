@@ -27,6 +27,7 @@ var _ Details = SchemaChangeDetails{}
 var _ Details = ChangefeedDetails{}
 var _ Details = CreateStatsDetails{}
 var _ Details = SchemaChangeGCDetails{}
+var _ Details = StreamIngestionDetails{}
 
 // ProgressDetails is a marker interface for job progress details proto structs.
 type ProgressDetails interface{}
@@ -37,6 +38,7 @@ var _ ProgressDetails = SchemaChangeProgress{}
 var _ ProgressDetails = ChangefeedProgress{}
 var _ ProgressDetails = CreateStatsProgress{}
 var _ ProgressDetails = SchemaChangeGCProgress{}
+var _ ProgressDetails = StreamIngestionProgress{}
 
 // Type returns the payload's job type.
 func (p *Payload) Type() Type {
@@ -64,6 +66,8 @@ func DetailsType(d isPayload_Details) Type {
 		return TypeCreateStats
 	case *Payload_SchemaChangeGC:
 		return TypeSchemaChangeGC
+	case *Payload_StreamIngestion:
+		return TypeStreamIngestion
 	default:
 		panic(fmt.Sprintf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -92,6 +96,8 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_CreateStats{CreateStats: &d}
 	case SchemaChangeGCProgress:
 		return &Progress_SchemaChangeGC{SchemaChangeGC: &d}
+	case StreamIngestionProgress:
+		return &Progress_StreamIngestion{StreamIngestion: &d}
 	default:
 		panic(fmt.Sprintf("WrapProgressDetails: unknown details type %T", d))
 	}
@@ -115,6 +121,8 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.CreateStats
 	case *Payload_SchemaChangeGC:
 		return *d.SchemaChangeGC
+	case *Payload_StreamIngestion:
+		return *d.StreamIngestion
 	default:
 		return nil
 	}
@@ -138,6 +146,8 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.CreateStats
 	case *Progress_SchemaChangeGC:
 		return *d.SchemaChangeGC
+	case *Progress_StreamIngestion:
+		return *d.StreamIngestion
 	default:
 		return nil
 	}
@@ -174,6 +184,8 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_CreateStats{CreateStats: &d}
 	case SchemaChangeGCDetails:
 		return &Payload_SchemaChangeGC{SchemaChangeGC: &d}
+	case StreamIngestionDetails:
+		return &Payload_StreamIngestion{StreamIngestion: &d}
 	default:
 		panic(fmt.Sprintf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -0,0 +1,289 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// RunningStatusQueued is the running status of a job which waits for other
+// jobs of its type to finish because the limit on the number of jobs of that
+// type running in the cluster is reached.
+const RunningStatusQueued RunningStatus = "queued"
+
+var (
+	retryInitialBackoffSetting = settings.RegisterNonNegativeDurationSetting(
+		"jobs.registry.retry.initial_backoff",
+		"the delay before the first retry of a job whose type does not define a retry policy; "+
+			"the delay doubles on every retry",
+		time.Second,
+	)
+	retryMaxBackoffSetting = settings.RegisterNonNegativeDurationSetting(
+		"jobs.registry.retry.max_backoff",
+		"the maximum delay between the retries of a job whose type does not define a retry policy",
+		time.Hour,
+	)
+)
+
+// maxRunningSettings are the limits on the number of jobs of a type running
+// in the cluster at the same time.
+var maxRunningSettings = map[jobspb.Type]*settings.IntSetting{
+	jobspb.TypeBackup: settings.RegisterNonNegativeIntSetting(
+		"jobs.registry.max_running.backup",
+		"the maximum number of BACKUP jobs running at the same time in the cluster (0 = no limit)",
+		0,
+	),
+	jobspb.TypeRestore: settings.RegisterNonNegativeIntSetting(
+		"jobs.registry.max_running.restore",
+		"the maximum number of RESTORE jobs running at the same time in the cluster (0 = no limit)",
+		0,
+	),
+	jobspb.TypeImport: settings.RegisterNonNegativeIntSetting(
+		"jobs.registry.max_running.import",
+		"the maximum number of IMPORT jobs running at the same time in the cluster (0 = no limit)",
+		0,
+	),
+	jobspb.TypeCreateStats: settings.RegisterNonNegativeIntSetting(
+		"jobs.registry.max_running.create_stats",
+		"the maximum number of CREATE STATISTICS jobs running at the same time in the cluster (0 = no limit)",
+		0,
+	),
+}
+
+// RetryPolicy determines how a job whose resumer returns an error created by
+// NewRetryJobError is retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a job is retried before it fails. Zero
+	// means that the job is retried indefinitely.
+	MaxRetries int32
+	// InitialBackoff is the delay before the first retry of a job. The delay
+	// doubles on every retry, up to MaxBackoff. The delay does not grow if
+	// MaxBackoff is less than InitialBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var retryPolicies = make(map[jobspb.Type]RetryPolicy)
+
+// RegisterRetryPolicy registers the retry policy of a job type. The jobs of the
+// types without a policy are retried indefinitely, with the backoff configured
+// by the jobs.registry.retry cluster settings.
+func RegisterRetryPolicy(typ jobspb.Type, policy RetryPolicy) {
+	retryPolicies[typ] = policy
+}
+
+func (r *Registry) retryPolicy(typ jobspb.Type) RetryPolicy {
+	if policy, ok := retryPolicies[typ]; ok {
+		return policy
+	}
+	return RetryPolicy{
+		InitialBackoff: retryInitialBackoffSetting.Get(&r.settings.SV),
+		MaxBackoff:     retryMaxBackoffSetting.Get(&r.settings.SV),
+	}
+}
+
+// backoff returns the delay before the retry of a job which was retried
+// numRetries times, including this retry.
+func (p RetryPolicy) backoff(numRetries int32) time.Duration {
+	backoff, maxBackoff := p.InitialBackoff, p.MaxBackoff
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+	for i := int32(1); i < numRetries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// recordRetry records in the payload of a job that it is retried after its
+// resumer returned the retryable error retryErr, and when it can be adopted
+// again. It returns the error with which the job fails if it has exhausted the
+// retries of its policy.
+func (r *Registry) recordRetry(ctx context.Context, job *Job, retryErr error) error {
+	var exhaustedErr error
+	if err := job.Update(ctx, func(_ *kv.Txn, md JobMetadata, ju *JobUpdater) error {
+		policy := r.retryPolicy(md.Payload.Type())
+		ju.recordHistoryEvent(HistoryEventResumeError, retryErr.Error())
+		md.Payload.NumRetries++
+		if policy.MaxRetries > 0 && md.Payload.NumRetries > policy.MaxRetries {
+			exhaustedErr = errors.Errorf("job exceeded the maximum of %d retries: %s",
+				policy.MaxRetries, retryErr)
+			return nil
+		}
+		if backoff := policy.backoff(md.Payload.NumRetries); backoff > 0 {
+			md.Payload.NextRetryMicros = timeutil.ToUnixMicros(r.clock.Now().GoTime().Add(backoff))
+		}
+		ju.UpdatePayload(md.Payload)
+		return nil
+	}); err != nil {
+		// The job is retried right away: recording the retry is best effort.
+		log.Warningf(ctx, "job %d: could not record retry: %v", *job.ID(), err)
+		return nil
+	}
+	return exhaustedErr
+}
+
+// waitingForRetry returns whether a running job with the given payload is
+// waiting for the backoff of its last retry to elapse.
+func (r *Registry) waitingForRetry(payload *jobspb.Payload) bool {
+	return payload.NextRetryMicros > timeutil.ToUnixMicros(r.clock.Now().GoTime())
+}
+
+// runSlotRetryOptions are the options of the loop which waits for a job to be
+// allowed to run by the limit on the number of running jobs of its type.
+var runSlotRetryOptions = retry.Options{
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+}
+
+// waitForRunSlot waits until the job can run without exceeding the limit on
+// the number of running jobs of its type. While it waits, the running status of
+// the job is RunningStatusQueued.
+func (r *Registry) waitForRunSlot(ctx context.Context, job *Job) error {
+	limitSetting, ok := maxRunningSettings[job.Payload().Type()]
+	if !ok {
+		return nil
+	}
+	for re := retry.StartWithCtx(ctx, runSlotRetryOptions); re.Next(); {
+		claimed, err := r.maybeClaimRunSlot(ctx, job, limitSetting.Get(&r.settings.SV))
+		if err != nil || claimed {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// maybeClaimRunSlot returns whether the job can run given the limit on the
+// number of running jobs of its type, and marks it as queued if it can't.
+//
+// The running jobs are counted in the same transaction which updates the
+// running status of the job, so two jobs which concurrently claim the last
+// slot conflict. The jobs which are not queued are counted as running, even
+// before they claimed a slot, so they never exceed the limit.
+func (r *Registry) maybeClaimRunSlot(ctx context.Context, job *Job, limit int64) (bool, error) {
+	var claimed bool
+	err := job.Update(ctx, func(txn *kv.Txn, md JobMetadata, ju *JobUpdater) error {
+		claimed = true
+		if limit > 0 {
+			running, err := r.countRunningJobs(ctx, txn, md.Payload.Type(), md.ID)
+			if err != nil {
+				return err
+			}
+			claimed = running < limit
+		}
+		queued := RunningStatus(md.Progress.RunningStatus) == RunningStatusQueued
+		if claimed == queued {
+			if claimed {
+				md.Progress.RunningStatus = ""
+			} else {
+				md.Progress.RunningStatus = string(RunningStatusQueued)
+			}
+			ju.UpdateProgress(md.Progress)
+		}
+		return nil
+	})
+	return claimed, err
+}
+
+// countRunningJobs returns the number of jobs of the given type, other than
+// the job with the ID skipID, which are running and not queued.
+//
+// Only the IDs of the running jobs are scanned, from the index on their status.
+// The types of jobs never change, so they're cached by the registry, and the
+// payload of a running job is only read the first time it's counted. The
+// progress is only read for the jobs of the given type.
+func (r *Registry) countRunningJobs(
+	ctx context.Context, txn *kv.Txn, typ jobspb.Type, skipID int64,
+) (int64, error) {
+	rows, err := r.ex.Query(ctx, "count-running-jobs", txn,
+		`SELECT id FROM system.jobs WHERE status = $1`, StatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	ofType := tree.NewDArray(types.Int)
+	unknownType := tree.NewDArray(types.Int)
+	running := make(map[int64]struct{}, len(rows))
+	r.mu.Lock()
+	for _, row := range rows {
+		id := int64(tree.MustBeDInt(row[0]))
+		if id == skipID {
+			continue
+		}
+		running[id] = struct{}{}
+		if t, ok := r.mu.runningJobTypes[id]; !ok {
+			unknownType.Array = append(unknownType.Array, row[0])
+		} else if t == typ {
+			ofType.Array = append(ofType.Array, row[0])
+		}
+	}
+	// Forget the types of the jobs which aren't running anymore.
+	for id := range r.mu.runningJobTypes {
+		if _, ok := running[id]; !ok {
+			delete(r.mu.runningJobTypes, id)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(unknownType.Array) > 0 {
+		rows, err := r.ex.Query(ctx, "running-job-types", txn,
+			`SELECT id, payload FROM system.jobs WHERE id = ANY($1)`, unknownType)
+		if err != nil {
+			return 0, err
+		}
+		for _, row := range rows {
+			payload, err := UnmarshalPayload(row[1])
+			if err != nil {
+				return 0, err
+			}
+			r.mu.Lock()
+			r.mu.runningJobTypes[int64(tree.MustBeDInt(row[0]))] = payload.Type()
+			r.mu.Unlock()
+			if payload.Type() == typ {
+				ofType.Array = append(ofType.Array, row[0])
+			}
+		}
+	}
+	if len(ofType.Array) == 0 {
+		return 0, nil
+	}
+
+	rows, err = r.ex.Query(ctx, "running-jobs-progress", txn,
+		`SELECT progress FROM system.jobs WHERE id = ANY($1)`, ofType)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, row := range rows {
+		progress, err := UnmarshalProgress(row[0])
+		if err != nil {
+			return 0, err
+		}
+		if RunningStatus(progress.RunningStatus) != RunningStatusQueued {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -149,6 +149,9 @@ type Registry struct {
 		// propagated to jobs via the .Progressed call. This function should not be
 		// used to cancel a job in that way.
 		jobs map[int64]context.CancelFunc
+		// runningJobTypes caches the types of the running jobs counted by
+		// countRunningJobs, by their ID.
+		runningJobTypes map[int64]jobspb.Type
 	}
 
 	TestingResumerCreationKnobs map[jobspb.Type]func(Resumer) Resumer
@@ -203,6 +206,7 @@ func MakeRegistry(
 	}
 	r.mu.epoch = 1
 	r.mu.jobs = make(map[int64]context.CancelFunc)
+	r.mu.runningJobTypes = make(map[int64]jobspb.Type)
 	r.metrics.InitHooks(histogramWindowInterval)
 	return r
 }
@@ -521,6 +525,8 @@ func (r *Registry) Start(
 			}
 		}
 	})
+
+	r.startJobScheduler(context.Background(), stopper)
 	return nil
 }
 
@@ -622,17 +628,23 @@ func (r *Registry) cleanupOldJobs(ctx context.Context, olderThan time.Time) erro
 	}
 	if len(toDelete.Array) > 0 {
 		log.Infof(ctx, "cleaning up %d expired job records", len(toDelete.Array))
-		const stmt = `DELETE FROM system.jobs WHERE id = ANY($1)`
-		var nDeleted int
-		if nDeleted, err = r.ex.Exec(
-			ctx, "gc-jobs", nil /* txn */, stmt, toDelete,
-		); err != nil {
-			return errors.Wrap(err, "deleting old jobs")
-		}
-		if nDeleted != len(toDelete.Array) {
-			return errors.Errorf("asked to delete %d rows but %d were actually deleted",
-				len(toDelete.Array), nDeleted)
-		}
+		// The history of the jobs is deleted in the same transaction, so that it
+		// can't outlive them if the cleanup fails halfway.
+		return r.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			const stmt = `DELETE FROM system.jobs WHERE id = ANY($1)`
+			nDeleted, err := r.ex.Exec(ctx, "gc-jobs", txn, stmt, toDelete)
+			if err != nil {
+				return errors.Wrap(err, "deleting old jobs")
+			}
+			if nDeleted != len(toDelete.Array) {
+				return errors.Errorf("asked to delete %d rows but %d were actually deleted",
+					len(toDelete.Array), nDeleted)
+			}
+			if err := r.deleteHistory(ctx, txn, toDelete); err != nil {
+				return errors.Wrap(err, "deleting the history of old jobs")
+			}
+			return nil
+		})
 	}
 	return nil
 }
@@ -827,10 +839,11 @@ func (r *Registry) stepThroughStateMachine(
 			// paused. We should make this error clearer.
 			return errors.Errorf("job %d: node liveness error: restarting in background", *job.ID())
 		}
-		// TODO(spaskob): enforce a limit on retries.
-		// TODO(spaskob,lucy): Add metrics on job retries. Consider having a backoff
-		// mechanism (possibly combined with a retry limit).
+		// TODO(spaskob,lucy): Add metrics on job retries.
 		if errors.Is(err, retryJobErrorSentinel) {
+			if exhaustedErr := r.recordRetry(ctx, job, err); exhaustedErr != nil {
+				return r.stepThroughStateMachine(ctx, phs, resumer, resultsCh, job, StatusReverting, exhaustedErr)
+			}
 			return errors.Errorf("job %d: %s: restarting in background", *job.ID(), err)
 		}
 		if err, ok := errors.Cause(err).(*InvalidStatusError); ok {
@@ -892,6 +905,7 @@ func (r *Registry) stepThroughStateMachine(
 			return errors.Errorf("job %d: node liveness error: restarting in background", *job.ID())
 		}
 		if errors.Is(err, retryJobErrorSentinel) {
+			r.recordHistoryError(ctx, job, HistoryEventCleanupError, err)
 			return errors.Errorf("job %d: %s: restarting in background", *job.ID(), err)
 		}
 		if err, ok := errors.Cause(err).(*InvalidStatusError); ok {
@@ -938,8 +952,14 @@ func (r *Registry) resume(
 		ctx, span = r.ac.AnnotateCtxWithSpan(ctx, spanName)
 		defer span.Finish()
 
-		// Run the actual job.
+		// Run the actual job, once the limit on the number of running jobs of its
+		// type allows it.
 		status, err := job.CurrentStatus(ctx)
+		if err == nil && status == StatusRunning {
+			if err = r.waitForRunSlot(ctx, job); err == nil {
+				status, err = job.CurrentStatus(ctx)
+			}
+		}
 		if err == nil {
 			var finalResumeError error
 			if job.Payload().FinalResumeError != nil {
@@ -1136,6 +1156,12 @@ WHERE status IN ($1, $2, $3, $4, $5) ORDER BY created DESC`
 				continue
 			}
 		}
+		if status == StatusRunning && r.waitingForRetry(payload) {
+			if log.V(2) {
+				log.Infof(ctx, "job %d: skipping: waiting for the backoff of its retry", *id)
+			}
+			continue
+		}
 		// Below we know that this node holds the lease on the job.
 		job := &Job{id: id, registry: r}
 		resumeCtx, cancel := r.makeCtx()
@@ -291,6 +291,9 @@ CREATE DATABASE IF NOT EXISTS t; CREATE TABLE IF NOT EXISTS t.to_be_mutated AS S
 		db.QueryRow(t,
 			`INSERT INTO system.jobs (status, payload, progress, created) VALUES ($1, $2, $3, $4) RETURNING id`,
 			status, payload, progress, created).Scan(&id)
+		db.Exec(t,
+			`INSERT INTO system.job_history (job_id, event_type, status) VALUES ($1, 'status_change', $2)`,
+			id, status)
 		return strconv.Itoa(int(id))
 	}
 
@@ -323,6 +326,9 @@ CREATE DATABASE IF NOT EXISTS t; CREATE TABLE IF NOT EXISTS t.to_be_mutated AS S
 				}
 				db.CheckQueryResults(t, `SELECT id FROM system.jobs ORDER BY id`, [][]string{
 					{oldRunningJob}, {newRunningJob}, {newSucceededJob}})
+				// The history of the deleted jobs is deleted with them.
+				db.CheckQueryResults(t, `SELECT DISTINCT job_id FROM system.job_history ORDER BY job_id`, [][]string{
+					{oldRunningJob}, {newRunningJob}, {newSucceededJob}})
 
 				if err := s.JobRegistry().(*Registry).cleanupOldJobs(ctx, earlier); err != nil {
 					t.Fatal(err)
@@ -344,7 +350,35 @@ CREATE DATABASE IF NOT EXISTS t; CREATE TABLE IF NOT EXISTS t.to_be_mutated AS S
 					t.Fatal(err)
 				}
 				db.CheckQueryResults(t, `SELECT id FROM system.jobs ORDER BY id`, [][]string{})
+				db.CheckQueryResults(t, `SELECT job_id FROM system.job_history`, [][]string{})
 			}
 		}
 	}
 }
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	for _, tc := range []struct {
+		numRetries int32
+		expected   time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{40, 10 * time.Second},
+	} {
+		if backoff := policy.backoff(tc.numRetries); backoff != tc.expected {
+			t.Errorf("%d retries: expected backoff %s, got %s", tc.numRetries, tc.expected, backoff)
+		}
+	}
+	if backoff := (RetryPolicy{}).backoff(3); backoff != 0 {
+		t.Errorf("expected no backoff, got %s", backoff)
+	}
+	if backoff := (RetryPolicy{InitialBackoff: time.Second}).backoff(3); backoff != time.Second {
+		t.Errorf("expected constant backoff, got %s", backoff)
+	}
+}
@@ -10,4 +10,321 @@
 
 package jobs
 
-import _ "github.com/gorhill/cronexpr" // TODO(yevgeniy): placeholder to force vendoring
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gorhill/cronexpr"
+)
+
+// scheduledJobColumns are the columns of system.scheduled_jobs, in the order
+// expected by (*ScheduledJob).fromDatums.
+const scheduledJobColumns = `schedule_id, schedule_name, created, owner, next_run, ` +
+	`schedule_expr, executor_type, execution_args, schedule_state`
+
+// ScheduledJob is a representation of a row of the system.scheduled_jobs
+// table. A schedule periodically executes an action, typically the creation
+// of a job, according to a cron expression.
+//
+// A schedule whose next_run is NULL is paused: the scheduler ignores it until
+// it is resumed.
+type ScheduledJob struct {
+	scheduleID    int64
+	scheduleName  string
+	created       time.Time
+	owner         string
+	nextRun       time.Time
+	scheduleExpr  string
+	executorType  string
+	executionArgs jobspb.ExecutionArguments
+	state         jobspb.ScheduleState
+}
+
+// NewScheduledJob creates a new ScheduledJob, which is only written to
+// system.scheduled_jobs once Create is called.
+func NewScheduledJob() *ScheduledJob {
+	return &ScheduledJob{}
+}
+
+// LoadScheduledJob loads the schedule with the given ID from
+// system.scheduled_jobs.
+func LoadScheduledJob(
+	ctx context.Context, ex sqlutil.InternalExecutor, id int64, txn *kv.Txn,
+) (*ScheduledJob, error) {
+	row, err := ex.QueryRowEx(ctx, "load-schedule", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		fmt.Sprintf("SELECT %s FROM system.scheduled_jobs WHERE schedule_id = $1", scheduledJobColumns),
+		id,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load schedule %d", id)
+	}
+	if row == nil {
+		return nil, errors.Errorf("schedule %d not found", id)
+	}
+	j := NewScheduledJob()
+	if err := j.fromDatums(row); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// LoadScheduledJobs loads all the schedules of system.scheduled_jobs, ordered
+// by ID.
+func LoadScheduledJobs(
+	ctx context.Context, ex sqlutil.InternalExecutor, txn *kv.Txn,
+) ([]*ScheduledJob, error) {
+	rows, err := ex.QueryEx(ctx, "load-schedules", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		fmt.Sprintf("SELECT %s FROM system.scheduled_jobs ORDER BY schedule_id", scheduledJobColumns),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load schedules")
+	}
+	schedules := make([]*ScheduledJob, 0, len(rows))
+	for _, row := range rows {
+		j := NewScheduledJob()
+		if err := j.fromDatums(row); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, j)
+	}
+	return schedules, nil
+}
+
+// ScheduleID returns the ID of the schedule, which is only set once the
+// schedule has been created.
+func (j *ScheduledJob) ScheduleID() int64 {
+	return j.scheduleID
+}
+
+// ScheduleName returns the name of the schedule.
+func (j *ScheduledJob) ScheduleName() string {
+	return j.scheduleName
+}
+
+// SetScheduleName sets the name of the schedule.
+func (j *ScheduledJob) SetScheduleName(name string) {
+	j.scheduleName = name
+}
+
+// Created returns the time at which the schedule was created.
+func (j *ScheduledJob) Created() time.Time {
+	return j.created
+}
+
+// Owner returns the user on behalf of whom the schedule runs.
+func (j *ScheduledJob) Owner() string {
+	return j.owner
+}
+
+// SetOwner sets the user on behalf of whom the schedule runs.
+func (j *ScheduledJob) SetOwner(owner string) {
+	j.owner = owner
+}
+
+// NextRun returns the next time the schedule is due to run, which is zero if
+// the schedule is paused.
+func (j *ScheduledJob) NextRun() time.Time {
+	return j.nextRun
+}
+
+// SetNextRun sets the next time the schedule is due to run.
+func (j *ScheduledJob) SetNextRun(t time.Time) {
+	j.nextRun = t
+}
+
+// ScheduleExpr returns the cron expression of the schedule. It is empty for a
+// schedule which only runs once.
+func (j *ScheduledJob) ScheduleExpr() string {
+	return j.scheduleExpr
+}
+
+// SetSchedule sets the cron expression of the schedule and schedules its next
+// run accordingly.
+func (j *ScheduledJob) SetSchedule(scheduleExpr string) error {
+	j.scheduleExpr = scheduleExpr
+	return j.ScheduleNextRun()
+}
+
+// ExecutorType returns the name of the ScheduledJobExecutor running the
+// schedule.
+func (j *ScheduledJob) ExecutorType() string {
+	return j.executorType
+}
+
+// ExecutionArgs returns the arguments passed to the executor of the schedule.
+func (j *ScheduledJob) ExecutionArgs() *jobspb.ExecutionArguments {
+	return &j.executionArgs
+}
+
+// SetExecutionDetails sets the executor of the schedule and its arguments.
+func (j *ScheduledJob) SetExecutionDetails(executor string, args jobspb.ExecutionArguments) {
+	j.executorType = executor
+	j.executionArgs = args
+}
+
+// ScheduleStatus returns the status of the last execution of the schedule.
+func (j *ScheduledJob) ScheduleStatus() string {
+	return j.state.Status
+}
+
+// SetScheduleStatus sets the status of the last execution of the schedule.
+func (j *ScheduledJob) SetScheduleStatus(format string, args ...interface{}) {
+	j.state.Status = fmt.Sprintf(format, args...)
+}
+
+// IsPaused returns true if the schedule is paused.
+func (j *ScheduledJob) IsPaused() bool {
+	return j.nextRun.IsZero()
+}
+
+// Pause pauses the schedule.
+func (j *ScheduledJob) Pause() {
+	j.nextRun = time.Time{}
+}
+
+// ScheduleNextRun computes the next time the schedule runs from its cron
+// expression. A schedule without a cron expression is paused.
+func (j *ScheduledJob) ScheduleNextRun() error {
+	if j.scheduleExpr == "" {
+		j.Pause()
+		return nil
+	}
+	expr, err := cronexpr.Parse(j.scheduleExpr)
+	if err != nil {
+		return errors.Wrapf(err, "invalid schedule %q", j.scheduleExpr)
+	}
+	nextRun := expr.Next(timeutil.Now())
+	if nextRun.IsZero() {
+		return errors.Newf("schedule %q never runs", j.scheduleExpr)
+	}
+	j.nextRun = nextRun
+	return nil
+}
+
+// Create writes the schedule to system.scheduled_jobs and sets its ID.
+func (j *ScheduledJob) Create(ctx context.Context, ex sqlutil.InternalExecutor, txn *kv.Txn) error {
+	if j.scheduleID != 0 {
+		return errors.AssertionFailedf("schedule %d already created", j.scheduleID)
+	}
+	args, err := protoutil.Marshal(&j.executionArgs)
+	if err != nil {
+		return err
+	}
+	state, err := protoutil.Marshal(&j.state)
+	if err != nil {
+		return err
+	}
+	row, err := ex.QueryRowEx(ctx, "create-schedule", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`INSERT INTO system.scheduled_jobs
+  (schedule_name, owner, next_run, schedule_expr, executor_type, execution_args, schedule_state)
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING schedule_id, created`,
+		j.scheduleName, j.owner, j.nextRunDatum(), j.scheduleExprDatum(), j.executorType, args, state,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create schedule")
+	}
+	j.scheduleID = int64(tree.MustBeDInt(row[0]))
+	j.created = row[1].(*tree.DTimestampTZ).Time
+	return nil
+}
+
+// Update writes the modifiable fields of the schedule to
+// system.scheduled_jobs.
+func (j *ScheduledJob) Update(ctx context.Context, ex sqlutil.InternalExecutor, txn *kv.Txn) error {
+	args, err := protoutil.Marshal(&j.executionArgs)
+	if err != nil {
+		return err
+	}
+	state, err := protoutil.Marshal(&j.state)
+	if err != nil {
+		return err
+	}
+	n, err := ex.ExecEx(ctx, "update-schedule", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`UPDATE system.scheduled_jobs
+SET schedule_name = $2, next_run = $3, schedule_expr = $4, execution_args = $5, schedule_state = $6
+WHERE schedule_id = $1`,
+		j.scheduleID, j.scheduleName, j.nextRunDatum(), j.scheduleExprDatum(), args, state,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update schedule %d", j.scheduleID)
+	}
+	if n != 1 {
+		return errors.Errorf("schedule %d not found", j.scheduleID)
+	}
+	return nil
+}
+
+// Delete removes the schedule from system.scheduled_jobs.
+func (j *ScheduledJob) Delete(ctx context.Context, ex sqlutil.InternalExecutor, txn *kv.Txn) error {
+	n, err := ex.ExecEx(ctx, "delete-schedule", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`DELETE FROM system.scheduled_jobs WHERE schedule_id = $1`, j.scheduleID,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete schedule %d", j.scheduleID)
+	}
+	if n != 1 {
+		return errors.Errorf("schedule %d not found", j.scheduleID)
+	}
+	return nil
+}
+
+func (j *ScheduledJob) nextRunDatum() tree.Datum {
+	if j.IsPaused() {
+		return tree.DNull
+	}
+	return tree.MustMakeDTimestampTZ(j.nextRun, time.Microsecond)
+}
+
+func (j *ScheduledJob) scheduleExprDatum() tree.Datum {
+	if j.scheduleExpr == "" {
+		return tree.DNull
+	}
+	return tree.NewDString(j.scheduleExpr)
+}
+
+// fromDatums initializes the schedule from a row of system.scheduled_jobs
+// whose columns are scheduledJobColumns.
+func (j *ScheduledJob) fromDatums(row tree.Datums) error {
+	if len(row) != 9 {
+		return errors.AssertionFailedf("expected 9 columns, found %d", len(row))
+	}
+	j.scheduleID = int64(tree.MustBeDInt(row[0]))
+	j.scheduleName = string(tree.MustBeDString(row[1]))
+	j.created = row[2].(*tree.DTimestampTZ).Time
+	j.owner = string(tree.MustBeDString(row[3]))
+	j.nextRun = time.Time{}
+	if row[4] != tree.DNull {
+		j.nextRun = row[4].(*tree.DTimestampTZ).Time
+	}
+	j.scheduleExpr = ""
+	if row[5] != tree.DNull {
+		j.scheduleExpr = string(tree.MustBeDString(row[5]))
+	}
+	j.executorType = string(tree.MustBeDString(row[6]))
+	j.executionArgs = jobspb.ExecutionArguments{}
+	if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[7])), &j.executionArgs); err != nil {
+		return errors.Wrapf(err, "failed to decode execution arguments of schedule %d", j.scheduleID)
+	}
+	j.state = jobspb.ScheduleState{}
+	if row[8] != tree.DNull {
+		if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[8])), &j.state); err != nil {
+			return errors.Wrapf(err, "failed to decode state of schedule %d", j.scheduleID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/errors"
+)
+
+// ScheduledJobExecutor executes the action of a schedule when it is due.
+type ScheduledJobExecutor interface {
+	// ExecuteJob runs the action of the schedule. It is called by the job
+	// scheduler in txn, which also advances the next run of the schedule, so
+	// that the action is executed exactly once per run. Typically, the action
+	// creates a job which the registry adopts once txn commits.
+	ExecuteJob(
+		ctx context.Context, env *ScheduledJobExecutorEnv, schedule *ScheduledJob, txn *kv.Txn,
+	) error
+}
+
+// ScheduledJobExecutorEnv holds the dependencies available to a
+// ScheduledJobExecutor.
+type ScheduledJobExecutorEnv struct {
+	Settings         *cluster.Settings
+	DB               *kv.DB
+	InternalExecutor sqlutil.InternalExecutor
+	Registry         *Registry
+	// PlanHookMaker returns a sql.PlanHookState as an interface{}; see
+	// planHookMaker.
+	PlanHookMaker func(opName, user string) (interface{}, func())
+}
+
+// ScheduledJobExecutorFactory creates a ScheduledJobExecutor.
+type ScheduledJobExecutorFactory func() (ScheduledJobExecutor, error)
+
+var executorRegistry = make(map[string]ScheduledJobExecutorFactory)
+
+// RegisterScheduledJobExecutorFactory registers the factory of the executors
+// of the schedules of the given executor type. It is meant to be called from
+// init functions.
+func RegisterScheduledJobExecutorFactory(name string, factory ScheduledJobExecutorFactory) {
+	if _, ok := executorRegistry[name]; ok {
+		panic(errors.AssertionFailedf("executor %q already registered", name))
+	}
+	executorRegistry[name] = factory
+}
+
+// NewScheduledJobExecutor creates the executor of the schedules of the given
+// executor type.
+func NewScheduledJobExecutor(name string) (ScheduledJobExecutor, error) {
+	factory, ok := executorRegistry[name]
+	if !ok {
+		return nil, errors.Errorf("executor %q is not registered", name)
+	}
+	return factory()
+}
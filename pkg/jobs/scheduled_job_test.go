@@ -0,0 +1,45 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledJobNextRun(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	j := NewScheduledJob()
+	require.True(t, j.IsPaused())
+
+	before := timeutil.Now()
+	require.NoError(t, j.SetSchedule("@hourly"))
+	require.False(t, j.IsPaused())
+	require.True(t, j.NextRun().After(before))
+	require.True(t, j.NextRun().Before(before.Add(time.Hour+time.Second)))
+	require.Equal(t, 0, j.NextRun().Minute())
+
+	j.Pause()
+	require.True(t, j.IsPaused())
+	require.NoError(t, j.ScheduleNextRun())
+	require.False(t, j.IsPaused())
+
+	// A schedule without a cron expression is paused once it ran.
+	require.NoError(t, j.SetSchedule(""))
+	require.True(t, j.IsPaused())
+
+	require.Error(t, j.SetSchedule("not a cron expression"))
+}
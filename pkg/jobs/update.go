@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -53,7 +54,8 @@ func (md *JobMetadata) CheckRunningOrReverting() error {
 
 // JobUpdater accumulates changes to job metadata that are to be persisted.
 type JobUpdater struct {
-	md JobMetadata
+	md     JobMetadata
+	events []historyEvent
 }
 
 // UpdateStatus sets a new status (to be persisted).
@@ -74,8 +76,14 @@ func (ju *JobUpdater) UpdateProgress(progress *jobspb.Progress) {
 	ju.md.Progress = progress
 }
 
+// recordHistoryEvent records an event to the history of the job (to be
+// persisted).
+func (ju *JobUpdater) recordHistoryEvent(typ HistoryEventType, err string) {
+	ju.events = append(ju.events, historyEvent{typ: typ, err: err})
+}
+
 func (ju *JobUpdater) hasUpdates() bool {
-	return ju.md != JobMetadata{}
+	return ju.md != JobMetadata{} || len(ju.events) > 0
 }
 
 // Update is used to read the metadata for a job and potentially update it.
@@ -104,7 +112,9 @@ func (j *Job) Update(ctx context.Context, updateFn UpdateFn) error {
 
 	var payload *jobspb.Payload
 	var progress *jobspb.Progress
+	var progressSampled time.Time
 	if err := j.runInTxn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		progressSampled = time.Time{}
 		const selectStmt = "SELECT status, payload, progress FROM system.jobs WHERE id = $1"
 		row, err := j.registry.ex.QueryRowEx(
 			ctx, "log-job", txn, sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
@@ -161,8 +171,13 @@ func (j *Job) Update(ctx context.Context, updateFn UpdateFn) error {
 			setters = append(setters, fmt.Sprintf("%s = $%d", column, len(params)))
 		}
 
+		events := ju.events
 		if ju.md.Status != "" {
 			addSetter("status", ju.md.Status)
+			if ju.md.Status != status {
+				status = ju.md.Status
+				events = append(events, historyEvent{typ: HistoryEventStatusChange})
+			}
 		}
 
 		if ju.md.Payload != nil {
@@ -182,25 +197,43 @@ func (j *Job) Update(ctx context.Context, updateFn UpdateFn) error {
 				return err
 			}
 			addSetter("progress", progressBytes)
+			if now := txn.ReadTimestamp().GoTime(); j.shouldSampleProgress(now) {
+				progressSampled = now
+				events = append(events, historyEvent{typ: HistoryEventProgress})
+			}
 		}
 
-		updateStmt := fmt.Sprintf(
-			"UPDATE system.jobs SET %s WHERE id = $1",
-			strings.Join(setters, ", "),
-		)
-		n, err := j.registry.ex.Exec(ctx, "job-update", txn, updateStmt, params...)
-		if err != nil {
-			return err
-		}
-		if n != 1 {
-			return errors.Errorf(
-				"Job: expected exactly one row affected, but %d rows affected by job update", n,
+		if len(setters) > 0 {
+			updateStmt := fmt.Sprintf(
+				"UPDATE system.jobs SET %s WHERE id = $1",
+				strings.Join(setters, ", "),
 			)
+			n, err := j.registry.ex.Exec(ctx, "job-update", txn, updateStmt, params...)
+			if err != nil {
+				return err
+			}
+			if n != 1 {
+				return errors.Errorf(
+					"Job: expected exactly one row affected, but %d rows affected by job update", n,
+				)
+			}
+		}
+		// The status change events record the error of the jobs which are
+		// reverting or failed.
+		for i := range events {
+			if events[i].typ == HistoryEventStatusChange && (status == StatusReverting || status == StatusFailed) {
+				events[i].err = payload.Error
+			}
 		}
-		return nil
+		return j.registry.writeHistory(ctx, txn, *j.id, status, progress, events)
 	}); err != nil {
 		return err
 	}
+	if !progressSampled.IsZero() {
+		j.mu.Lock()
+		j.mu.lastProgressSample = progressSampled
+		j.mu.Unlock()
+	}
 	if payload != nil {
 		j.mu.Lock()
 		j.mu.payload = *payload
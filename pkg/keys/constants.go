@@ -378,6 +378,9 @@ const (
 	StatementBundleChunksTableID        = 34
 	StatementDiagnosticsRequestsTableID = 35
 	StatementDiagnosticsTableID         = 36
+	ScheduledJobsTableID                = 37
+	JobHistoryTableID                   = 38
+	AdvisoryLocksTableID                = 39
 
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
@@ -46,6 +46,8 @@ func ReverseScan(
 		TargetBytes:      h.TargetBytes,
 		FailOnMoreRecent: args.KeyLocking != lock.None,
 		Reverse:          true,
+		SkipLocked:       h.WaitPolicy == lock.WaitPolicy_SkipLocked,
+		LockTable:        cArgs.lockTableView(args.KeyLocking),
 	}
 
 	switch args.ScanFormat {
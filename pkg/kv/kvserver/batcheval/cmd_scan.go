@@ -46,6 +46,8 @@ func Scan(
 		TargetBytes:      h.TargetBytes,
 		FailOnMoreRecent: args.KeyLocking != lock.None,
 		Reverse:          false,
+		SkipLocked:       h.WaitPolicy == lock.WaitPolicy_SkipLocked,
+		LockTable:        cArgs.lockTableView(args.KeyLocking),
 	}
 
 	switch args.ScanFormat {
@@ -14,8 +14,11 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 )
 
@@ -118,4 +121,24 @@ type CommandArgs struct {
 	Args    roachpb.Request
 	// *Stats should be mutated to reflect any writes made by the command.
 	Stats *enginepb.MVCCStats
+	// Concurrency is the request's concurrency guard, if any. It is consulted
+	// by requests that skip over locked keys.
+	Concurrency *concurrency.Guard
+}
+
+// lockTableView returns a view into the lock table, as observed by the
+// request when it was sequenced, which reports the keys that are locked by
+// transactions that conflict with the request at the provided lock strength.
+func (cArgs CommandArgs) lockTableView(str lock.Strength) storage.LockTableView {
+	return requestBoundLockTableView{g: cArgs.Concurrency, str: str}
+}
+
+type requestBoundLockTableView struct {
+	g   *concurrency.Guard
+	str lock.Strength
+}
+
+// IsKeyLockedByConflictingTxn implements the storage.LockTableView interface.
+func (v requestBoundLockTableView) IsKeyLockedByConflictingTxn(key roachpb.Key) bool {
+	return v.g.IsKeyLockedByConflictingTxn(key, v.str)
 }
@@ -15,6 +15,7 @@ package concurrency
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
@@ -324,6 +325,25 @@ type Request struct {
 	// The consistency level of the request. Only set if Txn is nil.
 	ReadConsistency roachpb.ReadConsistencyType
 
+	// The policy the request follows when it encounters conflicting locks.
+	// With lock.WaitPolicy_Error, the request does not wait in lock
+	// wait-queues and instead fails with a WriteIntentError. With
+	// lock.WaitPolicy_SkipLocked, the request neither waits nor fails, and
+	// instead skips over locked keys during evaluation.
+	WaitPolicy lock.WaitPolicy
+
+	// The maximum amount of time that the request waits on conflicting locks
+	// before failing with a WriteIntentError. The timeout bounds the total
+	// time spent waiting in the lockTable, including any time spent pushing
+	// the lock holders, but not the time spent waiting to acquire latches.
+	// Zero means no timeout.
+	LockTimeout time.Duration
+
+	// The time at which the LockTimeout of the request expires. Set when the
+	// request first waits on conflicting locks and kept in its Guard, so that
+	// it carries over when the request is re-sequenced.
+	lockDeadline time.Time
+
 	// The individual requests in the batch.
 	Requests []roachpb.RequestUnion
 
@@ -574,6 +594,13 @@ type lockTableGuard interface {
 
 	// CurState returns the latest waiting state.
 	CurState() waitingState
+
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// by a transaction that conflicts with the request at the provided lock
+	// strength, as of the last call to ScanAndEnqueue. It is used by requests
+	// with the lock.WaitPolicy_SkipLocked wait policy, which don't wait on
+	// conflicting locks, to skip over locked keys during evaluation.
+	IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) bool
 }
 
 // lockTableWaiter is concerned with waiting in lock wait-queues for locks held
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
@@ -167,6 +168,7 @@ func (m *managerImpl) sequenceReqWithGuard(
 			m.lm.Release(g.moveLatchGuard())
 
 			log.Event(ctx, "waiting in lock wait-queues")
+			g.startLockTimeout()
 			if err := m.ltw.WaitOn(ctx, g.Req, g.ltg); err != nil {
 				return nil, err
 			}
@@ -275,6 +277,7 @@ func (m *managerImpl) HandleWriterIntentError(
 	// intents to ensure that they are resolved and moved out of the request's
 	// way.
 	if wait {
+		g.startLockTimeout()
 		for i := range t.Intents {
 			intent := &t.Intents[i]
 			if err := m.ltw.WaitOnLock(ctx, g.Req, intent); err != nil {
@@ -453,6 +456,16 @@ func releaseGuard(g *Guard) {
 	guardPool.Put(g)
 }
 
+// startLockTimeout starts the lock timeout of the guard's request, if it has
+// one and it has not started yet. The deadline stays in the guard's request,
+// so the timeout bounds the time spent waiting on conflicting locks across
+// all the times the request is sequenced.
+func (g *Guard) startLockTimeout() {
+	if g.Req.LockTimeout != 0 && g.Req.lockDeadline.IsZero() {
+		g.Req.lockDeadline = timeutil.Now().Add(g.Req.LockTimeout)
+	}
+}
+
 // LatchSpans returns the maximal set of spans that the request will access.
 func (g *Guard) LatchSpans() *spanset.SpanSet {
 	return g.Req.LatchSpans
@@ -463,6 +476,18 @@ func (g *Guard) HoldingLatches() bool {
 	return g != nil && g.lg != nil
 }
 
+// IsKeyLockedByConflictingTxn returns whether the specified key is locked by a
+// transaction that conflicts with the request at the provided lock strength.
+// It is used to skip over locked keys when evaluating requests with the
+// lock.WaitPolicy_SkipLocked wait policy. Requests that did not scan the lock
+// table never consider keys to be locked.
+func (g *Guard) IsKeyLockedByConflictingTxn(key roachpb.Key, str lock.Strength) bool {
+	if g == nil || g.ltg == nil {
+		return false
+	}
+	return g.ltg.IsKeyLockedByConflictingTxn(key, str)
+}
+
 // AssertLatches asserts that the guard is non-nil and holding latches.
 func (g *Guard) AssertLatches() {
 	if !g.HoldingLatches() {
@@ -253,10 +253,11 @@ type lockTableGuardImpl struct {
 	seqNum uint64
 
 	// Information about this request.
-	txn     *enginepb.TxnMeta
-	spans   *spanset.SpanSet
-	readTS  hlc.Timestamp
-	writeTS hlc.Timestamp
+	txn        *enginepb.TxnMeta
+	spans      *spanset.SpanSet
+	readTS     hlc.Timestamp
+	writeTS    hlc.Timestamp
+	waitPolicy lock.WaitPolicy
 
 	// Snapshots of the trees for which this request has some spans. Note that
 	// the lockStates in these snapshots may have been removed from
@@ -395,6 +396,31 @@ func (g *lockTableGuardImpl) CurState() waitingState {
 	return g.mu.state
 }
 
+func (g *lockTableGuardImpl) IsKeyLockedByConflictingTxn(key roachpb.Key, str lock.Strength) bool {
+	ss := spanset.SpanGlobal
+	if keys.IsLocal(key) {
+		ss = spanset.SpanLocal
+	}
+	iter := g.tableSnapshot[ss].MakeIter()
+	iter.SeekGE(&lockState{key: key})
+	if !iter.Valid() || !iter.Cur().key.Equal(key) {
+		return false
+	}
+	l := iter.Cur()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lockHolderTxn, lockHolderTS := l.getLockerInfo()
+	if lockHolderTxn == nil || g.isSameTxn(lockHolderTxn) {
+		return false
+	}
+	// Non-locking reads only conflict with locks at or below their read
+	// timestamp. Locking reads conflict with locks at any timestamp.
+	if str == lock.None && g.readTS.Less(lockHolderTS) {
+		return false
+	}
+	return true
+}
+
 func (g *lockTableGuardImpl) notify() {
 	select {
 	case g.mu.signal <- struct{}{}:
@@ -1719,6 +1745,7 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 		g.spans = req.LockSpans
 		g.readTS = req.readConflictTimestamp()
 		g.writeTS = req.writeConflictTimestamp()
+		g.waitPolicy = req.WaitPolicy
 		g.sa = spanset.NumSpanAccess - 1
 		g.index = -1
 	} else {
@@ -1747,6 +1774,15 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 			}
 		}
 	}
+	if g.waitPolicy == lock.WaitPolicy_SkipLocked {
+		// Requests that skip locked keys never wait in lock wait-queues. They
+		// only use the snapshots to determine which keys to skip during
+		// evaluation. See IsKeyLockedByConflictingTxn.
+		g.mu.Lock()
+		g.mu.state = waitingState{kind: doneWaiting}
+		g.mu.Unlock()
+		return g
+	}
 	g.findNextLockAfter(true /* notify */)
 	return g
 }
@@ -15,6 +15,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -115,6 +116,11 @@ func (w *lockTableWaiterImpl) WaitOn(
 	var timer *timeutil.Timer
 	var timerC <-chan time.Time
 	var timerWaitingState waitingState
+	// Used to enforce lock timeouts. Carried over from the previous times the
+	// request was sequenced, or set the first time the request waits on a
+	// conflicting lock, so that the timeout bounds the total time spent
+	// waiting rather than the time spent waiting on each lock.
+	lockDeadline := req.lockDeadline
 	for {
 		select {
 		case <-newStateC:
@@ -144,6 +150,20 @@ func (w *lockTableWaiterImpl) WaitOn(
 				livenessPush := state.kind == waitForDistinguished
 				deadlockPush := true
 
+				// If the request doesn't want to wait on conflicting locks, it
+				// doesn't wait in the lock's wait-queue. Instead, if the lock is
+				// held, it pushes the lock holder to determine whether the lock
+				// is abandoned and can be removed. Otherwise, it fails immediately.
+				if req.WaitPolicy == lock.WaitPolicy_Error {
+					if state.held {
+						if err := w.pushLockTxn(ctx, req, state, time.Time{}); err != nil {
+							return err
+						}
+						continue
+					}
+					return newWriteIntentErr(state, roachpb.WriteIntentError_REASON_WAIT_POLICY)
+				}
+
 				// If the conflict is a reservation holder and not a held lock then
 				// there's no need to perform a liveness push - the request must be
 				// alive or its context would have been canceled and it would have
@@ -160,9 +180,14 @@ func (w *lockTableWaiterImpl) WaitOn(
 					deadlockPush = false
 				}
 
-				// If the request doesn't want to perform a push for either
-				// reason, continue waiting.
-				if !livenessPush && !deadlockPush {
+				// If the request has a lock timeout, it should push once the
+				// timeout expires, to fail if the conflicting transaction is
+				// still active.
+				timeoutPush := req.LockTimeout != 0
+
+				// If the request doesn't want to perform a push for any reason,
+				// continue waiting.
+				if !livenessPush && !deadlockPush && !timeoutPush {
 					continue
 				}
 
@@ -178,6 +203,12 @@ func (w *lockTableWaiterImpl) WaitOn(
 				if deadlockPush {
 					delay = minDuration(delay, LockTableDeadlockDetectionPushDelay.Get(&w.st.SV))
 				}
+				if timeoutPush {
+					if lockDeadline.IsZero() {
+						lockDeadline = timeutil.Now().Add(req.LockTimeout)
+					}
+					delay = minDuration(delay, timeutil.Until(lockDeadline))
+				}
 
 				// However, if the pushee has the minimum priority or if the
 				// pusher has the maximum priority, push immediately.
@@ -219,7 +250,10 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// this completes, the request should stop waiting on this
 				// lockTableGuard, as it will no longer observe lock-table state
 				// transitions.
-				return w.pushLockTxn(ctx, req, state)
+				if req.LockTimeout != 0 && lockDeadline.IsZero() {
+					lockDeadline = timeutil.Now().Add(req.LockTimeout)
+				}
+				return w.pushLockTxn(ctx, req, state, lockDeadline)
 
 			case waitSelf:
 				// Another request from the same transaction is the reservation
@@ -265,9 +299,13 @@ func (w *lockTableWaiterImpl) WaitOn(
 			// behind a lock. In this case, the request has a dependency on the
 			// conflicting request but not necessarily the entire conflicting
 			// transaction.
+			//
+			// In both cases, the push is bounded by the request's lock timeout,
+			// if it has one. Deadlock detection continues to run in the pushee's
+			// txnWaitQueue until then.
 			var err *Error
 			if timerWaitingState.held {
-				err = w.pushLockTxn(ctx, req, timerWaitingState)
+				err = w.pushLockTxn(ctx, req, timerWaitingState, lockDeadline)
 			} else {
 				// It would be more natural to launch an async task for the push
 				// and continue listening on this goroutine for lockTable state
@@ -277,7 +315,7 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// lockTable change, it cancels the context on the push.
 				pushCtx, pushCancel := context.WithCancel(ctx)
 				go w.watchForNotifications(pushCtx, pushCancel, newStateC)
-				err = w.pushRequestTxn(pushCtx, req, timerWaitingState)
+				err = w.pushRequestTxn(pushCtx, req, timerWaitingState, lockDeadline)
 				if pushCtx.Err() == context.Canceled {
 					// Ignore the context canceled error. If this was for the
 					// parent context then we'll notice on the next select.
@@ -306,13 +344,17 @@ func (w *lockTableWaiterImpl) WaitOnLock(
 	if err != nil {
 		return roachpb.NewError(err)
 	}
+	lockDeadline := req.lockDeadline
+	if req.LockTimeout != 0 && lockDeadline.IsZero() {
+		lockDeadline = timeutil.Now().Add(req.LockTimeout)
+	}
 	return w.pushLockTxn(ctx, req, waitingState{
 		kind:        waitFor,
 		txn:         &intent.Txn,
 		key:         intent.Key,
 		held:        true,
 		guardAccess: sa,
-	})
+	}, lockDeadline)
 }
 
 // pushLockTxn pushes the holder of the provided lock.
@@ -322,13 +364,16 @@ func (w *lockTableWaiterImpl) WaitOnLock(
 // method then synchronously updates the lock to trigger a state transition in
 // the lockTable that will free up the request to proceed. If the method returns
 // successfully then the caller can expect to have an updated waitingState.
+//
+// If the lockDeadline is set, the method stops blocking once it passes and
+// returns a WriteIntentError with the REASON_LOCK_TIMEOUT reason. If it has
+// already passed, the lock holder is only pushed to determine whether it is
+// abandoned.
 func (w *lockTableWaiterImpl) pushLockTxn(
-	ctx context.Context, req Request, ws waitingState,
+	ctx context.Context, req Request, ws waitingState, lockDeadline time.Time,
 ) *Error {
 	if w.disableTxnPushing {
-		return roachpb.NewError(&roachpb.WriteIntentError{
-			Intents: []roachpb.Intent{roachpb.MakeIntent(ws.txn, ws.key)},
-		})
+		return newWriteIntentErr(ws, roachpb.WriteIntentError_REASON_UNSPECIFIED)
 	}
 
 	// Determine which form of push to use. For read-write conflicts, try to
@@ -347,8 +392,36 @@ func (w *lockTableWaiterImpl) pushLockTxn(
 		log.VEventf(ctx, 3, "pushing txn %s to abort", ws.txn.ID.Short())
 	}
 
-	pusheeTxn, err := w.ir.PushTransaction(ctx, ws.txn, h, pushType)
+	// If the request doesn't want to wait on the lock, it only pushes the lock
+	// holder to check whether it is abandoned. Such a push fails immediately if
+	// the lock holder is active, in which case the request fails.
+	// The same is true if the request's lock timeout has expired.
+	timedOut := !lockDeadline.IsZero() && timeutil.Until(lockDeadline) <= 0
+	if req.WaitPolicy == lock.WaitPolicy_Error || timedOut {
+		pushType = roachpb.PUSH_TOUCH
+		log.VEventf(ctx, 3, "pushing txn %s to check if abandoned", ws.txn.ID.Short())
+	}
+
+	pushCtx := ctx
+	if !lockDeadline.IsZero() && !timedOut {
+		var cancel func()
+		pushCtx, cancel = context.WithDeadline(ctx, lockDeadline)
+		defer cancel()
+	}
+	pusheeTxn, err := w.ir.PushTransaction(pushCtx, ws.txn, h, pushType)
 	if err != nil {
+		// If pushing with an Error wait policy or an expired lock timeout and
+		// the push fails, then the lock holder is still active. Transform the
+		// error into a WriteIntentError.
+		if _, ok := err.GetDetail().(*roachpb.TransactionPushError); ok {
+			if req.WaitPolicy == lock.WaitPolicy_Error {
+				err = newWriteIntentErr(ws, roachpb.WriteIntentError_REASON_WAIT_POLICY)
+			} else if timedOut {
+				err = newWriteIntentErr(ws, roachpb.WriteIntentError_REASON_LOCK_TIMEOUT)
+			}
+		} else if lockTimeoutExpired(ctx, pushCtx) {
+			err = newWriteIntentErr(ws, roachpb.WriteIntentError_REASON_LOCK_TIMEOUT)
+		}
 		return err
 	}
 
@@ -400,8 +473,11 @@ func (w *lockTableWaiterImpl) pushLockTxn(
 // caller is expected to terminate the push if it observes any state transitions
 // in the lockTable. As such, the push is only expected to be allowed to run to
 // completion in cases where requests are truly deadlocked.
+//
+// If the lockDeadline is set, the method stops blocking once it passes and
+// returns a WriteIntentError with the REASON_LOCK_TIMEOUT reason.
 func (w *lockTableWaiterImpl) pushRequestTxn(
-	ctx context.Context, req Request, ws waitingState,
+	ctx context.Context, req Request, ws waitingState, lockDeadline time.Time,
 ) *Error {
 	// Regardless of whether the waiting request is reading from or writing to a
 	// key, it always performs a PUSH_ABORT when pushing a conflicting request
@@ -412,8 +488,21 @@ func (w *lockTableWaiterImpl) pushRequestTxn(
 	pushType := roachpb.PUSH_ABORT
 	log.VEventf(ctx, 3, "pushing txn %s to detect request deadlock", ws.txn.ID.Short())
 
-	_, err := w.ir.PushTransaction(ctx, ws.txn, h, pushType)
+	pushCtx := ctx
+	if !lockDeadline.IsZero() {
+		if timeutil.Until(lockDeadline) <= 0 {
+			// There's no lock holder to check on, so fail immediately.
+			return newWriteIntentErr(ws, roachpb.WriteIntentError_REASON_LOCK_TIMEOUT)
+		}
+		var cancel func()
+		pushCtx, cancel = context.WithDeadline(ctx, lockDeadline)
+		defer cancel()
+	}
+	_, err := w.ir.PushTransaction(pushCtx, ws.txn, h, pushType)
 	if err != nil {
+		if lockTimeoutExpired(ctx, pushCtx) {
+			err = newWriteIntentErr(ws, roachpb.WriteIntentError_REASON_LOCK_TIMEOUT)
+		}
 		return err
 	}
 
@@ -503,6 +592,20 @@ func (w *lockTableWaiterImpl) watchForNotifications(
 	}
 }
 
+func newWriteIntentErr(ws waitingState, reason roachpb.WriteIntentError_Reason) *Error {
+	return roachpb.NewError(&roachpb.WriteIntentError{
+		Intents: []roachpb.Intent{roachpb.MakeIntent(ws.txn, ws.key)},
+		Reason:  reason,
+	})
+}
+
+// lockTimeoutExpired returns whether the push context, derived from the
+// request's context with the request's lock deadline, expired while the
+// request's context is still active.
+func lockTimeoutExpired(ctx, pushCtx context.Context) bool {
+	return pushCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+}
+
 func hasMinPriority(txn *enginepb.TxnMeta) bool {
 	return txn != nil && txn.Priority == enginepb.MinTxnPriority
 }
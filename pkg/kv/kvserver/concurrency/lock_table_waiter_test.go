@@ -13,7 +13,9 @@ package concurrency
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -58,6 +60,9 @@ func (g *mockLockTableGuard) CurState() waitingState {
 	}
 	return s
 }
+func (g *mockLockTableGuard) IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) bool {
+	panic("unimplemented")
+}
 func (g *mockLockTableGuard) notify() { g.signal <- struct{}{} }
 
 func setupLockTableWaiterTest() (*lockTableWaiterImpl, *mockIntentResolver, *mockLockTableGuard) {
@@ -221,6 +226,269 @@ func TestLockTableWaiterWithNonTxn(t *testing.T) {
 	})
 }
 
+// TestLockTableWaiterWithErrorWaitPolicy tests the lockTableWaiter's behavior
+// under different waiting states with an Error wait policy.
+func TestLockTableWaiterWithErrorWaitPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	keyA := roachpb.Key("keyA")
+
+	txn := makeTxnProto("request")
+	req := Request{
+		Txn:        &txn,
+		Timestamp:  txn.ReadTimestamp,
+		WaitPolicy: lock.WaitPolicy_Error,
+	}
+
+	for _, tc := range []struct {
+		name string
+		kind waitKind
+	}{
+		{"waitFor", waitFor},
+		{"waitForDistinguished", waitForDistinguished},
+		{"waitElsewhere", waitElsewhere},
+	} {
+		k := tc.kind
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("reservation", func(t *testing.T) {
+				w, ir, g := setupLockTableWaiterTest()
+				defer w.stopper.Stop(ctx)
+				pusheeTxn := makeTxnProto("pushee")
+
+				// Reservation holders are not pushed. The request fails
+				// immediately.
+				g.state = waitingState{
+					kind:        k,
+					txn:         &pusheeTxn.TxnMeta,
+					key:         keyA,
+					held:        false,
+					guardAccess: spanset.SpanReadWrite,
+				}
+				g.notify()
+				ir.pushTxn = func(
+					_ context.Context, _ *enginepb.TxnMeta, _ roachpb.Header, _ roachpb.PushTxnType,
+				) (roachpb.Transaction, *Error) {
+					t.Fatal("unexpected push")
+					return roachpb.Transaction{}, nil
+				}
+
+				err := w.WaitOn(ctx, req, g)
+				if k == waitElsewhere {
+					require.Nil(t, err)
+					return
+				}
+				require.NotNil(t, err)
+				wiErr, ok := err.GetDetail().(*roachpb.WriteIntentError)
+				require.True(t, ok, "expected WriteIntentError, found %v", err)
+				require.Equal(t, roachpb.WriteIntentError_REASON_WAIT_POLICY, wiErr.Reason)
+			})
+
+			testutils.RunTrueAndFalse(t, "pusheeActive", func(t *testing.T, pusheeActive bool) {
+				w, ir, g := setupLockTableWaiterTest()
+				defer w.stopper.Stop(ctx)
+				pusheeTxn := makeTxnProto("pushee")
+
+				g.state = waitingState{
+					kind:        k,
+					txn:         &pusheeTxn.TxnMeta,
+					key:         keyA,
+					held:        true,
+					guardAccess: spanset.SpanReadWrite,
+				}
+				g.notify()
+
+				// Lock holders are only pushed to determine whether they are
+				// abandoned.
+				ir.pushTxn = func(
+					_ context.Context, pusheeArg *enginepb.TxnMeta, _ roachpb.Header, pushType roachpb.PushTxnType,
+				) (roachpb.Transaction, *Error) {
+					require.Equal(t, &pusheeTxn.TxnMeta, pusheeArg)
+					require.Equal(t, roachpb.PUSH_TOUCH, pushType)
+					if pusheeActive {
+						return roachpb.Transaction{}, roachpb.NewError(&roachpb.TransactionPushError{
+							PusheeTxn: pusheeTxn,
+						})
+					}
+					return roachpb.Transaction{TxnMeta: *pusheeArg, Status: roachpb.ABORTED}, nil
+				}
+				ir.resolveIntent = func(_ context.Context, intent roachpb.LockUpdate) *Error {
+					require.Equal(t, keyA, intent.Key)
+					require.Equal(t, roachpb.ABORTED, intent.Status)
+					g.state = waitingState{kind: doneWaiting}
+					g.notify()
+					return nil
+				}
+
+				err := w.WaitOn(ctx, req, g)
+				if !pusheeActive {
+					require.Nil(t, err)
+					return
+				}
+				require.NotNil(t, err)
+				wiErr, ok := err.GetDetail().(*roachpb.WriteIntentError)
+				require.True(t, ok, "expected WriteIntentError, found %v", err)
+				require.Equal(t, roachpb.WriteIntentError_REASON_WAIT_POLICY, wiErr.Reason)
+			})
+		})
+	}
+}
+
+// TestLockTableWaiterWithLockTimeout tests the lockTableWaiter's behavior
+// under different waiting states with a lock timeout.
+func TestLockTableWaiterWithLockTimeout(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	keyA := roachpb.Key("keyA")
+
+	txn := makeTxnProto("request")
+	req := Request{
+		Txn:         &txn,
+		Timestamp:   txn.ReadTimestamp,
+		LockTimeout: time.Millisecond,
+	}
+
+	for _, tc := range []struct {
+		name string
+		kind waitKind
+	}{
+		{"waitFor", waitFor},
+		{"waitForDistinguished", waitForDistinguished},
+		{"waitElsewhere", waitElsewhere},
+	} {
+		k := tc.kind
+		t.Run(tc.name, func(t *testing.T) {
+			testutils.RunTrueAndFalse(t, "lockHeld", func(t *testing.T, lockHeld bool) {
+				if k == waitElsewhere && !lockHeld {
+					t.Skip("waitElsewhere does not wait on reservations")
+				}
+				w, ir, g := setupLockTableWaiterTest()
+				defer w.stopper.Stop(ctx)
+				pusheeTxn := makeTxnProto("pushee")
+
+				g.state = waitingState{
+					kind:        k,
+					txn:         &pusheeTxn.TxnMeta,
+					key:         keyA,
+					held:        lockHeld,
+					guardAccess: spanset.SpanReadWrite,
+				}
+				g.notify()
+
+				// The pushee is active, so pushes block until the lock timeout
+				// expires. Once it has, the lock holder is only pushed to check
+				// whether it is abandoned.
+				ir.pushTxn = func(
+					ctx context.Context, _ *enginepb.TxnMeta, _ roachpb.Header, pushType roachpb.PushTxnType,
+				) (roachpb.Transaction, *Error) {
+					if pushType == roachpb.PUSH_TOUCH {
+						return roachpb.Transaction{}, roachpb.NewError(&roachpb.TransactionPushError{
+							PusheeTxn: pusheeTxn,
+						})
+					}
+					<-ctx.Done()
+					return roachpb.Transaction{}, roachpb.NewError(ctx.Err())
+				}
+
+				err := w.WaitOn(ctx, req, g)
+				require.NotNil(t, err)
+				wiErr, ok := err.GetDetail().(*roachpb.WriteIntentError)
+				require.True(t, ok, "expected WriteIntentError, found %v", err)
+				require.Equal(t, roachpb.WriteIntentError_REASON_LOCK_TIMEOUT, wiErr.Reason)
+			})
+		})
+	}
+}
+
+// TestLockTableWaiterWithLockTimeoutOnMultipleLocks tests that the lock
+// timeout bounds the total time a request spends waiting on the locks it
+// waits on in turn, including when the request waits again after being
+// re-sequenced.
+func TestLockTableWaiterWithLockTimeoutOnMultipleLocks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	keyA := roachpb.Key("keyA")
+	keyB := roachpb.Key("keyB")
+
+	txn := makeTxnProto("request")
+	req := Request{
+		Txn:         &txn,
+		Timestamp:   txn.ReadTimestamp,
+		LockTimeout: 100 * time.Millisecond,
+	}
+
+	w, ir, g := setupLockTableWaiterTest()
+	defer w.stopper.Stop(ctx)
+	pusheeTxnA := makeTxnProto("pusheeA")
+	pusheeTxnB := makeTxnProto("pusheeB")
+
+	g.state = waitingState{
+		kind:        waitFor,
+		txn:         &pusheeTxnA.TxnMeta,
+		key:         keyA,
+		held:        true,
+		guardAccess: spanset.SpanReadWrite,
+	}
+	g.notify()
+
+	// The holder of the lock on keyA commits as soon as it is pushed, after
+	// which the request waits on the lock on keyB, whose holder stays active.
+	var deadlines []time.Time
+	ir.pushTxn = func(
+		ctx context.Context, pushee *enginepb.TxnMeta, _ roachpb.Header, pushType roachpb.PushTxnType,
+	) (roachpb.Transaction, *Error) {
+		if pushType == roachpb.PUSH_TOUCH {
+			return roachpb.Transaction{}, roachpb.NewError(&roachpb.TransactionPushError{
+				PusheeTxn: pusheeTxnB,
+			})
+		}
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok, "expected push to have a deadline")
+		deadlines = append(deadlines, deadline)
+		if pushee.ID == pusheeTxnA.ID {
+			committed := pusheeTxnA
+			committed.Status = roachpb.COMMITTED
+			return committed, nil
+		}
+		<-ctx.Done()
+		return roachpb.Transaction{}, roachpb.NewError(ctx.Err())
+	}
+	ir.resolveIntent = func(_ context.Context, intent roachpb.LockUpdate) *Error {
+		require.Equal(t, keyA, intent.Key)
+		g.state = waitingState{
+			kind:        waitFor,
+			txn:         &pusheeTxnB.TxnMeta,
+			key:         keyB,
+			held:        true,
+			guardAccess: spanset.SpanReadWrite,
+		}
+		g.notify()
+		return nil
+	}
+
+	err := w.WaitOn(ctx, req, g)
+	require.NotNil(t, err)
+	wiErr, ok := err.GetDetail().(*roachpb.WriteIntentError)
+	require.True(t, ok, "expected WriteIntentError, found %v", err)
+	require.Equal(t, roachpb.WriteIntentError_REASON_LOCK_TIMEOUT, wiErr.Reason)
+	require.Equal(t, keyB, wiErr.Intents[0].Key)
+
+	// Both pushes were bounded by the same deadline.
+	require.Len(t, deadlines, 2)
+	require.Equal(t, deadlines[0], deadlines[1])
+
+	// A request which is sequenced again keeps the deadline set when it first
+	// waited, so once it has passed the request only checks whether the lock
+	// holder is abandoned before failing.
+	req.lockDeadline = deadlines[0]
+	g.notify()
+	err = w.WaitOn(ctx, req, g)
+	require.NotNil(t, err)
+	wiErr, ok = err.GetDetail().(*roachpb.WriteIntentError)
+	require.True(t, ok, "expected WriteIntentError, found %v", err)
+	require.Equal(t, roachpb.WriteIntentError_REASON_LOCK_TIMEOUT, wiErr.Reason)
+	require.Len(t, deadlines, 2)
+}
+
 func testWaitPush(t *testing.T, k waitKind, makeReq func() Request, expPushTS hlc.Timestamp) {
 	ctx := context.Background()
 	keyA := roachpb.Key("keyA")
@@ -122,6 +122,16 @@ func rangeFeedValue(key roachpb.Key, val roachpb.Value) *roachpb.RangeFeedEvent
 	return rangeFeedValueWithPrev(key, val, roachpb.Value{})
 }
 
+func rangeFeedValueWithTxn(
+	txnID uuid.UUID, key roachpb.Key, val roachpb.Value,
+) *roachpb.RangeFeedEvent {
+	return makeRangeFeedEvent(&roachpb.RangeFeedValue{
+		Key:   key,
+		Value: val,
+		TxnID: txnID,
+	})
+}
+
 func rangeFeedCheckpoint(span roachpb.Span, ts hlc.Timestamp) *roachpb.RangeFeedEvent {
 	return makeRangeFeedEvent(&roachpb.RangeFeedCheckpoint{
 		Span:       span,
@@ -288,13 +298,15 @@ func TestProcessorBasic(t *testing.T) {
 		r1Stream.Events(),
 	)
 	// Commit intent. Should forward resolved timestamp to closed timestamp.
+	// The committed value carries the ID of the transaction.
 	p.ConsumeLogicalOps(
 		commitIntentOpWithKV(txn2, roachpb.Key("e"), hlc.Timestamp{WallTime: 13}, []byte("ival")),
 	)
 	p.syncEventAndRegistrations()
 	require.Equal(t,
 		[]*roachpb.RangeFeedEvent{
-			rangeFeedValue(
+			rangeFeedValueWithTxn(
+				txn2,
 				roachpb.Key("e"),
 				roachpb.Value{
 					RawBytes:  []byte("ival"),
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -139,7 +140,8 @@ func optimizePuts(
 
 // evaluateBatch evaluates a batch request by splitting it up into its
 // individual commands, passing them to evaluateCommand, and combining
-// the results.
+// the results. The concurrency guard, if provided, is consulted by requests
+// that skip over locked keys.
 func evaluateBatch(
 	ctx context.Context,
 	idKey storagebase.CmdIDKey,
@@ -147,6 +149,7 @@ func evaluateBatch(
 	rec batcheval.EvalContext,
 	ms *enginepb.MVCCStats,
 	ba *roachpb.BatchRequest,
+	g *concurrency.Guard,
 	readOnly bool,
 ) (_ *roachpb.BatchResponse, _ result.Result, retErr *roachpb.Error) {
 
@@ -239,7 +242,7 @@ func evaluateBatch(
 		var pErr *roachpb.Error
 
 		curResult, pErr = evaluateCommand(
-			ctx, idKey, index, readWriter, rec, ms, baHeader, args, reply)
+			ctx, idKey, index, readWriter, rec, ms, baHeader, args, reply, g)
 
 		// If an EndTxn wants to restart because of a write too old, we
 		// might have a better error to return to the client.
@@ -436,6 +439,7 @@ func evaluateCommand(
 	h roachpb.Header,
 	args roachpb.Request,
 	reply roachpb.Response,
+	g *concurrency.Guard,
 ) (result.Result, *roachpb.Error) {
 	// If a unittest filter was installed, check for an injected error; otherwise, continue.
 	if filter := rec.EvalKnobs().TestingEvalFilter; filter != nil {
@@ -461,10 +465,11 @@ func evaluateCommand(
 
 	if cmd, ok := batcheval.LookupCommand(args.Method()); ok {
 		cArgs := batcheval.CommandArgs{
-			EvalCtx: rec,
-			Header:  h,
-			Args:    args,
-			Stats:   ms,
+			EvalCtx:     rec,
+			Header:      h,
+			Args:        args,
+			Stats:       ms,
+			Concurrency: g,
 		}
 
 		if cmd.EvalRW != nil {
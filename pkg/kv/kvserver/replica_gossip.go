@@ -155,7 +155,7 @@ func (r *Replica) MaybeGossipNodeLiveness(ctx context.Context, span roachpb.Span
 	defer rw.Close()
 
 	br, result, pErr :=
-		evaluateBatch(ctx, storagebase.CmdIDKey(""), rw, rec, nil, &ba, true /* readOnly */)
+		evaluateBatch(ctx, storagebase.CmdIDKey(""), rw, rec, nil, &ba, nil /* g */, true /* readOnly */)
 	if pErr != nil {
 		return errors.Wrapf(pErr.GoError(), "couldn't scan node liveness records in span %s", span)
 	}
@@ -198,7 +198,7 @@ func (r *Replica) loadSystemConfig(ctx context.Context) (*config.SystemConfigEnt
 	defer rw.Close()
 
 	br, result, pErr := evaluateBatch(
-		ctx, storagebase.CmdIDKey(""), rw, rec, nil, &ba, true, /* readOnly */
+		ctx, storagebase.CmdIDKey(""), rw, rec, nil, &ba, nil /* g */, true, /* readOnly */
 	)
 	if pErr != nil {
 		return nil, pErr.GoError()
@@ -226,6 +226,8 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 			Timestamp:       ba.Timestamp,
 			Priority:        ba.UserPriority,
 			ReadConsistency: ba.ReadConsistency,
+			WaitPolicy:      ba.WaitPolicy,
+			LockTimeout:     ba.LockTimeout,
 			Requests:        ba.Requests,
 			LatchSpans:      latchSpans,
 			LockSpans:       lockSpans,
@@ -467,6 +467,15 @@ func (r *Replica) evaluate1PC(
 				pErr:    roachpb.NewError(err),
 			}
 		}
+		// The values were written without the transaction, so attribute them to
+		// it for the benefit of rangefeed consumers.
+		if res.LogicalOpLog != nil {
+			for _, op := range res.LogicalOpLog.Ops {
+				if writeValue := op.WriteValue; writeValue != nil {
+					writeValue.TxnID = ba.Txn.ID
+				}
+			}
+		}
 	}
 
 	// Even though the transaction is 1PC and hasn't written any intents, it may
@@ -554,7 +563,7 @@ func (r *Replica) evaluateWriteBatchWrapper(
 	latchSpans *spanset.SpanSet,
 ) (storage.Batch, *roachpb.BatchResponse, result.Result, *roachpb.Error) {
 	batch, opLogger := r.newBatchedEngine(latchSpans)
-	br, res, pErr := evaluateBatch(ctx, idKey, batch, rec, ms, ba, false /* readOnly */)
+	br, res, pErr := evaluateBatch(ctx, idKey, batch, rec, ms, ba, nil /* g */, false /* readOnly */)
 	if pErr == nil {
 		if opLogger != nil {
 			res.LogicalOpLog = &storagepb.LogicalOpLog{
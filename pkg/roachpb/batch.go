@@ -604,5 +604,8 @@ func (ba BatchRequest) ValidateForEvaluation() error {
 			return errors.AssertionFailedf("WriteTooOld set but no offset in timestamps. txn: %s", ba.Txn)
 		}
 	}
+	if ba.WaitPolicy == lock.WaitPolicy_SkipLocked && !ba.IsReadOnly() {
+		return errors.AssertionFailedf("batch with SkipLocked wait policy must be read-only")
+	}
 	return nil
 }
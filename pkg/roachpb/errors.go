@@ -603,6 +603,16 @@ func (e *WriteIntentError) message(_ *Error) string {
 			buf.WriteString(end[i].Key.String())
 		}
 	}
+	switch e.Reason {
+	case WriteIntentError_REASON_UNSPECIFIED:
+		// Nothing to say.
+	case WriteIntentError_REASON_WAIT_POLICY:
+		buf.WriteString(" [reason=wait_policy]")
+	case WriteIntentError_REASON_LOCK_TIMEOUT:
+		buf.WriteString(" [reason=lock_timeout]")
+	default:
+		// Could panic, better to silently ignore.
+	}
 	return buf.String()
 }
 
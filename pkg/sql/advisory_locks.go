@@ -0,0 +1,456 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// advisoryLockLeaseDuration is the duration for which the rows of the advisory
+// locks held by a session are valid. They are heartbeated by the node of the
+// session, so the locks of a session are only released that long after its
+// node stopped or lost the ability to heartbeat them.
+var advisoryLockLeaseDuration = settings.RegisterValidatedDurationSetting(
+	"sql.advisory_locks.lease_duration",
+	"the duration for which the advisory locks of a session are held after "+
+		"its node stopped heartbeating them",
+	30*time.Second,
+	func(v time.Duration) error {
+		if v <= 0 {
+			return errors.Errorf("cannot set sql.advisory_locks.lease_duration to a non-positive duration: %s", v)
+		}
+		return nil
+	},
+)
+
+// advisoryLockRetryOptions are the options with which sessions poll for the
+// advisory locks they are waiting for.
+var advisoryLockRetryOptions = retry.Options{
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+}
+
+// advisoryLockRow identifies a row of system.advisory_locks: the lock held by
+// a session in a mode.
+type advisoryLockRow struct {
+	key       tree.AdvisoryLockKey
+	sessionID ClusterWideID
+	exclusive bool
+}
+
+// advisoryLockManager stores the advisory locks held by the sessions of a node
+// in system.advisory_locks, so that they are visible to the sessions of all
+// the nodes of the cluster. The rows of the locks expire unless the manager
+// heartbeats them, so that the locks of sessions whose node crashed or
+// restarted are released.
+type advisoryLockManager struct {
+	cfg *ExecutorConfig
+
+	mu struct {
+		syncutil.Mutex
+		// held contains the rows of the locks held by the sessions of the node,
+		// which are heartbeated. Each row maps to the sequence number of the
+		// acquisition which wrote it.
+		held map[advisoryLockRow]uint64
+		// seq is the sequence number of the last acquisition.
+		seq uint64
+		// lost contains, for each session of the node, the rows of the locks
+		// which expired and were deleted while the session still held them.
+		// They are reported to the session by its next statement.
+		lost map[ClusterWideID][]advisoryLockRow
+	}
+}
+
+func newAdvisoryLockManager(cfg *ExecutorConfig) *advisoryLockManager {
+	m := &advisoryLockManager{cfg: cfg}
+	m.mu.held = make(map[advisoryLockRow]uint64)
+	m.mu.lost = make(map[ClusterWideID][]advisoryLockRow)
+	return m
+}
+
+// start starts the loop which heartbeats the rows of the locks held by the
+// sessions of the node.
+func (m *advisoryLockManager) start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			// Heartbeat often enough that the rows don't expire if a heartbeat
+			// fails.
+			timer.Reset(advisoryLockLeaseDuration.Get(&m.cfg.Settings.SV) / 3)
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+				if err := m.heartbeat(ctx); err != nil {
+					log.Warningf(ctx, "failed to heartbeat advisory locks: %v", err)
+				}
+			}
+		}
+	})
+}
+
+// heartbeat extends the expiration of the rows of the locks held by the
+// sessions of the node. The rows which are gone expired and were deleted by
+// other sessions, which may have acquired the locks since: the locks are lost
+// by the sessions of the node, which are told about it by their next
+// statement.
+func (m *advisoryLockManager) heartbeat(ctx context.Context) error {
+	m.mu.Lock()
+	rows := make([]advisoryLockRow, 0, len(m.mu.held))
+	seqs := make([]uint64, 0, len(m.mu.held))
+	for row, seq := range m.mu.held {
+		rows = append(rows, row)
+		seqs = append(seqs, seq)
+	}
+	m.mu.Unlock()
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var query strings.Builder
+	args := []interface{}{advisoryLockLeaseDuration.Get(&m.cfg.Settings.SV)}
+	query.WriteString(`UPDATE system.advisory_locks SET expiration = now() + $1
+WHERE (database_id, lock_key, key_fields, session_id, exclusive) IN (`)
+	for i, row := range rows {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		n := len(args)
+		fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5)
+		args = append(args, row.key.DatabaseID, row.key.Key, row.key.KeyFields,
+			row.sessionID.GetBytes(), row.exclusive)
+	}
+	query.WriteString(`)
+RETURNING database_id, lock_key, key_fields, session_id, exclusive`)
+	updatedRows, err := m.cfg.InternalExecutor.QueryEx(
+		ctx, "advisory-locks-heartbeat", nil, /* txn */
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		query.String(), args...,
+	)
+	if err != nil {
+		return err
+	}
+	if len(updatedRows) == len(rows) {
+		return nil
+	}
+	updated := make(map[advisoryLockRow]struct{}, len(updatedRows))
+	for _, r := range updatedRows {
+		sessionID := BytesToClusterWideID([]byte(tree.MustBeDBytes(r[3])))
+		updated[advisoryLockRow{
+			key: tree.AdvisoryLockKey{
+				DatabaseID: int64(tree.MustBeDInt(r[0])),
+				Key:        int64(tree.MustBeDInt(r[1])),
+				KeyFields:  int(tree.MustBeDInt(r[2])),
+			},
+			sessionID: sessionID,
+			exclusive: bool(tree.MustBeDBool(r[4])),
+		}] = struct{}{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, row := range rows {
+		if _, ok := updated[row]; ok {
+			continue
+		}
+		// The rows of the locks which were released, or released and acquired
+		// again, concurrently with the heartbeat are gone too, but their
+		// sessions didn't lose them.
+		if seq, ok := m.mu.held[row]; !ok || seq != seqs[i] {
+			continue
+		}
+		log.Warningf(ctx, "advisory lock %d of session %s expired before it could be heartbeated",
+			row.key.Key, row.sessionID)
+		delete(m.mu.held, row)
+		m.mu.lost[row.sessionID] = append(m.mu.lost[row.sessionID], row)
+	}
+	return nil
+}
+
+// takeLost returns the rows of the locks lost by a session since the last
+// call, and forgets them.
+func (m *advisoryLockManager) takeLost(sessionID ClusterWideID) []advisoryLockRow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lost := m.mu.lost[sessionID]
+	delete(m.mu.lost, sessionID)
+	return lost
+}
+
+// acquire writes the row of a lock, once it isn't held by other sessions in a
+// conflicting mode. If wait is false, it returns false rather than waiting for
+// the lock to be released. Waiting is bounded by the lock timeout, if not
+// zero.
+func (m *advisoryLockManager) acquire(
+	ctx context.Context, row advisoryLockRow, wait bool, lockTimeout time.Duration,
+) (bool, error) {
+	if !m.cfg.Settings.Version.IsActive(ctx, clusterversion.VersionAdvisoryLocks) {
+		return false, pgerror.Newf(pgcode.FeatureNotSupported,
+			"advisory locks can only be used on a cluster that has been fully upgraded to version 20.2")
+	}
+	if !wait {
+		return m.tryAcquire(ctx, row)
+	}
+	waitCtx := ctx
+	if lockTimeout != 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, lockTimeout)
+		defer cancel()
+	}
+	var acquired bool
+	var err error
+	for r := retry.StartWithCtx(waitCtx, advisoryLockRetryOptions); r.Next(); {
+		if acquired, err = m.tryAcquire(waitCtx, row); acquired || err != nil {
+			break
+		}
+	}
+	if acquired {
+		return true, nil
+	}
+	if ctx.Err() == nil && waitCtx.Err() != nil {
+		return false, pgerror.New(pgcode.LockNotAvailable,
+			"canceling statement due to lock timeout on advisory lock")
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return false, err
+}
+
+// tryAcquire writes the row of a lock, unless the lock is held by other
+// sessions in a conflicting mode. The rows of the lock which expired are
+// deleted.
+func (m *advisoryLockManager) tryAcquire(ctx context.Context, row advisoryLockRow) (bool, error) {
+	sessionID := row.sessionID.GetBytes()
+	var acquired bool
+	if err := m.cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		acquired = false
+		holders, err := m.cfg.InternalExecutor.QueryEx(
+			ctx, "advisory-lock-holders", txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`SELECT session_id, exclusive, expiration <= now() FROM system.advisory_locks
+WHERE database_id = $1 AND lock_key = $2 AND key_fields = $3`,
+			row.key.DatabaseID, row.key.Key, row.key.KeyFields,
+		)
+		if err != nil {
+			return err
+		}
+		var expired bool
+		for _, holder := range holders {
+			if bool(tree.MustBeDBool(holder[2])) {
+				expired = true
+				continue
+			}
+			// The locks held by the session itself never conflict.
+			if bytes.Equal([]byte(tree.MustBeDBytes(holder[0])), sessionID) {
+				continue
+			}
+			if row.exclusive || bool(tree.MustBeDBool(holder[1])) {
+				return nil
+			}
+		}
+		if expired {
+			if _, err := m.cfg.InternalExecutor.ExecEx(
+				ctx, "advisory-lock-delete-expired", txn,
+				sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+				`DELETE FROM system.advisory_locks
+WHERE database_id = $1 AND lock_key = $2 AND key_fields = $3 AND expiration <= now()`,
+				row.key.DatabaseID, row.key.Key, row.key.KeyFields,
+			); err != nil {
+				return err
+			}
+		}
+		if _, err := m.cfg.InternalExecutor.ExecEx(
+			ctx, "advisory-lock-acquire", txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`UPSERT INTO system.advisory_locks VALUES ($1, $2, $3, $4, $5, now() + $6)`,
+			row.key.DatabaseID, row.key.Key, row.key.KeyFields, sessionID, row.exclusive,
+			advisoryLockLeaseDuration.Get(&m.cfg.Settings.SV),
+		); err != nil {
+			return err
+		}
+		acquired = true
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	if acquired {
+		m.mu.Lock()
+		m.mu.seq++
+		m.mu.held[row] = m.mu.seq
+		m.mu.Unlock()
+	}
+	return acquired, nil
+}
+
+// release deletes the row of a lock. The row is no longer heartbeated even if
+// it can't be deleted, so that it eventually expires.
+func (m *advisoryLockManager) release(ctx context.Context, row advisoryLockRow) error {
+	m.mu.Lock()
+	delete(m.mu.held, row)
+	m.mu.Unlock()
+	_, err := m.cfg.InternalExecutor.ExecEx(
+		ctx, "advisory-lock-release", nil, /* txn */
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`DELETE FROM system.advisory_locks
+WHERE database_id = $1 AND lock_key = $2 AND key_fields = $3 AND session_id = $4 AND exclusive = $5`,
+		row.key.DatabaseID, row.key.Key, row.key.KeyFields, row.sessionID.GetBytes(), row.exclusive,
+	)
+	return err
+}
+
+// advisoryLockHold is a lock held by a session in a mode.
+type advisoryLockHold struct {
+	key       tree.AdvisoryLockKey
+	exclusive bool
+}
+
+// advisoryLockCounts counts how many times a session acquired a lock in a
+// mode, at the session and at the transaction level, without releasing it.
+type advisoryLockCounts struct {
+	session, xact int
+}
+
+// advisoryLockSession tracks the advisory locks held by a session. It
+// implements tree.AdvisoryLocker.
+type advisoryLockSession struct {
+	mgr         *advisoryLockManager
+	sessionID   ClusterWideID
+	sessionData *sessiondata.SessionData
+
+	// held contains the locks held by the session. A session holds a lock in a
+	// mode, and has a row for it in system.advisory_locks, as long as it
+	// acquired it more times than it released it.
+	held map[advisoryLockHold]*advisoryLockCounts
+}
+
+var _ tree.AdvisoryLocker = &advisoryLockSession{}
+
+func (s *advisoryLockSession) row(h advisoryLockHold) advisoryLockRow {
+	return advisoryLockRow{key: h.key, sessionID: s.sessionID, exclusive: h.exclusive}
+}
+
+// AcquireAdvisoryLock is part of the tree.AdvisoryLocker interface.
+func (s *advisoryLockSession) AcquireAdvisoryLock(
+	ctx context.Context, key tree.AdvisoryLockKey, shared, xact, wait bool,
+) (bool, error) {
+	h := advisoryLockHold{key: key, exclusive: !shared}
+	counts, ok := s.held[h]
+	if !ok {
+		acquired, err := s.mgr.acquire(ctx, s.row(h), wait, s.sessionData.LockTimeout)
+		if err != nil || !acquired {
+			return false, err
+		}
+		if s.held == nil {
+			s.held = make(map[advisoryLockHold]*advisoryLockCounts)
+		}
+		counts = &advisoryLockCounts{}
+		s.held[h] = counts
+	}
+	if xact {
+		counts.xact++
+	} else {
+		counts.session++
+	}
+	return true, nil
+}
+
+// ReleaseAdvisoryLock is part of the tree.AdvisoryLocker interface.
+func (s *advisoryLockSession) ReleaseAdvisoryLock(
+	ctx context.Context, key tree.AdvisoryLockKey, shared bool,
+) (bool, error) {
+	h := advisoryLockHold{key: key, exclusive: !shared}
+	counts, ok := s.held[h]
+	if !ok || counts.session == 0 {
+		return false, nil
+	}
+	counts.session--
+	if counts.xact > 0 || counts.session > 0 {
+		return true, nil
+	}
+	delete(s.held, h)
+	return true, s.mgr.release(ctx, s.row(h))
+}
+
+// ReleaseAllAdvisoryLocks is part of the tree.AdvisoryLocker interface.
+func (s *advisoryLockSession) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	return s.releaseAll(ctx, func(counts *advisoryLockCounts) { counts.session = 0 })
+}
+
+// releaseXactLocks releases the transaction-level locks held by the session.
+// It is called when a transaction finishes or restarts.
+func (s *advisoryLockSession) releaseXactLocks(ctx context.Context) error {
+	return s.releaseAll(ctx, func(counts *advisoryLockCounts) { counts.xact = 0 })
+}
+
+// close releases all the locks held by the session.
+func (s *advisoryLockSession) close(ctx context.Context) error {
+	s.mgr.takeLost(s.sessionID)
+	return s.releaseAll(ctx, func(counts *advisoryLockCounts) { *counts = advisoryLockCounts{} })
+}
+
+// checkLost returns an error if the session lost some of the locks it held
+// since the last call, because their rows expired and were deleted by other
+// sessions. The session doesn't hold the lost locks any more.
+func (s *advisoryLockSession) checkLost() error {
+	lost := s.mgr.takeLost(s.sessionID)
+	if len(lost) == 0 {
+		return nil
+	}
+	for _, row := range lost {
+		delete(s.held, advisoryLockHold{key: row.key, exclusive: row.exclusive})
+	}
+	return errors.WithHint(
+		pgerror.Newf(pgcode.LockNotAvailable,
+			"advisory lock %d was lost because it could not be heartbeated in time", lost[0].key.Key),
+		"the lock may have been acquired by another session since; acquire it again if needed")
+}
+
+// releaseAll applies reset to the counts of the locks held by the session, and
+// releases the locks which aren't held any more.
+func (s *advisoryLockSession) releaseAll(
+	ctx context.Context, reset func(*advisoryLockCounts),
+) error {
+	var firstErr error
+	for h, counts := range s.held {
+		reset(counts)
+		if counts.session > 0 || counts.xact > 0 {
+			continue
+		}
+		delete(s.held, h)
+		if err := s.mgr.release(ctx, s.row(h)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
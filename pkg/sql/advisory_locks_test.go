@@ -0,0 +1,84 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdvisoryLockLostAfterExpiration verifies that a session is told that it
+// lost an advisory lock whose row expired and was deleted by another session
+// acquiring the lock, rather than believing it still holds it.
+func TestAdvisoryLockLostAfterExpiration(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	connA, err := sqlDB.Conn(ctx)
+	require.NoError(t, err)
+	defer connA.Close()
+	connB, err := sqlDB.Conn(ctx)
+	require.NoError(t, err)
+	defer connB.Close()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	var acquired bool
+	require.NoError(t, connA.QueryRowContext(ctx, `SELECT pg_advisory_lock(1)`).Scan(&acquired))
+	require.True(t, acquired)
+
+	// Let the lease of the lock expire. The heartbeat loop of the node may
+	// extend it before the other session notices, in which case try again.
+	testutils.SucceedsSoon(t, func() error {
+		runner.Exec(t, `UPDATE system.advisory_locks SET expiration = now() - '1s'::INTERVAL`)
+		if err := connB.QueryRowContext(
+			ctx, `SELECT pg_try_advisory_lock(1)`,
+		).Scan(&acquired); err != nil {
+			return err
+		}
+		if !acquired {
+			return errors.New("lock not acquired yet")
+		}
+		return nil
+	})
+
+	// The heartbeat finds out that the row of the first session is gone.
+	require.NoError(t, s.SQLServer().(*Server).advisoryLocks.heartbeat(ctx))
+
+	_, err = connA.ExecContext(ctx, `SELECT 1`)
+	var pqErr *pq.Error
+	require.True(t, errors.As(err, &pqErr), "expected pq error, got %v", err)
+	require.Equal(t, "55P03", string(pqErr.Code))
+	require.Contains(t, pqErr.Message, "advisory lock 1 was lost")
+
+	// The error is only reported once, and the first session doesn't hold the
+	// lock any more.
+	require.NoError(t, connA.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(1)`).Scan(&acquired))
+	require.False(t, acquired)
+	require.NoError(t, connA.QueryRowContext(ctx, `SELECT pg_advisory_unlock(1)`).Scan(&acquired))
+	require.False(t, acquired)
+
+	// The second session keeps the lock across heartbeats.
+	require.NoError(t, s.SQLServer().(*Server).advisoryLocks.heartbeat(ctx))
+	require.NoError(t, connB.QueryRowContext(ctx, `SELECT pg_advisory_unlock(1)`).Scan(&acquired))
+	require.True(t, acquired)
+}
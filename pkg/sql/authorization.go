@@ -157,6 +157,23 @@ func (p *planner) CheckAnyPrivilege(ctx context.Context, descriptor sqlbase.Desc
 		p.SessionData().User, descriptor.TypeName(), descriptor.GetName())
 }
 
+// checkCreatePrivilegeForSchema checks that the current user may create
+// objects in the given schema of the given database. Objects in user-defined
+// schemas require the CREATE privilege on the schema; objects anywhere else
+// require it on the database.
+func (p *planner) checkCreatePrivilegeForSchema(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor, scName string,
+) error {
+	schemaDesc, err := getUserDefinedSchemaDesc(ctx, p.txn, p.ExecCfg().Codec, dbDesc.ID, scName)
+	if err != nil {
+		return err
+	}
+	if schemaDesc != nil {
+		return p.CheckPrivilege(ctx, schemaDesc, privilege.CREATE)
+	}
+	return p.CheckPrivilege(ctx, dbDesc, privilege.CREATE)
+}
+
 // HasAdminRole implements the AuthorizationAccessor interface.
 // Requires a valid transaction to be open.
 func (p *planner) HasAdminRole(ctx context.Context) (bool, error) {
@@ -158,6 +158,8 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 	var constraintsToAddBeforeValidation []sqlbase.ConstraintToUpdate
 	var constraintsToValidate []sqlbase.ConstraintToUpdate
 
+	var viewToRefresh *sqlbase.MaterializedViewRefresh
+
 	tableDesc, err := sc.updateJobRunningStatus(ctx, RunningStatusBackfill)
 	if err != nil {
 		return err
@@ -205,6 +207,8 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 				}
 			case *sqlbase.DescriptorMutation_PrimaryKeySwap:
 				// The backfiller doesn't need to do anything here.
+			case *sqlbase.DescriptorMutation_MaterializedViewRefresh:
+				viewToRefresh = t.MaterializedViewRefresh
 			default:
 				return errors.AssertionFailedf(
 					"unsupported mutation: %+v", m)
@@ -220,7 +224,8 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 				}
 			case *sqlbase.DescriptorMutation_Constraint:
 				constraintsToDrop = append(constraintsToDrop, *t.Constraint)
-			case *sqlbase.DescriptorMutation_PrimaryKeySwap:
+			case *sqlbase.DescriptorMutation_PrimaryKeySwap,
+				*sqlbase.DescriptorMutation_MaterializedViewRefresh:
 				// The backfiller doesn't need to do anything here.
 			default:
 				return errors.AssertionFailedf(
@@ -263,6 +268,13 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 		}
 	}
 
+	// Recompute the contents of a materialized view into its new indexes.
+	if viewToRefresh != nil {
+		if err := sc.refreshMaterializedView(ctx, tableDesc.TableDesc(), viewToRefresh); err != nil {
+			return err
+		}
+	}
+
 	// Add check and foreign key constraints, publish the new version of the table descriptor,
 	// and wait until the entire cluster is on the new version. This is basically
 	// a state transition for the schema change, which must happen after the
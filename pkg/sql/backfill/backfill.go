@@ -124,6 +124,8 @@ func (cb *ColumnBackfiller) Init(
 		evalCtx.Codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&cb.alloc,
@@ -373,6 +375,8 @@ func (ib *IndexBackfiller) Init(
 		evalCtx.Codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&ib.alloc,
@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/typeconv"
@@ -182,6 +183,15 @@ type cFetcher struct {
 	// lockStr represents the row-level locking mode to use when fetching rows.
 	lockStr sqlbase.ScanLockingStrength
 
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+
+	// lockTimeout specifies the maximum amount of time that the fetcher will
+	// wait while attempting to acquire a lock on a key or while blocking on an
+	// existing lock in order to perform a non-locking read on a key.
+	lockTimeout time.Duration
+
 	// returnRangeInfo, if set, causes the underlying kvBatchFetcher to return
 	// information about the ranges descriptors/leases uses in servicing the
 	// requests. This has some cost, so it's only enabled by DistSQL when this
@@ -248,6 +258,8 @@ func (rf *cFetcher) Init(
 	allocator *colmem.Allocator,
 	reverse bool,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
+	lockTimeout time.Duration,
 	returnRangeInfo bool,
 	isCheck bool,
 	tables ...row.FetcherTableArgs,
@@ -259,6 +271,8 @@ func (rf *cFetcher) Init(
 
 	rf.reverse = reverse
 	rf.lockStr = lockStr
+	rf.lockWaitPolicy = lockWaitPolicy
+	rf.lockTimeout = lockTimeout
 	rf.returnRangeInfo = returnRangeInfo
 
 	if len(tables) > 1 {
@@ -489,7 +503,8 @@ func (rf *cFetcher) StartScan(
 	}
 
 	f, err := row.NewKVFetcher(
-		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.lockStr, rf.returnRangeInfo,
+		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.lockStr, rf.lockWaitPolicy,
+		rf.lockTimeout, rf.returnRangeInfo,
 	)
 	if err != nil {
 		return err
@@ -252,6 +252,9 @@ type Server struct {
 	// dbCache is a cache for database descriptors, maintained through Gossip
 	// updates.
 	dbCache *databaseCacheHolder
+
+	// advisoryLocks stores the advisory locks held by the sessions of the node.
+	advisoryLocks *advisoryLockManager
 }
 
 // Metrics collects timeseries data about SQL activity.
@@ -285,6 +288,7 @@ func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 		sqlStats:      sqlStats{st: cfg.Settings, apps: make(map[string]*appStats)},
 		reportedStats: sqlStats{st: cfg.Settings, apps: make(map[string]*appStats)},
 		reCache:       tree.NewRegexpCache(512),
+		advisoryLocks: newAdvisoryLockManager(cfg),
 	}
 }
 
@@ -340,6 +344,7 @@ func (s *Server) Start(ctx context.Context, stopper *stop.Stopper) {
 	s.PeriodicallyClearSQLStats(ctx, stopper, maxSQLStatReset, &s.reportedStats)
 	// Start a second loop to clear SQL stats at the requested interval.
 	s.PeriodicallyClearSQLStats(ctx, stopper, sqlStatReset, &s.sqlStats)
+	s.advisoryLocks.start(ctx, stopper)
 }
 
 // ResetSQLStats resets the executor's collected sql statistics.
@@ -596,6 +601,7 @@ func (s *Server) newConnExecutor(
 		executorType:              executorTypeExec,
 		hasCreatedTemporarySchema: false,
 		stmtDiagnosticsRecorder:   s.cfg.StmtDiagnosticsRecorder,
+		advisoryLocks:             advisoryLockSession{mgr: s.advisoryLocks, sessionData: sd},
 	}
 
 	ex.state.txnAbortCount = ex.metrics.EngineMetrics.TxnAbortCount
@@ -861,6 +867,10 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		log.Warningf(ctx, "error while cleaning up connExecutor: %s", err)
 	}
 
+	if err := ex.advisoryLocks.close(ctx); err != nil {
+		log.Warningf(ctx, "error while releasing advisory locks: %s", err)
+	}
+
 	if closeType != panicClose {
 		// Close all statements and prepared portals.
 		ex.extraTxnState.prepStmtsNamespace.resetTo(ctx, prepStmtNamespace{})
@@ -1078,6 +1088,10 @@ type connExecutor struct {
 	// temporary schema, which requires special cleanup on close.
 	hasCreatedTemporarySchema bool
 
+	// advisoryLocks tracks the advisory locks held by the session, which are
+	// released on close.
+	advisoryLocks advisoryLockSession
+
 	// stmtDiagnosticsRecorder is used to track which queries need to have
 	// information collected.
 	stmtDiagnosticsRecorder StmtDiagnosticsRecorder
@@ -1175,6 +1189,15 @@ func (ex *connExecutor) resetExtraTxnState(
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
 
+	switch ev {
+	case txnCommit, txnRollback, txnRestart:
+		// Transaction-level advisory locks are released when the transaction
+		// finishes. A restarted transaction acquires them again.
+		if err := ex.advisoryLocks.releaseXactLocks(ctx); err != nil {
+			log.Warningf(ctx, "error while releasing advisory locks: %s", err)
+		}
+	}
+
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
@@ -1288,6 +1311,7 @@ func (ex *connExecutor) run(
 	ex.sessionID = ex.generateID()
 	ex.server.cfg.SessionRegistry.register(ex.sessionID, ex)
 	ex.planner.extendedEvalCtx.setSessionID(ex.sessionID)
+	ex.advisoryLocks.sessionID = ex.sessionID
 	defer ex.server.cfg.SessionRegistry.deregister(ex.sessionID)
 
 	for {
@@ -1965,6 +1989,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		EvalContext: tree.EvalContext{
 			Planner:            p,
 			Sequence:           p,
+			AdvisoryLocker:     &ex.advisoryLocks,
 			SessionData:        ex.sessionData,
 			SessionAccessor:    p,
 			PrivilegedAccessor: p,
@@ -386,6 +386,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		}
 	}
 
+	// The session must learn that it lost some of its advisory locks before it
+	// relies on them any further.
+	if err := ex.advisoryLocks.checkLost(); err != nil {
+		return makeErrEvent(err)
+	}
+
 	p.semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
 
 	// For regular statements (the ones that get to this point), we
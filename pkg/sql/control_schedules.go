@@ -0,0 +1,125 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type controlSchedulesNode struct {
+	schedules string
+	command   tree.ScheduleCommand
+	numRows   int
+}
+
+// ControlSchedules plans a PAUSE/RESUME/DROP SCHEDULES statement.
+// Privileges: admin.
+func (p *planner) ControlSchedules(
+	ctx context.Context, n *tree.ControlSchedules,
+) (planNode, error) {
+	if err := p.RequireAdminRole(ctx, n.StatementTag()); err != nil {
+		return nil, err
+	}
+
+	// The schedules are selected by an internal query. Substitute placeholders
+	// with their values since the internal query can't refer to them.
+	fmtCtx := tree.NewFmtCtx(tree.FmtParsable)
+	fmtCtx.SetPlaceholderFormat(func(ctx *tree.FmtCtx, placeholder *tree.Placeholder) {
+		d, err := placeholder.Eval(p.EvalContext())
+		if err != nil {
+			panic(fmt.Sprintf("failed to serialize placeholder: %s", err))
+		}
+		d.Format(ctx)
+	})
+	fmtCtx.FormatNode(n.Schedules)
+
+	return &controlSchedulesNode{
+		schedules: fmtCtx.CloseAndGetString(),
+		command:   n.Command,
+	}, nil
+}
+
+// FastPathResults implements the planNodeFastPath interface.
+func (n *controlSchedulesNode) FastPathResults() (int, bool) {
+	return n.numRows, true
+}
+
+func (n *controlSchedulesNode) startExec(params runParams) error {
+	ex := params.p.ExecCfg().InternalExecutor
+	rows, err := ex.QueryEx(
+		params.ctx, "select-schedules", params.p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		n.schedules,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if len(row) != 1 {
+			return errors.Errorf("%s SCHEDULES expects a single column source, got %d columns",
+				n.command, len(row))
+		}
+		if row[0] == tree.DNull {
+			continue
+		}
+		scheduleID, ok := tree.AsDInt(row[0])
+		if !ok {
+			return errors.Errorf("%s SCHEDULES expects schedule IDs, found %q", n.command, row[0])
+		}
+
+		schedule, err := jobs.LoadScheduledJob(params.ctx, ex, int64(scheduleID), params.p.txn)
+		if err != nil {
+			return err
+		}
+
+		switch n.command {
+		case tree.PauseSchedule:
+			schedule.Pause()
+			schedule.SetScheduleStatus("paused by %s", params.p.User())
+			err = schedule.Update(params.ctx, ex, params.p.txn)
+		case tree.ResumeSchedule:
+			if schedule.IsPaused() {
+				if err := schedule.ScheduleNextRun(); err != nil {
+					return err
+				}
+				schedule.SetScheduleStatus("resumed by %s", params.p.User())
+				err = schedule.Update(params.ctx, ex, params.p.txn)
+			}
+		case tree.DropSchedule:
+			err = schedule.Delete(params.ctx, ex, params.p.txn)
+		default:
+			err = errors.AssertionFailedf("unhandled command %s", n.command)
+		}
+		if err != nil {
+			return err
+		}
+		n.numRows++
+	}
+	telemetry.Inc(sqltelemetry.ScheduleControlCounter(strings.ToLower(n.command.String())))
+	return nil
+}
+
+func (*controlSchedulesNode) Next(runParams) (bool, error) { return false, nil }
+
+func (*controlSchedulesNode) Values() tree.Datums { return nil }
+
+func (*controlSchedulesNode) Close(ctx context.Context) {}
@@ -80,6 +80,7 @@ var crdbInternal = virtualSchema{
 		sqlbase.CrdbInternalGossipLivenessTableID:       crdbInternalGossipLivenessTable,
 		sqlbase.CrdbInternalGossipNetworkTableID:        crdbInternalGossipNetworkTable,
 		sqlbase.CrdbInternalIndexColumnsTableID:         crdbInternalIndexColumnsTable,
+		sqlbase.CrdbInternalJobHistoryTableID:           crdbInternalJobHistoryTable,
 		sqlbase.CrdbInternalJobsTableID:                 crdbInternalJobsTable,
 		sqlbase.CrdbInternalKVNodeStatusTableID:         crdbInternalKVNodeStatusTable,
 		sqlbase.CrdbInternalKVStoreStatusTableID:        crdbInternalKVStoreStatusTable,
@@ -624,6 +625,63 @@ CREATE TABLE crdb_internal.jobs (
 	},
 }
 
+var crdbInternalJobHistoryTable = virtualSchemaTable{
+	comment: `events of the jobs visible by the current user, from system.job_history (KV scan)`,
+	schema: `
+CREATE TABLE crdb_internal.job_history (
+	job_id               INT,
+	recorded             TIMESTAMPTZ,
+	event_type           STRING,
+	node_id              INT,
+	status               STRING,
+	running_status       STRING,
+	fraction_completed   FLOAT,
+	high_water_timestamp DECIMAL,
+	error                STRING
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionJobHistory) {
+			return nil
+		}
+		currentUser := p.SessionData().User
+		isAdmin, err := p.HasAdminRole(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Beware: we're querying system.job_history as root; we need to be
+		// careful to filter out the events of the jobs that the current user is
+		// not able to see.
+		const query = `
+SELECT h.job_id, h.recorded, h.event_type, h.node_id, h.status, h.running_status,
+       h.fraction_completed, h.high_water, h.error, j.payload
+  FROM system.job_history AS h LEFT JOIN system.jobs AS j ON h.job_id = j.id
+ ORDER BY h.job_id, h.recorded, h.event_id`
+		rows, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.QueryEx(
+			ctx, "crdb-internal-job-history-table", p.txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			query)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			if !isAdmin {
+				if r[9] == tree.DNull {
+					continue
+				}
+				payload, err := jobs.UnmarshalPayload(r[9])
+				if err != nil || payload.Username != currentUser {
+					continue
+				}
+			}
+			if err := addRow(r[:9]...); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 type stmtList []stmtKey
 
 func (s stmtList) Len() int {
@@ -12,47 +12,141 @@ package sql
 
 import (
 	"context"
+	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
 )
 
 type createSchemaNode struct {
-	n *tree.CreateSchema
+	n      *tree.CreateSchema
+	dbDesc *sqlbase.DatabaseDescriptor
 }
 
+// CreateSchema creates a schema in the current database.
+// Privileges: CREATE on the current database.
+//   Notes: postgres requires CREATE on the database.
+func (p *planner) CreateSchema(ctx context.Context, n *tree.CreateSchema) (planNode, error) {
+	dbName := p.CurrentDatabase()
+	if dbName == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, dbName, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createSchemaNode{n: n, dbDesc: dbDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE SCHEMA performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *createSchemaNode) ReadingOwnWrites() {}
+
 func (n *createSchemaNode) startExec(params runParams) error {
-	var schemaExists bool
-	if n.n.Schema == tree.PublicSchema {
-		schemaExists = true
-	} else {
-		for _, virtualSchema := range virtualSchemas {
-			if n.n.Schema == virtualSchema.name {
-				schemaExists = true
-				break
-			}
+	p := params.p
+	scName := n.n.Schema
+
+	exists, err := p.schemaExists(params.ctx, n.dbDesc.ID, scName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if n.n.IfNotExists {
+			return nil
 		}
+		return pgerror.Newf(pgcode.DuplicateSchema, "schema %q already exists", scName)
+	}
+
+	if err := checkSchemaNameAvailable(scName); err != nil {
+		return err
 	}
-	if !schemaExists {
-		return unimplemented.NewWithIssuef(26443,
-			"new schemas are unsupported")
+
+	if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.VersionUserDefinedSchemas) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"creating schemas requires all nodes to be upgraded to %s",
+			clusterversion.VersionByKey(clusterversion.VersionUserDefinedSchemas))
 	}
-	if n.n.IfNotExists {
-		return nil
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("schema"))
+
+	id, err := GenerateUniqueDescID(params.ctx, params.extendedEvalCtx.ExecCfg.DB)
+	if err != nil {
+		return err
 	}
-	return pgerror.Newf(pgcode.DuplicateSchema, "schema %q already exists", n.n.Schema)
+
+	// Schemas start out with the privileges of their database, like the
+	// tables created in the database do.
+	desc := &sqlbase.SchemaDescriptor{
+		Name:       scName,
+		ParentID:   n.dbDesc.ID,
+		Privileges: n.dbDesc.GetPrivileges(),
+	}
+	sKey := sqlbase.NewSchemaKey(n.dbDesc.ID, scName)
+	if err := p.createDescriptorWithID(
+		params.ctx, sKey.Key(params.ExecCfg().Codec), id, desc, nil, /* st */
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+	p.Tables().releaseSchemas()
+
+	// Log Create Schema event. This is an auditable log event and is
+	// recorded in the same transaction as the schema descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		p.txn,
+		EventLogCreateSchema,
+		int32(id),
+		int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
+		struct {
+			SchemaName string
+			Statement  string
+			User       string
+		}{scName, n.n.String(), params.SessionData().User},
+	)
 }
 
 func (*createSchemaNode) Next(runParams) (bool, error) { return false, nil }
 func (*createSchemaNode) Values() tree.Datums          { return tree.Datums{} }
 func (n *createSchemaNode) Close(ctx context.Context)  {}
 
-// CreateSchema creates a schema. Currently only works in IF NOT EXISTS mode,
-// for schemas that do in fact already exist.
-func (p *planner) CreateSchema(ctx context.Context, n *tree.CreateSchema) (planNode, error) {
-	return &createSchemaNode{
-		n: n,
-	}, nil
+// schemaExists returns whether a schema with the given name exists in the
+// given database. This covers the public schema, the virtual schemas,
+// temporary schemas and user-defined schemas.
+func (p *planner) schemaExists(
+	ctx context.Context, dbID sqlbase.ID, scName string,
+) (bool, error) {
+	if scName == tree.PublicSchema {
+		return true, nil
+	}
+	if _, ok := p.ExecCfg().VirtualSchemas.getVirtualSchemaEntry(scName); ok {
+		return true, nil
+	}
+	exists, _, err := resolveSchemaID(ctx, p.txn, p.ExecCfg().Codec, dbID, scName)
+	return exists, err
+}
+
+// checkSchemaNameAvailable returns an error if the given name cannot be used
+// for a user-defined schema.
+func checkSchemaNameAvailable(scName string) error {
+	if strings.HasPrefix(scName, sessiondata.PgSchemaPrefix) {
+		return errors.WithDetail(
+			pgerror.Newf(pgcode.ReservedName, "unacceptable schema name %q", scName),
+			`The prefix "pg_" is reserved for system schemas.`)
+	}
+	return nil
 }
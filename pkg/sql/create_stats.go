@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -147,7 +148,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 	var err error
 	switch t := n.Table.(type) {
 	case *tree.UnresolvedObjectName:
-		tableDesc, err = n.p.ResolveExistingObjectEx(ctx, t, true /*required*/, ResolveRequireTableDesc)
+		tableDesc, err = n.p.ResolveExistingObjectEx(ctx, t, true /*required*/, ResolveRequireTableOrViewDesc)
 		if err != nil {
 			return nil, err
 		}
@@ -173,7 +174,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		)
 	}
 
-	if tableDesc.IsView() {
+	if tableDesc.IsView() && !tableDesc.MaterializedView() {
 		return nil, pgerror.New(
 			pgcode.WrongObjectType, "cannot create statistics on views",
 		)
@@ -523,4 +524,14 @@ func init() {
 	}
 	jobs.RegisterConstructor(jobspb.TypeCreateStats, createResumerFn)
 	jobs.RegisterConstructor(jobspb.TypeAutoCreateStats, createResumerFn)
+
+	// Statistics jobs are only retried after node failures. A job which keeps
+	// failing is given up on, since the statistics are collected again later.
+	retryPolicy := jobs.RetryPolicy{
+		MaxRetries:     5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+	jobs.RegisterRetryPolicy(jobspb.TypeCreateStats, retryPolicy)
+	jobs.RegisterRetryPolicy(jobspb.TypeAutoCreateStats, retryPolicy)
 }
@@ -152,14 +152,29 @@ func (n *createTableNode) ReadingOwnWrites() {}
 
 // getTableCreateParams returns the table key needed for the new table,
 // as well as the schema id. It returns valid data in the case that
-// the desired object exists.
+// the desired object exists. Temporary tables are always created in the
+// session's temporary schema, regardless of schemaName.
 func getTableCreateParams(
-	params runParams, dbID sqlbase.ID, isTemporary bool, tableName string,
+	params runParams, dbID sqlbase.ID, schemaName string, isTemporary bool, tableName string,
 ) (sqlbase.DescriptorKey, sqlbase.ID, error) {
-	// By default, all tables are created in the `public` schema.
+	// By default, tables are created in the `public` schema.
 	schemaID := sqlbase.ID(keys.PublicSchemaID)
 	tKey := sqlbase.MakePublicTableNameKey(params.ctx,
 		params.ExecCfg().Settings, dbID, tableName)
+	if !isTemporary && schemaName != tree.PublicSchema {
+		exists, id, err := resolveSchemaID(
+			params.ctx, params.p.txn, params.ExecCfg().Codec, dbID, schemaName,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !exists {
+			return nil, 0, pgerror.Newf(pgcode.InvalidSchemaName,
+				"schema %q does not exist", schemaName)
+		}
+		schemaID = id
+		tKey = sqlbase.NewTableKey(dbID, schemaID, tableName)
+	}
 	if isTemporary {
 		if !params.SessionData().TempTablesEnabled {
 			return nil, 0, errors.WithTelemetry(
@@ -207,7 +222,9 @@ func (n *createTableNode) startExec(params runParams) error {
 	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("table"))
 	isTemporary := n.n.Temporary
 
-	tKey, schemaID, err := getTableCreateParams(params, n.dbDesc.ID, isTemporary, n.n.Table.Table())
+	tKey, schemaID, err := getTableCreateParams(
+		params, n.dbDesc.ID, n.n.Table.Schema(), isTemporary, n.n.Table.Table(),
+	)
 	if err != nil {
 		if sqlbase.IsRelationAlreadyExistsError(err) && n.n.IfNotExists {
 			return nil
@@ -29,14 +29,19 @@ import (
 // createViewNode represents a CREATE VIEW statement.
 type createViewNode struct {
 	viewName tree.Name
+	// schemaName is the name of the schema the view is created in.
+	schemaName tree.Name
 	// viewQuery contains the view definition, with all table names fully
 	// qualified.
 	viewQuery   string
 	ifNotExists bool
 	replace     bool
 	temporary   bool
-	dbDesc      *sqlbase.DatabaseDescriptor
-	columns     sqlbase.ResultColumns
+	// materialized is set for CREATE MATERIALIZED VIEW, in which case the
+	// results of the view query are stored in the view.
+	materialized bool
+	dbDesc       *sqlbase.DatabaseDescriptor
+	columns      sqlbase.ResultColumns
 
 	// planDeps tracks which tables and views the view being created
 	// depends on. This is collected during the construction of
@@ -50,7 +55,11 @@ type createViewNode struct {
 func (n *createViewNode) ReadingOwnWrites() {}
 
 func (n *createViewNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("view"))
+	if n.materialized {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("materialized_view"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("view"))
+	}
 
 	viewName := string(n.viewName)
 	isTemporary := n.temporary
@@ -75,9 +84,18 @@ func (n *createViewNode) startExec(params runParams) error {
 		backRefMutables[id] = backRefMutable
 	}
 
+	// The contents of a materialized view are computed after the creating
+	// transaction commits, when temporary objects may not be visible.
+	if n.materialized && isTemporary {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"materialized views must not use temporary tables or views")
+	}
+
 	var replacingDesc *sqlbase.MutableTableDescriptor
 
-	tKey, schemaID, err := getTableCreateParams(params, n.dbDesc.ID, isTemporary, viewName)
+	tKey, schemaID, err := getTableCreateParams(
+		params, n.dbDesc.ID, string(n.schemaName), isTemporary, viewName,
+	)
 	if err != nil {
 		switch {
 		case !sqlbase.IsRelationAlreadyExistsError(err):
@@ -98,7 +116,7 @@ func (n *createViewNode) startExec(params runParams) error {
 			if err := params.p.CheckPrivilege(params.ctx, desc, privilege.DROP); err != nil {
 				return err
 			}
-			if !desc.IsView() {
+			if !desc.IsView() || desc.MaterializedView() {
 				return pgerror.Newf(pgcode.WrongObjectType, `%q is not a view`, viewName)
 			}
 			replacingDesc = desc
@@ -144,6 +162,7 @@ func (n *createViewNode) startExec(params runParams) error {
 			&params.p.semaCtx,
 			params.p.EvalContext(),
 			isTemporary,
+			n.materialized,
 		)
 		if err != nil {
 			return err
@@ -156,9 +175,13 @@ func (n *createViewNode) startExec(params runParams) error {
 
 		// TODO (lucy): I think this needs a NodeFormatter implementation. For now,
 		// do some basic string formatting (not accurate in the general case).
+		jobDesc := fmt.Sprintf("CREATE VIEW %q AS %q", n.viewName, n.viewQuery)
+		if n.materialized {
+			jobDesc = fmt.Sprintf("CREATE MATERIALIZED VIEW %q AS %q", n.viewName, n.viewQuery)
+		}
 		if err = params.p.createDescriptorWithID(
 			params.ctx, tKey.Key(params.ExecCfg().Codec), id, &desc, params.EvalContext().Settings,
-			fmt.Sprintf("CREATE VIEW %q AS %q", n.viewName, n.viewQuery),
+			jobDesc,
 		); err != nil {
 			return err
 		}
@@ -231,6 +254,10 @@ func (n *createViewNode) Close(ctx context.Context)  {}
 // dependencies in the same transaction that the view is created and it
 // doesn't matter if reads/writes use a cached descriptor that doesn't
 // include the back-references.
+//
+// Materialized views are the exception: they are created in the ADD state,
+// like tables created with CREATE TABLE ... AS, and made public once the
+// schema changer has populated them with the results of the view query.
 func makeViewTableDesc(
 	viewName string,
 	viewQuery string,
@@ -243,6 +270,7 @@ func makeViewTableDesc(
 	semaCtx *tree.SemaContext,
 	evalCtx *tree.EvalContext,
 	temporary bool,
+	materialized bool,
 ) (sqlbase.MutableTableDescriptor, error) {
 	desc := InitTableDescriptor(
 		id,
@@ -254,6 +282,12 @@ func makeViewTableDesc(
 		temporary,
 	)
 	desc.ViewQuery = viewQuery
+	if materialized {
+		// The view must be marked as materialized before its columns are added so
+		// that it is given a primary key and column families.
+		desc.IsMaterializedView = true
+		desc.State = sqlbase.TableDescriptor_ADD
+	}
 	if err := addResultColumns(semaCtx, evalCtx, &desc, resultColumns); err != nil {
 		return sqlbase.MutableTableDescriptor{}, err
 	}
@@ -67,6 +67,9 @@ func TryDelegate(
 	case *tree.ShowJobs:
 		return d.delegateShowJobs(t)
 
+	case *tree.ShowJobHistory:
+		return d.delegateShowJobHistory(t)
+
 	case *tree.ShowQueries:
 		return d.delegateShowQueries(t)
 
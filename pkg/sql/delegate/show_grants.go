@@ -74,6 +74,32 @@ FROM "".information_schema.table_privileges`
 		} else {
 			fmt.Fprintf(&cond, `WHERE database_name IN (%s)`, strings.Join(params, ","))
 		}
+	} else if n.Targets != nil && n.Targets.Schemas != nil {
+		// Get grants of schemas of the current database from
+		// information_schema.schema_privileges if the type of target is schema.
+		currDB := d.evalCtx.SessionData.Database
+		for _, sc := range n.Targets.Schemas.ToStrings() {
+			name := cat.SchemaName{
+				CatalogName:     tree.Name(currDB),
+				SchemaName:      tree.Name(sc),
+				ExplicitCatalog: true,
+				ExplicitSchema:  true,
+			}
+			_, _, err := d.catalog.ResolveSchema(d.ctx, cat.Flags{AvoidDescriptorCaches: true}, &name)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, lex.EscapeSQLString(sc))
+		}
+
+		fmt.Fprint(&source, dbPrivQuery)
+		orderBy = "1,2,3,4"
+		if len(params) == 0 {
+			cond.WriteString(`WHERE false`)
+		} else {
+			fmt.Fprintf(&cond, `WHERE database_name = %s AND schema_name IN (%s)`,
+				lex.EscapeSQLString(currDB), strings.Join(params, ","))
+		}
 	} else {
 		fmt.Fprint(&source, tablePrivQuery)
 		orderBy = "1,2,3,4,5"
@@ -61,3 +61,14 @@ func (d *delegator) delegateShowJobs(n *tree.ShowJobs) (tree.Statement, error) {
 	}
 	return parse(sqlStmt)
 }
+
+func (d *delegator) delegateShowJobHistory(n *tree.ShowJobHistory) (tree.Statement, error) {
+	sqltelemetry.IncrementShowCounter(sqltelemetry.JobHistory)
+	return parse(fmt.Sprintf(
+		`SELECT recorded, event_type, node_id, status, running_status,
+		        fraction_completed, high_water_timestamp, error
+		   FROM crdb_internal.job_history
+		  WHERE job_id = (%s)
+		  ORDER BY recorded`, n.JobID.String(),
+	))
+}
@@ -190,6 +190,8 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 		// strength here. Consider hooking this in to the same knob that will
 		// control whether we perform locking implicitly during DELETEs.
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		params.SessionData().LockTimeout,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&params.p.alloc,
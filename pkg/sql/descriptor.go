@@ -38,6 +38,7 @@ import (
 var (
 	errEmptyDatabaseName = pgerror.New(pgcode.Syntax, "empty database name")
 	errNoDatabase        = pgerror.New(pgcode.InvalidName, "no database specified")
+	errNoSchema          = pgerror.New(pgcode.InvalidName, "no schema specified")
 	errNoTable           = pgerror.New(pgcode.InvalidName, "no table specified")
 	errNoMatch           = pgerror.New(pgcode.UndefinedObject, "no object matched")
 )
@@ -212,6 +213,23 @@ func resolveSchemaID(
 	return true, schemaID, nil
 }
 
+// getUserDefinedSchemaDesc looks up the descriptor of the user-defined schema
+// with the given name in the given database. It returns a nil descriptor if
+// the schema does not exist or if it is the public schema, a virtual schema
+// or a temporary schema, none of which have a schema descriptor.
+func getUserDefinedSchemaDesc(
+	ctx context.Context, txn *kv.Txn, codec keys.SQLCodec, dbID sqlbase.ID, scName string,
+) (*sqlbase.SchemaDescriptor, error) {
+	if scName == tree.PublicSchema || sessiondata.IsSystemSchemaName(scName) {
+		return nil, nil
+	}
+	exists, schemaID, err := resolveSchemaID(ctx, txn, codec, dbID, scName)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return sqlbase.GetSchemaDescFromID(ctx, txn, codec, schemaID)
+}
+
 // lookupDescriptorByID looks up the descriptor for `id` and returns it.
 // It can be a table or database descriptor.
 // Returns the descriptor (if found), a bool representing whether the
@@ -286,6 +304,17 @@ func getDescriptorByID(
 			return err
 		}
 		*t = *database
+	case *sqlbase.SchemaDescriptor:
+		schema := desc.GetSchema()
+		if schema == nil {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"%q is not a schema", desc.String())
+		}
+
+		if err := schema.Validate(); err != nil {
+			return err
+		}
+		*t = *schema
 	}
 	return nil
 }
@@ -340,6 +369,8 @@ func GetAllDescriptors(
 			descs = append(descs, table)
 		case *sqlbase.Descriptor_Database:
 			descs = append(descs, desc.GetDatabase())
+		case *sqlbase.Descriptor_Schema:
+			descs = append(descs, desc.GetSchema())
 		default:
 			return nil, errors.AssertionFailedf("Descriptor.Union has unexpected type %T", t)
 		}
@@ -282,6 +282,7 @@ func (ds *ServerImpl) setupFlow(
 				ExtraFloatDigits:  int(req.EvalContext.ExtraFloatDigits),
 			},
 			VectorizeMode: sessiondata.VectorizeExecMode(req.EvalContext.Vectorize),
+			LockTimeout:   req.EvalContext.LockTimeout,
 		}
 		ie := &lazyInternalExecutor{
 			newInternalExecutor: func() sqlutil.InternalExecutor {
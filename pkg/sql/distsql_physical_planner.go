@@ -3220,7 +3220,8 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 }
 
 // createPlanForExport creates a physical plan for EXPORT.
-// We add a new stage of CSVWriter processors to the input plan.
+// We add a new stage of CSVWriter or ParquetWriter processors to the input
+// plan.
 func (dsp *DistSQLPlanner) createPlanForExport(
 	planCtx *PlanningCtx, n *exportNode,
 ) (PhysicalPlan, error) {
@@ -3229,13 +3230,30 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		return PhysicalPlan{}, err
 	}
 
-	core := execinfrapb.ProcessorCoreUnion{CSVWriter: &execinfrapb.CSVWriterSpec{
-		Destination:      n.fileName,
-		NamePattern:      exportFilePatternDefault,
-		Options:          n.csvOpts,
-		ChunkRows:        int64(n.chunkSize),
-		CompressionCodec: n.fileCompression,
-	}}
+	var core execinfrapb.ProcessorCoreUnion
+	switch n.fileFormat {
+	case "PARQUET":
+		cols := planColumns(n.source)
+		colNames := make([]string, len(cols))
+		for i := range cols {
+			colNames[i] = cols[i].Name
+		}
+		core.ParquetWriter = &execinfrapb.ParquetWriterSpec{
+			Destination:      n.fileName,
+			NamePattern:      exportParquetFilePatternDefault,
+			ColumnNames:      colNames,
+			ChunkRows:        int64(n.chunkSize),
+			CompressionCodec: n.fileCompression,
+		}
+	default:
+		core.CSVWriter = &execinfrapb.CSVWriterSpec{
+			Destination:      n.fileName,
+			NamePattern:      exportFilePatternDefault,
+			Options:          n.csvOpts,
+			ChunkRows:        int64(n.chunkSize),
+			CompressionCodec: n.fileCompression,
+		}
+	}
 
 	resTypes := make([]*types.T, len(sqlbase.ExportColumns))
 	for i := range sqlbase.ExportColumns {
@@ -3245,7 +3263,7 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		core, execinfrapb.PostProcessSpec{}, resTypes, execinfrapb.Ordering{},
 	)
 
-	// The CSVWriter produces the same columns as the EXPORT statement.
+	// The writers produce the same columns as the EXPORT statement.
 	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, len(sqlbase.ExportColumns))
 	return plan, nil
 }
@@ -23,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -255,11 +257,20 @@ func DistIngest(
 
 	dsp.FinalizePlan(planCtx, &p)
 
+	// The rows of a file rejected by earlier runs of the import count again once
+	// the file's resume position is back to where this run started reading it.
+	importProgress := job.Progress().GetImport()
+	startPos := make([]int64, len(from))
+	startRejected := make([]int64, len(from))
+	copy(startPos, importProgress.ResumePos)
+	copy(startRejected, importProgress.RejectedRows)
+
 	if err := job.FractionProgressed(ctx,
 		func(ctx context.Context, details jobspb.ProgressDetails) float32 {
 			prog := details.(*jobspb.Progress_Import).Import
 			prog.ReadProgress = make([]float32, len(from))
 			prog.ResumePos = make([]int64, len(from))
+			prog.RejectedRows = make([]int64, len(from))
 			return 0.0
 		},
 	); err != nil {
@@ -268,6 +279,7 @@ func DistIngest(
 
 	rowProgress := make([]int64, len(from))
 	fractionProgress := make([]uint32, len(from))
+	rejectedRows := make([]int64, len(from))
 
 	updateJobProgress := func() error {
 		return job.FractionProgressed(ctx,
@@ -276,6 +288,7 @@ func DistIngest(
 				prog := details.(*jobspb.Progress_Import).Import
 				for i := range rowProgress {
 					prog.ResumePos[i] = atomic.LoadInt64(&rowProgress[i])
+					prog.RejectedRows[i] = atomic.LoadInt64(&rejectedRows[i])
 				}
 				for i := range fractionProgress {
 					fileProgress := math.Float32frombits(atomic.LoadUint32(&fractionProgress[i]))
@@ -295,6 +308,24 @@ func DistIngest(
 			for i, v := range meta.BulkProcessorProgress.CompletedFraction {
 				atomic.StoreUint32(&fractionProgress[i], math.Float32bits(v))
 			}
+			for i, v := range meta.BulkProcessorProgress.RejectedRows {
+				if meta.BulkProcessorProgress.ResumePos[i] >= startPos[i] {
+					v += startRejected[i]
+				}
+				atomic.StoreInt64(&rejectedRows[i], v)
+			}
+			// Processors only know about the rows they rejected themselves, so
+			// the rejected_rows_limit of the whole import is enforced here.
+			if format.RejectedRowsLimit > 0 {
+				var total int64
+				for i := range rejectedRows {
+					total += atomic.LoadInt64(&rejectedRows[i])
+				}
+				if total > format.RejectedRowsLimit {
+					return pgerror.Newf(pgcode.DataCorrupted,
+						"too many parsing errors (%d) encountered", total)
+				}
+			}
 
 			if alwaysFlushProgress {
 				return updateJobProgress()
@@ -34,6 +34,10 @@ type dropDatabaseNode struct {
 	dbDesc          *sqlbase.DatabaseDescriptor
 	td              []toDelete
 	schemasToDelete []string
+	// schemaDescsToDelete holds the IDs of the user-defined schemas in the
+	// database, whose descriptors are deleted along with their namespace
+	// entries.
+	schemaDescsToDelete []sqlbase.ID
 }
 
 // DropDatabase drops a database.
@@ -74,7 +78,9 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 
 	var tbNames TableNames
 	tempSchemasToDelete := make(map[ClusterWideID]struct{})
-	for _, schema := range schemas {
+	var userSchemasToDelete []string
+	var schemaDescsToDelete []sqlbase.ID
+	for schemaID, schema := range schemas {
 		toAppend, err := GetObjectNames(
 			ctx, p.txn, p, p.ExecCfg().Codec, dbDesc, schema, true, /*explicitPrefix*/
 		)
@@ -89,6 +95,9 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		}
 		if isTempSchema {
 			tempSchemasToDelete[clusterWideID] = struct{}{}
+		} else if schema != tree.PublicSchema {
+			userSchemasToDelete = append(userSchemasToDelete, schema)
+			schemaDescsToDelete = append(schemaDescsToDelete, schemaID)
 		}
 	}
 
@@ -151,12 +160,19 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	schemasToDelete := make([]string, 0, len(tempSchemasToDelete))
+	schemasToDelete := make([]string, 0, len(tempSchemasToDelete)+len(userSchemasToDelete))
 	for clusterWideID := range tempSchemasToDelete {
 		schemasToDelete = append(schemasToDelete, temporarySchemaName(clusterWideID))
 	}
-
-	return &dropDatabaseNode{n: n, dbDesc: dbDesc, td: td, schemasToDelete: schemasToDelete}, nil
+	schemasToDelete = append(schemasToDelete, userSchemasToDelete...)
+
+	return &dropDatabaseNode{
+		n:                   n,
+		dbDesc:              dbDesc,
+		td:                  td,
+		schemasToDelete:     schemasToDelete,
+		schemaDescsToDelete: schemaDescsToDelete,
+	}, nil
 }
 
 func (n *dropDatabaseNode) startExec(params runParams) error {
@@ -211,6 +227,14 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	}
 	b.Del(descKey)
 
+	for _, schemaID := range n.schemaDescsToDelete {
+		schemaDescKey := sqlbase.MakeDescMetadataKey(p.ExecCfg().Codec, schemaID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", schemaDescKey)
+		}
+		b.Del(schemaDescKey)
+	}
+
 	for _, schemaToDelete := range n.schemasToDelete {
 		if err := sqlbase.RemoveSchemaNamespaceEntry(
 			ctx,
@@ -242,6 +266,9 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	}
 
 	p.Tables().addUncommittedDatabase(n.dbDesc.Name, n.dbDesc.ID, dbDropped)
+	if len(n.schemaDescsToDelete) > 0 {
+		p.Tables().releaseSchemas()
+	}
 
 	if err := p.txn.Run(ctx, b); err != nil {
 		return err
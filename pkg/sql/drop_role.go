@@ -134,6 +134,29 @@ func (n *DropRoleNode) startExec(params runParams) error {
 		}
 	}
 
+	// Then check all the user-defined schemas.
+	for _, desc := range descs {
+		schema, ok := desc.(*sqlbase.SchemaDescriptor)
+		if !ok {
+			continue
+		}
+		for _, u := range schema.GetPrivileges().Users {
+			if _, ok := userNames[u.User]; ok {
+				if f.Len() > 0 {
+					f.WriteString(", ")
+				}
+				sn := tree.ObjectNamePrefix{
+					CatalogName:     tree.Name(lCtx.dbNames[schema.ParentID]),
+					SchemaName:      tree.Name(schema.Name),
+					ExplicitCatalog: true,
+					ExplicitSchema:  true,
+				}
+				f.FormatNode(&sn)
+				break
+			}
+		}
+	}
+
 	// Was there any object depending on that user?
 	if f.Len() > 0 {
 		fnl := tree.NewFmtCtx(tree.FmtSimple)
@@ -0,0 +1,240 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+type dropSchemaNode struct {
+	n       *tree.DropSchema
+	dbDesc  *sqlbase.DatabaseDescriptor
+	schemas []*sqlbase.SchemaDescriptor
+	// td holds the objects in the dropped schemas, excluding those which are
+	// dropped anyway because they depend on another object in td.
+	td []toDelete
+}
+
+// DropSchema drops schemas of the current database.
+// Privileges: DROP on the schema, and DROP on all objects in the schema if
+// CASCADE is specified.
+//   Notes: postgres allows only the schema owner to DROP a schema.
+func (p *planner) DropSchema(ctx context.Context, n *tree.DropSchema) (planNode, error) {
+	dbName := p.CurrentDatabase()
+	if dbName == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, dbName, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []*sqlbase.SchemaDescriptor
+	var tbNames TableNames
+	seen := make(map[string]struct{}, len(n.Names))
+	for _, name := range n.Names {
+		scName := string(name)
+		if _, ok := seen[scName]; ok {
+			continue
+		}
+		seen[scName] = struct{}{}
+
+		if scName == tree.PublicSchema || sessiondata.IsSystemSchemaName(scName) {
+			return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
+				"cannot drop schema %q because it is required by the database system", scName)
+		}
+		schemaDesc, err := getUserDefinedSchemaDesc(ctx, p.txn, p.ExecCfg().Codec, dbDesc.ID, scName)
+		if err != nil {
+			return nil, err
+		}
+		if schemaDesc == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, pgerror.Newf(pgcode.InvalidSchemaName, "schema %q does not exist", scName)
+		}
+
+		if err := p.CheckPrivilege(ctx, schemaDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+
+		names, err := GetObjectNames(
+			ctx, p.txn, p, p.ExecCfg().Codec, dbDesc, scName, true, /* explicitPrefix */
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) > 0 && n.DropBehavior != tree.DropCascade {
+			return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
+				"schema %q is not empty and CASCADE was not specified", scName)
+		}
+		tbNames = append(tbNames, names...)
+		schemas = append(schemas, schemaDesc)
+	}
+
+	if len(schemas) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	td := make([]toDelete, 0, len(tbNames))
+	for i, tbName := range tbNames {
+		found, desc, err := p.LookupObject(
+			ctx,
+			tree.ObjectLookupFlags{
+				CommonLookupFlags: tree.CommonLookupFlags{Required: true},
+				RequireMutable:    true,
+			},
+			tbName.Catalog(),
+			tbName.Schema(),
+			tbName.Table(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		tbDesc, ok := desc.(*sqlbase.MutableTableDescriptor)
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"descriptor for %q is not MutableTableDescriptor",
+				tbName.String(),
+			)
+		}
+		if err := p.prepareDropWithTableDesc(ctx, tbDesc); err != nil {
+			return nil, err
+		}
+		td = append(td, toDelete{&tbNames[i], tbDesc})
+	}
+
+	// Objects outside of the dropped schemas that reference the objects being
+	// dropped are affected by the CASCADE, so check that we may modify them.
+	dropped := make(map[sqlbase.ID]struct{}, len(td))
+	for _, toDel := range td {
+		dropped[toDel.desc.ID] = struct{}{}
+	}
+	for _, toDel := range td {
+		tbDesc := toDel.desc
+		for i := range tbDesc.InboundFKs {
+			ref := &tbDesc.InboundFKs[i]
+			if _, ok := dropped[ref.OriginTableID]; !ok {
+				if err := p.canRemoveFKBackreference(ctx, tbDesc.Name, ref, tree.DropCascade); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, ref := range tbDesc.DependedOnBy {
+			if err := p.canRemoveDependentView(ctx, tbDesc, ref, tree.DropCascade); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	td, err = p.filterCascadedTables(ctx, td)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dropSchemaNode{n: n, dbDesc: dbDesc, schemas: schemas, td: td}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP SCHEMA performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropSchemaNode) ReadingOwnWrites() {}
+
+func (n *dropSchemaNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("schema"))
+
+	ctx := params.ctx
+	p := params.p
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+
+	// droppedObjects maps each dropped schema to the names of the objects that
+	// were dropped along with it.
+	droppedObjects := make(map[string][]string, len(n.schemas))
+	for _, toDel := range n.td {
+		desc := toDel.desc
+		var cascadedObjects []string
+		var err error
+		if desc.IsView() {
+			cascadedObjects, err = p.dropViewImpl(ctx, desc, true /* queueJob */, jobDesc, tree.DropCascade)
+		} else if desc.IsSequence() {
+			err = p.dropSequenceImpl(ctx, desc, true /* queueJob */, jobDesc, tree.DropCascade)
+		} else {
+			cascadedObjects, err = p.dropTableImpl(ctx, desc, true /* queueJob */, jobDesc)
+		}
+		if err != nil {
+			return err
+		}
+		scName := toDel.tn.Schema()
+		droppedObjects[scName] = append(droppedObjects[scName], cascadedObjects...)
+		droppedObjects[scName] = append(droppedObjects[scName], toDel.tn.FQString())
+	}
+
+	b := &kv.Batch{}
+	for _, schemaDesc := range n.schemas {
+		descKey := sqlbase.MakeDescMetadataKey(p.ExecCfg().Codec, schemaDesc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", descKey)
+		}
+		b.Del(descKey)
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	for _, schemaDesc := range n.schemas {
+		if err := sqlbase.RemoveSchemaNamespaceEntry(
+			ctx, p.txn, p.ExecCfg().Codec, n.dbDesc.ID, schemaDesc.Name,
+		); err != nil {
+			return err
+		}
+	}
+	p.Tables().releaseSchemas()
+
+	for _, schemaDesc := range n.schemas {
+		// Log Drop Schema event. This is an auditable log event and is recorded
+		// in the same transaction as the schema descriptor update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropSchema,
+			int32(schemaDesc.ID),
+			int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
+			struct {
+				SchemaName           string
+				Statement            string
+				User                 string
+				DroppedSchemaObjects []string
+			}{schemaDesc.Name, n.n.String(), p.SessionData().User, droppedObjects[schemaDesc.Name]},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropSchemaNode) Close(context.Context)        {}
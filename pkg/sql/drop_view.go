@@ -14,6 +14,8 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -43,6 +45,9 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 			// IfExists specified and the view did not exist.
 			continue
 		}
+		if err := checkViewMatchesMaterialized(droppedDesc, n.IsMaterialized); err != nil {
+			return nil, err
+		}
 
 		td = append(td, toDelete{tn, droppedDesc})
 	}
@@ -70,13 +75,32 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 	return &dropViewNode{n: n, td: td}, nil
 }
 
+// checkViewMatchesMaterialized returns an error if the view isn't materialized
+// while the statement is about materialized views, or vice versa.
+func checkViewMatchesMaterialized(desc *sqlbase.MutableTableDescriptor, materialized bool) error {
+	if desc.MaterializedView() && !materialized {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is a materialized view", desc.Name),
+			"use the corresponding MATERIALIZED VIEW command",
+		)
+	}
+	if !desc.MaterializedView() && materialized {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
+	}
+	return nil
+}
+
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
 // This is because DROP VIEW performs multiple KV operations on descriptors
 // and expects to see its own writes.
 func (n *dropViewNode) ReadingOwnWrites() {}
 
 func (n *dropViewNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("view"))
+	if n.n.IsMaterialized {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("materialized_view"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("view"))
+	}
 
 	ctx := params.ctx
 	for _, toDel := range n.td {
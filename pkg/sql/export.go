@@ -31,6 +31,7 @@ type exportNode struct {
 	source planNode
 
 	fileName        string
+	fileFormat      string
 	csvOpts         roachpb.CSVOptions
 	chunkSize       int
 	fileCompression execinfrapb.FileCompression
@@ -71,6 +72,7 @@ var exportOptionExpectValues = map[string]KVStringOptValidate{
 const exportChunkSizeDefault = 100000
 const exportFilePatternPart = "%part%"
 const exportFilePatternDefault = exportFilePatternPart + ".csv"
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
 const exportCompressionCodec = "gzip"
 
 // ConstructExport is part of the exec.Factory interface.
@@ -81,7 +83,9 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != "CSV" {
+	switch fileFormat {
+	case "CSV", "PARQUET":
+	default:
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
 
@@ -99,6 +103,15 @@ func (ef *execFactory) ConstructExport(
 		return nil, err
 	}
 
+	if fileFormat != "CSV" {
+		for _, opt := range []string{exportOptionDelimiter, exportOptionNullAs} {
+			if _, ok := optVals[opt]; ok {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s option is not supported with %s format", opt, fileFormat)
+			}
+		}
+	}
+
 	csvOpts := roachpb.CSVOptions{}
 
 	if override, ok := optVals[exportOptionDelimiter]; ok {
@@ -138,6 +151,7 @@ func (ef *execFactory) ConstructExport(
 	return &exportNode{
 		source:          input.(planNode),
 		fileName:        string(*fileNameStr),
+		fileFormat:      fileFormat,
 		csvOpts:         csvOpts,
 		chunkSize:       chunkSize,
 		fileCompression: codec,
@@ -22,16 +22,18 @@ import (
 
 // Grant adds privileges to users.
 // Current status:
-// - Target: single database, table, or view.
+// - Target: single database, schema, table, or view.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
-// Privileges: GRANT on database/table/view.
+// Privileges: GRANT on database/schema/table/view.
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 	if n.Targets.Databases != nil {
 		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnDatabase)
+	} else if n.Targets.Schemas != nil {
+		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnSchema)
 	} else {
 		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnTable)
 	}
@@ -48,16 +50,18 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 
 // Revoke removes privileges from users.
 // Current status:
-// - Target: single database, table, or view.
+// - Target: single database, schema, table, or view.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
-// Privileges: GRANT on database/table/view.
+// Privileges: GRANT on database/schema/table/view.
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error) {
 	if n.Targets.Databases != nil {
 		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnDatabase)
+	} else if n.Targets.Schemas != nil {
+		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnSchema)
 	} else {
 		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnTable)
 	}
@@ -157,6 +161,22 @@ func (n *changePrivilegesNode) startExec(params runParams) error {
 				return err
 			}
 
+		case *sqlbase.SchemaDescriptor:
+			if err := d.Validate(); err != nil {
+				return err
+			}
+			if err := writeDescToBatch(
+				ctx,
+				p.extendedEvalCtx.Tracing.KVTracingEnabled(),
+				p.ExecCfg().Settings,
+				b,
+				p.ExecCfg().Codec,
+				descriptor.GetID(),
+				descriptor,
+			); err != nil {
+				return err
+			}
+
 		case *sqlbase.MutableTableDescriptor:
 			// TODO (lucy): This should probably have a single consolidated job like
 			// DROP DATABASE.
@@ -995,6 +995,14 @@ CREATE TABLE information_schema.schema_privileges (
 			func(db *sqlbase.DatabaseDescriptor) error {
 				return forEachSchemaName(ctx, p, db, func(scName string) error {
 					privs := db.Privileges.Show()
+					// User-defined schemas carry their own privileges.
+					schemaDesc, err := getUserDefinedSchemaDesc(ctx, p.txn, p.ExecCfg().Codec, db.ID, scName)
+					if err != nil {
+						return err
+					}
+					if schemaDesc != nil {
+						privs = schemaDesc.Privileges.Show()
+					}
 					dbNameStr := tree.NewDString(db.Name)
 					scNameStr := tree.NewDString(scName)
 					// TODO(knz): This should filter for the current user, see
@@ -23,9 +23,11 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -2158,3 +2160,72 @@ func TestIntentOnSystemConfigDoesNotPreventSchemaChange(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+// TestDatabaseTableNameSchemaLookups checks that resolving a `<database>.<table>`
+// name, which is first looked up as `<schema>.<table>` in the current
+// database, only reads the missing schema's namespace entry once per
+// transaction, and not at all before user-defined schemas can exist.
+func TestDatabaseTableNameSchemaLookups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	// schemaKey is the namespace key of a schema named "db" in defaultdb.
+	var schemaKey atomic.Value
+	schemaKey.Store(roachpb.Key(nil))
+	var lookups int64
+	filter := func(_ context.Context, ba roachpb.BatchRequest) *roachpb.Error {
+		key := schemaKey.Load().(roachpb.Key)
+		if key == nil {
+			return nil
+		}
+		for _, ru := range ba.Requests {
+			if get, ok := ru.GetInner().(*roachpb.GetRequest); ok && get.Key.Equal(key) {
+				atomic.AddInt64(&lookups, 1)
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name             string
+		bootstrapVersion roachpb.Version
+		expectedLookups  int64
+	}{
+		{"user-defined schemas", clusterversion.TestingBinaryVersion, 1},
+		{
+			"no user-defined schemas",
+			clusterversion.VersionByKey(clusterversion.VersionUserDefinedSchemas - 1),
+			0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schemaKey.Store(roachpb.Key(nil))
+			s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+				Knobs: base.TestingKnobs{
+					Server: &server.TestingKnobs{
+						BootstrapVersionOverride:       tc.bootstrapVersion,
+						DisableAutomaticVersionUpgrade: 1,
+					},
+					Store: &kvserver.StoreTestingKnobs{
+						TestingRequestFilter: filter,
+					},
+				},
+			})
+			defer s.Stopper().Stop(ctx)
+			sqlDB := sqlutils.MakeSQLRunner(db)
+			sqlDB.Exec(t, `CREATE DATABASE db; CREATE TABLE db.t (a INT)`)
+
+			var dbID sqlbase.ID
+			sqlDB.QueryRow(t,
+				`SELECT id FROM system.namespace WHERE "parentID" = 0 AND name = 'defaultdb'`,
+			).Scan(&dbID)
+			atomic.StoreInt64(&lookups, 0)
+			schemaKey.Store(sqlbase.NewSchemaKey(dbID, "db").Key(keys.SystemSQLCodec))
+
+			sqlDB.Exec(t, `BEGIN; SELECT * FROM db.t; SELECT * FROM db.t; COMMIT`)
+			require.Equal(t, tc.expectedLookups, atomic.LoadInt64(&lookups))
+		})
+	}
+}
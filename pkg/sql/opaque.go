@@ -63,6 +63,8 @@ func buildOpaque(
 		plan, err = p.CommentOnIndex(ctx, n)
 	case *tree.CommentOnTable:
 		plan, err = p.CommentOnTable(ctx, n)
+	case *tree.ControlSchedules:
+		plan, err = p.ControlSchedules(ctx, n)
 	case *tree.CreateDatabase:
 		plan, err = p.CreateDatabase(ctx, n)
 	case *tree.CreateIndex:
@@ -85,6 +87,8 @@ func buildOpaque(
 		plan, err = p.DropDatabase(ctx, n)
 	case *tree.DropIndex:
 		plan, err = p.DropIndex(ctx, n)
+	case *tree.DropSchema:
+		plan, err = p.DropSchema(ctx, n)
 	case *tree.DropRole:
 		plan, err = p.DropRole(ctx, n)
 	case *tree.DropTable:
@@ -99,12 +103,16 @@ func buildOpaque(
 		plan, err = p.Grant(ctx, n)
 	case *tree.GrantRole:
 		plan, err = p.GrantRole(ctx, n)
+	case *tree.RefreshMaterializedView:
+		plan, err = p.RefreshMaterializedView(ctx, n)
 	case *tree.RenameColumn:
 		plan, err = p.RenameColumn(ctx, n)
 	case *tree.RenameDatabase:
 		plan, err = p.RenameDatabase(ctx, n)
 	case *tree.RenameIndex:
 		plan, err = p.RenameIndex(ctx, n)
+	case *tree.RenameSchema:
+		plan, err = p.RenameSchema(ctx, n)
 	case *tree.RenameTable:
 		plan, err = p.RenameTable(ctx, n)
 	case *tree.Revoke:
@@ -131,6 +139,8 @@ func buildOpaque(
 		plan, err = p.ShowClusterSetting(ctx, n)
 	case *tree.ShowHistogram:
 		plan, err = p.ShowHistogram(ctx, n)
+	case *tree.ShowSchedules:
+		plan, err = p.ShowSchedules(ctx, n)
 	case *tree.ShowTableStats:
 		plan, err = p.ShowTableStats(ctx, n)
 	case *tree.ShowTraceForSession:
@@ -170,6 +180,7 @@ func init() {
 		&tree.CommentOnDatabase{},
 		&tree.CommentOnIndex{},
 		&tree.CommentOnTable{},
+		&tree.ControlSchedules{},
 		&tree.CreateDatabase{},
 		&tree.CreateIndex{},
 		&tree.CreateSchema{},
@@ -181,6 +192,7 @@ func init() {
 		&tree.Discard{},
 		&tree.DropDatabase{},
 		&tree.DropIndex{},
+		&tree.DropSchema{},
 		&tree.DropTable{},
 		&tree.DropType{},
 		&tree.DropView{},
@@ -188,9 +200,11 @@ func init() {
 		&tree.DropSequence{},
 		&tree.Grant{},
 		&tree.GrantRole{},
+		&tree.RefreshMaterializedView{},
 		&tree.RenameColumn{},
 		&tree.RenameDatabase{},
 		&tree.RenameIndex{},
+		&tree.RenameSchema{},
 		&tree.RenameTable{},
 		&tree.Revoke{},
 		&tree.RevokeRole{},
@@ -204,6 +218,7 @@ func init() {
 		&tree.SetSessionCharacteristics{},
 		&tree.ShowClusterSetting{},
 		&tree.ShowHistogram{},
+		&tree.ShowSchedules{},
 		&tree.ShowTableStats{},
 		&tree.ShowTraceForSession{},
 		&tree.ShowZoneConfig{},
@@ -211,9 +226,11 @@ func init() {
 		&tree.Truncate{},
 
 		// CCL statements (without Export which has an optimizer operator).
+		&tree.AlterBackup{},
 		&tree.Backup{},
 		&tree.ShowBackup{},
 		&tree.Restore{},
+		&tree.ScheduledBackup{},
 		&tree.CreateChangefeed{},
 		&tree.Import{},
 	} {
@@ -41,6 +41,11 @@ type Table interface {
 	// information_schema tables.
 	IsVirtualTable() bool
 
+	// IsMaterializedView returns true if this table is a materialized view,
+	// which stores the results of its view query in its indexes. Materialized
+	// views can be read like tables, but can only be written by refreshing them.
+	IsMaterializedView() bool
+
 	// IsInterleaved returns true if any of this table's indexes are interleaved
 	// with index(es) from other table(s).
 	IsInterleaved() bool
@@ -67,6 +67,7 @@ func (b *Builder) buildCreateView(cv *memo.CreateViewExpr) (execPlan, error) {
 		cv.IfNotExists,
 		cv.Replace,
 		cv.Temporary,
+		cv.Materialized,
 		cv.ViewQuery,
 		cols,
 		cv.Deps,
@@ -505,6 +505,7 @@ type Factory interface {
 		ifNotExists bool,
 		replace bool,
 		temporary bool,
+		materialized bool,
 		viewQuery string,
 		columns sqlbase.ResultColumns,
 		deps opt.ViewDeps,
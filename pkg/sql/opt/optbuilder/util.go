@@ -476,8 +476,9 @@ func (b *Builder) resolveSchemaForCreate(name *tree.TableName) (cat.Schema, cat.
 		panic(err)
 	}
 
-	// Only allow creation of objects in the public schema.
-	if resName.Schema() != tree.PublicSchema {
+	// Objects can only be created in the public schema or in user-defined
+	// schemas.
+	if sessiondata.IsSystemSchemaName(resName.Schema()) {
 		panic(pgerror.Newf(pgcode.InvalidName,
 			"schema cannot be modified: %q", tree.ErrString(&resName)))
 	}
@@ -536,6 +537,12 @@ func (b *Builder) resolveTableForMutation(
 			"%q does not resolve to a table", tree.ErrString(n)))
 	}
 
+	// Materialized views can only be written by refreshing them.
+	if tab.IsMaterializedView() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot mutate materialized view %q", tab.Name()))
+	}
+
 	if outerAlias != nil {
 		alias = *outerAlias
 	}
@@ -87,6 +87,11 @@ type optSchema struct {
 	planner *planner
 	desc    *sqlbase.DatabaseDescriptor
 
+	// schemaDesc is the descriptor of the schema if it is a user-defined
+	// schema, and nil otherwise. If set, privileges are checked against it
+	// rather than against the database descriptor.
+	schemaDesc *sqlbase.SchemaDescriptor
+
 	name cat.SchemaName
 }
 
@@ -155,10 +160,18 @@ func (oc *optCatalog) ResolveSchema(
 			pgcode.InvalidSchemaName, "target database or schema does not exist",
 		)
 	}
+	dbDesc := desc.(*DatabaseDescriptor)
+	schemaDesc, err := getUserDefinedSchemaDesc(
+		ctx, oc.planner.Txn(), oc.codec(), dbDesc.ID, oc.tn.ObjectNamePrefix.Schema(),
+	)
+	if err != nil {
+		return nil, cat.SchemaName{}, err
+	}
 	return &optSchema{
-		planner: oc.planner,
-		desc:    desc.(*DatabaseDescriptor),
-		name:    oc.tn.ObjectNamePrefix,
+		planner:    oc.planner,
+		desc:       dbDesc,
+		schemaDesc: schemaDesc,
+		name:       oc.tn.ObjectNamePrefix,
 	}, oc.tn.ObjectNamePrefix, nil
 }
 
@@ -213,6 +226,9 @@ func (oc *optCatalog) ResolveDataSourceByID(
 func getDescForCatalogObject(o cat.Object) (sqlbase.DescriptorProto, error) {
 	switch t := o.(type) {
 	case *optSchema:
+		if t.schemaDesc != nil {
+			return t.schemaDesc, nil
+		}
 		return t.desc, nil
 	case *optTable:
 		return t.desc, nil
@@ -307,8 +323,9 @@ func (oc *optCatalog) dataSourceForDesc(
 	desc *sqlbase.ImmutableTableDescriptor,
 	name *cat.DataSourceName,
 ) (cat.DataSource, error) {
-	if desc.IsTable() {
-		// Tables require invalidation logic for cached wrappers.
+	if desc.IsTable() || desc.MaterializedView() {
+		// Tables and materialized views require invalidation logic for cached
+		// wrappers.
 		return oc.dataSourceForTable(ctx, flags, desc, name)
 	}
 
@@ -729,6 +746,11 @@ func (ot *optTable) IsVirtualTable() bool {
 	return false
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optTable) IsMaterializedView() bool {
+	return ot.desc.MaterializedView()
+}
+
 // IsInterleaved is part of the cat.Table interface.
 func (ot *optTable) IsInterleaved() bool {
 	return ot.desc.IsInterleaved()
@@ -1396,6 +1418,11 @@ func (ot *optVirtualTable) IsVirtualTable() bool {
 	return true
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optVirtualTable) IsMaterializedView() bool {
+	return false
+}
+
 // IsInterleaved is part of the cat.Table interface.
 func (ot *optVirtualTable) IsInterleaved() bool {
 	return ot.desc.IsInterleaved()
@@ -1800,6 +1800,7 @@ func (ef *execFactory) ConstructCreateView(
 	ifNotExists bool,
 	replace bool,
 	temporary bool,
+	materialized bool,
 	viewQuery string,
 	columns sqlbase.ResultColumns,
 	deps opt.ViewDeps,
@@ -1829,14 +1830,16 @@ func (ef *execFactory) ConstructCreateView(
 	}
 
 	return &createViewNode{
-		viewName:    tree.Name(viewName),
-		ifNotExists: ifNotExists,
-		replace:     replace,
-		temporary:   temporary,
-		viewQuery:   viewQuery,
-		dbDesc:      schema.(*optSchema).desc,
-		columns:     columns,
-		planDeps:    planDeps,
+		viewName:     tree.Name(viewName),
+		schemaName:   schema.(*optSchema).name.SchemaName,
+		ifNotExists:  ifNotExists,
+		replace:      replace,
+		temporary:    temporary,
+		viewQuery:    viewQuery,
+		materialized: materialized,
+		dbDesc:       schema.(*optSchema).desc,
+		columns:      columns,
+		planDeps:     planDeps,
 	}, nil
 }
 
@@ -53,6 +53,10 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER DATABASE foo RENAME ??`, `ALTER DATABASE`},
 		{`ALTER DATABASE foo RENAME TO bar ??`, `ALTER DATABASE`},
 
+		{`ALTER SCHEMA ??`, `ALTER SCHEMA`},
+		{`ALTER SCHEMA foo RENAME ??`, `ALTER SCHEMA`},
+		{`ALTER SCHEMA foo RENAME TO bar ??`, `ALTER SCHEMA`},
+
 		{`ALTER VIEW IF ??`, `ALTER VIEW`},
 		{`ALTER VIEW blah ??`, `ALTER VIEW`},
 		{`ALTER VIEW blah RENAME ??`, `ALTER VIEW`},
@@ -68,6 +72,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`ALTER ROLE bleh ?? WITH NOCREATEROLE`, `ALTER ROLE`},
 
+		{`ALTER BACKUP ??`, `ALTER BACKUP`},
+		{`ALTER BACKUP 'foo' ADD NEW_KMS = ??`, `ALTER BACKUP`},
+
 		{`ALTER RANGE foo CONFIGURE ??`, `ALTER RANGE`},
 		{`ALTER RANGE ??`, `ALTER RANGE`},
 
@@ -109,6 +116,7 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE ROLE bleh ?? WITH CREATEROLE`, `CREATE ROLE`},
 
 		{`CREATE VIEW blah (??`, `CREATE VIEW`},
+		{`CREATE MATERIALIZED VIEW blah (??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS (SELECT c FROM x) ??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS SELECT c FROM x ??`, `SELECT`},
 		{`CREATE VIEW blah AS (??`, `<SELECTCLAUSE>`},
@@ -117,6 +125,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`CREATE STATISTICS ??`, `CREATE STATISTICS`},
 
+		{`CREATE SCHEDULE ??`, `CREATE SCHEDULE FOR BACKUP`},
+		{`CREATE SCHEDULE FOR BACKUP ??`, `CREATE SCHEDULE FOR BACKUP`},
+
 		{`CREATE TABLE blah (??`, `CREATE TABLE`},
 		{`CREATE TABLE IF NOT ??`, `CREATE TABLE`},
 		{`CREATE TABLE blah (x, y) AS ??`, `CREATE TABLE`},
@@ -142,9 +153,14 @@ func TestContextualHelp(t *testing.T) {
 
 		{`DROP ??`, `DROP`},
 
+		{`DROP SCHEDULE ??`, `DROP SCHEDULES`},
+
 		{`DROP DATABASE IF ??`, `DROP DATABASE`},
 		{`DROP DATABASE IF EXISTS blah ??`, `DROP DATABASE`},
 
+		{`DROP SCHEMA ??`, `DROP SCHEMA`},
+		{`DROP SCHEMA IF EXISTS blah ??`, `DROP SCHEMA`},
+
 		{`DROP INDEX blah, ??`, `DROP INDEX`},
 		{`DROP INDEX blah@blih ??`, `DROP INDEX`},
 
@@ -216,9 +232,15 @@ func TestContextualHelp(t *testing.T) {
 		{`GRANT ALL ON foo TO ??`, `GRANT`},
 		{`GRANT ALL ON foo TO bar ??`, `GRANT`},
 
-		{`PAUSE ??`, `PAUSE JOBS`},
+		{`PAUSE ??`, `PAUSE`},
+		{`PAUSE JOB ??`, `PAUSE JOBS`},
+		{`PAUSE SCHEDULE ??`, `PAUSE SCHEDULES`},
+		{`PAUSE SCHEDULES ??`, `PAUSE SCHEDULES`},
 
-		{`RESUME ??`, `RESUME JOBS`},
+		{`RESUME ??`, `RESUME`},
+		{`RESUME JOB ??`, `RESUME JOBS`},
+		{`RESUME SCHEDULE ??`, `RESUME SCHEDULES`},
+		{`RESUME SCHEDULES ??`, `RESUME SCHEDULES`},
 
 		{`REVOKE ALL ??`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM ??`, `REVOKE`},
@@ -256,6 +278,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SHOW JOB ??`, `SHOW JOBS`},
 		{`SHOW JOBS ??`, `SHOW JOBS`},
+		{`SHOW SCHEDULE ??`, `SHOW SCHEDULES`},
+		{`SHOW SCHEDULES ??`, `SHOW SCHEDULES`},
 		{`SHOW AUTOMATIC JOBS ??`, `SHOW JOBS`},
 
 		{`SHOW BACKUP 'foo' ??`, `SHOW BACKUP`},
@@ -371,6 +395,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SAVEPOINT blah ??`, `SAVEPOINT`},
 
+		{`REFRESH ??`, `REFRESH`},
+		{`REFRESH MATERIALIZED VIEW blah ??`, `REFRESH`},
+
 		{`RELEASE blah ??`, `RELEASE`},
 		{`RELEASE SAVEPOINT blah ??`, `RELEASE`},
 
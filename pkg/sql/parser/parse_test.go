@@ -302,6 +302,11 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
 		{`CREATE TEMPORARY VIEW a AS SELECT b`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW IF NOT EXISTS a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a (x, y) AS SELECT c, d FROM b`},
+		{`REFRESH MATERIALIZED VIEW a.b`},
+		{`REFRESH MATERIALIZED VIEW CONCURRENTLY a.b`},
 
 		{`CREATE SEQUENCE a`},
 		{`EXPLAIN CREATE SEQUENCE a`},
@@ -370,6 +375,11 @@ func TestParse(t *testing.T) {
 		{`DROP DATABASE IF EXISTS a`},
 		{`DROP DATABASE a CASCADE`},
 		{`DROP DATABASE a RESTRICT`},
+		{`DROP SCHEMA a`},
+		{`EXPLAIN DROP SCHEMA a`},
+		{`DROP SCHEMA IF EXISTS a, b`},
+		{`DROP SCHEMA a CASCADE`},
+		{`DROP SCHEMA a RESTRICT`},
 		{`DROP TABLE a`},
 		{`EXPLAIN DROP TABLE a`},
 		{`DROP TABLE a.b`},
@@ -399,6 +409,8 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP MATERIALIZED VIEW a, b`},
+		{`DROP MATERIALIZED VIEW IF EXISTS a, b CASCADE`},
 		{`DROP SEQUENCE a`},
 		{`EXPLAIN DROP SEQUENCE a`},
 		{`DROP SEQUENCE a.b`},
@@ -425,6 +437,8 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN SHOW JOBS SELECT a`},
 		{`SHOW JOBS WHEN COMPLETE SELECT a`},
 		{`EXPLAIN SHOW JOBS WHEN COMPLETE SELECT a`},
+		{`SHOW JOB a WITH HISTORY`},
+		{`EXPLAIN SHOW JOB a WITH HISTORY`},
 
 		{`EXPLAIN SELECT 1`},
 		{`EXPLAIN EXPLAIN SELECT 1`},
@@ -545,6 +559,7 @@ func TestParse(t *testing.T) {
 		{`SHOW GRANTS ON TABLE foo, db.foo`},
 		{`SHOW GRANTS ON DATABASE foo, bar`},
 		{`SHOW GRANTS ON DATABASE foo FOR bar`},
+		{`SHOW GRANTS ON SCHEMA foo, bar`},
 		{`SHOW GRANTS FOR bar, baz`},
 
 		{`SHOW GRANTS ON ROLE`},
@@ -616,6 +631,8 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT CREATE ON SCHEMA foo TO root`},
+		{`GRANT ALL ON SCHEMA foo, bar TO root, test`},
 		{`GRANT rolea, roleb TO usera, userb`},
 		{`GRANT rolea, roleb TO usera, userb WITH ADMIN OPTION`},
 
@@ -627,6 +644,8 @@ func TestParse(t *testing.T) {
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
+		{`REVOKE CREATE ON SCHEMA foo FROM root`},
+		{`REVOKE ALL ON SCHEMA foo, bar FROM root, test`},
 		{`REVOKE rolea, roleb FROM usera, userb`},
 		{`REVOKE ADMIN OPTION FOR rolea, roleb FROM usera, userb`},
 
@@ -1180,6 +1199,9 @@ func TestParse(t *testing.T) {
 		{`ALTER DATABASE a RENAME TO b`},
 		{`EXPLAIN ALTER DATABASE a RENAME TO b`},
 
+		{`ALTER SCHEMA a RENAME TO b`},
+		{`EXPLAIN ALTER SCHEMA a RENAME TO b`},
+
 		{`ALTER INDEX b RENAME TO b`},
 		{`EXPLAIN ALTER INDEX b RENAME TO b`},
 		{`ALTER INDEX a@b RENAME TO b`},
@@ -1417,6 +1439,16 @@ func TestParse(t *testing.T) {
 		{`BACKUP DATABASE foo TO ($1, $2)`},
 		{`BACKUP DATABASE foo TO ($1, $2) INCREMENTAL FROM 'baz'`},
 
+		{`BACKUP TABLE foo INTO 'bar'`},
+		{`BACKUP DATABASE foo INTO LATEST IN 'bar'`},
+		{`BACKUP TABLE foo INTO 'subdir' IN ($1, 'bar') AS OF SYSTEM TIME '1' WITH revision_history`},
+		{`SHOW BACKUPS IN 'bar'`},
+		{`SHOW BACKUP 'subdir' IN 'bar'`},
+		{`SHOW BACKUP $1 IN $2 WITH foo = 'bar'`},
+		{`SHOW BACKUP ('foo', 'bar') WITH foo = 'bar'`},
+		{`SHOW BACKUP FILES ($1, $2)`},
+		{`SHOW BACKUP 'subdir' IN ('foo', 'bar')`},
+
 		{`RESTORE TABLE foo FROM 'bar'`},
 		{`EXPLAIN RESTORE TABLE foo FROM 'bar'`},
 		{`RESTORE TABLE foo FROM $1`},
@@ -1435,8 +1467,30 @@ func TestParse(t *testing.T) {
 		{`RESTORE DATABASE foo FROM ($1, $2), ($3, $4)`},
 		{`RESTORE DATABASE foo FROM ($1, $2), ($3, $4) AS OF SYSTEM TIME '1'`},
 
+		{`RESTORE TABLE foo FROM 'subdir' IN 'bar'`},
+		{`RESTORE DATABASE foo FROM $1 IN ($2, $3) AS OF SYSTEM TIME '1'`},
+
+		{`RESTORE TENANT 10 FROM REPLICATION STREAM FROM 'bar'`},
+		{`RESTORE TENANT 10 FROM REPLICATION STREAM FROM $1`},
+
 		{`BACKUP TABLE foo TO 'bar' WITH key1, key2 = 'value'`},
 		{`RESTORE TABLE foo FROM 'bar' WITH key1, key2 = 'value'`},
+		{`BACKUP TABLE foo TO 'bar' WITH kms = 'baz', kms = $1`},
+
+		{`ALTER BACKUP 'foo' ADD NEW_KMS = 'bar' WITH OLD_KMS = 'baz'`},
+		{`ALTER BACKUP $1 ADD NEW_KMS = ('bar', $2) WITH OLD_KMS = ($3, 'baz')`},
+
+		{`CREATE SCHEDULE FOR BACKUP TABLE foo TO 'bar' RECURRING '@hourly'`},
+		{`CREATE SCHEDULE 'my schedule' FOR BACKUP TABLE foo TO 'bar' RECURRING '@daily'`},
+		{`CREATE SCHEDULE FOR BACKUP TO 'bar' RECURRING '@daily' FULL BACKUP ALWAYS`},
+		{`CREATE SCHEDULE FOR BACKUP TO 'bar' WITH revision_history RECURRING '@daily' FULL BACKUP '@weekly'`},
+		{`CREATE SCHEDULE FOR BACKUP DATABASE foo TO ('bar', 'baz') RECURRING $1 FULL BACKUP $2 WITH SCHEDULE OPTIONS retention = '30 days'`},
+
+		{`PAUSE SCHEDULES SELECT a`},
+		{`RESUME SCHEDULES SELECT a`},
+		{`DROP SCHEDULES SELECT a`},
+		{`SHOW SCHEDULES`},
+		{`SHOW SCHEDULE 123`},
 
 		{`IMPORT TABLE foo CREATE USING 'nodelocal://0/some/file' CSV DATA ('path/to/some/file', $1) WITH temp = 'path/to/temp'`},
 		{`EXPLAIN IMPORT TABLE foo CREATE USING 'nodelocal://0/some/file' CSV DATA ('path/to/some/file', $1) WITH temp = 'path/to/temp'`},
@@ -1466,6 +1520,10 @@ func TestParse(t *testing.T) {
 		// {`CREATE CHANGEFEED FOR TABLE foo PARTITION bar, baz INTO 'sink'`},
 		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
+		{`CREATE CHANGEFEED FOR TABLE foo (a, b) INTO 'sink'`},
+		{`CREATE CHANGEFEED FOR TABLE foo WHERE a > 1 INTO 'sink' WITH bar = 'baz'`},
+		{`CREATE CHANGEFEED FOR TABLE foo (a) WHERE (a > 1) AND (b IS NULL) INTO 'sink'`},
+		{`EXPERIMENTAL CHANGEFEED FOR TABLE foo (a) WHERE a = 'x'`},
 
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
@@ -1972,6 +2030,13 @@ $function$`,
 		{`EXPLAIN RESUME JOB a`, `EXPLAIN RESUME JOBS VALUES (a)`},
 		{`PAUSE JOB a`, `PAUSE JOBS VALUES (a)`},
 		{`EXPLAIN PAUSE JOB a`, `EXPLAIN PAUSE JOBS VALUES (a)`},
+		{`PAUSE SCHEDULE a`, `PAUSE SCHEDULES VALUES (a)`},
+		{`RESUME SCHEDULE a`, `RESUME SCHEDULES VALUES (a)`},
+		{`DROP SCHEDULE a`, `DROP SCHEDULES VALUES (a)`},
+		{`CREATE SCHEDULE FOR BACKUP TO 'bar' RECURRING '@daily' WITH SCHEDULE OPTIONS (retention = '30 days')`,
+			`CREATE SCHEDULE FOR BACKUP TO 'bar' RECURRING '@daily' WITH SCHEDULE OPTIONS retention = '30 days'`},
+		{`RESTORE TABLE foo FROM LATEST IN 'bar'`, `RESTORE TABLE foo FROM 'latest' IN 'bar'`},
+		{`SHOW BACKUP LATEST IN 'bar'`, `SHOW BACKUP 'latest' IN 'bar'`},
 		{`SHOW JOB a`, `SHOW JOBS VALUES (a)`},
 		{`EXPLAIN SHOW JOB a`, `EXPLAIN SHOW JOBS VALUES (a)`},
 		{`SHOW JOB WHEN COMPLETE a`, `SHOW JOBS WHEN COMPLETE VALUES (a)`},
@@ -3181,7 +3246,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE FUNCTION a`, 17511, `create`, ``},
 		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`, ``},
 		{`CREATE LANGUAGE a`, 17511, `create language a`, ``},
-		{`CREATE MATERIALIZED VIEW a`, 41649, ``, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`, ``},
 		{`CREATE PUBLICATION a`, 0, `create publication`, ``},
 		{`CREATE RULE a`, 0, `create rule`, ``},
@@ -3203,7 +3267,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP OPERATOR a`, 0, `drop operator`, ``},
 		{`DROP PUBLICATION a`, 0, `drop publication`, ``},
 		{`DROP RULE a`, 0, `drop rule`, ``},
-		{`DROP SCHEMA a`, 26443, `drop`, ``},
 		{`DROP SERVER a`, 0, `drop server`, ``},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`, ``},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`, ``},
@@ -349,6 +349,9 @@ func (u *sqlSymUnion) targetList() tree.TargetList {
 func (u *sqlSymUnion) targetListPtr() *tree.TargetList {
     return u.val.(*tree.TargetList)
 }
+func (u *sqlSymUnion) fullBackupClause() *tree.FullBackupClause {
+    return u.val.(*tree.FullBackupClause)
+}
 func (u *sqlSymUnion) privilegeType() privilege.Kind {
     return u.val.(privilege.Kind)
 }
@@ -546,7 +549,7 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT AUTHORIZATION AUTOMATIC
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str> BACKUP BACKUPS BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BUNDLE BY
 
@@ -576,7 +579,7 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYCOLLECTION
 %token <str> GLOBAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HIGH HISTOGRAM HISTORY HOUR
 
 %token <str> IDENTITY
 %token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
@@ -589,17 +592,17 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 
 %token <str> KEY KEYS KV
 
-%token <str> LANGUAGE LAST LATERAL LC_CTYPE LC_COLLATE
+%token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LINESTRING LIST LOCAL
 %token <str> LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
 %token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE MINUTE MONTH
 %token <str> MULTILINESTRING MULTIPOINT MULTIPOLYGON
 
-%token <str> NAN NAME NAMES NATURAL NEXT NO NOCREATEROLE NOLOGIN NO_INDEX_JOIN
+%token <str> NAN NAME NAMES NATURAL NEW_KMS NEXT NO NOCREATEROLE NOLOGIN NO_INDEX_JOIN
 %token <str> NONE NORMAL NOT NOTHING NOTNULL NOWAIT NULL NULLIF NULLS NUMERIC
 
-%token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPT OPTION OPTIONS OR
+%token <str> OF OFF OFFSET OID OIDS OIDVECTOR OLD_KMS ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OPERATOR
 
 %token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PHYSICAL PLACING
@@ -608,20 +611,20 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 
 %token <str> QUERIES QUERY
 
-%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES
+%token <str> RANGE RANGES READ REAL RECURRING RECURSIVE REF REFERENCES REFRESH
 %token <str> REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE REINDEX
-%token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
+%token <str> REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
 %token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE
 
-%token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING STREAM SUBSTRING
 %token <str> SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION
 
-%token <str> TABLE TABLES TEMP TEMPLATE TEMPORARY TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
+%token <str> TABLE TABLES TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
 %token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE TRANSACTION TREAT TRIGGER TRIM TRUE
 %token <str> TRUNCATE TRUSTED TYPE
 %token <str> TRACING
@@ -664,9 +667,20 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %type <tree.Statement> alter_view_stmt
 %type <tree.Statement> alter_sequence_stmt
 %type <tree.Statement> alter_database_stmt
+%type <tree.Statement> alter_schema_stmt
 %type <tree.Statement> alter_range_stmt
 %type <tree.Statement> alter_partition_stmt
 %type <tree.Statement> alter_role_stmt
+%type <tree.Statement> alter_backup_stmt
+
+// CREATE SCHEDULE FOR BACKUP
+%type <tree.Statement> create_schedule_for_backup_stmt
+%type <tree.Expr> opt_description cron_expr
+%type <*tree.FullBackupClause> opt_full_backup_clause
+%type <*tree.TargetList> opt_backup_targets
+%type <[]tree.KVOption> opt_with_schedule_options
+
+%type <tree.Statement> drop_schedule_stmt
 
 // ALTER RANGE
 %type <tree.Statement> alter_zone_range_stmt
@@ -751,6 +765,7 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %type <tree.Statement> drop_database_stmt
 %type <tree.Statement> drop_index_stmt
 %type <tree.Statement> drop_role_stmt
+%type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
 %type <tree.Statement> drop_view_stmt
@@ -766,10 +781,10 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
 %type <tree.Statement> import_stmt
-%type <tree.Statement> pause_stmt
+%type <tree.Statement> pause_stmt pause_jobs_stmt pause_schedules_stmt
 %type <tree.Statement> release_stmt
 %type <tree.Statement> reset_stmt reset_session_stmt reset_csetting_stmt
-%type <tree.Statement> resume_stmt
+%type <tree.Statement> resume_stmt resume_jobs_stmt resume_schedules_stmt
 %type <tree.Statement> restore_stmt
 %type <tree.PartitionedBackup> partitioned_backup
 %type <[]tree.PartitionedBackup> partitioned_backup_list
@@ -801,6 +816,7 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %type <tree.Statement> show_indexes_stmt
 %type <tree.Statement> show_partitions_stmt
 %type <tree.Statement> show_jobs_stmt
+%type <tree.Statement> show_schedules_stmt
 %type <tree.Statement> show_queries_stmt
 %type <tree.Statement> show_ranges_stmt
 %type <tree.Statement> show_range_for_row_stmt
@@ -830,6 +846,7 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
 %type <tree.Statement> reindex_stmt
+%type <tree.Statement> refresh_stmt
 
 %type <[]string> opt_incremental
 %type <tree.KVOption> kv_option
@@ -1030,8 +1047,9 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 %type <str> non_reserved_word
 %type <str> non_reserved_word_or_sconst
 %type <tree.Expr> zone_value
-%type <tree.Expr> string_or_placeholder
+%type <tree.Expr> string_or_placeholder sconst_or_placeholder
 %type <tree.Expr> string_or_placeholder_list
+%type <tree.Exprs> string_or_placeholder_opt_list
 
 %type <str> unreserved_keyword type_func_name_keyword type_func_name_no_crdb_extra_keyword type_func_name_crdb_extra_keyword
 %type <str> col_name_keyword reserved_keyword cockroachdb_extra_reserved_keyword extra_var_value
@@ -1178,6 +1196,7 @@ stmt:
 | close_cursor_stmt
 | declare_cursor_stmt
 | reindex_stmt
+| refresh_stmt      // EXTEND WITH HELP: REFRESH
 | /* EMPTY */
   {
     $$.val = tree.Statement(nil)
@@ -1185,10 +1204,11 @@ stmt:
 
 // %Help: ALTER
 // %Category: Group
-// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER SEQUENCE, ALTER DATABASE, ALTER USER, ALTER ROLE
+// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER SEQUENCE, ALTER DATABASE, ALTER SCHEMA, ALTER USER, ALTER ROLE, ALTER BACKUP
 alter_stmt:
   alter_ddl_stmt      // help texts in sub-rule
 | alter_role_stmt     // EXTEND WITH HELP: ALTER ROLE
+| alter_backup_stmt   // EXTEND WITH HELP: ALTER BACKUP
 | ALTER error         // SHOW HELP: ALTER
 
 alter_ddl_stmt:
@@ -1197,6 +1217,7 @@ alter_ddl_stmt:
 | alter_view_stmt      // EXTEND WITH HELP: ALTER VIEW
 | alter_sequence_stmt  // EXTEND WITH HELP: ALTER SEQUENCE
 | alter_database_stmt  // EXTEND WITH HELP: ALTER DATABASE
+| alter_schema_stmt    // EXTEND WITH HELP: ALTER SCHEMA
 | alter_range_stmt     // EXTEND WITH HELP: ALTER RANGE
 | alter_partition_stmt // EXTEND WITH HELP: ALTER PARTITION
 
@@ -1328,6 +1349,18 @@ alter_database_stmt:
 // prefix is spread over multiple non-terminals.
 | ALTER DATABASE error // SHOW HELP: ALTER DATABASE
 
+// %Help: ALTER SCHEMA - change the definition of a schema
+// %Category: DDL
+// %Text:
+// ALTER SCHEMA <name> RENAME TO <newname>
+// %SeeAlso: CREATE SCHEMA, DROP SCHEMA
+alter_schema_stmt:
+  ALTER SCHEMA schema_name RENAME TO schema_name
+  {
+    $$.val = &tree.RenameSchema{Name: tree.Name($3), NewName: tree.Name($6)}
+  }
+| ALTER SCHEMA error // SHOW HELP: ALTER SCHEMA
+
 // %Help: ALTER RANGE - change the parameters of a range
 // %Category: DDL
 // %Text:
@@ -1875,6 +1908,10 @@ opt_validate_behavior:
 //        [ INCREMENTAL FROM <location...> ]
 //        [ WITH <option> [= <value>] [, ...] ]
 //
+// BACKUP [<targets...>] INTO [<subdir> IN | LATEST IN] <collection...>
+//        [ AS OF SYSTEM TIME <expr> ]
+//        [ WITH <option> [= <value>] [, ...] ]
+//
 // Targets:
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
@@ -1882,9 +1919,16 @@ opt_validate_behavior:
 // Location:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
 //
+// Collection:
+//    "[scheme]://[host]/[path to collection]?[parameters]"
+//    BACKUP INTO writes a full backup to a new subdirectory of the collection.
+//    BACKUP INTO <subdir> IN and BACKUP INTO LATEST IN append an incremental
+//    backup to a full backup of the collection.
+//
 // Options:
 //    INTO_DB
 //    SKIP_MISSING_FOREIGN_KEYS
+//    DETACHED
 //
 // %SeeAlso: RESTORE, WEBDOCS/backup.html
 backup_stmt:
@@ -1896,8 +1940,165 @@ backup_stmt:
   {
     $$.val = &tree.Backup{Targets: $2.targetList(), To: $4.partitionedBackup(), IncrementalFrom: $6.exprs(), AsOf: $5.asOfClause(), Options: $7.kvOptions()}
   }
+| BACKUP opt_backup_targets INTO sconst_or_placeholder IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    b := &tree.Backup{To: $6.partitionedBackup(), Nested: true, Subdir: $4.expr(), AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+    if targets := $2.targetListPtr(); targets != nil {
+      b.Targets = *targets
+    } else {
+      b.DescriptorCoverage = tree.AllDescriptors
+    }
+    $$.val = b
+  }
+| BACKUP opt_backup_targets INTO LATEST IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    b := &tree.Backup{To: $6.partitionedBackup(), Nested: true, AppendToLatest: true, AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+    if targets := $2.targetListPtr(); targets != nil {
+      b.Targets = *targets
+    } else {
+      b.DescriptorCoverage = tree.AllDescriptors
+    }
+    $$.val = b
+  }
+| BACKUP opt_backup_targets INTO partitioned_backup opt_as_of_clause opt_with_options
+  {
+    b := &tree.Backup{To: $4.partitionedBackup(), Nested: true, AsOf: $5.asOfClause(), Options: $6.kvOptions()}
+    if targets := $2.targetListPtr(); targets != nil {
+      b.Targets = *targets
+    } else {
+      b.DescriptorCoverage = tree.AllDescriptors
+    }
+    $$.val = b
+  }
 | BACKUP error // SHOW HELP: BACKUP
 
+// %Help: ALTER BACKUP - alter the encryption keys of a backup
+// %Category: CCL
+// %Text:
+// ALTER BACKUP <location>
+//       ADD NEW_KMS = <kms_uri> | ( <kms_uri> [, ...] )
+//       WITH OLD_KMS = <kms_uri> | ( <kms_uri> [, ...] )
+//
+// Location:
+//    "[scheme]://[host]/[path to backup]?[parameters]"
+//
+// %SeeAlso: BACKUP, RESTORE
+alter_backup_stmt:
+  ALTER BACKUP string_or_placeholder ADD NEW_KMS '=' string_or_placeholder_opt_list WITH OLD_KMS '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.AlterBackup{Backup: $3.expr(), NewKMSURIs: $7.exprs(), OldKMSURIs: $11.exprs()}
+  }
+| ALTER BACKUP error // SHOW HELP: ALTER BACKUP
+
+// %Help: CREATE SCHEDULE FOR BACKUP - backup data periodically
+// %Category: CCL
+// %Text:
+// CREATE SCHEDULE [<description>]
+// FOR BACKUP [<targets>] TO <location...>
+// [WITH <backup_option>[=<value>] [, ...]]
+// RECURRING <crontab> [FULL BACKUP <crontab>|ALWAYS]
+// [WITH SCHEDULE OPTIONS <schedule_option>[= <value>] [, ...] ]
+//
+// All backups run in UTC timezone.
+//
+// Description:
+//   Optional description (or name) for this schedule
+//
+// Targets:
+//   empty targets: Backup entire cluster
+//   DATABASE <pattern> [, ...]: comma separated list of databases to backup.
+//   TABLE <pattern> [, ...]: comma separated list of tables to backup.
+//
+// Location:
+//   "[scheme]://[host]/[path prefix to backup collections]?[parameters]"
+//   Every full backup, along with the incremental backups on top of it,
+//   is written to a new collection under that path.
+//
+// backup_options:
+//   BACKUP options
+//
+// RECURRING <crontab>:
+//   The RECURRING expression specifies when we backup. The expression is a
+//   standard crontab expression, such as '@daily' or '0 * * * *'.
+//
+// FULL BACKUP <crontab|ALWAYS>:
+//   The optional FULL BACKUP '<cron expr>' clause specifies when we'll start
+//   a new full backup, which becomes the base for the incremental backups
+//   taken at the RECURRING schedule until the next full backup.
+//   If omitted, or if set to ALWAYS, every backup is a full backup.
+//
+// schedule_option:
+//   retention: how long the backup collections superseded by a newer
+//   full backup are kept, e.g. retention = '30 days'.
+//
+// %SeeAlso: BACKUP, SHOW SCHEDULES, PAUSE SCHEDULES, RESUME SCHEDULES, DROP SCHEDULES
+create_schedule_for_backup_stmt:
+  CREATE SCHEDULE opt_description FOR BACKUP opt_backup_targets TO partitioned_backup opt_with_options cron_expr opt_full_backup_clause opt_with_schedule_options
+  {
+    $$.val = &tree.ScheduledBackup{
+      ScheduleName:    $3.expr(),
+      Recurrence:      $10.expr(),
+      FullBackup:      $11.fullBackupClause(),
+      To:              $8.partitionedBackup(),
+      Targets:         $6.targetListPtr(),
+      BackupOptions:   $9.kvOptions(),
+      ScheduleOptions: $12.kvOptions(),
+    }
+  }
+| CREATE SCHEDULE error  // SHOW HELP: CREATE SCHEDULE FOR BACKUP
+
+opt_description:
+  string_or_placeholder
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+cron_expr:
+  RECURRING sconst_or_placeholder
+  {
+    $$.val = $2.expr()
+  }
+
+opt_full_backup_clause:
+  FULL BACKUP sconst_or_placeholder
+  {
+    $$.val = &tree.FullBackupClause{Recurrence: $3.expr()}
+  }
+| FULL BACKUP ALWAYS
+  {
+    $$.val = &tree.FullBackupClause{AlwaysFull: true}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.FullBackupClause)(nil)
+  }
+
+opt_backup_targets:
+  /* EMPTY -- full cluster */
+  {
+    $$.val = (*tree.TargetList)(nil)
+  }
+| targets
+  {
+    t := $1.targetList()
+    $$.val = &t
+  }
+
+opt_with_schedule_options:
+  WITH SCHEDULE OPTIONS kv_option_list
+  {
+    $$.val = $4.kvOptions()
+  }
+| WITH SCHEDULE OPTIONS '(' kv_option_list ')'
+  {
+    $$.val = $5.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
 // %Help: RESTORE - restore data from external storage
 // %Category: CCL
 // %Text:
@@ -1905,6 +2106,12 @@ backup_stmt:
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 //
+// RESTORE <targets...> FROM {<subdir> | LATEST} IN <collection...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+//
+// RESTORE TENANT <id> FROM REPLICATION STREAM FROM <stream address>
+//
 // Targets:
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
@@ -1915,6 +2122,8 @@ backup_stmt:
 // Options:
 //    INTO_DB
 //    SKIP_MISSING_FOREIGN_KEYS
+//    SPAN_FILTER = '<key>' or '<start key>, <end key>'
+//    VERIFY_ONLY
 //
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
@@ -1926,6 +2135,18 @@ restore_stmt:
   {
     $$.val = &tree.Restore{Targets: $2.targetList(), From: $4.partitionedBackups(), AsOf: $5.asOfClause(), Options: $6.kvOptions()}
   }
+| RESTORE FROM string_or_placeholder IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{DescriptorCoverage: tree.AllDescriptors, Subdir: $3.expr(), From: []tree.PartitionedBackup{$5.partitionedBackup()}, AsOf: $6.asOfClause(), Options: $7.kvOptions()}
+  }
+| RESTORE targets FROM string_or_placeholder IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{Targets: $2.targetList(), Subdir: $4.expr(), From: []tree.PartitionedBackup{$6.partitionedBackup()}, AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+  }
+| RESTORE TENANT iconst64 FROM REPLICATION STREAM FROM string_or_placeholder
+  {
+    $$.val = &tree.StreamIngestion{TenantID: uint64($3.int64()), StreamAddress: $8.expr()}
+  }
 | RESTORE error // SHOW HELP: RESTORE
 
 partitioned_backup:
@@ -2035,6 +2256,7 @@ import_stmt:
 //
 // Formats:
 //    CSV
+//    PARQUET
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
@@ -2059,6 +2281,19 @@ string_or_placeholder:
     $$.val = p
   }
 
+// sconst_or_placeholder matches a simple string, or a placeholder.
+sconst_or_placeholder:
+  SCONST
+  {
+    $$.val = tree.NewStrVal($1)
+  }
+| PLACEHOLDER
+  {
+    p := $1.placeholder()
+    sqllex.(*lexer).UpdateNumPlaceholders(p)
+    $$.val = p
+  }
+
 string_or_placeholder_list:
   string_or_placeholder
   {
@@ -2069,6 +2304,16 @@ string_or_placeholder_list:
     $$.val = append($1.exprs(), $3.expr())
   }
 
+string_or_placeholder_opt_list:
+  string_or_placeholder
+  {
+    $$.val = tree.Exprs{$1.expr()}
+  }
+| '(' string_or_placeholder_list ')'
+  {
+    $$.val = $2.exprs()
+  }
+
 opt_incremental:
   INCREMENTAL FROM string_or_placeholder_list
   {
@@ -2281,11 +2526,12 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE, CREATE TYPE
+// CREATE ROLE, CREATE TYPE, CREATE SCHEDULE FOR BACKUP
 create_stmt:
   create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
 | create_stats_stmt    // EXTEND WITH HELP: CREATE STATISTICS
+| create_schedule_for_backup_stmt // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
 | create_unsupported   {}
 | CREATE error         // SHOW HELP: CREATE
 
@@ -2302,7 +2548,6 @@ create_unsupported:
 | CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
-| CREATE MATERIALIZED VIEW error { return unimplementedWithIssue(sqllex, 41649) }
 | CREATE OPERATOR error { return unimplemented(sqllex, "create operator") }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
@@ -2338,7 +2583,6 @@ drop_unsupported:
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
-| DROP SCHEMA error { return unimplementedWithIssueDetail(sqllex, 26443, "drop") }
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
@@ -2453,20 +2697,24 @@ create_stats_option:
   }
 
 create_changefeed_stmt:
-  CREATE CHANGEFEED FOR changefeed_targets opt_changefeed_sink opt_with_options
+  CREATE CHANGEFEED FOR changefeed_targets opt_column_list opt_where_clause opt_changefeed_sink opt_with_options
   {
     $$.val = &tree.CreateChangefeed{
       Targets: $4.targetList(),
-      SinkURI: $5.expr(),
-      Options: $6.kvOptions(),
+      Columns: $5.nameList(),
+      Where:   tree.NewWhere(tree.AstWhere, $6.expr()),
+      SinkURI: $7.expr(),
+      Options: $8.kvOptions(),
     }
   }
-| EXPERIMENTAL CHANGEFEED FOR changefeed_targets opt_with_options
+| EXPERIMENTAL CHANGEFEED FOR changefeed_targets opt_column_list opt_where_clause opt_with_options
   {
     /* SKIP DOC */
     $$.val = &tree.CreateChangefeed{
       Targets: $4.targetList(),
-      Options: $5.kvOptions(),
+      Columns: $5.nameList(),
+      Where:   tree.NewWhere(tree.AstWhere, $6.expr()),
+      Options: $7.kvOptions(),
     }
   }
 
@@ -2545,16 +2793,18 @@ discard_stmt:
 // %Help: DROP
 // %Category: Group
 // %Text:
-// DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE, DROP TYPE
+// DROP DATABASE, DROP SCHEMA, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
+// DROP USER, DROP ROLE, DROP TYPE, DROP SCHEDULES
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
+| drop_schedule_stmt // EXTEND WITH HELP: DROP SCHEDULES
 | drop_unsupported   {}
 | DROP error         // SHOW HELP: DROP
 
 drop_ddl_stmt:
   drop_database_stmt // EXTEND WITH HELP: DROP DATABASE
+| drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_index_stmt    // EXTEND WITH HELP: DROP INDEX
 | drop_table_stmt    // EXTEND WITH HELP: DROP TABLE
 | drop_view_stmt     // EXTEND WITH HELP: DROP VIEW
@@ -2563,7 +2813,7 @@ drop_ddl_stmt:
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
-// %Text: DROP VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: DROP [MATERIALIZED] VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
 // %SeeAlso: WEBDOCS/drop-index.html
 drop_view_stmt:
   DROP VIEW table_name_list opt_drop_behavior
@@ -2574,6 +2824,24 @@ drop_view_stmt:
   {
     $$.val = &tree.DropView{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP MATERIALIZED VIEW table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $4.tableNames(),
+      IfExists: false,
+      DropBehavior: $5.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
+| DROP MATERIALIZED VIEW IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $6.tableNames(),
+      IfExists: true,
+      DropBehavior: $7.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
 | DROP VIEW error // SHOW HELP: DROP VIEW
 
 // %Help: DROP SEQUENCE - remove a sequence
@@ -2654,6 +2922,29 @@ drop_database_stmt:
   }
 | DROP DATABASE error // SHOW HELP: DROP DATABASE
 
+// %Help: DROP SCHEMA - remove a schema
+// %Category: DDL
+// %Text: DROP SCHEMA [IF EXISTS] <schemaname> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE SCHEMA, ALTER SCHEMA
+drop_schema_stmt:
+  DROP SCHEMA name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{
+      Names: $3.nameList(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP SCHEMA IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{
+      Names: $5.nameList(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP SCHEMA error // SHOW HELP: DROP SCHEMA
+
 // %Help: DROP TYPE - remove a type
 // %Category: DDL
 // %Text: DROP TYPE [IF EXISTS] <type_name> [, ...] [CASCASE | RESTRICT]
@@ -2704,6 +2995,35 @@ drop_role_stmt:
   }
 | DROP role_or_group_or_user error // SHOW HELP: DROP ROLE
 
+// %Help: DROP SCHEDULES - destroy specified schedules
+// %Category: Misc
+// %Text:
+// DROP SCHEDULES <selectclause>
+//  selectclause: select statement returning schedule IDs to drop.
+//
+// DROP SCHEDULE <scheduleID>
+//
+// %SeeAlso: PAUSE SCHEDULES, SHOW SCHEDULES, CREATE SCHEDULE FOR BACKUP
+drop_schedule_stmt:
+  DROP SCHEDULE a_expr
+  {
+    $$.val = &tree.ControlSchedules{
+      Schedules: &tree.Select{
+        Select: &tree.ValuesClause{Rows: []tree.Exprs{tree.Exprs{$3.expr()}}},
+      },
+      Command: tree.DropSchedule,
+    }
+  }
+| DROP SCHEDULE error // SHOW HELP: DROP SCHEDULES
+| DROP SCHEDULES select_stmt
+  {
+    $$.val = &tree.ControlSchedules{
+      Schedules: $3.slct(),
+      Command: tree.DropSchedule,
+    }
+  }
+| DROP SCHEDULES error // SHOW HELP: DROP SCHEDULES
+
 table_name_list:
   table_name
   {
@@ -2798,10 +3118,10 @@ preparable_stmt:
 | explain_stmt      // EXTEND WITH HELP: EXPLAIN
 | import_stmt       // EXTEND WITH HELP: IMPORT
 | insert_stmt       // EXTEND WITH HELP: INSERT
-| pause_stmt        // EXTEND WITH HELP: PAUSE JOBS
+| pause_stmt        // help texts in sub-rule
 | reset_stmt        // help texts in sub-rule
 | restore_stmt      // EXTEND WITH HELP: RESTORE
-| resume_stmt       // EXTEND WITH HELP: RESUME JOBS
+| resume_stmt       // help texts in sub-rule
 | export_stmt       // EXTEND WITH HELP: EXPORT
 | scrub_stmt        // help texts in sub-rule
 | select_stmt       // help texts in sub-rule
@@ -2941,6 +3261,7 @@ deallocate_stmt:
 //
 // Targets:
 //   DATABASE <databasename> [, ...]
+//   SCHEMA <schemaname> [, ...]
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: REVOKE, WEBDOCS/grant.html
@@ -2972,6 +3293,7 @@ grant_stmt:
 //
 // Targets:
 //   DATABASE <databasename> [, <databasename>]...
+//   SCHEMA <schemaname> [, <schemaname>]...
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: GRANT, WEBDOCS/revoke.html
@@ -3454,6 +3776,7 @@ show_stmt:
 | show_indexes_stmt         // EXTEND WITH HELP: SHOW INDEXES
 | show_partitions_stmt      // EXTEND WITH HELP: SHOW PARTITIONS
 | show_jobs_stmt            // EXTEND WITH HELP: SHOW JOBS
+| show_schedules_stmt       // EXTEND WITH HELP: SHOW SCHEDULES
 | show_queries_stmt         // EXTEND WITH HELP: SHOW QUERIES
 | show_ranges_stmt          // EXTEND WITH HELP: SHOW RANGES
 | show_range_for_row_stmt
@@ -3503,6 +3826,21 @@ reindex_stmt:
     return purposelyUnimplemented(sqllex, "reindex system", "CockroachDB does not require reindexing.")
   }
 
+// %Help: REFRESH - recalculate a materialized view
+// %Category: Misc
+// %Text:
+// REFRESH MATERIALIZED VIEW [CONCURRENTLY] <viewname>
+// %SeeAlso: CREATE VIEW
+refresh_stmt:
+  REFRESH MATERIALIZED VIEW opt_concurrently view_name
+  {
+    $$.val = &tree.RefreshMaterializedView{
+      Name: $5.unresolvedObjectName(),
+      Concurrently: $4.bool(),
+    }
+  }
+| REFRESH error // SHOW HELP: REFRESH
+
 // %Help: SHOW SESSION - display session variables
 // %Category: Cfg
 // %Text: SHOW [SESSION] { <var> | ALL }
@@ -3570,45 +3908,74 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text:
+// SHOW BACKUP [SCHEMAS|FILES|RANGES] <location...>
+// SHOW BACKUP {<subdir> | LATEST} IN <collection...>
+// SHOW BACKUPS IN <collection>
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
-  SHOW BACKUP string_or_placeholder opt_with_options
+  SHOW BACKUPS IN string_or_placeholder
   {
     $$.val = &tree.ShowBackup{
-      Details: tree.BackupDefaultDetails,
-      Path:    $3.expr(),
-      Options: $4.kvOptions(),
+      InCollection: $4.expr(),
+    }
+  }
+| SHOW BACKUP partitioned_backup opt_with_options
+  {
+    uris := $3.partitionedBackup()
+    $$.val = &tree.ShowBackup{
+      Details:      tree.BackupDefaultDetails,
+      Path:         uris[0],
+      LocalityURIs: uris[1:],
+      Options:      $4.kvOptions(),
+    }
+  }
+| SHOW BACKUP string_or_placeholder IN partitioned_backup opt_with_options
+  {
+    uris := $5.partitionedBackup()
+    $$.val = &tree.ShowBackup{
+      Details:      tree.BackupDefaultDetails,
+      Path:         $3.expr(),
+      InCollection: uris[0],
+      LocalityURIs: uris[1:],
+      Options:      $6.kvOptions(),
     }
   }
-| SHOW BACKUP SCHEMAS string_or_placeholder opt_with_options
+| SHOW BACKUP SCHEMAS partitioned_backup opt_with_options
   {
+    uris := $4.partitionedBackup()
     $$.val = &tree.ShowBackup{
       Details: tree.BackupDefaultDetails,
       ShouldIncludeSchemas: true,
-      Path:    $4.expr(),
+      Path:    uris[0],
+      LocalityURIs: uris[1:],
       Options: $5.kvOptions(),
     }
   }
-| SHOW BACKUP RANGES string_or_placeholder opt_with_options
+| SHOW BACKUP RANGES partitioned_backup opt_with_options
   {
     /* SKIP DOC */
+    uris := $4.partitionedBackup()
     $$.val = &tree.ShowBackup{
       Details: tree.BackupRangeDetails,
-      Path:    $4.expr(),
+      Path:    uris[0],
+      LocalityURIs: uris[1:],
       Options: $5.kvOptions(),
     }
   }
-| SHOW BACKUP FILES string_or_placeholder opt_with_options
+| SHOW BACKUP FILES partitioned_backup opt_with_options
   {
     /* SKIP DOC */
+    uris := $4.partitionedBackup()
     $$.val = &tree.ShowBackup{
       Details: tree.BackupFileDetails,
-      Path:    $4.expr(),
+      Path:    uris[0],
+      LocalityURIs: uris[1:],
       Options: $5.kvOptions(),
     }
   }
 | SHOW BACKUP error // SHOW HELP: SHOW BACKUP
+| SHOW BACKUPS error // SHOW HELP: SHOW BACKUP
 
 // %Help: SHOW CLUSTER SETTING - display cluster settings
 // %Category: Cfg
@@ -3785,6 +4152,7 @@ opt_cluster:
 // %Text:
 // SHOW [AUTOMATIC] JOBS
 // SHOW JOB <jobid>
+// SHOW JOB <jobid> WITH HISTORY
 // %SeeAlso: CANCEL JOBS, PAUSE JOBS, RESUME JOBS
 show_jobs_stmt:
   SHOW AUTOMATIC JOBS
@@ -3823,8 +4191,30 @@ show_jobs_stmt:
       Block: true,
     }
   }
+| SHOW JOB a_expr WITH HISTORY
+  {
+    $$.val = &tree.ShowJobHistory{JobID: $3.expr()}
+  }
 | SHOW JOB error // SHOW HELP: SHOW JOBS
 
+// %Help: SHOW SCHEDULES - list periodic schedules
+// %Category: Misc
+// %Text:
+// SHOW SCHEDULES
+// SHOW SCHEDULE <schedule_id>
+// %SeeAlso: PAUSE SCHEDULES, RESUME SCHEDULES, DROP SCHEDULES
+show_schedules_stmt:
+  SHOW SCHEDULES
+  {
+    $$.val = &tree.ShowSchedules{}
+  }
+| SHOW SCHEDULES error // SHOW HELP: SHOW SCHEDULES
+| SHOW SCHEDULE a_expr
+  {
+    $$.val = &tree.ShowSchedules{ScheduleID: $3.expr()}
+  }
+| SHOW SCHEDULE error // SHOW HELP: SHOW SCHEDULES
+
 // %Help: SHOW TRACE - display an execution trace
 // %Category: Misc
 // %Text:
@@ -4306,6 +4696,10 @@ targets:
   {
     $$.val = tree.TargetList{Databases: $2.nameList()}
   }
+| SCHEMA name_list
+  {
+    $$.val = tree.TargetList{Schemas: $2.nameList()}
+  }
 
 // target_roles is the variant of targets which recognizes ON ROLES
 // with a name list. This cannot be included in targets directly
@@ -4327,13 +4721,25 @@ for_grantee_clause:
     $$.val = tree.NameList(nil)
   }
 
+// %Help: PAUSE
+// %Category: Misc
+// %Text:
+//
+// Pause various background tasks and activities.
+//
+// PAUSE JOBS, PAUSE SCHEDULES
+pause_stmt:
+  pause_jobs_stmt       // EXTEND WITH HELP: PAUSE JOBS
+| pause_schedules_stmt  // EXTEND WITH HELP: PAUSE SCHEDULES
+| PAUSE error           // SHOW HELP: PAUSE
+
 // %Help: PAUSE JOBS - pause background jobs
 // %Category: Misc
 // %Text:
 // PAUSE JOBS <selectclause>
 // PAUSE JOB <jobid>
 // %SeeAlso: SHOW JOBS, CANCEL JOBS, RESUME JOBS
-pause_stmt:
+pause_jobs_stmt:
   PAUSE JOB a_expr
   {
     $$.val = &tree.ControlJobs{
@@ -4347,12 +4753,41 @@ pause_stmt:
   {
     $$.val = &tree.ControlJobs{Jobs: $3.slct(), Command: tree.PauseJob}
   }
-| PAUSE error // SHOW HELP: PAUSE JOBS
+| PAUSE JOB error // SHOW HELP: PAUSE JOBS
+| PAUSE JOBS error // SHOW HELP: PAUSE JOBS
 
-// %Help: CREATE SCHEMA - create a new schema (not yet supported)
+// %Help: PAUSE SCHEDULES - pause scheduled jobs
+// %Category: Misc
+// %Text:
+// PAUSE SCHEDULES <selectclause>
+//   select clause: select statement returning schedule id to pause.
+// PAUSE SCHEDULE <scheduleID>
+// %SeeAlso: RESUME SCHEDULES, SHOW SCHEDULES, DROP SCHEDULES
+pause_schedules_stmt:
+  PAUSE SCHEDULE a_expr
+  {
+    $$.val = &tree.ControlSchedules{
+      Schedules: &tree.Select{
+        Select: &tree.ValuesClause{Rows: []tree.Exprs{tree.Exprs{$3.expr()}}},
+      },
+      Command: tree.PauseSchedule,
+    }
+  }
+| PAUSE SCHEDULE error // SHOW HELP: PAUSE SCHEDULES
+| PAUSE SCHEDULES select_stmt
+  {
+    $$.val = &tree.ControlSchedules{
+      Schedules: $3.slct(),
+      Command: tree.PauseSchedule,
+    }
+  }
+| PAUSE SCHEDULES error // SHOW HELP: PAUSE SCHEDULES
+
+// %Help: CREATE SCHEMA - create a new schema
 // %Category: DDL
 // %Text:
 // CREATE SCHEMA [IF NOT EXISTS] <schemaname>
+// %SeeAlso: ALTER SCHEMA, DROP SCHEMA
 create_schema_stmt:
   CREATE SCHEMA schema_name
   {
@@ -5363,7 +5798,9 @@ role_or_group_or_user:
 
 // %Help: CREATE VIEW - create a new view
 // %Category: DDL
-// %Text: CREATE [TEMPORARY | TEMP] VIEW <viewname> [( <colnames...> )] AS <source>
+// %Text:
+// CREATE [TEMPORARY | TEMP] VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source>
+// CREATE MATERIALIZED VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source>
 // %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
@@ -5404,7 +5841,29 @@ create_view_stmt:
       Replace: false,
     }
   }
+| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt
+  {
+    name := $4.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateView{
+      Name: name,
+      ColumnNames: $5.nameList(),
+      AsSource: $7.slct(),
+      Materialized: true,
+    }
+  }
+| CREATE MATERIALIZED VIEW IF NOT EXISTS view_name opt_column_list AS select_stmt
+  {
+    name := $7.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateView{
+      Name: name,
+      ColumnNames: $8.nameList(),
+      AsSource: $10.slct(),
+      IfNotExists: true,
+      Materialized: true,
+    }
+  }
 | CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
+| CREATE MATERIALIZED VIEW error // SHOW HELP: CREATE VIEW
 
 role_option:
   CREATEROLE
@@ -5762,13 +6221,25 @@ release_stmt:
   }
 | RELEASE error // SHOW HELP: RELEASE
 
+// %Help: RESUME
+// %Category: Misc
+// %Text:
+//
+// Resume various background tasks and activities.
+//
+// RESUME JOBS, RESUME SCHEDULES
+resume_stmt:
+  resume_jobs_stmt       // EXTEND WITH HELP: RESUME JOBS
+| resume_schedules_stmt  // EXTEND WITH HELP: RESUME SCHEDULES
+| RESUME error           // SHOW HELP: RESUME
+
 // %Help: RESUME JOBS - resume background jobs
 // %Category: Misc
 // %Text:
 // RESUME JOBS <selectclause>
 // RESUME JOB <jobid>
 // %SeeAlso: SHOW JOBS, CANCEL JOBS, PAUSE JOBS
-resume_stmt:
+resume_jobs_stmt:
   RESUME JOB a_expr
   {
     $$.val = &tree.ControlJobs{
@@ -5782,7 +6253,35 @@ resume_stmt:
   {
     $$.val = &tree.ControlJobs{Jobs: $3.slct(), Command: tree.ResumeJob}
   }
-| RESUME error // SHOW HELP: RESUME JOBS
+| RESUME JOB error // SHOW HELP: RESUME JOBS
+| RESUME JOBS error // SHOW HELP: RESUME JOBS
+
+// %Help: RESUME SCHEDULES - resume executing scheduled jobs
+// %Category: Misc
+// %Text:
+// RESUME SCHEDULES <selectclause>
+//   select clause: select statement returning schedule id to resume.
+// RESUME SCHEDULE <scheduleID>
+// %SeeAlso: PAUSE SCHEDULES, SHOW SCHEDULES, DROP SCHEDULES
+resume_schedules_stmt:
+  RESUME SCHEDULE a_expr
+  {
+    $$.val = &tree.ControlSchedules{
+      Schedules: &tree.Select{
+        Select: &tree.ValuesClause{Rows: []tree.Exprs{tree.Exprs{$3.expr()}}},
+      },
+      Command: tree.ResumeSchedule,
+    }
+  }
+| RESUME SCHEDULE error // SHOW HELP: RESUME SCHEDULES
+| RESUME SCHEDULES select_stmt
+  {
+    $$.val = &tree.ControlSchedules{
+      Schedules: $3.slct(),
+      Command: tree.ResumeSchedule,
+    }
+  }
+| RESUME SCHEDULES error // SHOW HELP: RESUME SCHEDULES
 
 // %Help: SAVEPOINT - start a sub-transaction
 // %Category: Txn
@@ -10047,6 +10546,7 @@ unreserved_keyword:
 | AUTOMATIC
 | AUTHORIZATION
 | BACKUP
+| BACKUPS
 | BEGIN
 | BUCKET_COUNT
 | BUNDLE
@@ -10118,6 +10618,7 @@ unreserved_keyword:
 | HASH
 | HIGH
 | HISTOGRAM
+| HISTORY
 | HOUR
 | IDENTITY
 | IMMEDIATE
@@ -10140,6 +10641,7 @@ unreserved_keyword:
 | KV
 | LANGUAGE
 | LAST
+| LATEST
 | LC_COLLATE
 | LC_CTYPE
 | LEASE
@@ -10164,6 +10666,7 @@ unreserved_keyword:
 | MONTH
 | NAMES
 | NAN
+| NEW_KMS
 | NEXT
 | NO
 | NORMAL
@@ -10176,6 +10679,7 @@ unreserved_keyword:
 | OF
 | OFF
 | OIDS
+| OLD_KMS
 | OPERATOR
 | OPT
 | OPTION
@@ -10204,13 +10708,16 @@ unreserved_keyword:
 | RANGE
 | RANGES
 | READ
+| RECURRING
 | RECURSIVE
 | REF
+| REFRESH
 | REINDEX
 | RELEASE
 | RENAME
 | REPEATABLE
 | REPLACE
+| REPLICATION
 | RESET
 | RESTORE
 | RESTRICT
@@ -10227,6 +10734,8 @@ unreserved_keyword:
 | STATUS
 | SAVEPOINT
 | SCATTER
+| SCHEDULE
+| SCHEDULES
 | SCHEMA
 | SCHEMAS
 | SCRUB
@@ -10253,6 +10762,7 @@ unreserved_keyword:
 | STORE
 | STORED
 | STORING
+| STREAM
 | STRICT
 | SUBSCRIPTION
 | SYNTAX
@@ -10261,6 +10771,7 @@ unreserved_keyword:
 | TEMP
 | TEMPLATE
 | TEMPORARY
+| TENANT
 | TESTING_RELOCATE
 | TEXT
 | TIES
@@ -21,6 +21,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -623,10 +624,11 @@ CREATE TABLE pg_catalog.pg_available_extensions (
 }
 
 var (
-	relKindTable    = tree.NewDString("r")
-	relKindIndex    = tree.NewDString("i")
-	relKindView     = tree.NewDString("v")
-	relKindSequence = tree.NewDString("S")
+	relKindTable            = tree.NewDString("r")
+	relKindIndex            = tree.NewDString("i")
+	relKindView             = tree.NewDString("v")
+	relKindMaterializedView = tree.NewDString("m")
+	relKindSequence         = tree.NewDString("S")
 
 	relPersistencePermanent = tree.NewDString("p")
 )
@@ -672,7 +674,9 @@ CREATE TABLE pg_catalog.pg_class (
 		// The only difference between tables, views and sequences are the relkind and relam columns.
 		relKind := relKindTable
 		relAm := forwardIndexOid
-		if table.IsView() {
+		if table.MaterializedView() {
+			relKind = relKindMaterializedView
+		} else if table.IsView() {
 			relKind = relKindView
 			relAm = oidZero
 		} else if table.IsSequence() {
@@ -1792,7 +1796,7 @@ CREATE TABLE pg_catalog.pg_language (
 }
 
 var pgCatalogLocksTable = virtualSchemaTable{
-	comment: `locks held by active processes (empty - feature does not exist)
+	comment: `locks held by active processes (advisory locks only)
 https://www.postgresql.org/docs/9.6/view-pg-locks.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_locks (
@@ -1813,6 +1817,53 @@ CREATE TABLE pg_catalog.pg_locks (
   fastpath BOOLEAN
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionAdvisoryLocks) {
+			return nil
+		}
+		const query = `
+SELECT database_id, lock_key, key_fields, session_id, exclusive
+  FROM system.advisory_locks
+ WHERE expiration > now()`
+		rows, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.QueryEx(
+			ctx, "pg-catalog-locks-table", p.txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			query)
+		if err != nil {
+			return err
+		}
+		advisory := tree.NewDString("advisory")
+		exclusiveLock := tree.NewDString("ExclusiveLock")
+		shareLock := tree.NewDString("ShareLock")
+		for _, r := range rows {
+			// As in Postgres, the key of an advisory lock is split across
+			// classid (high-order bits) and objid (low-order bits), and
+			// objsubid is the number of keys the lock was taken with.
+			key := int64(tree.MustBeDInt(r[1]))
+			mode := shareLock
+			if tree.MustBeDBool(r[4]) {
+				mode = exclusiveLock
+			}
+			sessionID := BytesToClusterWideID([]byte(tree.MustBeDBytes(r[3])))
+			if err := addRow(
+				advisory,                                 // locktype
+				dbOid(sqlbase.ID(tree.MustBeDInt(r[0]))), // database
+				tree.DNull,                               // relation
+				tree.DNull,                               // page
+				tree.DNull,                               // tuple
+				tree.DNull,                               // virtualxid
+				tree.DNull,                               // transactionid
+				tree.NewDOid(tree.DInt(uint32(key>>32))), // classid
+				tree.NewDOid(tree.DInt(uint32(key))),     // objid
+				r[2],                                     // objsubid
+				tree.NewDString(sessionID.String()),      // virtualtransaction
+				tree.DNull,                               // pid
+				mode,                                     // mode
+				tree.DBoolTrue,                           // granted
+				tree.DBoolFalse,                          // fastpath
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 }
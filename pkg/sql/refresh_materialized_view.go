@@ -0,0 +1,94 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+type refreshMaterializedViewNode struct {
+	n    *tree.RefreshMaterializedView
+	desc *sqlbase.MutableTableDescriptor
+}
+
+// RefreshMaterializedView recomputes the contents of a materialized view.
+// Privileges: CREATE on view.
+//   notes: postgres requires ownership of the view.
+func (p *planner) RefreshMaterializedView(
+	ctx context.Context, n *tree.RefreshMaterializedView,
+) (planNode, error) {
+	desc, err := p.ResolveMutableTableDescriptorEx(ctx, n.Name, true /* required */, ResolveRequireViewDesc)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.MaterializedView() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return &refreshMaterializedViewNode{n: n, desc: desc}, nil
+}
+
+func (n *refreshMaterializedViewNode) startExec(params runParams) error {
+	if n.n.Concurrently {
+		// Reads of the view are never blocked by a refresh, which writes the new
+		// contents of the view into new indexes.
+		params.p.SendClientNotice(
+			params.ctx,
+			pgnotice.Newf("CONCURRENTLY is not required as views are refreshed concurrently"),
+		)
+	}
+	if len(n.desc.Mutations) > 0 {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot refresh materialized view %q while a schema change is in progress", n.desc.Name)
+	}
+
+	// The new contents of the view are written into a copy of each of its
+	// indexes, which replace the existing ones once the refresh is complete.
+	newPrimaryIndex := protoutil.Clone(&n.desc.PrimaryIndex).(*sqlbase.IndexDescriptor)
+	newPrimaryIndex.ID = n.desc.GetNextIndexID()
+	n.desc.NextIndexID++
+	newIndexes := make([]sqlbase.IndexDescriptor, len(n.desc.Indexes))
+	for i := range n.desc.Indexes {
+		idx := protoutil.Clone(&n.desc.Indexes[i]).(*sqlbase.IndexDescriptor)
+		idx.ID = n.desc.GetNextIndexID()
+		n.desc.NextIndexID++
+		newIndexes[i] = *idx
+	}
+
+	n.desc.AddMaterializedViewRefreshMutation(&sqlbase.MaterializedViewRefresh{
+		NewPrimaryIndex: *newPrimaryIndex,
+		NewIndexes:      newIndexes,
+		AsOf:            params.p.Txn().ReadTimestamp(),
+	})
+	if err := n.desc.Validate(params.ctx, params.p.txn, params.ExecCfg().Codec); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx,
+		n.desc,
+		n.desc.ClusterVersion.NextMutationID,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *refreshMaterializedViewNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *refreshMaterializedViewNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *refreshMaterializedViewNode) Close(ctx context.Context)           {}
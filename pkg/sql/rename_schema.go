@@ -0,0 +1,176 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+type renameSchemaNode struct {
+	n          *tree.RenameSchema
+	dbDesc     *sqlbase.DatabaseDescriptor
+	schemaDesc *sqlbase.SchemaDescriptor
+}
+
+// RenameSchema renames a schema of the current database.
+// Privileges: DROP on the schema, CREATE on the current database.
+//   Notes: postgres requires the schema owner and CREATE on the database.
+func (p *planner) RenameSchema(ctx context.Context, n *tree.RenameSchema) (planNode, error) {
+	dbName := p.CurrentDatabase()
+	if dbName == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, dbName, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	scName := string(n.Name)
+	if scName == tree.PublicSchema || sessiondata.IsSystemSchemaName(scName) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported, "cannot rename schema %q", scName)
+	}
+	schemaDesc, err := getUserDefinedSchemaDesc(ctx, p.txn, p.ExecCfg().Codec, dbDesc.ID, scName)
+	if err != nil {
+		return nil, err
+	}
+	if schemaDesc == nil {
+		return nil, pgerror.Newf(pgcode.InvalidSchemaName, "schema %q does not exist", scName)
+	}
+
+	if err := p.CheckPrivilege(ctx, schemaDesc, privilege.DROP); err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	if n.Name == n.NewName {
+		// Noop.
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	return &renameSchemaNode{n: n, dbDesc: dbDesc, schemaDesc: schemaDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because RENAME SCHEMA performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *renameSchemaNode) ReadingOwnWrites() {}
+
+func (n *renameSchemaNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	oldName := n.schemaDesc.Name
+	newName := string(n.n.NewName)
+
+	exists, err := p.schemaExists(ctx, n.dbDesc.ID, newName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return pgerror.Newf(pgcode.DuplicateSchema, "schema %q already exists", newName)
+	}
+	if err := checkSchemaNameAvailable(newName); err != nil {
+		return err
+	}
+
+	// Views and sequence defaults store the names of the objects they depend
+	// on, which may include the schema name. Rather than trying to rewrite
+	// them, disallow renaming a schema whose objects are depended on.
+	tbNames, err := GetObjectNames(
+		ctx, p.txn, p, p.ExecCfg().Codec, n.dbDesc, oldName, true, /* explicitPrefix */
+	)
+	if err != nil {
+		return err
+	}
+	for i := range tbNames {
+		tbDesc, err := p.ResolveUncachedTableDescriptor(ctx, &tbNames[i], false /* required */, ResolveAnyDescType)
+		if err != nil {
+			return err
+		}
+		if tbDesc == nil || len(tbDesc.DependedOnBy) == 0 {
+			continue
+		}
+		dependentDesc, err := sqlbase.GetTableDescFromID(
+			ctx, p.txn, p.ExecCfg().Codec, tbDesc.DependedOnBy[0].ID,
+		)
+		if err != nil {
+			return err
+		}
+		dependentName, err := p.getQualifiedTableName(ctx, dependentDesc)
+		if err != nil {
+			return err
+		}
+		return errors.WithHintf(
+			sqlbase.NewDependentObjectErrorf(
+				"cannot rename schema because relation %q depends on relation %q",
+				dependentName, tbNames[i].String()),
+			"you can drop %q instead", dependentName)
+	}
+
+	descID := n.schemaDesc.ID
+	n.schemaDesc.SetName(newName)
+	if err := n.schemaDesc.Validate(); err != nil {
+		return err
+	}
+
+	newKey := sqlbase.NewSchemaKey(n.dbDesc.ID, newName).Key(p.ExecCfg().Codec)
+	b := &kv.Batch{}
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "CPut %s -> %d", newKey, descID)
+	}
+	b.CPut(newKey, descID, nil)
+	if err := writeDescToBatch(
+		ctx, p.ExtendedEvalContext().Tracing.KVTracingEnabled(), p.ExecCfg().Settings,
+		b, p.ExecCfg().Codec, descID, n.schemaDesc,
+	); err != nil {
+		return err
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+	if err := sqlbase.RemoveSchemaNamespaceEntry(
+		ctx, p.txn, p.ExecCfg().Codec, n.dbDesc.ID, oldName,
+	); err != nil {
+		return err
+	}
+	p.Tables().releaseSchemas()
+
+	// Log Rename Schema event. This is an auditable log event and is recorded
+	// in the same transaction as the schema descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogRenameSchema,
+		int32(descID),
+		int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
+		struct {
+			SchemaName    string
+			NewSchemaName string
+			Statement     string
+			User          string
+		}{oldName, newName, n.n.String(), p.SessionData().User},
+	)
+}
+
+func (*renameSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*renameSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*renameSchemaNode) Close(context.Context)        {}
@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -90,6 +91,18 @@ func (n *renameTableNode) startExec(params runParams) error {
 	}
 	oldTn.ObjectNamePrefix = prefix
 
+	// As in Postgres, a table in a user-defined schema stays in its schema
+	// when it is given an unqualified new name.
+	if !newTn.ExplicitSchema && !newTn.ExplicitCatalog &&
+		tableDesc.GetParentSchemaID() != keys.PublicSchemaID {
+		newTn.ObjectNamePrefix = tree.ObjectNamePrefix{
+			CatalogName:     oldTn.CatalogName,
+			SchemaName:      oldTn.SchemaName,
+			ExplicitCatalog: true,
+			ExplicitSchema:  true,
+		}
+	}
+
 	// Check if target database exists.
 	// We also look at uncached descriptors here.
 	newUn := newTn.ToUnresolvedObjectName()
@@ -99,7 +112,14 @@ func (n *renameTableNode) startExec(params runParams) error {
 	}
 	newTn.ObjectNamePrefix = prefix
 
-	if err := p.CheckPrivilege(ctx, targetDbDesc, privilege.CREATE); err != nil {
+	if err := p.checkCreatePrivilegeForSchema(ctx, targetDbDesc, newTn.Schema()); err != nil {
+		return err
+	}
+
+	_, targetSchemaID, err := resolveSchemaID(
+		ctx, p.txn, p.ExecCfg().Codec, targetDbDesc.ID, newTn.Schema(),
+	)
+	if err != nil {
 		return err
 	}
 
@@ -111,19 +131,22 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return nil
 	}
 
+	descID := tableDesc.GetID()
+	parentSchemaID := tableDesc.GetParentSchemaID()
+
 	tableDesc.SetName(newTn.Table())
 	tableDesc.ParentID = targetDbDesc.ID
+	if targetSchemaID != parentSchemaID {
+		tableDesc.UnexposedParentSchemaID = targetSchemaID
+	}
 
-	newTbKey := sqlbase.MakePublicTableNameKey(ctx, params.ExecCfg().Settings,
-		targetDbDesc.ID, newTn.Table()).Key(p.ExecCfg().Codec)
+	newTbKey := sqlbase.MakeObjectNameKey(ctx, params.ExecCfg().Settings,
+		targetDbDesc.ID, targetSchemaID, newTn.Table()).Key(p.ExecCfg().Codec)
 
 	if err := tableDesc.Validate(ctx, p.txn, p.ExecCfg().Codec); err != nil {
 		return err
 	}
 
-	descID := tableDesc.GetID()
-	parentSchemaID := tableDesc.GetParentSchemaID()
-
 	renameDetails := sqlbase.TableDescriptor_NameInfo{
 		ParentID:       prevDbDesc.ID,
 		ParentSchemaID: parentSchemaID,
@@ -148,8 +171,8 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return err
 	}
 
-	exists, _, err := sqlbase.LookupPublicTableID(
-		params.ctx, params.p.txn, p.ExecCfg().Codec, targetDbDesc.ID, newTn.Table(),
+	exists, _, err := sqlbase.LookupObjectID(
+		params.ctx, params.p.txn, p.ExecCfg().Codec, targetDbDesc.ID, targetSchemaID, newTn.Table(),
 	)
 	if err == nil && exists {
 		return sqlbase.NewRelationAlreadyExistsError(newTn.Table())
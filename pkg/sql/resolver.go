@@ -252,7 +252,7 @@ func ResolveTargetObject(
 		err = errors.WithHint(err, "verify that the current database and search_path are valid and/or the target database exists")
 		return nil, prefix, err
 	}
-	if prefix.Schema() != tree.PublicSchema {
+	if sessiondata.IsSystemSchemaName(prefix.Schema()) {
 		return nil, prefix, pgerror.Newf(pgcode.InvalidName,
 			"schema cannot be modified: %q", tree.ErrString(&prefix))
 	}
@@ -351,6 +351,36 @@ func getDescriptorsFromTargetList(
 		return descs, nil
 	}
 
+	if targets.Schemas != nil {
+		if len(targets.Schemas) == 0 {
+			return nil, errNoSchema
+		}
+		dbName := p.CurrentDatabase()
+		if dbName == "" {
+			return nil, errNoDatabase
+		}
+		dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, dbName, true /*required*/)
+		if err != nil {
+			return nil, err
+		}
+		descs := make([]sqlbase.DescriptorProto, 0, len(targets.Schemas))
+		for _, scName := range targets.Schemas {
+			// Only user-defined schemas have privileges of their own.
+			descriptor, err := getUserDefinedSchemaDesc(
+				ctx, p.txn, p.ExecCfg().Codec, dbDesc.ID, string(scName),
+			)
+			if err != nil {
+				return nil, err
+			}
+			if descriptor == nil {
+				return nil, pgerror.Newf(pgcode.InvalidSchemaName,
+					"schema %q does not exist or does not support privileges", string(scName))
+			}
+			descs = append(descs, descriptor)
+		}
+		return descs, nil
+	}
+
 	if len(targets.Tables) == 0 {
 		return nil, errNoTable
 	}
@@ -74,6 +74,16 @@ func RevertTables(
 		log.Infof(ctx, "reverting table %s (%d) to time %v", tables[i].Name, tables[i].ID, targetTime)
 	}
 
+	return RevertSpans(ctx, db, spans, targetTime, batchSize)
+}
+
+// RevertSpans reverts the passed spans to the target time, by clearing all the
+// revisions of their keys which are more recent than it.
+func RevertSpans(
+	ctx context.Context, db *kv.DB, spans []roachpb.Span, targetTime hlc.Timestamp, batchSize int64,
+) error {
+	// The spans are replaced by the resume spans of each batch.
+	spans = append([]roachpb.Span(nil), spans...)
 	// TODO(dt): pre-split requests up using a rangedesc cache and run batches in
 	// parallel (since we're passing a key limit, distsender won't do its usual
 	// splitting/parallel sending to separate ranges).
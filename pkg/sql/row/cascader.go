@@ -421,6 +421,8 @@ func (c *cascader) addIndexPKRowFetcher(
 		c.evalCtx.Codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		c.evalCtx.SessionData.LockTimeout,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
@@ -491,6 +493,8 @@ func (c *cascader) addRowDeleter(
 		// strength here. Consider hooking this in to the same knob that will
 		// control whether we perform locking implicitly during DELETEs.
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		c.evalCtx.SessionData.LockTimeout,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
@@ -559,6 +563,8 @@ func (c *cascader) addRowUpdater(
 		// strength here. Consider hooking this in to the same knob that will
 		// control whether we perform locking implicitly during UPDATEs.
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		c.evalCtx.SessionData.LockTimeout,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
@@ -51,6 +51,9 @@ func ConvertBatchError(
 	ctx context.Context, tableDesc *sqlbase.ImmutableTableDescriptor, b *kv.Batch,
 ) error {
 	origPErr := b.MustPErr()
+	if _, ok := origPErr.GetDetail().(*roachpb.WriteIntentError); ok {
+		return convertLockError(origPErr.GoError())
+	}
 	if origPErr.Index == nil {
 		return origPErr.GoError()
 	}
@@ -66,6 +69,45 @@ func ConvertBatchError(
 	return origPErr.GoError()
 }
 
+// convertLockError converts the errors returned by KV when encountering
+// conflicting locks into user friendly errors. A conflicting lock encountered
+// by a request that doesn't wait on locks, or that waited on it for longer
+// than its lock timeout, is reported as a lock_not_available error.
+func convertLockError(err error) error {
+	var wiErr *roachpb.WriteIntentError
+	if errors.As(err, &wiErr) {
+		switch wiErr.Reason {
+		case roachpb.WriteIntentError_REASON_WAIT_POLICY:
+			return newLockNotAvailableError(wiErr)
+		case roachpb.WriteIntentError_REASON_LOCK_TIMEOUT:
+			return newLockTimeoutError(wiErr)
+		}
+	}
+	return err
+}
+
+// newLockNotAvailableError creates an error that represents an inability to
+// acquire a lock because of a conflicting lock held by another transaction.
+func newLockNotAvailableError(wiErr *roachpb.WriteIntentError) error {
+	err := pgerror.New(pgcode.LockNotAvailable, "could not obtain lock on row")
+	if len(wiErr.Intents) > 0 {
+		err = errors.WithDetailf(err, "key %s is locked by transaction %s",
+			wiErr.Intents[0].Key, wiErr.Intents[0].Txn.ID.Short())
+	}
+	return err
+}
+
+// newLockTimeoutError creates an error that represents a lock wait that was
+// canceled because it exceeded the lock_timeout.
+func newLockTimeoutError(wiErr *roachpb.WriteIntentError) error {
+	err := pgerror.New(pgcode.LockNotAvailable, "canceling statement due to lock timeout on row")
+	if len(wiErr.Intents) > 0 {
+		err = errors.WithDetailf(err, "key %s is locked by transaction %s",
+			wiErr.Intents[0].Key, wiErr.Intents[0].Txn.ID.Short())
+	}
+	return err
+}
+
 // NewUniquenessConstraintViolationError creates an error that represents a
 // violation of a UNIQUE constraint.
 func NewUniquenessConstraintViolationError(
@@ -118,6 +160,8 @@ func NewUniquenessConstraintViolationError(
 		codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&sqlbase.DatumAlloc{},
@@ -196,6 +196,15 @@ type Fetcher struct {
 	// lockStr represents the row-level locking mode to use when fetching rows.
 	lockStr sqlbase.ScanLockingStrength
 
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+
+	// lockTimeout specifies the maximum amount of time that the fetcher will
+	// wait while attempting to acquire a lock on a key or while blocking on an
+	// existing lock in order to perform a non-locking read on a key.
+	lockTimeout time.Duration
+
 	// returnRangeInfo, if set, causes the underlying kvBatchFetcher to return
 	// information about the ranges descriptors/leases uses in servicing the
 	// requests. This has some cost, so it's only enabled by DistSQL when this
@@ -251,6 +260,8 @@ func (rf *Fetcher) Init(
 	codec keys.SQLCodec,
 	reverse bool,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
+	lockTimeout time.Duration,
 	returnRangeInfo bool,
 	isCheck bool,
 	alloc *sqlbase.DatumAlloc,
@@ -263,6 +274,8 @@ func (rf *Fetcher) Init(
 	rf.codec = codec
 	rf.reverse = reverse
 	rf.lockStr = lockStr
+	rf.lockWaitPolicy = lockWaitPolicy
+	rf.lockTimeout = lockTimeout
 	rf.returnRangeInfo = returnRangeInfo
 	rf.alloc = alloc
 	rf.isCheck = isCheck
@@ -483,6 +496,8 @@ func (rf *Fetcher) StartScan(
 		limitBatches,
 		rf.firstBatchLimit(limitHint),
 		rf.lockStr,
+		rf.lockWaitPolicy,
+		rf.lockTimeout,
 		rf.returnRangeInfo,
 	)
 	if err != nil {
@@ -563,6 +578,8 @@ func (rf *Fetcher) StartInconsistentScan(
 		limitBatches,
 		rf.firstBatchLimit(limitHint),
 		rf.lockStr,
+		rf.lockWaitPolicy,
+		rf.lockTimeout,
 		rf.returnRangeInfo,
 	)
 	if err != nil {
@@ -109,6 +109,8 @@ func TestRowFetcherMVCCMetadata(t *testing.T) {
 		keys.SystemSQLCodec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
 		false, /* returnRangeInfo */
 		true,  /* isCheck */
 		&sqlbase.DatumAlloc{},
@@ -77,6 +77,8 @@ func initFetcher(
 		fetcherCodec,
 		reverseScan,
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		alloc,
@@ -1062,7 +1064,7 @@ func TestRowFetcherReset(t *testing.T) {
 
 	fetcherArgs := makeFetcherArgs(args)
 	if err := resetFetcher.Init(
-		keys.SystemSQLCodec, false /*reverse*/, 0 /* todo */, false /* returnRangeInfo */, false /* isCheck */, &da, fetcherArgs...,
+		keys.SystemSQLCodec, false /*reverse*/, 0 /* todo */, 0 /* todo */, 0 /* lockTimeout */, false /* returnRangeInfo */, false /* isCheck */, &da, fetcherArgs...,
 	); err != nil {
 		t.Fatal(err)
 	}
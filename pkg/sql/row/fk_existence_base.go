@@ -152,6 +152,8 @@ func makeFkExistenceCheckBaseHelper(
 		codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		alloc,
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
@@ -57,6 +58,13 @@ type txnKVFetcher struct {
 	reverse         bool
 	// lockStr represents the locking mode to use when fetching KVs.
 	lockStr sqlbase.ScanLockingStrength
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+	// lockTimeout specifies the maximum amount of time that the fetcher will
+	// wait while attempting to acquire a lock on a key or while blocking on an
+	// existing lock in order to perform a non-locking read on a key.
+	lockTimeout time.Duration
 	// returnRangeInfo, if set, causes the kvBatchFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
@@ -164,6 +172,24 @@ func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
 	}
 }
 
+// getWaitPolicy returns the configured lock wait policy to use for key-value
+// scans.
+func (f *txnKVFetcher) getWaitPolicy() lock.WaitPolicy {
+	switch f.lockWaitPolicy {
+	case sqlbase.ScanLockingWaitPolicy_BLOCK:
+		return lock.WaitPolicy_Block
+
+	case sqlbase.ScanLockingWaitPolicy_SKIP:
+		return lock.WaitPolicy_SkipLocked
+
+	case sqlbase.ScanLockingWaitPolicy_ERROR:
+		return lock.WaitPolicy_Error
+
+	default:
+		panic(fmt.Sprintf("unknown wait policy %s", f.lockWaitPolicy))
+	}
+}
+
 // makeKVBatchFetcher initializes a kvBatchFetcher for the given spans.
 //
 // If useBatchLimit is true, batches are limited to kvBatchSize. If
@@ -178,6 +204,8 @@ func makeKVBatchFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
+	lockTimeout time.Duration,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	sendFn := func(ctx context.Context, ba roachpb.BatchRequest) (*roachpb.BatchResponse, error) {
@@ -188,7 +216,8 @@ func makeKVBatchFetcher(
 		return res, nil
 	}
 	return makeKVBatchFetcherWithSendFunc(
-		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, returnRangeInfo,
+		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, lockWaitPolicy, lockTimeout,
+		returnRangeInfo,
 	)
 }
 
@@ -201,6 +230,8 @@ func makeKVBatchFetcherWithSendFunc(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
+	lockTimeout time.Duration,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
@@ -253,6 +284,8 @@ func makeKVBatchFetcherWithSendFunc(
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
 		lockStr:         lockStr,
+		lockWaitPolicy:  lockWaitPolicy,
+		lockTimeout:     lockTimeout,
 		returnRangeInfo: returnRangeInfo,
 	}, nil
 }
@@ -271,6 +304,8 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		// TargetBytes would interfere with.
 		ba.Header.TargetBytes = 10 * (1 << 20)
 	}
+	ba.Header.WaitPolicy = f.getWaitPolicy()
+	ba.Header.LockTimeout = f.lockTimeout
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	keyLocking := f.getKeyLockingStrength()
@@ -314,7 +349,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 
 	br, err := f.sendFn(ctx, ba)
 	if err != nil {
-		return err
+		return convertLockError(err)
 	}
 	if br != nil {
 		f.responses = br.Responses
@@ -12,6 +12,7 @@ package row
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -40,10 +41,13 @@ func NewKVFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
+	lockTimeout time.Duration,
 	returnRangeInfo bool,
 ) (*KVFetcher, error) {
 	kvBatchFetcher, err := makeKVBatchFetcher(
-		txn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, returnRangeInfo,
+		txn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, lockWaitPolicy, lockTimeout,
+		returnRangeInfo,
 	)
 	return newKVFetcher(&kvBatchFetcher), err
 }
@@ -373,6 +373,7 @@ func (c *DatumRowConverter) Row(ctx context.Context, sourceID int32, rowIndex in
 	if err != nil {
 		return errors.Wrap(err, "generate insert row")
 	}
+	batchLen := len(c.KvBatch.KVs)
 	if err := c.ri.InsertRow(
 		ctx,
 		KVInserter(func(kv roachpb.KeyValue) {
@@ -384,6 +385,9 @@ func (c *DatumRowConverter) Row(ctx context.Context, sourceID int32, rowIndex in
 		SkipFKs,
 		false, /* traceKV */
 	); err != nil {
+		// Drop the KVs of the partially encoded row, so that callers can skip it
+		// and carry on with the next one.
+		c.KvBatch.KVs = c.KvBatch.KVs[:batchLen]
 		return errors.Wrap(err, "insert row")
 	}
 	// If our batch is full, flush it and start a new one.
@@ -129,6 +129,8 @@ func newIndexSkipTableReader(
 		flowCtx.Codec(),
 		t.reverse,
 		spec.LockingStrength,
+		spec.LockingWaitPolicy,
+		flowCtx.EvalCtx.SessionData.LockTimeout,
 		true,  /* returnRangeInfo */
 		false, /* isCheck */
 		&t.alloc,
@@ -110,6 +110,7 @@ func newIndexJoiner(
 		&ij.alloc,
 		spec.Visibility,
 		spec.LockingStrength,
+		spec.LockingWaitPolicy,
 	); err != nil {
 		return nil, err
 	}
@@ -368,7 +368,8 @@ func newInterleavedReaderJoiner(
 	}
 
 	if err := irj.initRowFetcher(
-		flowCtx, spec.Tables, tables, spec.Reverse, spec.LockingStrength, &irj.alloc,
+		flowCtx, spec.Tables, tables, spec.Reverse, spec.LockingStrength, spec.LockingWaitPolicy,
+		&irj.alloc,
 	); err != nil {
 		return nil, err
 	}
@@ -406,6 +407,7 @@ func (irj *interleavedReaderJoiner) initRowFetcher(
 	tableInfos []tableInfo,
 	reverseScan bool,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	alloc *sqlbase.DatumAlloc,
 ) error {
 	args := make([]row.FetcherTableArgs, len(tables))
@@ -432,6 +434,8 @@ func (irj *interleavedReaderJoiner) initRowFetcher(
 		flowCtx.Codec(),
 		reverseScan,
 		lockStr,
+		lockWaitPolicy,
+		flowCtx.EvalCtx.SessionData.LockTimeout,
 		true, /* returnRangeInfo */
 		true, /* isCheck */
 		alloc,
@@ -207,6 +207,7 @@ func newJoinReader(
 	_, _, err = initRowFetcher(
 		flowCtx, &fetcher, &jr.desc, int(spec.IndexIdx), jr.colIdxMap, false, /* reverse */
 		neededRightCols, false /* isCheck */, &jr.alloc, spec.Visibility, spec.LockingStrength,
+		spec.LockingWaitPolicy,
 	)
 	if err != nil {
 		return nil, err
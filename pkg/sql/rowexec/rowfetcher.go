@@ -65,6 +65,7 @@ func initRowFetcher(
 	alloc *sqlbase.DatumAlloc,
 	scanVisibility execinfrapb.ScanVisibility,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 ) (index *sqlbase.IndexDescriptor, isSecondaryIndex bool, err error) {
 	immutDesc := sqlbase.NewImmutableTableDescriptor(*desc)
 	index, isSecondaryIndex, err = immutDesc.FindIndexByIndexIdx(indexIdx)
@@ -88,6 +89,8 @@ func initRowFetcher(
 		flowCtx.Codec(),
 		reverseScan,
 		lockStr,
+		lockWaitPolicy,
+		flowCtx.EvalCtx.SessionData.LockTimeout,
 		true, /* returnRangeInfo */
 		isCheck,
 		alloc,
@@ -453,8 +453,10 @@ func (z *zigzagJoiner) setupInfo(
 		info.alloc,
 		execinfrapb.ScanVisibility_PUBLIC,
 		// NB: zigzag joins are disabled when a row-level locking clause is
-		// supplied, so there is no locking strength on *ZigzagJoinerSpec.
+		// supplied, so there is no locking strength or wait policy on
+		// *ZigzagJoinerSpec.
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 	)
 	if err != nil {
 		return err
@@ -208,16 +208,50 @@ func (sc *SchemaChanger) maybeBackfillCreateTableAs(
 	if !(table.Adding() && table.IsAs()) {
 		return nil
 	}
+	return sc.backfillQueryIntoTable(ctx, table, table.CreateQuery, table.CreateAsOfTime, "ctasBackfill")
+}
+
+// maybe backfill a created materialized view by executing the view query.
+// Return nil if successfully backfilled.
+func (sc *SchemaChanger) maybeBackfillMaterializedView(
+	ctx context.Context, table *sqlbase.TableDescriptor,
+) error {
+	if !(table.Adding() && table.MaterializedView()) {
+		return nil
+	}
+	return sc.backfillQueryIntoTable(ctx, table, table.ViewQuery, table.CreateAsOfTime, "materializedViewBackfill")
+}
+
+// refreshMaterializedView recomputes the contents of a materialized view into
+// the new indexes of the refresh, which are swapped in for the existing ones
+// once the mutation is complete.
+func (sc *SchemaChanger) refreshMaterializedView(
+	ctx context.Context, table *sqlbase.TableDescriptor, refresh *sqlbase.MaterializedViewRefresh,
+) error {
+	// Write into the new indexes by backfilling a copy of the descriptor that
+	// has them in place of the existing ones. The bulk row writer writes at the
+	// CreateAsOfTime of the descriptor, which is set to the refresh timestamp.
+	tableToRefresh := protoutil.Clone(table).(*sqlbase.TableDescriptor)
+	tableToRefresh.PrimaryIndex = refresh.NewPrimaryIndex
+	tableToRefresh.Indexes = refresh.NewIndexes
+	tableToRefresh.CreateAsOfTime = refresh.AsOf
+	return sc.backfillQueryIntoTable(ctx, tableToRefresh, table.ViewQuery, refresh.AsOf, "refreshView")
+}
 
+// backfillQueryIntoTable writes the results of the query, evaluated as of ts,
+// into the indexes of the table.
+func (sc *SchemaChanger) backfillQueryIntoTable(
+	ctx context.Context, table *sqlbase.TableDescriptor, query string, ts hlc.Timestamp, desc string,
+) error {
 	return sc.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
-		txn.SetFixedTimestamp(ctx, table.CreateAsOfTime)
+		txn.SetFixedTimestamp(ctx, ts)
 
 		// Create an internal planner as the planner used to serve the user query
 		// would have committed by this point.
-		p, cleanup := NewInternalPlanner("ctasBackfill", txn, security.RootUser, &MemoryMetrics{}, sc.execCfg)
+		p, cleanup := NewInternalPlanner(desc, txn, security.RootUser, &MemoryMetrics{}, sc.execCfg)
 		defer cleanup()
 		localPlanner := p.(*planner)
-		stmt, err := parser.ParseOne(table.CreateQuery)
+		stmt, err := parser.ParseOne(query)
 		if err != nil {
 			return err
 		}
@@ -453,6 +487,10 @@ func (sc *SchemaChanger) exec(ctx context.Context) error {
 		return err
 	}
 
+	if err := sc.maybeBackfillMaterializedView(ctx, tableDesc); err != nil {
+		return err
+	}
+
 	if err := sc.maybeMakeAddTablePublic(ctx, tableDesc); err != nil {
 		return err
 	}
@@ -480,7 +518,7 @@ func (sc *SchemaChanger) exec(ctx context.Context) error {
 
 	if sc.mutationID == sqlbase.InvalidMutationID {
 		// Nothing more to do.
-		isCreateTableAs := tableDesc.Adding() && tableDesc.IsAs()
+		isCreateTableAs := tableDesc.Adding() && (tableDesc.IsAs() || tableDesc.MaterializedView())
 		return waitToUpdateLeases(isCreateTableAs /* refreshStats */)
 	}
 
@@ -802,36 +840,28 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 			if indexDesc := mutation.GetIndex(); mutation.Direction == sqlbase.DescriptorMutation_DROP &&
 				indexDesc != nil {
 				if canClearRangeForDrop(indexDesc) {
-					// how we keep track of dropped index names (for, e.g., zone config
-					// lookups), even though in the absence of a GC job there's nothing to
-					// clean them up.
-					scDesc.GCMutations = append(
-						scDesc.GCMutations,
-						sqlbase.TableDescriptor_GCDescriptorMutation{
-							IndexID: indexDesc.ID,
-						})
-
-					dropTime := timeutil.Now().UnixNano()
-					indexGCDetails := jobspb.SchemaChangeGCDetails{
-						Indexes: []jobspb.SchemaChangeGCDetails_DroppedIndex{
-							{
-								IndexID:  indexDesc.ID,
-								DropTime: dropTime,
-							},
-						},
-						ParentID: sc.tableID,
-					}
-
-					description := sc.job.Payload().Description
-					if isRollback {
-						description = "ROLLBACK of " + description
+					indexGCJob, err := sc.createIndexGCJob(ctx, scDesc, indexDesc.ID, txn, isRollback)
+					if err != nil {
+						return err
 					}
-					gcJobRecord := CreateGCJobRecord(description, sc.job.Payload().Username, indexGCDetails)
-					indexGCJob, err := sc.jobRegistry.CreateStartableJobWithTxn(ctx, gcJobRecord, txn, nil /* resultsCh */)
+					childJobs = append(childJobs, indexGCJob)
+				}
+			}
+			if refresh := mutation.GetMaterializedViewRefresh(); refresh != nil {
+				// Once a refresh is complete, the existing indexes of the view are
+				// swapped out and can be garbage collected. If it was rolled back, the
+				// new indexes that were partially backfilled are instead.
+				var toGC []sqlbase.IndexDescriptor
+				if mutation.Direction == sqlbase.DescriptorMutation_ADD {
+					toGC = append([]sqlbase.IndexDescriptor{scDesc.PrimaryIndex}, scDesc.Indexes...)
+				} else {
+					toGC = append([]sqlbase.IndexDescriptor{refresh.NewPrimaryIndex}, refresh.NewIndexes...)
+				}
+				for i := range toGC {
+					indexGCJob, err := sc.createIndexGCJob(ctx, scDesc, toGC[i].ID, txn, isRollback)
 					if err != nil {
 						return err
 					}
-					log.VEventf(ctx, 2, "created index GC job %d", *indexGCJob.ID())
 					childJobs = append(childJobs, indexGCJob)
 				}
 			}
@@ -1368,10 +1398,14 @@ func (sc *SchemaChanger) reverseMutation(
 		if col := mutation.GetColumn(); col != nil {
 			columns[col.Name] = struct{}{}
 		}
-		// PrimaryKeySwap doesn't have a concept of the state machine.
+		// PrimaryKeySwap and MaterializedViewRefresh don't have a concept of the
+		// state machine.
 		if pkSwap := mutation.GetPrimaryKeySwap(); pkSwap != nil {
 			return mutation, columns
 		}
+		if refresh := mutation.GetMaterializedViewRefresh(); refresh != nil {
+			return mutation, columns
+		}
 		if notStarted && mutation.State != sqlbase.DescriptorMutation_DELETE_ONLY {
 			panic(fmt.Sprintf("mutation in bad state: %+v", mutation))
 		}
@@ -1385,6 +1419,48 @@ func (sc *SchemaChanger) reverseMutation(
 	return mutation, columns
 }
 
+// createIndexGCJob records the index as dropped in the table descriptor and
+// creates a job to garbage collect its data once the descriptor is published.
+func (sc *SchemaChanger) createIndexGCJob(
+	ctx context.Context,
+	scDesc *sqlbase.MutableTableDescriptor,
+	indexID sqlbase.IndexID,
+	txn *kv.Txn,
+	isRollback bool,
+) (*jobs.StartableJob, error) {
+	// how we keep track of dropped index names (for, e.g., zone config
+	// lookups), even though in the absence of a GC job there's nothing to
+	// clean them up.
+	scDesc.GCMutations = append(
+		scDesc.GCMutations,
+		sqlbase.TableDescriptor_GCDescriptorMutation{
+			IndexID: indexID,
+		})
+
+	dropTime := timeutil.Now().UnixNano()
+	indexGCDetails := jobspb.SchemaChangeGCDetails{
+		Indexes: []jobspb.SchemaChangeGCDetails_DroppedIndex{
+			{
+				IndexID:  indexID,
+				DropTime: dropTime,
+			},
+		},
+		ParentID: sc.tableID,
+	}
+
+	description := sc.job.Payload().Description
+	if isRollback {
+		description = "ROLLBACK of " + description
+	}
+	gcJobRecord := CreateGCJobRecord(description, sc.job.Payload().Username, indexGCDetails)
+	indexGCJob, err := sc.jobRegistry.CreateStartableJobWithTxn(ctx, gcJobRecord, txn, nil /* resultsCh */)
+	if err != nil {
+		return nil, err
+	}
+	log.VEventf(ctx, 2, "created index GC job %d", *indexGCJob.ID())
+	return indexGCJob, nil
+}
+
 // CreateGCJobRecord creates the job record for a GC job, setting some
 // properties which are common for all GC jobs.
 func CreateGCJobRecord(
@@ -1783,4 +1859,11 @@ func init() {
 		return &schemaChangeResumer{job: job}
 	}
 	jobs.RegisterConstructor(jobspb.TypeSchemaChange, createResumerFn)
+	// Schema changes are retried until they finish, since their descriptors are
+	// left with mutations otherwise. The backoff is short because the errors
+	// they're retried after are mostly transient.
+	jobs.RegisterRetryPolicy(jobspb.TypeSchemaChange, jobs.RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	})
 }
@@ -48,6 +48,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -3340,6 +3341,33 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	"crdb_internal.complete_stream_ingestion_job": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlacklist: true,
+			Impure:           true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"job_id", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Decimal),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if CompleteStreamIngestion == nil {
+					return nil, pgerror.New(pgcode.FeatureNotSupported,
+						"crdb_internal.complete_stream_ingestion_job is only available in ccl distribution")
+				}
+				cutoverTime, err := CompleteStreamIngestion(evalCtx, int64(tree.MustBeDInt(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.TimestampToDecimal(cutoverTime), nil
+			},
+			Info: "Cuts over the replication job with the given ID: the job stops " +
+				"ingesting the replication stream, reverts the replicated tenant to the " +
+				"last resolved timestamp of the stream and completes. Returns that " +
+				"timestamp.",
+		},
+	),
+
 	"crdb_internal.round_decimal_values": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -5313,6 +5341,11 @@ func checkPrivilegedUser(ctx *tree.EvalContext) error {
 // if an enterprise license is not installed.
 var EvalFollowerReadOffset func(clusterID uuid.UUID, _ *cluster.Settings) (time.Duration, error)
 
+// CompleteStreamIngestion requests the cutover of a replication job, as of the
+// last resolved timestamp of its stream, which it returns. It is injected by
+// streamingccl.
+var CompleteStreamIngestion func(evalCtx *tree.EvalContext, jobID int64) (hlc.Timestamp, error)
+
 func recentTimestamp(ctx *tree.EvalContext) (time.Time, error) {
 	if EvalFollowerReadOffset == nil {
 		return time.Time{}, pgerror.New(pgcode.FeatureNotSupported,
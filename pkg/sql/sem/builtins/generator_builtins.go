@@ -274,6 +274,69 @@ var generators = map[string]builtinDefinition{
 				"SELECT * FROM crdb_internal.check_consistency(true, '\\x02', '\\x04')",
 		),
 	),
+
+	"crdb_internal.backup_revisions": makeBuiltin(
+		tree.FunctionProperties{
+			Impure:   true,
+			Class:    tree.GeneratorClass,
+			Category: categorySystemInfo,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{Name: "backup_uris", Typ: types.StringArray},
+				{Name: "start_key", Typ: types.Bytes},
+				{Name: "end_key", Typ: types.Bytes},
+			},
+			BackupRevisionsGeneratorType,
+			makeBackupRevisionsGenerator,
+			"Returns every revision of the keys in [start_key, end_key) found in a "+
+				"chain of backups, given as the URIs of its full backup and of its "+
+				"incremental backups in order. Each returned row contains the key, the "+
+				"timestamp of the revision and its value, which is NULL for a deletion. "+
+				"An empty start or end key is treated as the minimum and maximum "+
+				"possible, respectively. Only the revisions of backups taken with "+
+				"revision_history include the revisions between the backups.\n\n"+
+				"Example usage:\n"+
+				"SELECT * FROM crdb_internal.backup_revisions("+
+				"ARRAY['nodelocal://1/full', 'nodelocal://1/inc'], '\\xbd89', '\\xbd8a')",
+		),
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{Name: "backup_uris", Typ: types.StringArray},
+				{Name: "start_key", Typ: types.Bytes},
+				{Name: "end_key", Typ: types.Bytes},
+				{Name: "encryption_passphrase", Typ: types.String},
+			},
+			BackupRevisionsGeneratorType,
+			makeBackupRevisionsGenerator,
+			"Returns every revision of the keys in [start_key, end_key) found in a "+
+				"chain of backups encrypted with encryption_passphrase, like "+
+				"crdb_internal.backup_revisions(backup_uris, start_key, end_key).",
+		),
+	),
+
+	"crdb_internal.replication_stream": makeBuiltin(
+		tree.FunctionProperties{
+			Impure:   true,
+			Class:    tree.GeneratorClass,
+			Category: categorySystemInfo,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{Name: "tenant_id", Typ: types.Int},
+				{Name: "start_time", Typ: types.Decimal},
+			},
+			ReplicationStreamGeneratorType,
+			makeReplicationStreamGenerator,
+			"Streams the writes to the keyspace of a tenant made after start_time, "+
+				"as they are committed. Each returned row contains either a write, as "+
+				"its key, value and timestamp, where the value is NULL for a deletion, or "+
+				"a resolved timestamp, below which all the writes have been returned. "+
+				"If start_time is zero, the stream starts with the contents of the "+
+				"keyspace as of the current time. The stream never ends; it is "+
+				"consumed by the replication jobs of other clusters.",
+		),
+	),
 }
 
 func makeGeneratorOverload(
@@ -1074,6 +1137,48 @@ func makeCheckConsistencyGenerator(
 	}, nil
 }
 
+// MakeBackupRevisionsGenerator creates the generator of
+// crdb_internal.backup_revisions. It is injected by backupccl.
+var MakeBackupRevisionsGenerator tree.GeneratorFactory
+
+// BackupRevisionsGeneratorType is the type of the rows returned by
+// crdb_internal.backup_revisions.
+var BackupRevisionsGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.Bytes, types.String, types.Decimal, types.Bytes},
+	[]string{"key", "key_pretty", "timestamp", "value"},
+)
+
+func makeBackupRevisionsGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	if MakeBackupRevisionsGenerator == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"crdb_internal.backup_revisions is only available in ccl distribution")
+	}
+	return MakeBackupRevisionsGenerator(ctx, args)
+}
+
+// MakeReplicationStreamGenerator creates the generator of
+// crdb_internal.replication_stream. It is injected by streamingccl.
+var MakeReplicationStreamGenerator tree.GeneratorFactory
+
+// ReplicationStreamGeneratorType is the type of the rows returned by
+// crdb_internal.replication_stream.
+var ReplicationStreamGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.Bytes, types.Bytes, types.Decimal, types.Decimal},
+	[]string{"key", "value", "timestamp", "resolved"},
+)
+
+func makeReplicationStreamGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	if MakeReplicationStreamGenerator == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"crdb_internal.replication_stream is only available in ccl distribution")
+	}
+	return MakeReplicationStreamGenerator(ctx, args)
+}
+
 var checkConsistencyGeneratorType = types.MakeLabeledTuple(
 	[]*types.T{types.Int, types.Bytes, types.String, types.String, types.String},
 	[]string{"range_id", "start_key", "start_key_pretty", "status", "detail"},
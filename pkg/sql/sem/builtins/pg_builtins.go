@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -937,25 +938,83 @@ SELECT description
 		},
 	),
 
-	"pg_try_advisory_lock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info: notUsableInfo,
-		},
+	// https://www.postgresql.org/docs/10/functions-admin.html#FUNCTIONS-ADVISORY-LOCKS
+	//
+	// The functions which return void in Postgres return true.
+	"pg_advisory_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive session-level advisory lock, waiting if necessary.",
+		false /* shared */, false /* xact */, true, /* wait */
+	),
+
+	"pg_advisory_lock_shared": makeAdvisoryLockBuiltin(
+		"Obtains a shared session-level advisory lock, waiting if necessary.",
+		true /* shared */, false /* xact */, true, /* wait */
+	),
+
+	"pg_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive transaction-level advisory lock, waiting if necessary.",
+		false /* shared */, true /* xact */, true, /* wait */
+	),
+
+	"pg_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(
+		"Obtains a shared transaction-level advisory lock, waiting if necessary.",
+		true /* shared */, true /* xact */, true, /* wait */
+	),
+
+	"pg_try_advisory_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive session-level advisory lock if available. "+
+			"Returns whether the lock was obtained.",
+		false /* shared */, false /* xact */, false, /* wait */
+	),
+
+	"pg_try_advisory_lock_shared": makeAdvisoryLockBuiltin(
+		"Obtains a shared session-level advisory lock if available. "+
+			"Returns whether the lock was obtained.",
+		true /* shared */, false /* xact */, false, /* wait */
+	),
+
+	"pg_try_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive transaction-level advisory lock if available. "+
+			"Returns whether the lock was obtained.",
+		false /* shared */, true /* xact */, false, /* wait */
 	),
 
-	"pg_advisory_unlock": makeBuiltin(defProps(),
+	"pg_try_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(
+		"Obtains a shared transaction-level advisory lock if available. "+
+			"Returns whether the lock was obtained.",
+		true /* shared */, true /* xact */, false, /* wait */
+	),
+
+	"pg_advisory_unlock": makeAdvisoryUnlockBuiltin(
+		"Releases a previously-acquired exclusive session-level advisory lock. "+
+			"Returns whether the lock was held.",
+		false, /* shared */
+	),
+
+	"pg_advisory_unlock_shared": makeAdvisoryUnlockBuiltin(
+		"Releases a previously-acquired shared session-level advisory lock. "+
+			"Returns whether the lock was held.",
+		true, /* shared */
+	),
+
+	"pg_advisory_unlock_all": makeBuiltin(
+		tree.FunctionProperties{
+			DistsqlBlacklist: true,
+			Impure:           true,
+		},
 		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
+			Types:      tree.ArgTypes{},
 			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				if ctx.AdvisoryLocker == nil {
+					return nil, errAdvisoryLocksUnavailable
+				}
+				if err := ctx.AdvisoryLocker.ReleaseAllAdvisoryLocks(ctx.Ctx()); err != nil {
+					return nil, err
+				}
 				return tree.DBoolTrue, nil
 			},
-			Info: notUsableInfo,
+			Info: "Releases all session-level advisory locks held by the current session.",
 		},
 	),
 
@@ -1852,3 +1911,101 @@ SELECT description
 	}
 	return r[0], nil
 }
+
+var errAdvisoryLocksUnavailable = pgerror.New(pgcode.FeatureNotSupported,
+	"advisory locks can only be used by client sessions")
+
+// makeAdvisoryLockKey returns the key of the advisory lock specified by the
+// arguments of an advisory lock builtin: either a single bigint key, or two
+// integer keys. The lock is in the current database.
+func makeAdvisoryLockKey(ctx *tree.EvalContext, args tree.Datums) (tree.AdvisoryLockKey, error) {
+	key := tree.AdvisoryLockKey{KeyFields: len(args)}
+	if len(args) == 1 {
+		key.Key = int64(tree.MustBeDInt(args[0]))
+	} else {
+		k1, k2 := int64(tree.MustBeDInt(args[0])), int64(tree.MustBeDInt(args[1]))
+		if k1 != int64(int32(k1)) || k2 != int64(int32(k2)) {
+			return key, pgerror.New(pgcode.NumericValueOutOfRange, "integer out of range")
+		}
+		key.Key = k1<<32 | int64(uint32(k2))
+	}
+	if db := ctx.SessionData.Database; db != "" {
+		id, found, err := ctx.PrivilegedAccessor.LookupNamespaceID(ctx.Ctx(), 0 /* parentID */, db)
+		if err != nil {
+			return key, err
+		}
+		if found {
+			key.DatabaseID = int64(id)
+		}
+	}
+	return key, nil
+}
+
+// makeAdvisoryLockBuiltins returns an advisory lock builtin with overloads
+// for locks specified with a single bigint key and with two integer keys.
+func makeAdvisoryLockBuiltins(
+	info string, fn func(ctx *tree.EvalContext, key tree.AdvisoryLockKey) (tree.Datum, error),
+) builtinDefinition {
+	evalFn := func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+		if ctx.AdvisoryLocker == nil {
+			return nil, errAdvisoryLocksUnavailable
+		}
+		key, err := makeAdvisoryLockKey(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, key)
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{
+			DistsqlBlacklist: true,
+			Impure:           true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"key", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn:         evalFn,
+			Info:       info,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"key1", types.Int}, {"key2", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn:         evalFn,
+			Info:       info,
+		},
+	)
+}
+
+func makeAdvisoryLockBuiltin(info string, shared, xact, wait bool) builtinDefinition {
+	return makeAdvisoryLockBuiltins(info,
+		func(ctx *tree.EvalContext, key tree.AdvisoryLockKey) (tree.Datum, error) {
+			acquired, err := ctx.AdvisoryLocker.AcquireAdvisoryLock(ctx.Ctx(), key, shared, xact, wait)
+			if err != nil {
+				return nil, err
+			}
+			return tree.MakeDBool(tree.DBool(acquired)), nil
+		},
+	)
+}
+
+func makeAdvisoryUnlockBuiltin(info string, shared bool) builtinDefinition {
+	return makeAdvisoryLockBuiltins(info,
+		func(ctx *tree.EvalContext, key tree.AdvisoryLockKey) (tree.Datum, error) {
+			released, err := ctx.AdvisoryLocker.ReleaseAdvisoryLock(ctx.Ctx(), key, shared)
+			if err != nil {
+				return nil, err
+			}
+			if !released && ctx.ClientNoticeSender != nil {
+				mode := "ExclusiveLock"
+				if shared {
+					mode = "ShareLock"
+				}
+				ctx.ClientNoticeSender.SendClientNotice(
+					ctx.Ctx(),
+					pgnotice.NewWithSeverityf("WARNING", "you don't own a lock of type %s", mode),
+				)
+			}
+			return tree.MakeDBool(tree.DBool(released)), nil
+		},
+	)
+}
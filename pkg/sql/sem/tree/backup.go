@@ -37,6 +37,16 @@ type Backup struct {
 	IncrementalFrom    Exprs
 	AsOf               AsOfClause
 	Options            KVOptions
+
+	// Nested is set for BACKUP INTO, in which case To is a collection and the
+	// backup is written to a subdirectory of it.
+	Nested bool
+	// AppendToLatest is set for BACKUP INTO LATEST IN, which appends an
+	// incremental backup to the latest full backup of the collection.
+	AppendToLatest bool
+	// Subdir is set for BACKUP INTO <subdir> IN, which appends an incremental
+	// backup to the full backup in the given subdirectory of the collection.
+	Subdir Expr
 }
 
 var _ Statement = &Backup{}
@@ -47,7 +57,17 @@ func (node *Backup) Format(ctx *FmtCtx) {
 	if node.DescriptorCoverage == RequestedDescriptors {
 		ctx.FormatNode(&node.Targets)
 	}
-	ctx.WriteString(" TO ")
+	if node.Nested {
+		ctx.WriteString(" INTO ")
+		if node.Subdir != nil {
+			ctx.FormatNode(node.Subdir)
+			ctx.WriteString(" IN ")
+		} else if node.AppendToLatest {
+			ctx.WriteString("LATEST IN ")
+		}
+	} else {
+		ctx.WriteString(" TO ")
+	}
 	ctx.FormatNode(&node.To)
 	if node.AsOf.Expr != nil {
 		ctx.WriteString(" ")
@@ -70,6 +90,10 @@ type Restore struct {
 	From               []PartitionedBackup
 	AsOf               AsOfClause
 	Options            KVOptions
+
+	// Subdir is set for RESTORE FROM <subdir> IN, in which case From is a
+	// single collection and the backup is in the given subdirectory of it.
+	Subdir Expr
 }
 
 var _ Statement = &Restore{}
@@ -81,6 +105,10 @@ func (node *Restore) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&node.Targets)
 	}
 	ctx.WriteString(" FROM ")
+	if node.Subdir != nil {
+		ctx.FormatNode(node.Subdir)
+		ctx.WriteString(" IN ")
+	}
 	for i := range node.From {
 		if i > 0 {
 			ctx.WriteString(", ")
@@ -97,6 +125,50 @@ func (node *Restore) Format(ctx *FmtCtx) {
 	}
 }
 
+// StreamIngestion represents a RESTORE TENANT ... FROM REPLICATION STREAM
+// statement, which starts the replication of a tenant from a source cluster.
+type StreamIngestion struct {
+	TenantID      uint64
+	StreamAddress Expr
+}
+
+var _ Statement = &StreamIngestion{}
+
+// Format implements the NodeFormatter interface.
+func (node *StreamIngestion) Format(ctx *FmtCtx) {
+	ctx.Printf("RESTORE TENANT %d FROM REPLICATION STREAM FROM ", node.TenantID)
+	ctx.FormatNode(node.StreamAddress)
+}
+
+// AlterBackup represents an ALTER BACKUP statement.
+type AlterBackup struct {
+	Backup     Expr
+	NewKMSURIs Exprs
+	OldKMSURIs Exprs
+}
+
+var _ Statement = &AlterBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER BACKUP ")
+	ctx.FormatNode(node.Backup)
+	ctx.WriteString(" ADD NEW_KMS = ")
+	formatKMSURIs(ctx, node.NewKMSURIs)
+	ctx.WriteString(" WITH OLD_KMS = ")
+	formatKMSURIs(ctx, node.OldKMSURIs)
+}
+
+func formatKMSURIs(ctx *FmtCtx, uris Exprs) {
+	if len(uris) > 1 {
+		ctx.WriteString("(")
+	}
+	ctx.FormatNode(&uris)
+	if len(uris) > 1 {
+		ctx.WriteString(")")
+	}
+}
+
 // KVOption is a key-value option.
 type KVOption struct {
 	Key   Name
@@ -1508,12 +1508,13 @@ func (node *AlterRole) Format(ctx *FmtCtx) {
 
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
-	Name        TableName
-	ColumnNames NameList
-	AsSource    *Select
-	IfNotExists bool
-	Temporary   bool
-	Replace     bool
+	Name         TableName
+	ColumnNames  NameList
+	AsSource     *Select
+	IfNotExists  bool
+	Temporary    bool
+	Replace      bool
+	Materialized bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -1528,6 +1529,10 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 		ctx.WriteString("TEMPORARY ")
 	}
 
+	if node.Materialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+
 	ctx.WriteString("VIEW ")
 
 	if node.IfNotExists {
@@ -1546,6 +1551,23 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.AsSource)
 }
 
+// RefreshMaterializedView represents a REFRESH MATERIALIZED VIEW statement.
+type RefreshMaterializedView struct {
+	Name         *UnresolvedObjectName
+	Concurrently bool
+}
+
+var _ Statement = &RefreshMaterializedView{}
+
+// Format implements the NodeFormatter interface.
+func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
+	ctx.WriteString("REFRESH MATERIALIZED VIEW ")
+	if node.Concurrently {
+		ctx.WriteString("CONCURRENTLY ")
+	}
+	ctx.FormatNode(node.Name)
+}
+
 // CreateStats represents a CREATE STATISTICS statement.
 type CreateStats struct {
 	Name        Name
@@ -2773,6 +2773,38 @@ type SequenceOperators interface {
 	SetSequenceValue(ctx context.Context, seqName *TableName, newVal int64, isCalled bool) error
 }
 
+// AdvisoryLockKey identifies an advisory lock. Advisory locks are scoped to a
+// database, and locks specified with a single bigint key and with two integer
+// keys are in separate key spaces.
+type AdvisoryLockKey struct {
+	DatabaseID int64
+	// Key is the bigint key of the lock, or the two integer keys of the lock in
+	// its high and low 32 bits.
+	Key int64
+	// KeyFields is the number of keys the lock was specified with: 1 or 2.
+	KeyFields int
+}
+
+// AdvisoryLocker is used by the advisory lock builtins to acquire and release
+// the advisory locks of the session.
+type AdvisoryLocker interface {
+	// AcquireAdvisoryLock acquires the advisory lock in shared or exclusive
+	// mode. Session-level locks are held until they are released, while
+	// transaction-level locks are held until the end of the current
+	// transaction. If wait is false and the lock is held by another session in
+	// a conflicting mode, it returns false rather than waiting for it.
+	AcquireAdvisoryLock(ctx context.Context, key AdvisoryLockKey, shared, xact, wait bool) (bool, error)
+
+	// ReleaseAdvisoryLock releases a session-level advisory lock held in shared
+	// or exclusive mode. It returns false if the session doesn't hold the lock
+	// in that mode.
+	ReleaseAdvisoryLock(ctx context.Context, key AdvisoryLockKey, shared bool) (bool, error)
+
+	// ReleaseAllAdvisoryLocks releases all the session-level advisory locks
+	// held by the session.
+	ReleaseAllAdvisoryLocks(ctx context.Context) error
+}
+
 // EvalContextTestingKnobs contains test knobs.
 type EvalContextTestingKnobs struct {
 	// AssertFuncExprReturnTypes indicates whether FuncExpr evaluations
@@ -2889,6 +2921,10 @@ type EvalContext struct {
 
 	Sequence SequenceOperators
 
+	// AdvisoryLocker is only set on the gateway node, in the EvalContext of
+	// sessions.
+	AdvisoryLocker AdvisoryLocker
+
 	// The transaction in which the statement is executing.
 	Txn *kv.Txn
 	// A handle to the database.
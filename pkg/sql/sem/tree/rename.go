@@ -33,6 +33,20 @@ func (node *RenameDatabase) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.NewName)
 }
 
+// RenameSchema represents an ALTER SCHEMA ... RENAME TO statement.
+type RenameSchema struct {
+	Name    Name
+	NewName Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RenameSchema) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER SCHEMA ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" RENAME TO ")
+	ctx.FormatNode(&node.NewName)
+}
+
 // RenameTable represents a RENAME TABLE or RENAME VIEW or RENAME SEQUENCE
 // statement. Whether the user has asked to rename a view or a sequence
 // is indicated by the IsView and IsSequence fields.
@@ -0,0 +1,129 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// FullBackupClause describes the frequency of full backups of a backup
+// schedule.
+type FullBackupClause struct {
+	AlwaysFull bool
+	Recurrence Expr
+}
+
+// ScheduledBackup represents a CREATE SCHEDULE FOR BACKUP statement.
+type ScheduledBackup struct {
+	ScheduleName    Expr
+	Recurrence      Expr
+	FullBackup      *FullBackupClause /* nil implies choose default */
+	Targets         *TargetList       /* nil implies full cluster backup */
+	To              PartitionedBackup
+	BackupOptions   KVOptions
+	ScheduleOptions KVOptions
+}
+
+var _ Statement = &ScheduledBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *ScheduledBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEDULE")
+
+	if node.ScheduleName != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.ScheduleName)
+	}
+
+	ctx.WriteString(" FOR BACKUP")
+	if node.Targets != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Targets)
+	}
+
+	ctx.WriteString(" TO ")
+	ctx.FormatNode(&node.To)
+
+	if node.BackupOptions != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.BackupOptions)
+	}
+
+	ctx.WriteString(" RECURRING ")
+	ctx.FormatNode(node.Recurrence)
+
+	if node.FullBackup != nil {
+		if node.FullBackup.AlwaysFull {
+			ctx.WriteString(" FULL BACKUP ALWAYS")
+		} else {
+			ctx.WriteString(" FULL BACKUP ")
+			ctx.FormatNode(node.FullBackup.Recurrence)
+		}
+	}
+
+	if node.ScheduleOptions != nil {
+		ctx.WriteString(" WITH SCHEDULE OPTIONS ")
+		ctx.FormatNode(&node.ScheduleOptions)
+	}
+}
+
+// ControlSchedules represents PAUSE/RESUME/DROP SCHEDULES statement.
+type ControlSchedules struct {
+	Schedules *Select
+	Command   ScheduleCommand
+}
+
+// ScheduleCommand determines which type of action to effect on the selected
+// schedule(s).
+type ScheduleCommand int
+
+// ScheduleCommand values
+const (
+	PauseSchedule ScheduleCommand = iota
+	ResumeSchedule
+	DropSchedule
+)
+
+func (c ScheduleCommand) String() string {
+	switch c {
+	case PauseSchedule:
+		return "PAUSE"
+	case ResumeSchedule:
+		return "RESUME"
+	case DropSchedule:
+		return "DROP"
+	default:
+		panic("unhandled schedule command")
+	}
+}
+
+var _ Statement = &ControlSchedules{}
+
+// Format implements the NodeFormatter interface.
+func (n *ControlSchedules) Format(ctx *FmtCtx) {
+	ctx.WriteString(n.Command.String())
+	ctx.WriteString(" SCHEDULES ")
+	ctx.FormatNode(n.Schedules)
+}
+
+// ShowSchedules represents a SHOW SCHEDULES statement.
+type ShowSchedules struct {
+	// If non-nil, the ID of the schedule to show.
+	ScheduleID Expr
+}
+
+var _ Statement = &ShowSchedules{}
+
+// Format implements the NodeFormatter interface.
+func (n *ShowSchedules) Format(ctx *FmtCtx) {
+	if n.ScheduleID != nil {
+		ctx.WriteString("SHOW SCHEDULE ")
+		ctx.FormatNode(n.ScheduleID)
+		return
+	}
+	ctx.WriteString("SHOW SCHEDULES")
+}
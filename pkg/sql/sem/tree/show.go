@@ -87,10 +87,24 @@ type ShowBackup struct {
 	Details              BackupDetails
 	ShouldIncludeSchemas bool
 	Options              KVOptions
+
+	// InCollection is the collection in which Path is a subdirectory. If Path
+	// is nil, the backups of the collection are listed.
+	InCollection Expr
+
+	// LocalityURIs are the URIs of the other localities of a backup
+	// partitioned by locality, the default one being Path, or InCollection if
+	// it is set.
+	LocalityURIs []Expr
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowBackup) Format(ctx *FmtCtx) {
+	if node.InCollection != nil && node.Path == nil {
+		ctx.WriteString("SHOW BACKUPS IN ")
+		ctx.FormatNode(node.InCollection)
+		return
+	}
 	ctx.WriteString("SHOW BACKUP ")
 	if node.Details == BackupRangeDetails {
 		ctx.WriteString("RANGES ")
@@ -100,7 +114,15 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 	if node.ShouldIncludeSchemas {
 		ctx.WriteString("SCHEMAS ")
 	}
-	ctx.FormatNode(node.Path)
+	if node.InCollection != nil {
+		ctx.FormatNode(node.Path)
+		ctx.WriteString(" IN ")
+		uris := append(PartitionedBackup{node.InCollection}, node.LocalityURIs...)
+		ctx.FormatNode(&uris)
+	} else {
+		uris := append(PartitionedBackup{node.Path}, node.LocalityURIs...)
+		ctx.FormatNode(&uris)
+	}
 	if len(node.Options) > 0 {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
@@ -244,6 +266,18 @@ func (node *ShowJobs) Format(ctx *FmtCtx) {
 	}
 }
 
+// ShowJobHistory represents a SHOW JOB <jobid> WITH HISTORY statement.
+type ShowJobHistory struct {
+	JobID Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowJobHistory) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW JOB ")
+	ctx.FormatNode(node.JobID)
+	ctx.WriteString(" WITH HISTORY")
+}
+
 // ShowSessions represents a SHOW SESSIONS statement
 type ShowSessions struct {
 	All     bool
@@ -760,6 +760,15 @@ func (stmt *Backup) walkStmt(v Visitor) Statement {
 			ret.To[i] = e
 		}
 	}
+	if stmt.Subdir != nil {
+		e, changed := WalkExpr(v, stmt.Subdir)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Subdir = e
+		}
+	}
 	for i, expr := range stmt.IncrementalFrom {
 		e, changed := WalkExpr(v, expr)
 		if changed {
@@ -1019,6 +1028,15 @@ func (stmt *Restore) walkStmt(v Visitor) Statement {
 			}
 		}
 	}
+	if stmt.Subdir != nil {
+		e, changed := WalkExpr(v, stmt.Subdir)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Subdir = e
+		}
+	}
 	{
 		opts, changed := walkKVOptions(v, stmt.Options)
 		if changed {
@@ -108,6 +108,18 @@ func (p *planner) processSerialInColumnDef(
 	// The constraint on the name is that an object of this name must not exist already.
 	seqName := tree.NewUnqualifiedTableName(
 		tree.Name(tableName.Table() + "_" + string(d.Name) + "_seq"))
+	// The sequence for a column of a table in a user-defined schema lives in
+	// the same schema as the table, rather than in the first schema of the
+	// search path.
+	if scName := tableName.Schema(); scName != tree.PublicSchema &&
+		!sessiondata.IsSystemSchemaName(scName) {
+		seqName.ObjectNamePrefix = tree.ObjectNamePrefix{
+			CatalogName:     tableName.CatalogName,
+			SchemaName:      tableName.SchemaName,
+			ExplicitCatalog: true,
+			ExplicitSchema:  true,
+		}
+	}
 
 	// The first step in the search is to prepare the seqName to fill in
 	// the catalog/schema parent. This is what ResolveUncachedDatabase does.
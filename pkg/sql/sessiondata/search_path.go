@@ -36,6 +36,22 @@ const CRDBInternalSchemaName = "crdb_internal"
 // PgTempSchemaName is the alias for temporary schemas across sessions.
 const PgTempSchemaName = "pg_temp"
 
+// PgSchemaPrefix is the prefix reserved for system schemas, such as
+// pg_catalog and the session-specific temporary schemas.
+const PgSchemaPrefix = "pg_"
+
+// IsSystemSchemaName returns true if the given name refers to a virtual
+// schema or uses the prefix reserved for system schemas. Such schemas cannot
+// be created, dropped or renamed by users, and objects cannot be created in
+// them by name.
+func IsSystemSchemaName(name string) bool {
+	switch name {
+	case InformationSchemaName, CRDBInternalSchemaName:
+		return true
+	}
+	return strings.HasPrefix(name, PgSchemaPrefix)
+}
+
 // SearchPath represents a list of namespaces to search builtins in.
 // The names must be normalized (as per Name.Normalize) already.
 type SearchPath struct {
@@ -63,6 +63,11 @@ type SessionData struct {
 	// StmtTimeout is the duration a query is permitted to run before it is
 	// canceled by the session. If set to 0, there is no timeout.
 	StmtTimeout time.Duration
+	// LockTimeout is the maximum amount of time that a query will wait while
+	// attempting to acquire a lock on a key or while blocking on an existing
+	// lock in order to perform a non-locking read on a key. If set to 0, there
+	// is no timeout.
+	LockTimeout time.Duration
 	// User is the name of the user logged into the session.
 	User string
 	// SafeUpdates causes errors when the client
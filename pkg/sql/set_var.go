@@ -270,54 +270,75 @@ func timeZoneVarSet(_ context.Context, m *sessionDataMutator, s string) error {
 	return nil
 }
 
-func stmtTimeoutVarGetStringVal(
-	ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr,
-) (string, error) {
-	if len(values) != 1 {
-		return "", newSingleArgVarError("statement_timeout")
-	}
-	d, err := values[0].Eval(&evalCtx.EvalContext)
-	if err != nil {
-		return "", err
-	}
-
-	var timeout time.Duration
-	switch v := tree.UnwrapDatum(&evalCtx.EvalContext, d).(type) {
-	case *tree.DString:
-		return string(*v), nil
-	case *tree.DInterval:
-		timeout, err = intervalToDuration(v)
+func makeTimeoutVarGetter(
+	varName string,
+) func(ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr) (string, error) {
+	return func(
+		ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr,
+	) (string, error) {
+		if len(values) != 1 {
+			return "", newSingleArgVarError(varName)
+		}
+		d, err := values[0].Eval(&evalCtx.EvalContext)
 		if err != nil {
-			return "", wrapSetVarError("statement_timeout", values[0].String(), "%v", err)
+			return "", err
 		}
-	case *tree.DInt:
-		timeout = time.Duration(*v) * time.Millisecond
+
+		var timeout time.Duration
+		switch v := tree.UnwrapDatum(&evalCtx.EvalContext, d).(type) {
+		case *tree.DString:
+			return string(*v), nil
+		case *tree.DInterval:
+			timeout, err = intervalToDuration(v)
+			if err != nil {
+				return "", wrapSetVarError(varName, values[0].String(), "%v", err)
+			}
+		case *tree.DInt:
+			timeout = time.Duration(*v) * time.Millisecond
+		}
+		return timeout.String(), nil
 	}
-	return timeout.String(), nil
 }
 
-func stmtTimeoutVarSet(ctx context.Context, m *sessionDataMutator, s string) error {
+func validateTimeoutVar(varName string, s string) (time.Duration, error) {
 	interval, err := tree.ParseDIntervalWithTypeMetadata(s, types.IntervalTypeMetadata{
 		DurationField: types.IntervalDurationField{
 			DurationType: types.IntervalDurationType_MILLISECOND,
 		},
 	})
 	if err != nil {
-		return wrapSetVarError("statement_timeout", s, "%v", err)
+		return 0, wrapSetVarError(varName, s, "%v", err)
 	}
 	timeout, err := intervalToDuration(interval)
 	if err != nil {
-		return wrapSetVarError("statement_timeout", s, "%v", err)
+		return 0, wrapSetVarError(varName, s, "%v", err)
 	}
 
 	if timeout < 0 {
-		return wrapSetVarError("statement_timeout", s,
-			"statement_timeout cannot have a negative duration")
+		return 0, wrapSetVarError(varName, s,
+			"%s cannot have a negative duration", varName)
+	}
+	return timeout, nil
+}
+
+func stmtTimeoutVarSet(ctx context.Context, m *sessionDataMutator, s string) error {
+	timeout, err := validateTimeoutVar("statement_timeout", s)
+	if err != nil {
+		return err
 	}
 	m.SetStmtTimeout(timeout)
 	return nil
 }
 
+func lockTimeoutVarSet(ctx context.Context, m *sessionDataMutator, s string) error {
+	timeout, err := validateTimeoutVar("lock_timeout", s)
+	if err != nil {
+		return err
+	}
+	m.SetLockTimeout(timeout)
+	return nil
+}
+
 func intervalToDuration(interval *tree.DInterval) (time.Duration, error) {
 	nanos, _, _, err := interval.Encode()
 	if err != nil {
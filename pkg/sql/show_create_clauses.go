@@ -82,13 +82,23 @@ func ShowCreateView(
 	if desc.Temporary {
 		f.WriteString("TEMP ")
 	}
+	if desc.MaterializedView() {
+		f.WriteString("MATERIALIZED ")
+	}
 	f.WriteString("VIEW ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
+	first := true
 	for i := range desc.Columns {
-		if i > 0 {
+		// Materialized views have a hidden rowid column, which isn't part of
+		// the view query.
+		if desc.Columns[i].Hidden {
+			continue
+		}
+		if !first {
 			f.WriteString(", ")
 		}
+		first = false
 		f.FormatNameP(&desc.Columns[i].Name)
 	}
 	f.WriteString(") AS ")
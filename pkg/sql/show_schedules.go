@@ -0,0 +1,115 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+var showSchedulesColumns = sqlbase.ResultColumns{
+	{Name: "id", Typ: types.Int},
+	{Name: "label", Typ: types.String},
+	{Name: "schedule_status", Typ: types.String},
+	{Name: "next_run", Typ: types.TimestampTZ},
+	{Name: "state", Typ: types.String},
+	{Name: "recurrence", Typ: types.String},
+	{Name: "executor_type", Typ: types.String},
+	{Name: "owner", Typ: types.String},
+	{Name: "created", Typ: types.TimestampTZ},
+}
+
+// ShowSchedules returns a SHOW SCHEDULES statement.
+// Privileges: admin.
+func (p *planner) ShowSchedules(ctx context.Context, n *tree.ShowSchedules) (planNode, error) {
+	if err := p.RequireAdminRole(ctx, "SHOW SCHEDULES"); err != nil {
+		return nil, err
+	}
+
+	var scheduleID tree.TypedExpr
+	if n.ScheduleID != nil {
+		var err error
+		scheduleID, err = p.analyzeExpr(
+			ctx, n.ScheduleID, nil, tree.IndexedVarHelper{}, types.Int, true, "SHOW SCHEDULE",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &delayedNode{
+		name:    n.String(),
+		columns: showSchedulesColumns,
+
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			sqltelemetry.IncrementShowCounter(sqltelemetry.Schedules)
+			ex := p.ExecCfg().InternalExecutor
+
+			var schedules []*jobs.ScheduledJob
+			if scheduleID != nil {
+				d, err := scheduleID.Eval(p.EvalContext())
+				if err != nil {
+					return nil, err
+				}
+				if d == tree.DNull {
+					return nil, errors.New("schedule ID cannot be NULL")
+				}
+				schedule, err := jobs.LoadScheduledJob(ctx, ex, int64(tree.MustBeDInt(d)), p.txn)
+				if err != nil {
+					return nil, err
+				}
+				schedules = append(schedules, schedule)
+			} else {
+				var err error
+				if schedules, err = jobs.LoadScheduledJobs(ctx, ex, p.txn); err != nil {
+					return nil, err
+				}
+			}
+
+			v := p.newContainerValuesNode(showSchedulesColumns, len(schedules))
+			for _, schedule := range schedules {
+				status, nextRun := "ACTIVE", tree.Datum(tree.DNull)
+				if schedule.IsPaused() {
+					status = "PAUSED"
+				} else {
+					nextRun = tree.MustMakeDTimestampTZ(schedule.NextRun(), time.Microsecond)
+				}
+				recurrence := tree.Datum(tree.DNull)
+				if expr := schedule.ScheduleExpr(); expr != "" {
+					recurrence = tree.NewDString(expr)
+				}
+				row := tree.Datums{
+					tree.NewDInt(tree.DInt(schedule.ScheduleID())),
+					tree.NewDString(schedule.ScheduleName()),
+					tree.NewDString(status),
+					nextRun,
+					tree.NewDString(schedule.ScheduleStatus()),
+					recurrence,
+					tree.NewDString(schedule.ExecutorType()),
+					tree.NewDString(schedule.Owner()),
+					tree.MustMakeDTimestampTZ(schedule.Created(), time.Microsecond),
+				}
+				if _, err := v.rows.AddRow(ctx, row); err != nil {
+					v.Close(ctx)
+					return nil, err
+				}
+			}
+			return v, nil
+		},
+	}, nil
+}
@@ -36,7 +36,7 @@ type DescriptorKey interface {
 }
 
 // DescriptorProto is the interface implemented by DatabaseDescriptor,
-// TableDescriptor, and TypeDescriptor.
+// TableDescriptor, TypeDescriptor, and SchemaDescriptor.
 // TODO(marc): this is getting rather large.
 type DescriptorProto interface {
 	protoutil.Message
@@ -61,6 +61,8 @@ func WrapDescriptor(descriptor DescriptorProto) *Descriptor {
 		desc.Union = &Descriptor_Database{Database: t}
 	case *TypeDescriptor:
 		desc.Union = &Descriptor_Type{Type: t}
+	case *SchemaDescriptor:
+		desc.Union = &Descriptor_Schema{Schema: t}
 	default:
 		panic(fmt.Sprintf("unknown descriptor type: %s", descriptor.TypeName()))
 	}
@@ -350,6 +350,25 @@ func GetDatabaseDescFromID(
 	return db, nil
 }
 
+// GetSchemaDescFromID retrieves the schema descriptor for the schema ID passed
+// in using an existing proto getter. Returns an error if the descriptor
+// doesn't exist or if it exists and is not a schema.
+func GetSchemaDescFromID(
+	ctx context.Context, protoGetter protoGetter, codec keys.SQLCodec, id ID,
+) (*SchemaDescriptor, error) {
+	desc := &Descriptor{}
+	descKey := MakeDescMetadataKey(codec, id)
+	_, err := protoGetter.GetProtoTs(ctx, descKey, desc)
+	if err != nil {
+		return nil, err
+	}
+	schema := desc.GetSchema()
+	if schema == nil {
+		return nil, ErrDescriptorNotFound
+	}
+	return schema, nil
+}
+
 // GetTableDescFromID retrieves the table descriptor for the table
 // ID passed in using an existing proto getter. Returns an error if the
 // descriptor doesn't exist or if it exists and is not a table.
@@ -676,6 +695,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// MaterializedView returns whether or not this TableDescriptor is a
+// MaterializedView.
+func (desc *TableDescriptor) MaterializedView() bool {
+	return desc.IsMaterializedView
+}
+
 // IsAs returns true if the TableDescriptor actually describes
 // a Table resource with an As source.
 func (desc *TableDescriptor) IsAs() bool {
@@ -719,9 +744,9 @@ func IsVirtualTable(id ID) bool {
 // different resource like a view or a virtual table. Physical tables have
 // primary keys, column families, and indexes (unlike virtual tables).
 // Sequences count as physical tables because their values are stored in
-// the KV layer.
+// the KV layer, and so do materialized views because their results are.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
-	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable())
+	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable()) || desc.MaterializedView()
 }
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
@@ -1225,8 +1250,9 @@ func (desc *MutableTableDescriptor) AllocateIDs() error {
 		}
 	}
 
-	// Only tables can have / need indexes and column families.
-	if desc.IsTable() {
+	// Only tables and materialized views can have / need indexes and column
+	// families.
+	if desc.IsTable() || desc.MaterializedView() {
 		if err := desc.allocateIndexIDs(columnNames); err != nil {
 			return err
 		}
@@ -1847,6 +1873,11 @@ func (desc *TableDescriptor) ValidateTable() error {
 				return errors.AssertionFailedf(
 					"primary key swap mutation in state %s, direction %s", errors.Safe(m.State), errors.Safe(m.Direction))
 			}
+		case *DescriptorMutation_MaterializedViewRefresh:
+			if m.Direction == DescriptorMutation_NONE {
+				return errors.AssertionFailedf(
+					"materialized view refresh mutation in state %s, direction %s", errors.Safe(m.State), errors.Safe(m.Direction))
+			}
 		default:
 			return errors.AssertionFailedf(
 				"mutation in state %s, direction %s, and no column/index descriptor",
@@ -1879,8 +1910,14 @@ func (desc *TableDescriptor) ValidateTable() error {
 	// Ensure that mutations cannot be queued if a primary key change has
 	// either been started in this transaction, or is currently in progress.
 	var alterPKMutation MutationID
-	var foundAlterPK bool
+	var foundAlterPK, foundRefresh bool
 	for _, m := range desc.Mutations {
+		// A materialized view refresh swaps out all the indexes of the view, so
+		// it can't be queued with any other mutation.
+		if foundRefresh {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cannot perform a schema change operation while a materialized view refresh is in progress")
+		}
 		// If we have seen an alter primary key mutation, then
 		// m we are considering right now is invalid.
 		if foundAlterPK {
@@ -1899,6 +1936,9 @@ func (desc *TableDescriptor) ValidateTable() error {
 			foundAlterPK = true
 			alterPKMutation = m.MutationID
 		}
+		if m.GetMaterializedViewRefresh() != nil {
+			foundRefresh = true
+		}
 	}
 
 	// Validate the privilege descriptor.
@@ -3151,6 +3191,11 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 					return err
 				}
 			}
+		case *DescriptorMutation_MaterializedViewRefresh:
+			// Swap in the backfilled indexes. The existing indexes are garbage
+			// collected by the caller.
+			desc.PrimaryIndex = t.MaterializedViewRefresh.NewPrimaryIndex
+			desc.Indexes = t.MaterializedViewRefresh.NewIndexes
 		}
 
 	case DescriptorMutation_DROP:
@@ -3299,6 +3344,18 @@ func (desc *MutableTableDescriptor) AddPrimaryKeySwapMutation(swap *PrimaryKeySw
 	desc.addMutation(m)
 }
 
+// AddMaterializedViewRefreshMutation adds a MaterializedViewRefreshMutation to
+// the table descriptor.
+func (desc *MutableTableDescriptor) AddMaterializedViewRefreshMutation(
+	refresh *MaterializedViewRefresh,
+) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_MaterializedViewRefresh{MaterializedViewRefresh: refresh},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
 func (desc *MutableTableDescriptor) addMutation(m DescriptorMutation) {
 	switch m.Direction {
 	case DescriptorMutation_ADD:
@@ -3510,6 +3567,8 @@ func (desc *Descriptor) GetID() ID {
 		return t.Table.ID
 	case *Descriptor_Database:
 		return t.Database.ID
+	case *Descriptor_Schema:
+		return t.Schema.ID
 	default:
 		return 0
 	}
@@ -3522,6 +3581,8 @@ func (desc *Descriptor) GetName() string {
 		return t.Table.Name
 	case *Descriptor_Database:
 		return t.Database.Name
+	case *Descriptor_Schema:
+		return t.Schema.Name
 	default:
 		return ""
 	}
@@ -3574,10 +3635,10 @@ func (desc *TableDescriptor) maybeSetTimeFromMVCCTimestamp(ts hlc.Timestamp) {
 
 	// Ensure that if the table is in the process of being added and relies on
 	// CreateAsOfTime that it is now set.
-	if desc.Adding() && desc.IsAs() && desc.CreateAsOfTime.IsEmpty() {
+	if desc.Adding() && (desc.IsAs() || desc.MaterializedView()) && desc.CreateAsOfTime.IsEmpty() {
 		log.Fatalf(context.TODO(), "table descriptor for %q (%d.%d) is in the "+
-			"ADD state and was created with CREATE TABLE ... AS but does not have a "+
-			"CreateAsOfTime set", desc.Name, desc.ParentID, desc.ID)
+			"ADD state and was created with CREATE TABLE ... AS or CREATE MATERIALIZED "+
+			"VIEW but does not have a CreateAsOfTime set", desc.Name, desc.ParentID, desc.ID)
 	}
 
 	// Set the ModificationTime based on the passed ts if we should.
@@ -4071,6 +4132,40 @@ func (desc *TypeDescriptor) SetName(name string) {
 // NameResolutionResult implements the NameResolutionResult interface.
 func (desc *TypeDescriptor) NameResolutionResult() {}
 
+// GetAuditMode implements the DescriptorProto interface.
+func (desc *SchemaDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// SetID implements the DescriptorProto interface.
+func (desc *SchemaDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName implements the DescriptorProto interface.
+func (desc *SchemaDescriptor) TypeName() string {
+	return "schema"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *SchemaDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// Validate validates that the schema descriptor is well formed.
+func (desc *SchemaDescriptor) Validate() error {
+	if err := validateName(desc.Name, "descriptor"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid schema ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d for schema %q", desc.ParentID, desc.Name)
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
 // DatabaseKey implements DescriptorKey.
 type DatabaseKey struct {
 	name string
@@ -286,6 +286,61 @@ create table system.statement_diagnostics(
 
 	FAMILY "primary" (id, statement_fingerprint, statement, collected_at, trace, bundle_chunks, error)
 );`
+
+	ScheduledJobsTableSchema = `
+CREATE TABLE system.scheduled_jobs (
+	schedule_id      INT DEFAULT unique_rowid() NOT NULL,
+	schedule_name    STRING NOT NULL,
+	created          TIMESTAMPTZ NOT NULL DEFAULT now(),
+	owner            STRING NOT NULL,
+	next_run         TIMESTAMPTZ,
+	schedule_expr    STRING,
+	executor_type    STRING NOT NULL,
+	execution_args   BYTES NOT NULL,
+	schedule_state   BYTES,
+
+	CONSTRAINT "primary" PRIMARY KEY (schedule_id),
+	INDEX "next_run_idx" (next_run),
+
+	FAMILY "primary" (schedule_id, schedule_name, created, owner, next_run, schedule_expr, executor_type, execution_args, schedule_state)
+)`
+
+	// job_history is an append-only log of the events of jobs: their state
+	// transitions, adoptions by nodes, errors and samples of their progress.
+	JobHistoryTableSchema = `
+CREATE TABLE system.job_history (
+	job_id             INT NOT NULL,
+	recorded           TIMESTAMPTZ NOT NULL DEFAULT now(),
+	event_id           INT NOT NULL DEFAULT unique_rowid(),
+	event_type         STRING NOT NULL,
+	node_id            INT,
+	status             STRING NOT NULL,
+	running_status     STRING,
+	fraction_completed FLOAT,
+	high_water         DECIMAL,
+	error              STRING,
+
+	CONSTRAINT "primary" PRIMARY KEY (job_id, recorded, event_id),
+
+	FAMILY "primary" (job_id, recorded, event_id, event_type, node_id, status, running_status, fraction_completed, high_water, error)
+)`
+
+	// advisory_locks stores the advisory locks held by sessions. The rows of a
+	// session expire unless they are heartbeated by the node of the session, so
+	// that the locks of sessions that went away are released.
+	AdvisoryLocksTableSchema = `
+CREATE TABLE system.advisory_locks (
+	database_id INT NOT NULL,
+	lock_key    INT NOT NULL,
+	key_fields  INT NOT NULL,
+	session_id  BYTES NOT NULL,
+	exclusive   BOOL NOT NULL,
+	expiration  TIMESTAMPTZ NOT NULL,
+
+	CONSTRAINT "primary" PRIMARY KEY (database_id, lock_key, key_fields, session_id, exclusive),
+
+	FAMILY "primary" (database_id, lock_key, key_fields, session_id, exclusive, expiration)
+)`
 )
 
 func pk(name string) IndexDescriptor {
@@ -338,6 +393,9 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.StatementBundleChunksTableID:         privilege.ReadWriteData,
 	keys.StatementDiagnosticsRequestsTableID:  privilege.ReadWriteData,
 	keys.StatementDiagnosticsTableID:          privilege.ReadWriteData,
+	keys.ScheduledJobsTableID:                 privilege.ReadWriteData,
+	keys.JobHistoryTableID:                    privilege.ReadWriteData,
+	keys.AdvisoryLocksTableID:                 privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -1454,6 +1512,152 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	nowTZString = "now():::TIMESTAMPTZ"
+
+	// ScheduledJobsTable is the descriptor for the scheduled jobs table.
+	ScheduledJobsTable = TableDescriptor{
+		Name:                    "scheduled_jobs",
+		ID:                      keys.ScheduledJobsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "schedule_id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString, Nullable: false},
+			{Name: "schedule_name", ID: 2, Type: types.String, Nullable: false},
+			{Name: "created", ID: 3, Type: types.TimestampTZ, DefaultExpr: &nowTZString, Nullable: false},
+			{Name: "owner", ID: 4, Type: types.String, Nullable: false},
+			{Name: "next_run", ID: 5, Type: types.TimestampTZ, Nullable: true},
+			{Name: "schedule_expr", ID: 6, Type: types.String, Nullable: true},
+			{Name: "executor_type", ID: 7, Type: types.String, Nullable: false},
+			{Name: "execution_args", ID: 8, Type: types.Bytes, Nullable: false},
+			{Name: "schedule_state", ID: 9, Type: types.Bytes, Nullable: true},
+		},
+		NextColumnID: 10,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ID:   0,
+				ColumnNames: []string{
+					"schedule_id", "schedule_name", "created", "owner", "next_run",
+					"schedule_expr", "executor_type", "execution_args", "schedule_state",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: pk("schedule_id"),
+		Indexes: []IndexDescriptor{
+			{
+				Name:             "next_run_idx",
+				ID:               2,
+				Unique:           false,
+				ColumnNames:      []string{"next_run"},
+				ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC},
+				ColumnIDs:        []ColumnID{5},
+				ExtraColumnIDs:   []ColumnID{1},
+				Version:          SecondaryIndexFamilyFormatVersion,
+			},
+		},
+		NextIndexID: 3,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.ScheduledJobsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// JobHistoryTable is the descriptor for the job history table.
+	JobHistoryTable = TableDescriptor{
+		Name:                    "job_history",
+		ID:                      keys.JobHistoryTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "job_id", ID: 1, Type: types.Int, Nullable: false},
+			{Name: "recorded", ID: 2, Type: types.TimestampTZ, DefaultExpr: &nowTZString, Nullable: false},
+			{Name: "event_id", ID: 3, Type: types.Int, DefaultExpr: &uniqueRowIDString, Nullable: false},
+			{Name: "event_type", ID: 4, Type: types.String, Nullable: false},
+			{Name: "node_id", ID: 5, Type: types.Int, Nullable: true},
+			{Name: "status", ID: 6, Type: types.String, Nullable: false},
+			{Name: "running_status", ID: 7, Type: types.String, Nullable: true},
+			{Name: "fraction_completed", ID: 8, Type: types.Float, Nullable: true},
+			{Name: "high_water", ID: 9, Type: types.Decimal, Nullable: true},
+			{Name: "error", ID: 10, Type: types.String, Nullable: true},
+		},
+		NextColumnID: 11,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ID:   0,
+				ColumnNames: []string{
+					"job_id", "recorded", "event_id", "event_type", "node_id", "status",
+					"running_status", "fraction_completed", "high_water", "error",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"job_id", "recorded", "event_id"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2, 3},
+			Version:          SecondaryIndexFamilyFormatVersion,
+		},
+		NextIndexID: 2,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.JobHistoryTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// AdvisoryLocksTable is the descriptor for the advisory locks table.
+	AdvisoryLocksTable = TableDescriptor{
+		Name:                    "advisory_locks",
+		ID:                      keys.AdvisoryLocksTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "database_id", ID: 1, Type: types.Int, Nullable: false},
+			{Name: "lock_key", ID: 2, Type: types.Int, Nullable: false},
+			{Name: "key_fields", ID: 3, Type: types.Int, Nullable: false},
+			{Name: "session_id", ID: 4, Type: types.Bytes, Nullable: false},
+			{Name: "exclusive", ID: 5, Type: types.Bool, Nullable: false},
+			{Name: "expiration", ID: 6, Type: types.TimestampTZ, Nullable: false},
+		},
+		NextColumnID: 7,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ID:   0,
+				ColumnNames: []string{
+					"database_id", "lock_key", "key_fields", "session_id", "exclusive", "expiration",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:        "primary",
+			ID:          1,
+			Unique:      true,
+			ColumnNames: []string{"database_id", "lock_key", "key_fields", "session_id", "exclusive"},
+			ColumnDirections: []IndexDescriptor_Direction{
+				IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC,
+			},
+			ColumnIDs: []ColumnID{1, 2, 3, 4, 5},
+			Version:   SecondaryIndexFamilyFormatVersion,
+		},
+		NextIndexID: 2,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.AdvisoryLocksTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
@@ -1514,6 +1718,11 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	target.AddDescriptor(keys.SystemDatabaseID, &StatementBundleChunksTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &StatementDiagnosticsRequestsTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &StatementDiagnosticsTable)
+
+	// Tables introduced in 20.2.
+	target.AddDescriptor(keys.SystemDatabaseID, &ScheduledJobsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &JobHistoryTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &AdvisoryLocksTable)
 }
 
 // addSystemDatabaseToSchema populates the supplied MetadataSchema with the
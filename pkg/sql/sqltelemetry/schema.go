@@ -127,6 +127,12 @@ func SchemaJobControlCounter(desiredStatus string) telemetry.Counter {
 	return telemetry.GetCounter("sql.schema.job.control." + desiredStatus)
 }
 
+// ScheduleControlCounter is to be incremented every time a schedule control
+// action is taken.
+func ScheduleControlCounter(command string) telemetry.Counter {
+	return telemetry.GetCounter("sql.schedule.control." + command)
+}
+
 // SchemaChangeInExplicitTxnCounter is to be incremented every time a schema change
 // is scheduled using an explicit transaction.
 var SchemaChangeInExplicitTxnCounter = telemetry.GetCounterOnce("sql.schema.change_in_explicit_txn")
@@ -42,6 +42,10 @@ const (
 	Jobs
 	// Roles represents the SHOW ROLES command.
 	Roles
+	// Schedules represents the SHOW SCHEDULES command.
+	Schedules
+	// JobHistory represents the SHOW JOB ... WITH HISTORY command.
+	JobHistory
 )
 
 var showTelemetryNameMap = map[ShowTelemetryType]string{
@@ -55,6 +59,8 @@ var showTelemetryNameMap = map[ShowTelemetryType]string{
 	Constraints: "constraints",
 	Jobs:        "jobs",
 	Roles:       "roles",
+	Schedules:   "schedules",
+	JobHistory:  "job_history",
 }
 
 func (s ShowTelemetryType) String() string {
@@ -127,11 +127,11 @@ type TableCollection struct {
 	// database descriptors.
 	databaseCache *databaseCache
 
-	// schemaCache maps {databaseID, schemaName} -> (schemaID, if exists, otherwise nil).
+	// schemaCache maps {databaseID, schemaName} -> (schemaID, if exists, otherwise InvalidID).
 	// TODO(sqlexec): replace with leasing system with custom schemas.
-	// This is currently never cleared, because there should only be unique schemas
-	// being added for each TableCollection as only temporary schemas can be
-	// made, and you cannot read from other schema caches.
+	// The cache is cleared at the end of each transaction, and whenever a
+	// schema is created, renamed or dropped within the transaction, since
+	// user-defined schemas can be modified by other sessions.
 	schemaCache sync.Map
 
 	// dbCacheSubscriber is used to block until the node's database cache has been
@@ -172,15 +172,25 @@ type dbCacheSubscriber interface {
 	waitForCacheState(cond func(*databaseCache) bool)
 }
 
-// isSupportedSchemaName returns whether this schema name is supported.
-// TODO(sqlexec): this should be deleted when we use custom schemas.
-// However, this introduces an extra lookup for cases where `<database>.<table>`
-// is looked up.
-// See #44733.
+// isSupportedSchemaName returns whether this schema name is supported
+// without user-defined schemas.
+// Once user-defined schemas are in use, this introduces an extra lookup for
+// cases where `<database>.<table>` is looked up. See #44733.
 func isSupportedSchemaName(n tree.Name) bool {
 	return n == tree.PublicSchemaName || strings.HasPrefix(string(n), "pg_temp")
 }
 
+// maybeUserDefinedSchema returns whether the schema name may refer to a
+// user-defined schema. Until the cluster version allowing CREATE SCHEMA is
+// active, only the public and temporary schemas exist, so no lookup is
+// needed for any other name.
+func (tc *TableCollection) maybeUserDefinedSchema(ctx context.Context, n tree.Name) bool {
+	if isSupportedSchemaName(n) {
+		return true
+	}
+	return tc.settings.Version.IsActive(ctx, clusterversion.VersionUserDefinedSchemas)
+}
+
 // getMutableTableDescriptor returns a mutable table descriptor.
 //
 // If flags.required is false, getMutableTableDescriptor() will gracefully
@@ -193,7 +203,7 @@ func (tc *TableCollection) getMutableTableDescriptor(
 		log.Infof(ctx, "reading mutable descriptor on table '%s'", tn)
 	}
 
-	if !isSupportedSchemaName(tn.SchemaName) {
+	if !tc.maybeUserDefinedSchema(ctx, tn.SchemaName) {
 		return nil, nil
 	}
 
@@ -259,14 +269,21 @@ func (tc *TableCollection) resolveSchemaID(
 	key := schemaCacheKey{dbID: dbID, schemaName: schemaName}
 	// First lookup the cache.
 	if val, ok := tc.schemaCache.Load(key); ok {
-		return true, val.(sqlbase.ID), nil
+		schemaID := val.(sqlbase.ID)
+		return schemaID != sqlbase.InvalidID, schemaID, nil
 	}
 
 	// Next, try lookup the result from KV, storing and returning the value.
+	// Misses are cached too, so that `<database>.<table>` names, which are
+	// first looked up as `<schema>.<table>`, only pay for the extra lookup
+	// once per transaction.
 	exists, schemaID, err := resolveSchemaID(ctx, txn, tc.codec(), dbID, schemaName)
-	if err != nil || !exists {
+	if err != nil {
 		return exists, schemaID, err
 	}
+	if !exists {
+		schemaID = sqlbase.InvalidID
+	}
 	tc.schemaCache.Store(key, schemaID)
 	return exists, schemaID, err
 }
@@ -289,7 +306,7 @@ func (tc *TableCollection) getTableVersion(
 		log.Infof(ctx, "planner acquiring lease on table '%s'", tn)
 	}
 
-	if !isSupportedSchemaName(tn.SchemaName) {
+	if !tc.maybeUserDefinedSchema(ctx, tn.SchemaName) {
 		return nil, nil
 	}
 
@@ -529,7 +546,7 @@ func (tc *TableCollection) releaseTables(ctx context.Context) {
 	tc.releaseLeases(ctx)
 	tc.uncommittedTables = nil
 	tc.uncommittedDatabases = nil
-	tc.releaseAllDescriptors()
+	tc.releaseSchemas()
 }
 
 // Wait until the database cache has been updated to properly
@@ -824,6 +841,13 @@ func (tc *TableCollection) getSchemasForDatabase(
 	return tc.allSchemasForDatabase[dbID], nil
 }
 
+// releaseSchemas releases the cached schema IDs and schema names held by
+// TableCollection, along with all cached descriptors.
+func (tc *TableCollection) releaseSchemas() {
+	tc.schemaCache = sync.Map{}
+	tc.releaseAllDescriptors()
+}
+
 // releaseAllDescriptors releases the cached slice of all descriptors
 // held by TableCollection.
 func (tc *TableCollection) releaseAllDescriptors() {
@@ -12,6 +12,7 @@ package sql
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
@@ -114,11 +115,23 @@ type tableWriterBase struct {
 	b *kv.Batch
 	// batchSize is the current batch size (when known).
 	batchSize int
+	// lockTimeout specifies the maximum amount of time that the writer will
+	// wait while attempting to acquire a lock on a key.
+	lockTimeout time.Duration
 }
 
-func (tb *tableWriterBase) init(txn *kv.Txn) {
+func (tb *tableWriterBase) init(txn *kv.Txn, evalCtx *tree.EvalContext) {
 	tb.txn = txn
-	tb.b = txn.NewBatch()
+	if evalCtx != nil && evalCtx.SessionData != nil {
+		tb.lockTimeout = evalCtx.SessionData.LockTimeout
+	}
+	tb.initNewBatch()
+}
+
+// initNewBatch starts a new batch in the current transaction.
+func (tb *tableWriterBase) initNewBatch() {
+	tb.b = tb.txn.NewBatch()
+	tb.b.Header.LockTimeout = tb.lockTimeout
 }
 
 // flushAndStartNewBatch shares the common flushAndStartNewBatch() code between
@@ -129,7 +142,7 @@ func (tb *tableWriterBase) flushAndStartNewBatch(
 	if err := tb.txn.Run(ctx, tb.b); err != nil {
 		return row.ConvertBatchError(ctx, tableDesc, tb.b)
 	}
-	tb.b = tb.txn.NewBatch()
+	tb.initNewBatch()
 	tb.batchSize = 0
 	return nil
 }
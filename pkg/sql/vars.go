@@ -721,8 +721,16 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
-	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-LOC-TIMEOUT
-	`lock_timeout`: makeCompatIntVar(`lock_timeout`, 0),
+	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-LOCK-TIMEOUT
+	`lock_timeout`: {
+		GetStringVal: makeTimeoutVarGetter(`lock_timeout`),
+		Set:          lockTimeoutVarSet,
+		Get: func(evalCtx *extendedEvalContext) string {
+			ms := evalCtx.SessionData.LockTimeout.Nanoseconds() / int64(time.Millisecond)
+			return strconv.FormatInt(ms, 10)
+		},
+		GlobalDefault: func(sv *settings.Values) string { return "0" },
+	},
 
 	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-IDLE-IN-TRANSACTION-SESSION-TIMEOUT
 	// See also issue #5924.
@@ -877,7 +885,7 @@ var varGen = map[string]sessionVar{
 	`row_security`: makeCompatBoolVar(`row_security`, false, true /* anyAllowed */),
 
 	`statement_timeout`: {
-		GetStringVal: stmtTimeoutVarGetStringVal,
+		GetStringVal: makeTimeoutVarGetter(`statement_timeout`),
 		Set:          stmtTimeoutVarSet,
 		Get: func(evalCtx *extendedEvalContext) string {
 			ms := evalCtx.SessionData.StmtTimeout.Nanoseconds() / int64(time.Millisecond)
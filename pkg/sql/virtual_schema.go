@@ -236,6 +236,7 @@ func (v virtualSchemaView) initVirtualTableDesc(
 		nil,   /* semaCtx */
 		nil,   /* evalCtx */
 		false, /* temporary */
+		false, /* materialized */
 	)
 	return mutDesc.TableDescriptor, err
 }
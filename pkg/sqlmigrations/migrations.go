@@ -312,6 +312,27 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "add CREATEROLE privilege to admin/root",
 		workFn: addCreateRoleToAdminAndRoot,
 	},
+	{
+		// Introduced in v20.2.
+		name:                "create system.scheduled_jobs table",
+		workFn:              createScheduledJobsTable,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionScheduledJobs),
+		newDescriptorIDs:    staticIDs(keys.ScheduledJobsTableID),
+	},
+	{
+		// Introduced in v20.2.
+		name:                "create system.job_history table",
+		workFn:              createJobHistoryTable,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionJobHistory),
+		newDescriptorIDs:    staticIDs(keys.JobHistoryTableID),
+	},
+	{
+		// Introduced in v20.2.
+		name:                "create system.advisory_locks table",
+		workFn:              createAdvisoryLocksTable,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionAdvisoryLocks),
+		newDescriptorIDs:    staticIDs(keys.AdvisoryLocksTableID),
+	},
 }
 
 func staticIDs(
@@ -1433,6 +1454,18 @@ func createStatementInfoSystemTables(ctx context.Context, r runner) error {
 	return nil
 }
 
+func createScheduledJobsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.ScheduledJobsTable)
+}
+
+func createJobHistoryTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.JobHistoryTable)
+}
+
+func createAdvisoryLocksTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.AdvisoryLocksTable)
+}
+
 // SettingsDefaultOverrides documents the effect of several migrations that add
 // an explicit value for a setting, effectively changing the "default value"
 // from what was defined in code.
@@ -12,6 +12,7 @@ package cloud
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/url"
@@ -22,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -44,6 +46,7 @@ type azureStorage struct {
 }
 
 var _ ExternalStorage = &azureStorage{}
+var _ MultipartUploader = &azureStorage{}
 
 func makeAzureStorage(
 	conf *roachpb.ExternalStorage_Azure, settings *cluster.Settings,
@@ -96,14 +99,118 @@ func (s *azureStorage) WriteFile(
 }
 
 func (s *azureStorage) ReadFile(ctx context.Context, basename string) (io.ReadCloser, error) {
+	reader, _, err := s.ReadFileAt(ctx, basename, 0)
+	return reader, err
+}
+
+func (s *azureStorage) ReadFileAt(
+	ctx context.Context, basename string, offset int64,
+) (io.ReadCloser, int64, error) {
 	// https://github.com/cockroachdb/cockroach/issues/23859
 	blob := s.getBlob(basename)
-	get, err := blob.Download(ctx, 0, 0, azblob.BlobAccessConditions{}, false)
+	get, err := blob.Download(ctx, offset, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create azure reader")
+		return nil, 0, errors.Wrap(err, "failed to create azure reader")
+	}
+	size := get.ContentLength()
+	if offset != 0 {
+		size, err = fileSizeFromContentRange(get.ContentRange())
+		if err != nil {
+			_ = get.Response().Body.Close()
+			return nil, 0, errors.Wrap(err, "failed to create azure reader")
+		}
 	}
 	reader := get.Body(azblob.RetryReaderOptions{MaxRetryRequests: 3})
-	return reader, nil
+	return reader, size, nil
+}
+
+// StartMultipart implements the MultipartUploader interface. Azure block blobs
+// are uploaded as blocks which are committed at once, so there is nothing to
+// start; the upload ID only names the blocks.
+func (s *azureStorage) StartMultipart(ctx context.Context, basename string) (string, error) {
+	return uuid.MakeV4().String(), nil
+}
+
+// azureBlockID returns the ID of the block of a part of an upload. The IDs of
+// the blocks of a blob must all have the same length.
+func azureBlockID(upload *roachpb.ExternalStorageUpload, number int32) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s-%06d", upload.UploadID, number)))
+}
+
+func (s *azureStorage) UploadPart(
+	ctx context.Context, upload *roachpb.ExternalStorageUpload, number int32, content io.ReadSeeker,
+) (string, error) {
+	blockID := azureBlockID(upload, number)
+	err := contextutil.RunWithTimeout(ctx, "stage azure block", timeoutSetting.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			blob := s.getBlob(upload.Basename)
+			_, err := blob.StageBlock(ctx, blockID, content, azblob.LeaseAccessConditions{}, nil)
+			return err
+		})
+	if err != nil {
+		return "", errors.Wrapf(err, "stage block %d of file: %s", number, upload.Basename)
+	}
+	return blockID, nil
+}
+
+func (s *azureStorage) CompleteMultipart(
+	ctx context.Context, upload *roachpb.ExternalStorageUpload,
+) error {
+	blockIDs := make([]string, len(upload.Parts))
+	for i, part := range upload.Parts {
+		blockIDs[i] = part.ETag
+	}
+	err := contextutil.RunWithTimeout(ctx, "commit azure blocks", timeoutSetting.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			blob := s.getBlob(upload.Basename)
+			_, err := blob.CommitBlockList(
+				ctx, blockIDs, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{},
+			)
+			return err
+		})
+	return errors.Wrapf(err, "commit blocks of file: %s", upload.Basename)
+}
+
+// AbortMultipart implements the MultipartUploader interface. Azure has no way
+// to discard the uncommitted blocks of a blob other than committing a block
+// list, so an empty block list is committed and the empty blob is deleted.
+// If the blob already exists, it's left alone and its uncommitted blocks are
+// garbage collected by Azure after a week instead.
+func (s *azureStorage) AbortMultipart(
+	ctx context.Context, upload *roachpb.ExternalStorageUpload,
+) error {
+	err := contextutil.RunWithTimeout(ctx, "abort azure blocks", timeoutSetting.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			blob := s.getBlob(upload.Basename)
+			if _, err := blob.CommitBlockList(
+				ctx, nil /* base64BlockIDs */, azblob.BlobHTTPHeaders{}, azblob.Metadata{},
+				azblob.BlobAccessConditions{
+					ModifiedAccessConditions: azblob.ModifiedAccessConditions{IfNoneMatch: azblob.ETagAny},
+				},
+			); err != nil {
+				if isAzureBlobExistsError(err) {
+					return nil
+				}
+				return err
+			}
+			_, err := blob.Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+			return err
+		})
+	return errors.Wrapf(err, "abort blocks of file: %s", upload.Basename)
+}
+
+// isAzureBlobExistsError returns whether err is the error of a request which
+// required a blob not to exist.
+func isAzureBlobExistsError(err error) bool {
+	var storageErr azblob.StorageError
+	if !errors.As(err, &storageErr) {
+		return false
+	}
+	switch storageErr.ServiceCode() {
+	case azblob.ServiceCodeBlobAlreadyExists, azblob.ServiceCodeConditionNotMet:
+		return true
+	}
+	return false
 }
 
 func (s *azureStorage) ListFiles(ctx context.Context, patternSuffix string) ([]string, error) {
@@ -41,6 +41,16 @@ const (
 	S3EndpointParam = "AWS_ENDPOINT"
 	// S3RegionParam is the query parameter for the 'endpoint' in an S3 URI.
 	S3RegionParam = "AWS_REGION"
+	// S3AddressingStyleParam is the query parameter for the addressing style
+	// of buckets in an S3 URI: "path", "virtual" or "auto".
+	S3AddressingStyleParam = "AWS_ADDRESSING_STYLE"
+	// S3CABundleParam is the query parameter for the base64-encoded PEM root CA
+	// certificates to trust for the endpoint in an S3 URI.
+	S3CABundleParam = "AWS_CA_BUNDLE"
+
+	s3AddressingStyleAuto    = "auto"
+	s3AddressingStylePath    = "path"
+	s3AddressingStyleVirtual = "virtual"
 
 	// AzureAccountNameParam is the query parameter for account_name in an azure URI.
 	AzureAccountNameParam = "AZURE_ACCOUNT_NAME"
@@ -51,6 +61,16 @@ const (
 	// in a gs URI.
 	GoogleBillingProjectParam = "GOOGLE_BILLING_PROJECT"
 
+	// SFTPPrivateKeyParam is the query parameter for the base64-encoded PEM
+	// private key in an sftp URI.
+	SFTPPrivateKeyParam = "SFTP_PRIVATE_KEY"
+	// SFTPPrivateKeyPassphraseParam is the query parameter for the passphrase
+	// of an encrypted private key in an sftp URI.
+	SFTPPrivateKeyPassphraseParam = "SFTP_PRIVATE_KEY_PASSPHRASE"
+	// SFTPHostKeyParam is the query parameter for the public key of the server,
+	// in authorized_keys format, in an sftp URI.
+	SFTPHostKeyParam = "SFTP_HOST_KEY"
+
 	// AuthParam is the query parameter for the cluster settings named
 	// key in a URI.
 	AuthParam          = "AUTH"
@@ -79,10 +99,12 @@ const (
 
 // See SanitizeExternalStorageURI.
 var redactedQueryParams = map[string]struct{}{
-	S3SecretParam:        {},
-	S3TempTokenParam:     {},
-	AzureAccountKeyParam: {},
-	CredentialsParam:     {},
+	S3SecretParam:                 {},
+	S3TempTokenParam:              {},
+	AzureAccountKeyParam:          {},
+	CredentialsParam:              {},
+	SFTPPrivateKeyParam:           {},
+	SFTPPrivateKeyPassphraseParam: {},
 }
 
 // ErrListingUnsupported is a marker for indicating listing is unsupported.
@@ -115,6 +137,10 @@ type ExternalStorage interface {
 	// ReadFile should return a Reader for requested name.
 	ReadFile(ctx context.Context, basename string) (io.ReadCloser, error)
 
+	// ReadFileAt returns a Reader for requested name reading at offset.
+	// It also returns the size of the whole file.
+	ReadFileAt(ctx context.Context, basename string, offset int64) (io.ReadCloser, int64, error)
+
 	// WriteFile should write the content to requested name.
 	WriteFile(ctx context.Context, basename string, content io.ReadSeeker) error
 
@@ -157,8 +183,16 @@ func ExternalStorageConfFromURI(path string) (roachpb.ExternalStorage, error) {
 			Endpoint:  uri.Query().Get(S3EndpointParam),
 			Region:    uri.Query().Get(S3RegionParam),
 			Auth:      uri.Query().Get(AuthParam),
+			CABundle:  uri.Query().Get(S3CABundleParam),
 			/* NB: additions here should also update s3QueryParams() serializer */
 		}
+		switch style := uri.Query().Get(S3AddressingStyleParam); style {
+		case "", s3AddressingStyleAuto:
+		case s3AddressingStylePath, s3AddressingStyleVirtual:
+			conf.S3Config.AddressingStyle = style
+		default:
+			return conf, errors.Errorf("unsupported value %q for %s", style, S3AddressingStyleParam)
+		}
 		conf.S3Config.Prefix = strings.TrimLeft(conf.S3Config.Prefix, "/")
 		// AWS secrets often contain + characters, which must be escaped when
 		// included in a query string; otherwise, they represent a space character.
@@ -195,6 +229,29 @@ func ExternalStorageConfFromURI(path string) (roachpb.ExternalStorage, error) {
 			return conf, errors.Errorf("azure uri missing %q parameter", AzureAccountKeyParam)
 		}
 		conf.AzureConfig.Prefix = strings.TrimLeft(conf.AzureConfig.Prefix, "/")
+	case "sftp":
+		conf.Provider = roachpb.ExternalStorageProvider_SFTP
+		conf.SFTPConfig = &roachpb.ExternalStorage_SFTP{
+			Host:                 uri.Host,
+			User:                 uri.User.Username(),
+			Prefix:               uri.Path,
+			PrivateKey:           uri.Query().Get(SFTPPrivateKeyParam),
+			PrivateKeyPassphrase: uri.Query().Get(SFTPPrivateKeyPassphraseParam),
+			HostKey:              uri.Query().Get(SFTPHostKeyParam),
+			/* NB: additions here should also update sftpQueryParams() serializer */
+		}
+		if conf.SFTPConfig.User == "" {
+			return conf, errors.Errorf("sftp uri missing user")
+		}
+		if conf.SFTPConfig.PrivateKey == "" {
+			return conf, errors.Errorf("sftp uri missing %q parameter", SFTPPrivateKeyParam)
+		}
+		if conf.SFTPConfig.HostKey == "" {
+			return conf, errors.Errorf("sftp uri missing %q parameter", SFTPHostKeyParam)
+		}
+		// Like AWS secrets, base64-encoded keys never contain spaces, which are
+		// + characters that weren't escaped in the query string.
+		conf.SFTPConfig.PrivateKey = strings.Replace(conf.SFTPConfig.PrivateKey, " ", "+", -1)
 	case "http", "https":
 		conf.Provider = roachpb.ExternalStorageProvider_Http
 		conf.HttpPath.BaseUri = path
@@ -305,6 +362,9 @@ func MakeExternalStorage(
 	case roachpb.ExternalStorageProvider_Workload:
 		telemetry.Count("external-io.workload")
 		return makeWorkloadStorage(dest.WorkloadConfig)
+	case roachpb.ExternalStorageProvider_SFTP:
+		telemetry.Count("external-io.sftp")
+		return makeSFTPStorage(ctx, dest.SFTPConfig, settings)
 	}
 	return nil, errors.Errorf("unsupported external destination type: %s", dest.Provider.String())
 }
@@ -26,10 +26,12 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/blobs"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/workload"
 	"github.com/cockroachdb/cockroach/pkg/workload/bank"
+	"github.com/cockroachdb/errors"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2/google"
@@ -164,6 +166,102 @@ func testExportStoreWithExternalIOConfig(
 			t.Fatal(err)
 		}
 	})
+	t.Run("read-file-at", func(t *testing.T) {
+		const testingFilename = "ranged"
+		payload := []byte("0123456789")
+		if err := s.WriteFile(ctx, testingFilename, bytes.NewReader(payload)); err != nil {
+			t.Fatal(err)
+		}
+		for _, offset := range []int64{0, 4, 9} {
+			r, size, err := s.ReadFileAt(ctx, testingFilename, offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := ioutil.ReadAll(r)
+			_ = r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != int64(len(payload)) {
+				t.Errorf("size mismatch at offset %d, got %d, expected %d", offset, size, len(payload))
+			}
+			if !bytes.Equal(res, payload[offset:]) {
+				t.Errorf("got %q at offset %d, expected %q", res, offset, payload[offset:])
+			}
+		}
+		if err := s.Delete(ctx, testingFilename); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("write-file-resumable", func(t *testing.T) {
+		const testingFilename = "resumable"
+		// 5MiB is the smallest part size S3 allows.
+		testingContent := make([]byte, 11<<20)
+		if _, err := rand.Read(testingContent); err != nil {
+			t.Fatal(err)
+		}
+		upload := roachpb.ExternalStorageUpload{PartSize: 5 << 20}
+		var checkpoints int
+		if err := WriteFileResumable(ctx, s, testingFilename, bytes.NewReader(testingContent), &upload,
+			func(context.Context) error {
+				checkpoints++
+				return nil
+			}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.(MultipartUploader); ok && checkpoints != 5 {
+			t.Errorf("expected a checkpoint after start, each of 3 parts and completion, got %d", checkpoints)
+		}
+		if upload.UploadID != "" {
+			t.Errorf("expected the upload to be reset, got %+v", upload)
+		}
+		r, err := s.ReadFile(ctx, testingFilename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(content, testingContent) {
+			t.Fatalf("wrong content")
+		}
+		if err := s.Delete(ctx, testingFilename); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("abort-resumable-upload", func(t *testing.T) {
+		if _, ok := s.(MultipartUploader); !ok {
+			t.Skip("storage doesn't support multipart uploads")
+		}
+		const testingFilename = "aborted"
+		testingContent := make([]byte, 11<<20)
+		if _, err := rand.Read(testingContent); err != nil {
+			t.Fatal(err)
+		}
+		upload := roachpb.ExternalStorageUpload{PartSize: 5 << 20}
+		stop := errors.New("stop")
+		if err := WriteFileResumable(ctx, s, testingFilename, bytes.NewReader(testingContent), &upload,
+			func(context.Context) error {
+				if len(upload.Parts) == 1 {
+					return stop
+				}
+				return nil
+			}); !errors.Is(err, stop) {
+			t.Fatalf("expected the upload to stop after a part, got %v", err)
+		}
+		if err := AbortResumableUpload(ctx, s, &upload); err != nil {
+			t.Fatal(err)
+		}
+		if upload.UploadID != "" || upload.PartSize != 5<<20 {
+			t.Errorf("expected the upload to be reset, got %+v", upload)
+		}
+		if r, err := s.ReadFile(ctx, testingFilename); err == nil {
+			_ = r.Close()
+			t.Fatal("expected the aborted file not to exist")
+		}
+	})
 	if skipSingleFile {
 		return
 	}
@@ -17,6 +17,7 @@ import (
 	"io"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 
 	gcs "cloud.google.com/go/storage"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/iterator"
@@ -55,6 +57,7 @@ type gcsStorage struct {
 }
 
 var _ ExternalStorage = &gcsStorage{}
+var _ MultipartUploader = &gcsStorage{}
 
 func (g *gcsStorage) Conf() roachpb.ExternalStorage {
 	return roachpb.ExternalStorage{
@@ -231,6 +234,120 @@ func (g *gcsStorage) ReadFile(ctx context.Context, basename string) (io.ReadClos
 	}, nil
 }
 
+func (g *gcsStorage) ReadFileAt(
+	ctx context.Context, basename string, offset int64,
+) (io.ReadCloser, int64, error) {
+	// https://github.com/cockroachdb/cockroach/issues/23859
+	r := &resumingGoogleStorageReader{
+		ctx:    ctx,
+		bucket: g.bucket,
+		object: path.Join(g.prefix, basename),
+		pos:    offset,
+	}
+	if err := r.openStream(); err != nil {
+		return nil, 0, err
+	}
+	return r, r.data.Attrs.Size, nil
+}
+
+// gcsMaxComposeSources is the maximum number of objects which can be composed
+// into one.
+const gcsMaxComposeSources = 32
+
+// StartMultipart implements the MultipartUploader interface. GCS has no
+// multipart uploads as such: the parts are uploaded as objects of their own,
+// which are composed into the file once they are all uploaded. The upload ID
+// only names these objects.
+func (g *gcsStorage) StartMultipart(ctx context.Context, basename string) (string, error) {
+	return uuid.MakeV4().String(), nil
+}
+
+// partObject returns the handle of the object holding a part of an upload, or
+// of an intermediate object of the composition if number is negative.
+func (g *gcsStorage) partObject(
+	upload *roachpb.ExternalStorageUpload, number int32,
+) *gcs.ObjectHandle {
+	name := fmt.Sprintf("%s.upload-%s.part-%06d", upload.Basename, upload.UploadID, number)
+	if number < 0 {
+		name = fmt.Sprintf("%s.upload-%s.compose-%06d", upload.Basename, upload.UploadID, -number)
+	}
+	return g.bucket.Object(path.Join(g.prefix, name))
+}
+
+func (g *gcsStorage) UploadPart(
+	ctx context.Context, upload *roachpb.ExternalStorageUpload, number int32, content io.ReadSeeker,
+) (string, error) {
+	var generation int64
+	err := contextutil.RunWithTimeout(ctx, "put gcs part", timeoutSetting.Get(&g.settings.SV),
+		func(ctx context.Context) error {
+			w := g.partObject(upload, number).NewWriter(ctx)
+			if _, err := io.Copy(w, content); err != nil {
+				_ = w.Close()
+				return err
+			}
+			if err := w.Close(); err != nil {
+				return err
+			}
+			generation = w.Attrs().Generation
+			return nil
+		})
+	if err != nil {
+		return "", errors.Wrapf(err, "write part %d to google cloud", number)
+	}
+	return strconv.FormatInt(generation, 10), nil
+}
+
+func (g *gcsStorage) CompleteMultipart(
+	ctx context.Context, upload *roachpb.ExternalStorageUpload,
+) error {
+	var srcs, uploaded []*gcs.ObjectHandle
+	for _, part := range upload.Parts {
+		generation, err := strconv.ParseInt(part.ETag, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid generation of part %d", part.Number)
+		}
+		// Compose the very generation of the part which was uploaded.
+		srcs = append(srcs, g.partObject(upload, part.Number).Generation(generation))
+		uploaded = append(uploaded, g.partObject(upload, part.Number))
+	}
+
+	err := contextutil.RunWithTimeout(ctx, "compose gcs parts", timeoutSetting.Get(&g.settings.SV),
+		func(ctx context.Context) error {
+			// Parts are composed in batches, each batch into an intermediate object
+			// which is the first source of the next batch.
+			for i := int32(1); len(srcs) > gcsMaxComposeSources; i++ {
+				intermediate := g.partObject(upload, -i)
+				if _, err := intermediate.ComposerFrom(srcs[:gcsMaxComposeSources]...).Run(ctx); err != nil {
+					return err
+				}
+				uploaded = append(uploaded, intermediate)
+				srcs = append([]*gcs.ObjectHandle{intermediate}, srcs[gcsMaxComposeSources:]...)
+			}
+			dst := g.bucket.Object(path.Join(g.prefix, upload.Basename))
+			_, err := dst.ComposerFrom(srcs...).Run(ctx)
+			return err
+		})
+	if err != nil {
+		return errors.Wrap(err, "compose parts in google cloud")
+	}
+
+	for _, o := range uploaded {
+		if err := o.Delete(ctx); err != nil && err != gcs.ErrObjectNotExist {
+			log.Warningf(ctx, "failed to delete uploaded part %s: %v", o.ObjectName(), err)
+		}
+	}
+	return nil
+}
+
+func (g *gcsStorage) AbortMultipart(ctx context.Context, upload *roachpb.ExternalStorageUpload) error {
+	for _, part := range upload.Parts {
+		if err := g.partObject(upload, part.Number).Delete(ctx); err != nil && err != gcs.ErrObjectNotExist {
+			return errors.Wrapf(err, "delete part %d from google cloud", part.Number)
+		}
+	}
+	return nil
+}
+
 func (g *gcsStorage) ListFiles(ctx context.Context, patternSuffix string) ([]string, error) {
 	var fileList []string
 	it := g.bucket.Objects(ctx, &gcs.Query{
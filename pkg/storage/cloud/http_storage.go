@@ -58,17 +58,25 @@ var httpRetryOptions = retry.Options{
 	Multiplier:     4,
 }
 
-func makeHTTPClient(settings *cluster.Settings) (*http.Client, error) {
+// makeHTTPClient makes a client which trusts the CA of the
+// cloudstorage.http.custom_ca setting, as well as those in the PEM encoded
+// extraCA if not empty, on top of the system's.
+func makeHTTPClient(settings *cluster.Settings, extraCA string) (*http.Client, error) {
 	var tlsConf *tls.Config
-	if pem := httpCustomCA.Get(&settings.SV); pem != "" {
-		roots, err := x509.SystemCertPool()
-		if err != nil {
-			return nil, errors.Wrap(err, "could not load system root CA pool")
+	for _, pem := range []string{httpCustomCA.Get(&settings.SV), extraCA} {
+		if pem == "" {
+			continue
+		}
+		if tlsConf == nil {
+			roots, err := x509.SystemCertPool()
+			if err != nil {
+				return nil, errors.Wrap(err, "could not load system root CA pool")
+			}
+			tlsConf = &tls.Config{RootCAs: roots}
 		}
-		if !roots.AppendCertsFromPEM([]byte(pem)) {
+		if !tlsConf.RootCAs.AppendCertsFromPEM([]byte(pem)) {
 			return nil, errors.Errorf("failed to parse root CA certificate from %q", pem)
 		}
-		tlsConf = &tls.Config{RootCAs: roots}
 	}
 	// Copy the defaults from http.DefaultTransport. We cannot just copy the
 	// entire struct because it has a sync Mutex. This has the unfortunate problem
@@ -93,7 +101,7 @@ func makeHTTPStorage(base string, settings *cluster.Settings) (ExternalStorage,
 		return nil, errors.Errorf("HTTP storage requested but base path not provided")
 	}
 
-	client, err := makeHTTPClient(settings)
+	client, err := makeHTTPClient(settings, "" /* extraCA */)
 	if err != nil {
 		return nil, err
 	}
@@ -129,23 +137,44 @@ type resumingHTTPReader struct {
 
 var _ io.ReadCloser = &resumingHTTPReader{}
 
+// newResumingHTTPReader returns a reader of url starting at pos, along with
+// the size of the whole file (-1 if the server didn't report it).
 func newResumingHTTPReader(
-	ctx context.Context, client *httpStorage, url string,
-) (*resumingHTTPReader, error) {
+	ctx context.Context, client *httpStorage, url string, pos int64,
+) (*resumingHTTPReader, int64, error) {
 	r := &resumingHTTPReader{
 		ctx:    ctx,
 		client: client,
 		url:    url,
+		pos:    pos,
 	}
 
-	resp, err := r.sendRequest(nil)
+	var headers map[string]string
+	if pos > 0 {
+		headers = map[string]string{"Range": fmt.Sprintf("bytes=%d-", pos)}
+	}
+	resp, err := r.sendRequest(headers)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	size := resp.ContentLength
+	if pos > 0 {
+		h := resp.Header.Get("Content-Range")
+		err = checkHTTPContentRangeHeader(h, pos)
+		if err == nil {
+			size, err = fileSizeFromContentRange(h)
+		}
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, 0, err
+		}
 	}
 
-	r.canResume = resp.Header.Get("Accept-Ranges") == "bytes"
+	// A server which honored a range request can resume downloads.
+	r.canResume = pos > 0 || resp.Header.Get("Accept-Ranges") == "bytes"
 	r.body = resp.Body
-	return r, nil
+	return r, size, nil
 }
 
 func (r *resumingHTTPReader) Close() error {
@@ -182,6 +211,20 @@ func checkHTTPContentRangeHeader(h string, pos int64) error {
 	return nil
 }
 
+// fileSizeFromContentRange returns the size of the whole file from a
+// Content-Range header, e.g. 10 for "bytes 5-9/10".
+func fileSizeFromContentRange(h string) (int64, error) {
+	slash := strings.LastIndexByte(h, '/')
+	if slash < 0 {
+		return 0, errors.Errorf("malformed Content-Range header: %s", h)
+	}
+	size, err := strconv.ParseInt(h[slash+1:], 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("malformed size in Content-Range header: %s", h)
+	}
+	return size, nil
+}
+
 func (r *resumingHTTPReader) sendRequest(
 	reqHeaders map[string]string,
 ) (resp *http.Response, err error) {
@@ -253,8 +296,19 @@ func (r *resumingHTTPReader) Read(p []byte) (n int, err error) {
 }
 
 func (h *httpStorage) ReadFile(ctx context.Context, basename string) (io.ReadCloser, error) {
+	reader, _, err := h.ReadFileAt(ctx, basename, 0)
+	return reader, err
+}
+
+func (h *httpStorage) ReadFileAt(
+	ctx context.Context, basename string, offset int64,
+) (io.ReadCloser, int64, error) {
 	// https://github.com/cockroachdb/cockroach/issues/23859
-	return newResumingHTTPReader(ctx, h, basename)
+	reader, size, err := newResumingHTTPReader(ctx, h, basename, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reader, size, nil
 }
 
 func (h *httpStorage) WriteFile(ctx context.Context, basename string, content io.ReadSeeker) error {
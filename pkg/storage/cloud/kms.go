@@ -0,0 +1,73 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/errors"
+)
+
+// KMS provides an API to interact with a key management service, which holds a
+// master key that is used to encrypt and decrypt the data keys of files stored
+// outside of cockroach, for example by BACKUP/RESTORE.
+type KMS interface {
+	// MasterKeyID returns an identifier of the master key used by this KMS. It
+	// is the same for all the URIs which refer to the same master key.
+	MasterKeyID() (string, error)
+	// Encrypt encrypts data with the master key.
+	Encrypt(ctx context.Context, data []byte) ([]byte, error)
+	// Decrypt decrypts data which was encrypted with the master key.
+	Decrypt(ctx context.Context, data []byte) ([]byte, error)
+
+	Close() error
+}
+
+// KMSFromURIFactory describes a factory function for a KMS given a URI.
+type KMSFromURIFactory func(ctx context.Context, uri string, settings *cluster.Settings) (KMS, error)
+
+var kmsFactoriesByScheme = make(map[string]KMSFromURIFactory)
+
+// RegisterKMSFromURIFactory registers the factory used to make a KMS for URIs
+// of the given scheme. It is meant to be called from init functions.
+func RegisterKMSFromURIFactory(scheme string, factory KMSFromURIFactory) {
+	if _, ok := kmsFactoriesByScheme[scheme]; ok {
+		panic(errors.AssertionFailedf("KMS factory for scheme %q already registered", scheme))
+	}
+	kmsFactoriesByScheme[scheme] = factory
+}
+
+// KMSFromURI returns a KMS for the given URI, made by the factory registered
+// for its scheme.
+func KMSFromURI(ctx context.Context, uri string, settings *cluster.Settings) (KMS, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	factory, ok := kmsFactoriesByScheme[parsed.Scheme]
+	if !ok {
+		err := errors.Errorf("unsupported KMS scheme: %q", parsed.Scheme)
+		if len(kmsFactoriesByScheme) == 0 {
+			return nil, errors.WithHint(err, "no KMS is available in this build")
+		}
+		schemes := make([]string, 0, len(kmsFactoriesByScheme))
+		for scheme := range kmsFactoriesByScheme {
+			schemes = append(schemes, scheme)
+		}
+		sort.Strings(schemes)
+		return nil, errors.WithHintf(err, "supported schemes: %s", strings.Join(schemes, ", "))
+	}
+	return factory(ctx, uri, settings)
+}
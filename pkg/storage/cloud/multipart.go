@@ -0,0 +1,167 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// MultipartUploader is implemented by ExternalStorage providers which can
+// upload a file in parts. Each part can be retried on its own, and an upload
+// that was interrupted can be resumed with the state of the upload. Use
+// WriteFileResumable rather than calling these methods directly.
+type MultipartUploader interface {
+	// StartMultipart starts an upload of the named file and returns its ID.
+	StartMultipart(ctx context.Context, basename string) (string, error)
+
+	// UploadPart uploads the numbered part of an upload. It returns the ETag
+	// which identifies the part when the upload is completed.
+	UploadPart(
+		ctx context.Context, upload *roachpb.ExternalStorageUpload, number int32, content io.ReadSeeker,
+	) (string, error)
+
+	// CompleteMultipart assembles the uploaded parts into the file.
+	CompleteMultipart(ctx context.Context, upload *roachpb.ExternalStorageUpload) error
+
+	// AbortMultipart discards the parts of an upload which won't be completed.
+	AbortMultipart(ctx context.Context, upload *roachpb.ExternalStorageUpload) error
+}
+
+// defaultMultipartPartSize is the part size of uploads which don't specify
+// one. It is well above the 5MiB minimum of S3.
+const defaultMultipartPartSize = 64 << 20
+
+// WriteFileResumable writes content to the named file, uploading it in parts
+// if the storage is a MultipartUploader, or with WriteFile otherwise.
+//
+// upload is the state of the upload. If it describes an upload of the same
+// file which was interrupted, that upload is resumed from its last uploaded
+// part, which requires content to be the same as when it was started;
+// otherwise a new upload is started, with upload.PartSize sized parts if set.
+// Files no larger than a single part are written with WriteFile.
+// checkpoint, if not nil, is called whenever upload changes so that the caller
+// can persist it. Once the file is written, upload is reset.
+func WriteFileResumable(
+	ctx context.Context,
+	es ExternalStorage,
+	basename string,
+	content io.ReadSeeker,
+	upload *roachpb.ExternalStorageUpload,
+	checkpoint func(context.Context) error,
+) error {
+	uploader, ok := es.(MultipartUploader)
+	if !ok {
+		return es.WriteFile(ctx, basename, content)
+	}
+	if checkpoint == nil {
+		checkpoint = func(context.Context) error { return nil }
+	}
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	if upload.UploadID != "" && (upload.Basename != basename || upload.FileSize != size) {
+		// The upload is of some other file, which won't be resumed anymore.
+		if err := uploader.AbortMultipart(ctx, upload); err != nil {
+			log.Warningf(ctx, "failed to abort upload of %s: %v", upload.Basename, err)
+		}
+		*upload = roachpb.ExternalStorageUpload{PartSize: upload.PartSize}
+	}
+	if upload.UploadID == "" {
+		partSize := upload.PartSize
+		if partSize <= 0 {
+			partSize = defaultMultipartPartSize
+		}
+		if size <= partSize {
+			// A file which fits in a single part is cheaper to write at once.
+			if _, err := content.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			return es.WriteFile(ctx, basename, content)
+		}
+		id, err := uploader.StartMultipart(ctx, basename)
+		if err != nil {
+			return err
+		}
+		*upload = roachpb.ExternalStorageUpload{
+			Basename: basename, UploadID: id, PartSize: partSize, FileSize: size,
+		}
+		if err := checkpoint(ctx); err != nil {
+			return err
+		}
+	}
+
+	var offset int64
+	for _, part := range upload.Parts {
+		offset += part.Length
+	}
+	if _, err := content.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	buf := make([]byte, upload.PartSize)
+	for offset < size {
+		n := upload.PartSize
+		if rest := size - offset; rest < n {
+			n = rest
+		}
+		if _, err := io.ReadFull(content, buf[:n]); err != nil {
+			return errors.Wrapf(err, "reading part at offset %d", offset)
+		}
+		number := int32(len(upload.Parts) + 1)
+		var etag string
+		if err := delayedRetry(ctx, func() error {
+			var err error
+			etag, err = uploader.UploadPart(ctx, upload, number, bytes.NewReader(buf[:n]))
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "uploading part %d of %s", number, basename)
+		}
+		upload.Parts = append(upload.Parts, roachpb.ExternalStorageUpload_Part{
+			Number: number, ETag: etag, Length: n,
+		})
+		offset += n
+		if err := checkpoint(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := delayedRetry(ctx, func() error {
+		return uploader.CompleteMultipart(ctx, upload)
+	}); err != nil {
+		return errors.Wrapf(err, "completing upload of %s", basename)
+	}
+	*upload = roachpb.ExternalStorageUpload{PartSize: upload.PartSize}
+	return checkpoint(ctx)
+}
+
+// AbortResumableUpload discards the parts of an upload started by
+// WriteFileResumable which won't be resumed, and resets upload. It does
+// nothing if upload wasn't started or the storage isn't a MultipartUploader.
+func AbortResumableUpload(
+	ctx context.Context, es ExternalStorage, upload *roachpb.ExternalStorageUpload,
+) error {
+	uploader, ok := es.(MultipartUploader)
+	if !ok || upload.UploadID == "" {
+		return nil
+	}
+	if err := uploader.AbortMultipart(ctx, upload); err != nil {
+		return errors.Wrapf(err, "aborting upload of %s", upload.Basename)
+	}
+	*upload = roachpb.ExternalStorageUpload{PartSize: upload.PartSize}
+	return nil
+}
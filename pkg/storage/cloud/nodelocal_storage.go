@@ -78,7 +78,14 @@ func (l *localFileStorage) WriteFile(
 }
 
 func (l *localFileStorage) ReadFile(ctx context.Context, basename string) (io.ReadCloser, error) {
-	return l.blobClient.ReadFile(ctx, joinRelativePath(l.base, basename))
+	reader, _, err := l.ReadFileAt(ctx, basename, 0)
+	return reader, err
+}
+
+func (l *localFileStorage) ReadFileAt(
+	ctx context.Context, basename string, offset int64,
+) (io.ReadCloser, int64, error) {
+	return l.blobClient.ReadFile(ctx, joinRelativePath(l.base, basename), offset)
 }
 
 func (l *localFileStorage) ListFiles(ctx context.Context, patternSuffix string) ([]string, error) {
@@ -12,6 +12,8 @@ package cloud
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"io"
 	"net/url"
 	"path"
@@ -37,6 +39,7 @@ type s3Storage struct {
 }
 
 var _ ExternalStorage = &s3Storage{}
+var _ MultipartUploader = &s3Storage{}
 
 func s3QueryParams(conf *roachpb.ExternalStorage_S3) string {
 	q := make(url.Values)
@@ -51,6 +54,8 @@ func s3QueryParams(conf *roachpb.ExternalStorage_S3) string {
 	setIf(S3EndpointParam, conf.Endpoint)
 	setIf(S3RegionParam, conf.Region)
 	setIf(AuthParam, conf.Auth)
+	setIf(S3AddressingStyleParam, conf.AddressingStyle)
+	setIf(S3CABundleParam, conf.CABundle)
 
 	return q.Encode()
 }
@@ -72,10 +77,18 @@ func makeS3Storage(
 				"custom endpoints disallowed for s3 due to --external-io-disable-http flag")
 		}
 		config.Endpoint = &conf.Endpoint
+		// S3-compatible stores often have no notion of regions, but requests
+		// must still be signed with one.
 		if conf.Region == "" {
 			region = "default-region"
 		}
-		client, err := makeHTTPClient(settings)
+	}
+	ca, err := base64.StdEncoding.DecodeString(conf.CABundle)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding value of %s", S3CABundleParam)
+	}
+	if conf.Endpoint != "" || len(ca) > 0 {
+		client, err := makeHTTPClient(settings, string(ca))
 		if err != nil {
 			return nil, err
 		}
@@ -112,6 +125,9 @@ func makeS3Storage(
 				"implicit credentials disallowed for s3 due to --external-io-implicit-credentials flag")
 		}
 		opts.SharedConfigState = session.SharedConfigEnable
+		// The endpoint and its client still apply, but not the credentials.
+		config.Credentials = nil
+		opts.Config.MergeIn(config)
 	default:
 		return nil, errors.Errorf("unsupported value %s for %s", conf.Auth, AuthParam)
 	}
@@ -120,6 +136,13 @@ func makeS3Storage(
 	if err != nil {
 		return nil, errors.Wrap(err, "new aws session")
 	}
+	if len(ca) > 0 {
+		// The session trusts only the CAs of the AWS_CA_BUNDLE environment
+		// variable if it is set, but the bundle of the URI is more specific.
+		if sess.Config.HTTPClient, err = makeHTTPClient(settings, string(ca)); err != nil {
+			return nil, err
+		}
+	}
 	if region == "" {
 		err = delayedRetry(ctx, func() error {
 			var err error
@@ -131,8 +154,15 @@ func makeS3Storage(
 		}
 	}
 	sess.Config.Region = aws.String(region)
-	if conf.Endpoint != "" {
+	switch conf.AddressingStyle {
+	case s3AddressingStylePath:
 		sess.Config.S3ForcePathStyle = aws.Bool(true)
+	case s3AddressingStyleVirtual:
+	default:
+		// Stores behind custom endpoints rarely have DNS entries for buckets.
+		if conf.Endpoint != "" {
+			sess.Config.S3ForcePathStyle = aws.Bool(true)
+		}
 	}
 	return &s3Storage{
 		bucket:   aws.String(conf.Bucket),
@@ -165,15 +195,113 @@ func (s *s3Storage) WriteFile(ctx context.Context, basename string, content io.R
 }
 
 func (s *s3Storage) ReadFile(ctx context.Context, basename string) (io.ReadCloser, error) {
+	reader, _, err := s.ReadFileAt(ctx, basename, 0)
+	return reader, err
+}
+
+func (s *s3Storage) ReadFileAt(
+	ctx context.Context, basename string, offset int64,
+) (io.ReadCloser, int64, error) {
 	// https://github.com/cockroachdb/cockroach/issues/23859
-	out, err := s.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+	input := &s3.GetObjectInput{
 		Bucket: s.bucket,
 		Key:    aws.String(path.Join(s.prefix, basename)),
-	})
+	}
+	if offset != 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+	out, err := s.s3.GetObjectWithContext(ctx, input)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get s3 object")
+		return nil, 0, errors.Wrap(err, "failed to get s3 object")
 	}
-	return out.Body, nil
+	size := aws.Int64Value(out.ContentLength)
+	if offset != 0 {
+		size, err = fileSizeFromContentRange(aws.StringValue(out.ContentRange))
+		if err != nil {
+			_ = out.Body.Close()
+			return nil, 0, errors.Wrap(err, "failed to get s3 object")
+		}
+	}
+	return out.Body, size, nil
+}
+
+func (s *s3Storage) StartMultipart(ctx context.Context, basename string) (string, error) {
+	var out *s3.CreateMultipartUploadOutput
+	err := contextutil.RunWithTimeout(ctx, "create s3 multipart upload",
+		timeoutSetting.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			var err error
+			out, err = s.s3.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+				Bucket: s.bucket,
+				Key:    aws.String(path.Join(s.prefix, basename)),
+			})
+			return err
+		})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create s3 multipart upload")
+	}
+	return aws.StringValue(out.UploadId), nil
+}
+
+func (s *s3Storage) UploadPart(
+	ctx context.Context, upload *roachpb.ExternalStorageUpload, number int32, content io.ReadSeeker,
+) (string, error) {
+	var out *s3.UploadPartOutput
+	err := contextutil.RunWithTimeout(ctx, "upload s3 part",
+		timeoutSetting.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			var err error
+			out, err = s.s3.UploadPartWithContext(ctx, &s3.UploadPartInput{
+				Bucket:     s.bucket,
+				Key:        aws.String(path.Join(s.prefix, upload.Basename)),
+				UploadId:   aws.String(upload.UploadID),
+				PartNumber: aws.Int64(int64(number)),
+				Body:       content,
+			})
+			return err
+		})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to upload s3 part")
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+func (s *s3Storage) CompleteMultipart(
+	ctx context.Context, upload *roachpb.ExternalStorageUpload,
+) error {
+	parts := make([]*s3.CompletedPart, len(upload.Parts))
+	for i, part := range upload.Parts {
+		parts[i] = &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(int64(part.Number)),
+		}
+	}
+	err := contextutil.RunWithTimeout(ctx, "complete s3 multipart upload",
+		timeoutSetting.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			_, err := s.s3.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+				Bucket:          s.bucket,
+				Key:             aws.String(path.Join(s.prefix, upload.Basename)),
+				UploadId:        aws.String(upload.UploadID),
+				MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+			})
+			return err
+		})
+	return errors.Wrap(err, "failed to complete s3 multipart upload")
+}
+
+func (s *s3Storage) AbortMultipart(ctx context.Context, upload *roachpb.ExternalStorageUpload) error {
+	err := contextutil.RunWithTimeout(ctx, "abort s3 multipart upload",
+		timeoutSetting.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			_, err := s.s3.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   s.bucket,
+				Key:      aws.String(path.Join(s.prefix, upload.Basename)),
+				UploadId: aws.String(upload.UploadID),
+			})
+			return err
+		})
+	return errors.Wrap(err, "failed to abort s3 multipart upload")
 }
 
 func getPrefixBeforeWildcard(p string) string {
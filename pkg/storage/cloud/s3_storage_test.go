@@ -11,10 +11,19 @@
 package cloud
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -23,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/blobs"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -152,3 +162,241 @@ func TestS3DisallowImplicitCredentials(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "implicit"))
 }
+
+// fakeS3 is an in-memory S3 server which supports just enough of the API
+// for s3Storage: reading, writing and deleting objects, and multipart uploads.
+type fakeS3 struct {
+	syncutil.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	// failParts makes the upload of the given part numbers fail that many
+	// times.
+	failParts map[int]int
+	// uploadedParts are the numbers of the parts uploaded successfully.
+	uploadedParts []int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	// Requests are path-style: /bucket/key.
+	key := r.URL.Path
+	q := r.URL.Query()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	writeXML := func(v interface{}) {
+		if err := xml.NewEncoder(w).Encode(v); err != nil {
+			http.Error(w, err.Error(), 500)
+		}
+	}
+	s3Error := func(status int, code string) {
+		w.WriteHeader(status)
+		writeXML(struct {
+			XMLName xml.Name `xml:"Error"`
+			Code    string
+		}{Code: code})
+	}
+
+	_, initiate := q["uploads"]
+	switch {
+	case r.Method == "POST" && initiate:
+		id := strconv.Itoa(len(f.uploads) + 1)
+		f.uploads[id] = make(map[int][]byte)
+		writeXML(struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			UploadID string   `xml:"UploadId"`
+		}{UploadID: id})
+	case r.Method == "PUT" && q.Get("uploadId") != "":
+		parts, ok := f.uploads[q.Get("uploadId")]
+		if !ok {
+			s3Error(404, "NoSuchUpload")
+			return
+		}
+		number, _ := strconv.Atoi(q.Get("partNumber"))
+		if f.failParts[number] > 0 {
+			f.failParts[number]--
+			s3Error(400, "InjectedFailure")
+			return
+		}
+		parts[number] = body
+		f.uploadedParts = append(f.uploadedParts, number)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, number))
+	case r.Method == "POST" && q.Get("uploadId") != "":
+		parts, ok := f.uploads[q.Get("uploadId")]
+		if !ok {
+			s3Error(404, "NoSuchUpload")
+			return
+		}
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			s3Error(400, "MalformedXML")
+			return
+		}
+		var content []byte
+		for i, part := range complete.Parts {
+			if part.PartNumber != i+1 || part.ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+				s3Error(400, "InvalidPart")
+				return
+			}
+			content = append(content, parts[part.PartNumber]...)
+		}
+		f.objects[key] = content
+		delete(f.uploads, q.Get("uploadId"))
+		writeXML(struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		}{})
+	case r.Method == "DELETE" && q.Get("uploadId") != "":
+		delete(f.uploads, q.Get("uploadId"))
+		w.WriteHeader(204)
+	case r.Method == "PUT":
+		f.objects[key] = body
+	case r.Method == "GET" || r.Method == "HEAD":
+		content, ok := f.objects[key]
+		if !ok {
+			s3Error(404, "NoSuchKey")
+			return
+		}
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if err != nil || start >= len(content) {
+				s3Error(416, "InvalidRange")
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+			w.WriteHeader(206)
+			_, _ = w.Write(content[start:])
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if r.Method == "GET" {
+			_, _ = w.Write(content)
+		}
+	case r.Method == "DELETE":
+		delete(f.objects, key)
+		w.WriteHeader(204)
+	default:
+		s3Error(400, "NotImplemented")
+	}
+}
+
+func TestS3FakeMultipart(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	fake := &fakeS3{
+		objects:   make(map[string][]byte),
+		uploads:   make(map[string]map[int][]byte),
+		failParts: make(map[int]int),
+	}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	q := make(url.Values)
+	q.Add(S3AccessKeyParam, "key")
+	q.Add(S3SecretParam, "secret")
+	q.Add(S3EndpointParam, srv.URL)
+	u := url.URL{Scheme: "s3", Host: "bucket", Path: "backup-test", RawQuery: q.Encode()}
+
+	t.Run("export-store", func(t *testing.T) {
+		testExportStore(t, u.String(), true)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		s := storeFromURI(ctx, t, u.String(), blobs.TestEmptyBlobClientFactory)
+		defer s.Close()
+
+		content := make([]byte, 10<<10)
+		_, err := rand.Read(content)
+		require.NoError(t, err)
+
+		// The second part fails more times than it is retried, which interrupts
+		// the upload after the first part.
+		fake.Lock()
+		fake.failParts[2] = 3
+		fake.uploadedParts = nil
+		fake.Unlock()
+		upload := roachpb.ExternalStorageUpload{PartSize: 4 << 10}
+		var persisted roachpb.ExternalStorageUpload
+		checkpoint := func(context.Context) error {
+			persisted = upload
+			persisted.Parts = append([]roachpb.ExternalStorageUpload_Part(nil), upload.Parts...)
+			return nil
+		}
+		err = WriteFileResumable(ctx, s, "resumed", bytes.NewReader(content), &upload, checkpoint)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "uploading part 2 of resumed")
+		require.Len(t, persisted.Parts, 1)
+
+		// Resuming the upload from its persisted state only uploads the
+		// remaining parts.
+		upload = persisted
+		require.NoError(t,
+			WriteFileResumable(ctx, s, "resumed", bytes.NewReader(content), &upload, checkpoint))
+		fake.Lock()
+		require.Equal(t, []int{1, 2, 3}, fake.uploadedParts)
+		fake.Unlock()
+		require.Equal(t, roachpb.ExternalStorageUpload{PartSize: 4 << 10}, persisted)
+
+		r, size, err := s.ReadFileAt(ctx, "resumed", 5000)
+		require.NoError(t, err)
+		defer r.Close()
+		read, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), size)
+		require.Equal(t, content[5000:], read)
+
+		// An upload of some other file is aborted rather than resumed.
+		upload = roachpb.ExternalStorageUpload{PartSize: 4 << 10}
+		fake.Lock()
+		fake.failParts[3] = 3
+		fake.Unlock()
+		require.Error(t,
+			WriteFileResumable(ctx, s, "other", bytes.NewReader(content), &upload, checkpoint))
+		require.Len(t, fake.uploads, 1)
+		upload = persisted
+		require.NoError(t,
+			WriteFileResumable(ctx, s, "other", bytes.NewReader(content[:100]), &upload, checkpoint))
+		require.Len(t, fake.uploads, 0)
+	})
+}
+
+func TestS3CompatibleEndpoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	fake := &fakeS3{
+		objects: make(map[string][]byte),
+		uploads: make(map[string]map[int][]byte),
+	}
+	srv := httptest.NewTLSServer(fake)
+	defer srv.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	// The endpoint has no notion of regions, and its certificate is signed by a
+	// private CA.
+	q := make(url.Values)
+	q.Add(S3AccessKeyParam, "key")
+	q.Add(S3SecretParam, "secret")
+	q.Add(S3EndpointParam, srv.URL)
+	q.Add(S3AddressingStyleParam, s3AddressingStylePath)
+	u := url.URL{Scheme: "s3", Host: "bucket", Path: "backup-test", RawQuery: q.Encode()}
+
+	s := storeFromURI(ctx, t, u.String(), blobs.TestEmptyBlobClientFactory)
+	err := s.WriteFile(ctx, "file", bytes.NewReader([]byte("content")))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "certificate")
+	require.NoError(t, s.Close())
+
+	q.Add(S3CABundleParam, base64.StdEncoding.EncodeToString(ca))
+	u.RawQuery = q.Encode()
+	testExportStore(t, u.String(), true)
+}
@@ -0,0 +1,644 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Packet types, status codes and flags of version 3 of the SSH File Transfer
+// Protocol, see https://tools.ietf.org/html/draft-ietf-secsh-filexfer-02.
+const (
+	sftpPacketInit    = 1
+	sftpPacketVersion = 2
+	sftpPacketOpen    = 3
+	sftpPacketClose   = 4
+	sftpPacketRead    = 5
+	sftpPacketWrite   = 6
+	sftpPacketOpendir = 11
+	sftpPacketReaddir = 12
+	sftpPacketRemove  = 13
+	sftpPacketMkdir   = 14
+	sftpPacketStat    = 17
+	sftpPacketRename  = 18
+	sftpPacketStatus  = 101
+	sftpPacketHandle  = 102
+	sftpPacketData    = 103
+	sftpPacketName    = 104
+	sftpPacketAttrs   = 105
+
+	sftpStatusOK         = 0
+	sftpStatusEOF        = 1
+	sftpStatusNoSuchFile = 2
+
+	sftpOpenRead     = 0x01
+	sftpOpenWrite    = 0x02
+	sftpOpenCreate   = 0x08
+	sftpOpenTruncate = 0x10
+
+	sftpAttrSize        = 0x01
+	sftpAttrUIDGID      = 0x02
+	sftpAttrPermissions = 0x04
+	sftpAttrACModTime   = 0x08
+	sftpAttrExtended    = 0x80000000
+
+	// sftpModeDir is the file type bit of directories in the permissions
+	// attribute, as in stat(2).
+	sftpModeDir = 0040000
+
+	// sftpMaxData is the most data read or written by a single request. Some
+	// servers don't accept more than that.
+	sftpMaxData = 32 << 10
+	// sftpMaxPacket bounds the size of the packets which are accepted.
+	sftpMaxPacket = 256 << 10
+	// sftpMaxInflight is the number of read or write requests of a file which
+	// are sent before waiting for their responses.
+	sftpMaxInflight = 16
+)
+
+// sftpStatusError is an error status returned by an SFTP server.
+type sftpStatusError struct {
+	code uint32
+	msg  string
+}
+
+func (e *sftpStatusError) Error() string {
+	return fmt.Sprintf("sftp: %s (status %d)", e.msg, e.code)
+}
+
+// sftpHasStatus returns whether err is the given status.
+func sftpHasStatus(err error, code uint32) bool {
+	var statusErr *sftpStatusError
+	return errors.As(err, &statusErr) && statusErr.code == code
+}
+
+// sftpAttrs are the attributes of a file which the client cares about.
+type sftpAttrs struct {
+	size  int64
+	isDir bool
+}
+
+// sftpClient is a minimal client of version 3 of the SSH File Transfer
+// Protocol, which is spoken by OpenSSH and most other SFTP servers. It supports
+// just what sftpStorage needs.
+//
+// Requests are written by a writer goroutine and their responses are read by a
+// reader goroutine, which hands them to their requesters by request ID, so
+// that concurrent requests, and the pipelined requests of reads and writes,
+// share the connection. A requester whose context is done stops waiting for its
+// response, which is dropped once received.
+type sftpClient struct {
+	w io.WriteCloser
+	// writes are the packets handed to the writer goroutine.
+	writes chan []byte
+	// done is closed once the connection is broken or closed.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu struct {
+		syncutil.Mutex
+		nextID uint32
+		// pending are the channels of the requests which await a response, by
+		// request ID.
+		pending map[uint32]chan sftpResponse
+		// err is the error which broke the connection.
+		err error
+	}
+}
+
+// sftpResponse is a response packet, without its request ID.
+type sftpResponse struct {
+	typ     byte
+	payload []byte
+}
+
+// newSFTPClient negotiates the protocol version with the server on the other
+// end of r and w, typically the sftp subsystem of an SSH session.
+func newSFTPClient(r io.Reader, w io.WriteCloser) (*sftpClient, error) {
+	br := bufio.NewReader(r)
+	// The version exchange is the only one without a request ID.
+	if _, err := w.Write(makeSFTPPacket(sftpPacketInit, appendUint32(nil, 3))); err != nil {
+		return nil, errors.Wrap(err, "sftp: writing request")
+	}
+	typ, payload, err := readSFTPPacket(br)
+	if err != nil {
+		return nil, err
+	}
+	d := sftpDecoder{b: payload}
+	if version := d.uint32(); typ != sftpPacketVersion || d.err != nil || version != 3 {
+		return nil, errors.Errorf("sftp: unsupported server version (packet %d)", typ)
+	}
+
+	c := &sftpClient{
+		w:      w,
+		writes: make(chan []byte),
+		done:   make(chan struct{}),
+	}
+	c.mu.pending = make(map[uint32]chan sftpResponse)
+	go c.readLoop(br)
+	go c.writeLoop()
+	return c, nil
+}
+
+// Close closes the connection to the server.
+func (c *sftpClient) Close() error {
+	c.fail(errors.New("sftp: client closed"))
+	return c.w.Close()
+}
+
+// fail marks the connection as broken by err, which is returned to all the
+// requests waiting for a response and all the later ones.
+func (c *sftpClient) fail(err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.mu.err = err
+		c.mu.Unlock()
+		close(c.done)
+	})
+}
+
+func (c *sftpClient) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.err
+}
+
+func (c *sftpClient) writeLoop() {
+	for {
+		select {
+		case packet := <-c.writes:
+			if _, err := c.w.Write(packet); err != nil {
+				c.fail(errors.Wrap(err, "sftp: writing request"))
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *sftpClient) readLoop(r *bufio.Reader) {
+	for {
+		typ, payload, err := readSFTPPacket(r)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		d := sftpDecoder{b: payload}
+		id := d.uint32()
+		if d.err != nil {
+			c.fail(errors.Errorf("sftp: response %d without request ID", typ))
+			return
+		}
+		c.mu.Lock()
+		ch, ok := c.mu.pending[id]
+		delete(c.mu.pending, id)
+		c.mu.Unlock()
+		// The responses of abandoned requests are dropped.
+		if ok {
+			ch <- sftpResponse{typ: typ, payload: d.b}
+		}
+	}
+}
+
+func makeSFTPPacket(typ byte, payload []byte) []byte {
+	b := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(b, uint32(1+len(payload)))
+	b[4] = typ
+	return append(b, payload...)
+}
+
+func readSFTPPacket(r *bufio.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, errors.Wrap(err, "sftp: reading response")
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length < 1 || length > sftpMaxPacket {
+		return 0, nil, errors.Errorf("sftp: invalid response length %d", length)
+	}
+	payload := make([]byte, length-1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, errors.Wrap(err, "sftp: reading response")
+	}
+	return header[4], payload, nil
+}
+
+// sftpCall is a request sent to the server, whose response is awaited with
+// wait.
+type sftpCall struct {
+	id  uint32
+	typ byte
+	ch  chan sftpResponse
+}
+
+// send sends a request of the given type, with the given payload after its ID.
+func (c *sftpClient) send(ctx context.Context, typ byte, payload []byte) (*sftpCall, error) {
+	call := &sftpCall{typ: typ, ch: make(chan sftpResponse, 1)}
+	c.mu.Lock()
+	if c.mu.err != nil {
+		c.mu.Unlock()
+		return nil, c.mu.err
+	}
+	c.mu.nextID++
+	call.id = c.mu.nextID
+	c.mu.pending[call.id] = call.ch
+	c.mu.Unlock()
+
+	select {
+	case c.writes <- makeSFTPPacket(typ, append(appendUint32(nil, call.id), payload...)):
+		return call, nil
+	case <-ctx.Done():
+		c.abandon(call)
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, c.err()
+	}
+}
+
+// abandon stops waiting for the response to call.
+func (c *sftpClient) abandon(call *sftpCall) {
+	c.mu.Lock()
+	delete(c.mu.pending, call.id)
+	c.mu.Unlock()
+}
+
+// wait returns the type and the decoder of the response to call. A response
+// with an error status is returned as an error.
+func (c *sftpClient) wait(ctx context.Context, call *sftpCall) (byte, *sftpDecoder, error) {
+	var resp sftpResponse
+	select {
+	case resp = <-call.ch:
+	case <-ctx.Done():
+		c.abandon(call)
+		return 0, nil, ctx.Err()
+	case <-c.done:
+		// The response may have been received right before the connection broke.
+		select {
+		case resp = <-call.ch:
+		default:
+			return 0, nil, c.err()
+		}
+	}
+	d := &sftpDecoder{b: resp.payload}
+	if resp.typ == sftpPacketStatus {
+		code, msg := d.uint32(), d.string()
+		if d.err != nil {
+			return 0, nil, d.err
+		}
+		if code != sftpStatusOK {
+			return 0, nil, &sftpStatusError{code: code, msg: msg}
+		}
+	}
+	return resp.typ, d, nil
+}
+
+// expectResponse waits for the response to call, which must be of the given
+// type.
+func (c *sftpClient) expectResponse(
+	ctx context.Context, call *sftpCall, respTyp byte,
+) (*sftpDecoder, error) {
+	t, d, err := c.wait(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	if t != respTyp {
+		return nil, errors.Errorf("sftp: unexpected response %d to request %d", t, call.typ)
+	}
+	return d, nil
+}
+
+// expect performs a request whose response must be of the given type.
+func (c *sftpClient) expect(
+	ctx context.Context, typ byte, payload []byte, respTyp byte,
+) (*sftpDecoder, error) {
+	call, err := c.send(ctx, typ, payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.expectResponse(ctx, call, respTyp)
+}
+
+// open opens the named file with the given SFTP open flags, and returns its
+// handle.
+func (c *sftpClient) open(ctx context.Context, name string, flags uint32) (string, error) {
+	payload := appendString(nil, name)
+	payload = appendUint32(payload, flags)
+	payload = appendUint32(payload, 0 /* attrs */)
+	d, err := c.expect(ctx, sftpPacketOpen, payload, sftpPacketHandle)
+	if err != nil {
+		return "", err
+	}
+	handle := d.string()
+	return handle, d.err
+}
+
+func (c *sftpClient) close(ctx context.Context, handle string) error {
+	_, err := c.expect(ctx, sftpPacketClose, appendString(nil, handle), sftpPacketStatus)
+	return err
+}
+
+// sendRead sends a request to read up to n bytes of the file at offset.
+func (c *sftpClient) sendRead(
+	ctx context.Context, handle string, offset int64, n int,
+) (*sftpCall, error) {
+	payload := appendString(nil, handle)
+	payload = appendUint64(payload, uint64(offset))
+	payload = appendUint32(payload, uint32(n))
+	return c.send(ctx, sftpPacketRead, payload)
+}
+
+// waitRead returns the data of a read request, or io.EOF at the end of the
+// file.
+func (c *sftpClient) waitRead(ctx context.Context, call *sftpCall) ([]byte, error) {
+	d, err := c.expectResponse(ctx, call, sftpPacketData)
+	if err != nil {
+		if sftpHasStatus(err, sftpStatusEOF) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	data := d.string()
+	return []byte(data), d.err
+}
+
+// writeFrom writes the content of r to the file, from its start. Up to
+// sftpMaxInflight write requests are sent before waiting for their responses.
+func (c *sftpClient) writeFrom(ctx context.Context, handle string, r io.Reader) error {
+	var inflight []*sftpCall
+	waitOldest := func() error {
+		_, err := c.expectResponse(ctx, inflight[0], sftpPacketStatus)
+		inflight = inflight[1:]
+		return err
+	}
+	// Abandon the requests left in flight by an error.
+	defer func() {
+		for _, call := range inflight {
+			c.abandon(call)
+		}
+	}()
+
+	buf := make([]byte, sftpMaxData)
+	var offset int64
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			if len(inflight) == sftpMaxInflight {
+				if err := waitOldest(); err != nil {
+					return err
+				}
+			}
+			payload := appendString(nil, handle)
+			payload = appendUint64(payload, uint64(offset))
+			payload = appendString(payload, string(buf[:n]))
+			call, err := c.send(ctx, sftpPacketWrite, payload)
+			if err != nil {
+				return err
+			}
+			inflight = append(inflight, call)
+			offset += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	for len(inflight) > 0 {
+		if err := waitOldest(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *sftpClient) stat(ctx context.Context, name string) (sftpAttrs, error) {
+	d, err := c.expect(ctx, sftpPacketStat, appendString(nil, name), sftpPacketAttrs)
+	if err != nil {
+		return sftpAttrs{}, err
+	}
+	attrs := d.attrs()
+	return attrs, d.err
+}
+
+// readDir returns the names of the entries of the named directory, other than
+// "." and "..", along with their attributes.
+func (c *sftpClient) readDir(ctx context.Context, name string) (map[string]sftpAttrs, error) {
+	d, err := c.expect(ctx, sftpPacketOpendir, appendString(nil, name), sftpPacketHandle)
+	if err != nil {
+		return nil, err
+	}
+	handle := d.string()
+	if d.err != nil {
+		return nil, d.err
+	}
+	entries := make(map[string]sftpAttrs)
+	for {
+		d, err := c.expect(ctx, sftpPacketReaddir, appendString(nil, handle), sftpPacketName)
+		if err != nil {
+			if sftpHasStatus(err, sftpStatusEOF) {
+				break
+			}
+			_ = c.close(ctx, handle)
+			return nil, err
+		}
+		for count := d.uint32(); count > 0 && d.err == nil; count-- {
+			entry := d.string()
+			_ = d.string() // longname
+			attrs := d.attrs()
+			if entry != "." && entry != ".." {
+				entries[entry] = attrs
+			}
+		}
+		if d.err != nil {
+			_ = c.close(ctx, handle)
+			return nil, d.err
+		}
+	}
+	return entries, c.close(ctx, handle)
+}
+
+func (c *sftpClient) remove(ctx context.Context, name string) error {
+	_, err := c.expect(ctx, sftpPacketRemove, appendString(nil, name), sftpPacketStatus)
+	return err
+}
+
+func (c *sftpClient) mkdir(ctx context.Context, name string) error {
+	payload := appendString(nil, name)
+	payload = appendUint32(payload, 0 /* attrs */)
+	_, err := c.expect(ctx, sftpPacketMkdir, payload, sftpPacketStatus)
+	return err
+}
+
+func (c *sftpClient) rename(ctx context.Context, from, to string) error {
+	payload := appendString(nil, from)
+	payload = appendString(payload, to)
+	_, err := c.expect(ctx, sftpPacketRename, payload, sftpPacketStatus)
+	return err
+}
+
+// sftpFile reads a file opened with sftpClient.open sequentially. Up to
+// sftpMaxInflight read requests are sent ahead of the data being read.
+type sftpFile struct {
+	ctx    context.Context
+	c      *sftpClient
+	handle string
+	// offset is the offset of the next read request to send.
+	offset   int64
+	inflight []sftpRead
+	// buf is the data received but not read yet.
+	buf []byte
+	err error
+}
+
+// sftpRead is a read request in flight.
+type sftpRead struct {
+	call   *sftpCall
+	offset int64
+	n      int
+}
+
+var _ io.ReadCloser = &sftpFile{}
+
+func (f *sftpFile) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(f.buf) == 0 {
+		if f.err != nil {
+			return 0, f.err
+		}
+		for len(f.inflight) < sftpMaxInflight {
+			call, err := f.c.sendRead(f.ctx, f.handle, f.offset, sftpMaxData)
+			if err != nil {
+				f.err = err
+				break
+			}
+			f.inflight = append(f.inflight, sftpRead{call: call, offset: f.offset, n: sftpMaxData})
+			f.offset += sftpMaxData
+		}
+		if len(f.inflight) == 0 {
+			return 0, f.err
+		}
+		read := f.inflight[0]
+		f.inflight = f.inflight[1:]
+		data, err := f.c.waitRead(f.ctx, read.call)
+		if err != nil {
+			f.abandonInflight()
+			f.err = err
+			return 0, err
+		}
+		if len(data) < read.n {
+			// The rest of a short read is requested again, after which the reads
+			// in flight are all wrong.
+			f.abandonInflight()
+			f.offset = read.offset + int64(len(data))
+		}
+		f.buf = data
+	}
+	n := copy(p, f.buf)
+	f.buf = f.buf[n:]
+	return n, nil
+}
+
+func (f *sftpFile) abandonInflight() {
+	for _, read := range f.inflight {
+		f.c.abandon(read.call)
+	}
+	f.inflight = nil
+}
+
+func (f *sftpFile) Close() error {
+	f.abandonInflight()
+	return f.c.close(f.ctx, f.handle)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendUint32(b, uint32(len(s))), s...)
+}
+
+// sftpDecoder decodes the fields of an SFTP packet. Once a field cannot be
+// decoded, err is set and all further fields are zero.
+type sftpDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *sftpDecoder) uint32() uint32 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.b) < 4 {
+		d.err = errors.New("sftp: truncated packet")
+		return 0
+	}
+	v := binary.BigEndian.Uint32(d.b)
+	d.b = d.b[4:]
+	return v
+}
+
+func (d *sftpDecoder) uint64() uint64 {
+	return uint64(d.uint32())<<32 | uint64(d.uint32())
+}
+
+func (d *sftpDecoder) string() string {
+	n := d.uint32()
+	if d.err != nil {
+		return ""
+	}
+	if uint32(len(d.b)) < n {
+		d.err = errors.New("sftp: truncated packet")
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
+
+func (d *sftpDecoder) attrs() sftpAttrs {
+	var attrs sftpAttrs
+	flags := d.uint32()
+	if flags&sftpAttrSize != 0 {
+		attrs.size = int64(d.uint64())
+	}
+	if flags&sftpAttrUIDGID != 0 {
+		_, _ = d.uint32(), d.uint32()
+	}
+	if flags&sftpAttrPermissions != 0 {
+		attrs.isDir = d.uint32()&0170000 == sftpModeDir
+	}
+	if flags&sftpAttrACModTime != 0 {
+		_, _ = d.uint32(), d.uint32()
+	}
+	if flags&sftpAttrExtended != 0 {
+		for count := d.uint32(); count > 0 && d.err == nil; count-- {
+			_, _ = d.string(), d.string()
+		}
+	}
+	return attrs
+}
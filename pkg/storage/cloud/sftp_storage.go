@@ -0,0 +1,339 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+const defaultSFTPPort = "22"
+
+type sftpStorage struct {
+	conf   *roachpb.ExternalStorage_SFTP
+	prefix string
+	conn   *ssh.Client
+	client *sftpClient
+}
+
+var _ ExternalStorage = &sftpStorage{}
+
+func sftpQueryParams(conf *roachpb.ExternalStorage_SFTP) string {
+	q := make(url.Values)
+	setIf := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	setIf(SFTPPrivateKeyParam, conf.PrivateKey)
+	setIf(SFTPPrivateKeyPassphraseParam, conf.PrivateKeyPassphrase)
+	setIf(SFTPHostKeyParam, conf.HostKey)
+	return q.Encode()
+}
+
+func makeSFTPStorage(
+	ctx context.Context, conf *roachpb.ExternalStorage_SFTP, settings *cluster.Settings,
+) (ExternalStorage, error) {
+	if conf == nil {
+		return nil, errors.Errorf("sftp upload requested but info missing")
+	}
+	keyPEM, err := base64.StdEncoding.DecodeString(conf.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding value of %s", SFTPPrivateKeyParam)
+	}
+	var signer ssh.Signer
+	if conf.PrivateKeyPassphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(keyPEM, []byte(conf.PrivateKeyPassphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(keyPEM)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing value of %s", SFTPPrivateKeyParam)
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(conf.HostKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing value of %s", SFTPHostKeyParam)
+	}
+	addr := conf.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultSFTPPort)
+	}
+	clientConf := &ssh.ClientConfig{
+		User:            conf.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	}
+
+	var conn *ssh.Client
+	if err := contextutil.RunWithTimeout(ctx, "connect to sftp server",
+		timeoutSetting.Get(&settings.SV),
+		func(ctx context.Context) error {
+			var d net.Dialer
+			netConn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			// The SSH handshake can only be bounded by a deadline.
+			if deadline, ok := ctx.Deadline(); ok {
+				_ = netConn.SetDeadline(deadline)
+			}
+			c, chans, reqs, err := ssh.NewClientConn(netConn, addr, clientConf)
+			if err != nil {
+				_ = netConn.Close()
+				return err
+			}
+			_ = netConn.SetDeadline(time.Time{})
+			conn = ssh.NewClient(c, chans, reqs)
+			return nil
+		}); err != nil {
+		return nil, errors.Wrapf(err, "connecting to sftp server %s", addr)
+	}
+
+	client, err := startSFTPSession(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrapf(err, "starting sftp session with %s", addr)
+	}
+	return &sftpStorage{
+		conf:   conf,
+		prefix: conf.Prefix,
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+func startSFTPSession(conn *ssh.Client) (*sftpClient, error) {
+	session, err := conn.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	w, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return nil, err
+	}
+	return newSFTPClient(r, w)
+}
+
+func (s *sftpStorage) Conf() roachpb.ExternalStorage {
+	return roachpb.ExternalStorage{
+		Provider:   roachpb.ExternalStorageProvider_SFTP,
+		SFTPConfig: s.conf,
+	}
+}
+
+// mkdirAll creates the named directory along with any missing parents.
+func (s *sftpStorage) mkdirAll(ctx context.Context, dir string) error {
+	if dir == "" || dir == "." || dir == "/" {
+		return nil
+	}
+	attrs, err := s.client.stat(ctx, dir)
+	if err == nil {
+		if !attrs.isDir {
+			return errors.Errorf("%s is not a directory", dir)
+		}
+		return nil
+	}
+	if !sftpHasStatus(err, sftpStatusNoSuchFile) {
+		return err
+	}
+	if err := s.mkdirAll(ctx, path.Dir(dir)); err != nil {
+		return err
+	}
+	if err := s.client.mkdir(ctx, dir); err != nil {
+		// The directory may have been created concurrently.
+		if attrs, statErr := s.client.stat(ctx, dir); statErr != nil || !attrs.isDir {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sftpStorage) WriteFile(ctx context.Context, basename string, content io.ReadSeeker) error {
+	err := func() error {
+		p := path.Join(s.prefix, basename)
+		if err := s.mkdirAll(ctx, path.Dir(p)); err != nil {
+			return err
+		}
+		// The content is written to a temporary file first so that the file is
+		// never seen partially written.
+		tmp := fmt.Sprintf("%s.%s.tmp", p, uuid.MakeV4())
+		handle, err := s.client.open(ctx, tmp, sftpOpenWrite|sftpOpenCreate|sftpOpenTruncate)
+		if err != nil {
+			return err
+		}
+		err = s.client.writeFrom(ctx, handle, content)
+		if closeErr := s.client.close(ctx, handle); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			// Renaming onto an existing file fails in version 3 of the protocol.
+			if err = s.client.remove(ctx, p); err != nil && !sftpHasStatus(err, sftpStatusNoSuchFile) {
+				return err
+			}
+			err = s.client.rename(ctx, tmp, p)
+		}
+		if err != nil {
+			_ = s.client.remove(ctx, tmp)
+		}
+		return err
+	}()
+	return errors.Wrap(err, "failed to write sftp file")
+}
+
+func (s *sftpStorage) ReadFile(ctx context.Context, basename string) (io.ReadCloser, error) {
+	reader, _, err := s.ReadFileAt(ctx, basename, 0)
+	return reader, err
+}
+
+func (s *sftpStorage) ReadFileAt(
+	ctx context.Context, basename string, offset int64,
+) (io.ReadCloser, int64, error) {
+	p := path.Join(s.prefix, basename)
+	attrs, err := s.client.stat(ctx, p)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to read sftp file")
+	}
+	handle, err := s.client.open(ctx, p, sftpOpenRead)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to read sftp file")
+	}
+	return &sftpFile{ctx: ctx, c: s.client, handle: handle, offset: offset}, attrs.size, nil
+}
+
+// glob returns the names of the files matching pattern, like filepath.Glob.
+func (s *sftpStorage) glob(ctx context.Context, pattern string) ([]string, error) {
+	if !containsGlob(pattern) {
+		if _, err := s.client.stat(ctx, pattern); err != nil {
+			if sftpHasStatus(err, sftpStatusNoSuchFile) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := path.Split(pattern)
+	if dir != "/" {
+		dir = strings.TrimSuffix(dir, "/")
+	}
+	dirs := []string{dir}
+	if containsGlob(dir) {
+		var err error
+		if dirs, err = s.glob(ctx, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []string
+	for _, d := range dirs {
+		readDir := d
+		if readDir == "" {
+			readDir = "."
+		}
+		entries, err := s.client.readDir(ctx, readDir)
+		if err != nil {
+			// Like filepath.Glob, ignore what isn't a directory.
+			if sftpHasStatus(err, sftpStatusNoSuchFile) {
+				continue
+			}
+			if attrs, statErr := s.client.stat(ctx, readDir); statErr == nil && !attrs.isDir {
+				continue
+			}
+			return nil, err
+		}
+		for name := range entries {
+			matched, err := path.Match(file, name)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				matches = append(matches, path.Join(d, name))
+			}
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func (s *sftpStorage) ListFiles(ctx context.Context, patternSuffix string) ([]string, error) {
+	pattern := s.prefix
+	if patternSuffix != "" {
+		if containsGlob(s.prefix) {
+			return nil, errors.New("prefix cannot contain globs pattern when passing an explicit pattern")
+		}
+		pattern = path.Join(pattern, patternSuffix)
+	}
+
+	matches, err := s.glob(ctx, pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to match pattern provided")
+	}
+
+	var fileList []string
+	for _, fileName := range matches {
+		if patternSuffix != "" {
+			if !strings.HasPrefix(fileName, s.prefix) {
+				// TODO(dt): return a nice rel-path instead of erroring out.
+				return nil, errors.Errorf("pattern matched file outside of base path %q", s.prefix)
+			}
+			fileList = append(fileList, strings.TrimPrefix(strings.TrimPrefix(fileName, s.prefix), "/"))
+		} else {
+			sftpURL := url.URL{
+				Scheme:   "sftp",
+				User:     url.User(s.conf.User),
+				Host:     s.conf.Host,
+				Path:     fileName,
+				RawQuery: sftpQueryParams(s.conf),
+			}
+			fileList = append(fileList, sftpURL.String())
+		}
+	}
+	return fileList, nil
+}
+
+func (s *sftpStorage) Delete(ctx context.Context, basename string) error {
+	return errors.Wrap(s.client.remove(ctx, path.Join(s.prefix, basename)), "failed to delete sftp file")
+}
+
+func (s *sftpStorage) Size(ctx context.Context, basename string) (int64, error) {
+	attrs, err := s.client.stat(ctx, path.Join(s.prefix, basename))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get sftp file size")
+	}
+	return attrs.size, nil
+}
+
+func (s *sftpStorage) Close() error {
+	_ = s.client.Close()
+	return s.conn.Close()
+}
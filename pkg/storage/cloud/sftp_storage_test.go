@@ -0,0 +1,431 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/blobs"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpTestServer is an SSH server whose sftp subsystem serves the files of a
+// local directory. It implements just the requests sftpClient makes.
+type sftpTestServer struct {
+	root    string
+	ln      net.Listener
+	conf    *ssh.ServerConfig
+	hostKey ssh.PublicKey
+
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newTestSSHKey(t *testing.T) (ssh.Signer, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return signer, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func startSFTPTestServer(t *testing.T, root string, userKey ssh.PublicKey) *sftpTestServer {
+	hostSigner, _ := newTestSSHKey(t)
+	s := &sftpTestServer{
+		root:    root,
+		hostKey: hostSigner.PublicKey(),
+		conf: &ssh.ServerConfig{
+			PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+				if !bytes.Equal(key.Marshal(), userKey.Marshal()) {
+					return nil, errors.New("unknown key")
+				}
+				return nil, nil
+			},
+		},
+	}
+	s.conf.AddHostKey(hostSigner)
+	var err error
+	s.ln, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := s.ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serveConn(conn)
+			}()
+		}
+	}()
+	return s
+}
+
+func (s *sftpTestServer) close() {
+	_ = s.ln.Close()
+	s.mu.Lock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *sftpTestServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, s.conf)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			_ = newChan.Reject(ssh.UnknownChannelType, "")
+			continue
+		}
+		ch, reqs, err := newChan.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for req := range reqs {
+				ok := req.Type == "subsystem" && bytes.Equal(req.Payload, appendString(nil, "sftp"))
+				_ = req.Reply(ok, nil)
+				if ok {
+					s.wg.Add(1)
+					go func() {
+						defer s.wg.Done()
+						s.serveSFTP(ch)
+					}()
+				}
+			}
+		}()
+	}
+}
+
+func sftpTestAttrs(info os.FileInfo) []byte {
+	mode := uint32(info.Mode().Perm())
+	if info.IsDir() {
+		mode |= sftpModeDir
+	}
+	b := appendUint32(nil, sftpAttrSize|sftpAttrPermissions)
+	b = appendUint64(b, uint64(info.Size()))
+	return appendUint32(b, mode)
+}
+
+func (s *sftpTestServer) serveSFTP(ch ssh.Channel) {
+	defer ch.Close()
+	r := bufio.NewReader(ch)
+	files := make(map[string]*os.File)
+	dirs := make(map[string][]os.FileInfo)
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	var nextHandle int
+
+	send := func(typ byte, payload []byte) error {
+		b := appendUint32(nil, uint32(1+len(payload)))
+		_, err := ch.Write(append(append(b, typ), payload...))
+		return err
+	}
+	for {
+		var header [5]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[:4])-1)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return
+		}
+		if header[4] == sftpPacketInit {
+			if err := send(sftpPacketVersion, appendUint32(nil, 3)); err != nil {
+				return
+			}
+			continue
+		}
+
+		d := &sftpDecoder{b: payload}
+		id := appendUint32(nil, d.uint32())
+		status := func(code uint32, msg string) error {
+			b := appendUint32(id, code)
+			return send(sftpPacketStatus, appendString(appendString(b, msg), ""))
+		}
+		errStatus := func(err error) error {
+			if err == nil {
+				return status(sftpStatusOK, "")
+			}
+			if os.IsNotExist(err) {
+				return status(sftpStatusNoSuchFile, err.Error())
+			}
+			return status(4 /* failure */, err.Error())
+		}
+		newHandle := func() string {
+			nextHandle++
+			return strconv.Itoa(nextHandle)
+		}
+		local := func(name string) string {
+			return filepath.Join(s.root, filepath.FromSlash(name))
+		}
+
+		var err error
+		switch header[4] {
+		case sftpPacketOpen:
+			name, pflags := d.string(), d.uint32()
+			flags := os.O_RDONLY
+			if pflags&sftpOpenWrite != 0 {
+				flags = os.O_WRONLY
+			}
+			if pflags&sftpOpenCreate != 0 {
+				flags |= os.O_CREATE
+			}
+			if pflags&sftpOpenTruncate != 0 {
+				flags |= os.O_TRUNC
+			}
+			f, openErr := os.OpenFile(local(name), flags, 0644)
+			if openErr != nil {
+				err = errStatus(openErr)
+				break
+			}
+			handle := newHandle()
+			files[handle] = f
+			err = send(sftpPacketHandle, appendString(id, handle))
+		case sftpPacketClose:
+			handle := d.string()
+			if f, ok := files[handle]; ok {
+				delete(files, handle)
+				err = errStatus(f.Close())
+			} else {
+				delete(dirs, handle)
+				err = errStatus(nil)
+			}
+		case sftpPacketRead:
+			handle, offset, length := d.string(), d.uint64(), d.uint32()
+			buf := make([]byte, length)
+			n, readErr := files[handle].ReadAt(buf, int64(offset))
+			if n == 0 && readErr == io.EOF {
+				err = status(sftpStatusEOF, "")
+			} else if n == 0 {
+				err = errStatus(readErr)
+			} else {
+				err = send(sftpPacketData, appendString(id, string(buf[:n])))
+			}
+		case sftpPacketWrite:
+			handle, offset, data := d.string(), d.uint64(), d.string()
+			_, writeErr := files[handle].WriteAt([]byte(data), int64(offset))
+			err = errStatus(writeErr)
+		case sftpPacketOpendir:
+			name := d.string()
+			infos, readErr := ioutil.ReadDir(local(name))
+			if readErr != nil {
+				err = errStatus(readErr)
+				break
+			}
+			self, _ := os.Stat(local(name))
+			handle := newHandle()
+			dirs[handle] = append([]os.FileInfo{self}, infos...)
+			err = send(sftpPacketHandle, appendString(id, handle))
+		case sftpPacketReaddir:
+			handle := d.string()
+			infos := dirs[handle]
+			if len(infos) == 0 {
+				err = status(sftpStatusEOF, "")
+				break
+			}
+			dirs[handle] = nil
+			b := appendUint32(id, uint32(len(infos)))
+			for i, info := range infos {
+				name := info.Name()
+				if i == 0 {
+					name = "."
+				}
+				b = appendString(appendString(b, name), name)
+				b = append(b, sftpTestAttrs(info)...)
+			}
+			err = send(sftpPacketName, b)
+		case sftpPacketRemove:
+			err = errStatus(os.Remove(local(d.string())))
+		case sftpPacketMkdir:
+			err = errStatus(os.Mkdir(local(d.string()), 0755))
+		case sftpPacketStat:
+			info, statErr := os.Stat(local(d.string()))
+			if statErr != nil {
+				err = errStatus(statErr)
+				break
+			}
+			err = send(sftpPacketAttrs, append(id, sftpTestAttrs(info)...))
+		case sftpPacketRename:
+			from, to := local(d.string()), local(d.string())
+			// Like most servers, don't replace existing files.
+			if _, statErr := os.Stat(to); statErr == nil {
+				err = errStatus(errors.New("file exists"))
+				break
+			}
+			err = errStatus(os.Rename(from, to))
+		default:
+			err = status(8 /* unsupported */, "unsupported")
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func TestPutSFTP(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	root, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	userSigner, userKey := newTestSSHKey(t)
+	srv := startSFTPTestServer(t, root, userSigner.PublicKey())
+	defer srv.close()
+
+	makeURI := func(path string, key []byte, hostKey ssh.PublicKey) string {
+		q := make(url.Values)
+		q.Set(SFTPPrivateKeyParam, base64.StdEncoding.EncodeToString(key))
+		q.Set(SFTPHostKeyParam, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostKey))))
+		u := url.URL{
+			Scheme:   "sftp",
+			User:     url.User("backup"),
+			Host:     srv.ln.Addr().String(),
+			Path:     path,
+			RawQuery: q.Encode(),
+		}
+		return u.String()
+	}
+
+	testExportStore(t, makeURI("/backup-test", userKey, srv.hostKey), false)
+	testListFiles(t, makeURI("/listing-test/basepath", userKey, srv.hostKey))
+
+	t.Run("wrong-host-key", func(t *testing.T) {
+		otherHost, _ := newTestSSHKey(t)
+		_, err := ExternalStorageFromURI(ctx, makeURI("/backup-test", userKey, otherHost.PublicKey()),
+			base.ExternalIOConfig{}, testSettings, blobs.TestEmptyBlobClientFactory)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "host key mismatch")
+	})
+
+	t.Run("unauthorized-key", func(t *testing.T) {
+		_, otherKey := newTestSSHKey(t)
+		_, err := ExternalStorageFromURI(ctx, makeURI("/backup-test", otherKey, srv.hostKey),
+			base.ExternalIOConfig{}, testSettings, blobs.TestEmptyBlobClientFactory)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to authenticate")
+	})
+
+	t.Run("uri", func(t *testing.T) {
+		for uri, expected := range map[string]string{
+			"sftp://host/path?SFTP_PRIVATE_KEY=a&SFTP_HOST_KEY=b":                      "sftp uri missing user",
+			"sftp://user@host/path?SFTP_HOST_KEY=b":                                    `missing "SFTP_PRIVATE_KEY"`,
+			"sftp://user@host/path?SFTP_PRIVATE_KEY=a":                                 `missing "SFTP_HOST_KEY"`,
+			"sftp://user@host:2222/path?SFTP_PRIVATE_KEY=a+b&SFTP_HOST_KEY=b":          "",
+			"s3://bucket/path?AWS_ADDRESSING_STYLE=path&AWS_CA_BUNDLE=Y2E=":            "",
+			"s3://bucket/path?AWS_ADDRESSING_STYLE=dns&AWS_ACCESS_KEY_ID=a":            "unsupported value",
+			"s3://bucket/path?AWS_ADDRESSING_STYLE=auto&AWS_ENDPOINT=http://ceph:7480": "",
+		} {
+			conf, err := ExternalStorageConfFromURI(uri)
+			if !testutils.IsError(err, expected) {
+				t.Fatalf("%s: expected error %q, got %v", uri, expected, err)
+			}
+			if conf.SFTPConfig != nil && err == nil {
+				require.Equal(t, "host:2222", conf.SFTPConfig.Host)
+				require.Equal(t, "user", conf.SFTPConfig.User)
+				require.Equal(t, "a+b", conf.SFTPConfig.PrivateKey)
+			}
+		}
+		sanitized, err := SanitizeExternalStorageURI(
+			"sftp://user@host/path?SFTP_PRIVATE_KEY=a&SFTP_PRIVATE_KEY_PASSPHRASE=b&SFTP_HOST_KEY=c", nil)
+		require.NoError(t, err)
+		require.Equal(t,
+			"sftp://user@host/path?SFTP_HOST_KEY=c&SFTP_PRIVATE_KEY=redacted&SFTP_PRIVATE_KEY_PASSPHRASE=redacted",
+			sanitized)
+	})
+}
+
+func TestSFTPClientContext(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// The server negotiates the version and then reads requests without ever
+	// responding to them.
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		defer respW.Close()
+		r := bufio.NewReader(reqR)
+		for {
+			typ, _, err := readSFTPPacket(r)
+			if err != nil {
+				return
+			}
+			if typ == sftpPacketInit {
+				if _, err := respW.Write(makeSFTPPacket(sftpPacketVersion, appendUint32(nil, 3))); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	c, err := newSFTPClient(respR, reqW)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.stat(ctx, "/foo")
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "%+v", err)
+	require.Empty(t, c.mu.pending)
+
+	require.NoError(t, c.Close())
+	<-serverDone
+	_, err = c.stat(context.Background(), "/foo")
+	require.EqualError(t, err, "sftp: client closed")
+}
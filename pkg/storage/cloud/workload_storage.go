@@ -85,6 +85,12 @@ func (s *workloadStorage) ReadFile(_ context.Context, basename string) (io.ReadC
 	return ioutil.NopCloser(r), nil
 }
 
+func (s *workloadStorage) ReadFileAt(
+	_ context.Context, _ string, _ int64,
+) (io.ReadCloser, int64, error) {
+	return nil, 0, errors.Errorf(`workload storage does not support ranged reads`)
+}
+
 func (s *workloadStorage) WriteFile(_ context.Context, _ string, _ io.ReadSeeker) error {
 	return errors.Errorf(`workload storage does not support writes`)
 }
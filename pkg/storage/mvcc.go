@@ -711,6 +711,11 @@ type MVCCGetOptions struct {
 	Tombstones       bool
 	FailOnMoreRecent bool
 	Txn              *roachpb.Transaction
+	// SkipLocked instructs MVCCGet to treat the key as missing if it is locked
+	// by a conflicting transaction, either through a replicated intent or
+	// through an entry in LockTable, instead of returning a WriteIntentError.
+	SkipLocked bool
+	LockTable  LockTableView
 }
 
 func (opts *MVCCGetOptions) validate() error {
@@ -720,9 +725,22 @@ func (opts *MVCCGetOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
 	return nil
 }
 
+// LockTableView is a transaction-bound view into the in-memory collection of
+// key-level locks maintained by a range's concurrency manager. It allows reads
+// that skip locked keys to recognize the keys locked by unreplicated locks,
+// which are not visible in the storage engine.
+type LockTableView interface {
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// by a conflicting transaction.
+	IsKeyLockedByConflictingTxn(roachpb.Key) bool
+}
+
 // MVCCGet returns the most recent value for the specified key whose timestamp
 // is less than or equal to the supplied timestamp. If no such value exists, nil
 // is returned instead.
@@ -768,8 +786,9 @@ func mvccGet(
 		return nil, nil, err
 	}
 
-	// If the iterator has a specialized implementation, defer to that.
-	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() {
+	// If the iterator has a specialized implementation, defer to that. The
+	// specialized implementations don't know how to skip locked keys.
+	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() && !opts.SkipLocked {
 		return mvccIter.MVCCGet(key, timestamp, opts)
 	}
 
@@ -787,6 +806,8 @@ func mvccGet(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 	}
 
 	mvccScanner.init(opts.Txn)
@@ -2338,8 +2359,9 @@ func mvccScanToBytes(
 		return MVCCScanResult{ResumeSpan: resumeSpan}, nil
 	}
 
-	// If the iterator has a specialized implementation, defer to that.
-	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() {
+	// If the iterator has a specialized implementation, defer to that. The
+	// specialized implementations don't know how to skip locked keys.
+	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() && !opts.SkipLocked {
 		return mvccIter.MVCCScan(key, endKey, timestamp, opts)
 	}
 
@@ -2357,6 +2379,8 @@ func mvccScanToBytes(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 	}
 
 	mvccScanner.init(opts.Txn)
@@ -2469,6 +2493,12 @@ type MVCCScanOptions struct {
 	//
 	// The zero value indicates no limit.
 	TargetBytes int64
+	// SkipLocked instructs the scan to skip over the keys that are locked by
+	// conflicting transactions, either through replicated intents or through
+	// entries in LockTable, instead of returning a WriteIntentError. Skipped
+	// keys are not returned and do not count towards MaxKeys or TargetBytes.
+	SkipLocked bool
+	LockTable  LockTableView
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -2478,6 +2508,9 @@ func (opts *MVCCScanOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
 	return nil
 }
 
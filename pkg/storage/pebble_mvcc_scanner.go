@@ -116,10 +116,13 @@ type pebbleMVCCScanner struct {
 	// package level MVCCScan for what these mean.
 	inconsistent, tombstones bool
 	failOnMoreRecent         bool
+	skipLocked               bool
 	checkUncertainty         bool
 	isGet                    bool
 	keyBuf                   []byte
 	savedBuf                 []byte
+	// Consulted to skip keys locked by unreplicated locks if skipLocked is set.
+	lockTable LockTableView
 	// cur* variables store the "current" record we're pointing to. Updated in
 	// updateCurrent.
 	curKey, curValue []byte
@@ -267,6 +270,13 @@ func (p *pebbleMVCCScanner) uncertaintyError(ts hlc.Timestamp) bool {
 // Emit a tuple and return true if we have reason to believe iteration can
 // continue.
 func (p *pebbleMVCCScanner) getAndAdvance() bool {
+	if p.skipLocked && p.lockTable != nil && p.lockTable.IsKeyLockedByConflictingTxn(p.curKey) {
+		// 0. The key is locked by a conflicting transaction and we've been
+		// asked to skip locked keys. Move on to the next key without returning
+		// anything for this one.
+		return p.advanceKey()
+	}
+
 	mvccKey := MVCCKey{p.curKey, p.curTS}
 	if mvccKey.IsValue() {
 		if p.curTS.LessEq(p.ts) {
@@ -383,6 +393,11 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 		// Note that this will trigger an error higher up the stack. We
 		// continue scanning so that we can return all of the intents
 		// in the scan range.
+		if p.skipLocked {
+			// Unless we've been asked to skip locked keys, in which case we
+			// move on to the next key without returning anything for this one.
+			return p.advanceKey()
+		}
 		p.keyBuf = EncodeKeyToBuf(p.keyBuf[:0], p.curMVCCKey())
 		p.err = p.intents.Set(p.keyBuf, p.curValue, nil)
 		if p.err != nil {
@@ -873,6 +873,18 @@ var charts = []sectionDescription{
 					"changefeed.buffer_entries.out",
 				},
 			},
+			{
+				Title: "Buffer Pushback Time",
+				Metrics: []string{
+					"changefeed.buffer_pushback_nanos",
+				},
+			},
+			{
+				Title: "Emit Latency",
+				Metrics: []string{
+					"changefeed.emit_hist_nanos",
+				},
+			},
 			{
 				Title: "Errors",
 				Metrics: []string{
@@ -885,6 +897,12 @@ var charts = []sectionDescription{
 					"changefeed.flushes",
 				},
 			},
+			{
+				Title: "Flush Latency",
+				Metrics: []string{
+					"changefeed.flush_hist_nanos",
+				},
+			},
 			{
 				Title: "Max Behind Nanos",
 				Metrics: []string{
@@ -903,6 +921,18 @@ var charts = []sectionDescription{
 					"changefeed.poll_request_nanos",
 				},
 			},
+			{
+				Title: "Sink Buffered Bytes",
+				Metrics: []string{
+					"changefeed.sink_buffered_bytes",
+				},
+			},
+			{
+				Title: "Sink Queue Depth",
+				Metrics: []string{
+					"changefeed.sink_queue_depth",
+				},
+			},
 			{
 				Title: "Total Time Spent",
 				Metrics: []string{
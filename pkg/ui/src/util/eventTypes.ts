@@ -20,6 +20,12 @@ type Event = protos.cockroach.server.serverpb.EventsResponse.Event;
 export const CREATE_DATABASE = "create_database";
 // Recorded when a database is dropped.
 export const DROP_DATABASE = "drop_database";
+// Recorded when a schema is created.
+export const CREATE_SCHEMA = "create_schema";
+// Recorded when a schema is dropped.
+export const DROP_SCHEMA = "drop_schema";
+// Recorded when a schema is renamed.
+export const RENAME_SCHEMA = "rename_schema";
 // Recorded when a table is created.
 export const CREATE_TABLE = "create_table";
 // Recorded when a table is dropped.
@@ -70,7 +76,9 @@ export const CREATE_STATISTICS = "create_statistics";
 
 // Node Event Types
 export const nodeEvents = [NODE_JOIN, NODE_RESTART, NODE_DECOMMISSIONED, NODE_RECOMMISSIONED];
-export const databaseEvents = [CREATE_DATABASE, DROP_DATABASE];
+export const databaseEvents = [
+  CREATE_DATABASE, DROP_DATABASE, CREATE_SCHEMA, DROP_SCHEMA, RENAME_SCHEMA,
+];
 export const tableEvents = [
   CREATE_TABLE, DROP_TABLE, TRUNCATE_TABLE, ALTER_TABLE, CREATE_INDEX,
   ALTER_INDEX, DROP_INDEX, CREATE_VIEW, DROP_VIEW, REVERSE_SCHEMA_CHANGE,
@@ -28,6 +28,13 @@ export function getEventDescription(e: Event$Properties): string {
     case eventTypes.DROP_DATABASE:
       const tableDropText = getDroppedObjectsText(info);
       return `Database Dropped: User ${info.User} dropped database ${info.DatabaseName}. ${tableDropText}`;
+    case eventTypes.CREATE_SCHEMA:
+      return `Schema Created: User ${info.User} created schema ${info.SchemaName}`;
+    case eventTypes.DROP_SCHEMA:
+      const schemaDropText = getDroppedObjectsText(info);
+      return `Schema Dropped: User ${info.User} dropped schema ${info.SchemaName}. ${schemaDropText}`;
+    case eventTypes.RENAME_SCHEMA:
+      return `Schema Renamed: User ${info.User} renamed schema ${info.SchemaName} to ${info.NewSchemaName}`;
     case eventTypes.CREATE_TABLE:
       return `Table Created: User ${info.User} created table ${info.TableName}`;
     case eventTypes.DROP_TABLE:
@@ -87,6 +94,8 @@ export function getEventDescription(e: Event$Properties): string {
 export interface EventInfo {
   User: string;
   DatabaseName?: string;
+  SchemaName?: string;
+  NewSchemaName?: string;
   TableName?: string;
   IndexName?: string;
   MutationID?: string;
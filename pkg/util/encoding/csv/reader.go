@@ -161,6 +161,9 @@ type Reader struct {
 	// numLine is the current line being read in the CSV file.
 	numLine int
 
+	// offset is the input stream byte offset of the current reader position.
+	offset int64
+
 	// rawBuffer is a line buffer only used by the readLine method.
 	rawBuffer []byte
 
@@ -222,6 +225,13 @@ func (r *Reader) ReadAll() (records [][]string, err error) {
 	}
 }
 
+// InputOffset returns the input stream byte offset of the current reader
+// position. The offset gives the location of the end of the most recently
+// read row and the beginning of the next row.
+func (r *Reader) InputOffset() int64 {
+	return r.offset
+}
+
 // readLine reads the next line (with the trailing endline).
 // If EOF is hit without a trailing endline, it will be omitted.
 // If some bytes were read, then the error is never io.EOF.
@@ -236,6 +246,7 @@ func (r *Reader) readLine() ([]byte, error) {
 		}
 		line = r.rawBuffer
 	}
+	readSize := len(line)
 	if len(line) > 0 && err == io.EOF {
 		err = nil
 		// For backwards compatibility, drop trailing \r before EOF.
@@ -244,6 +255,7 @@ func (r *Reader) readLine() ([]byte, error) {
 		}
 	}
 	r.numLine++
+	r.offset += int64(readSize)
 	return line, err
 }
 
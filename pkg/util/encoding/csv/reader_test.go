@@ -422,6 +422,35 @@ x,,,
 	}
 }
 
+func TestInputOffset(t *testing.T) {
+	const input = "a,b\n\n\"c\nd\",e\r\nf\"g,h\ni,j"
+	r := NewReader(strings.NewReader(input))
+	for _, expected := range []struct {
+		record string
+		err    bool
+		offset int64
+	}{
+		{record: "a,b", offset: 4},
+		{record: "c\nd,e", offset: 15},
+		{err: true, offset: 20},
+		{record: "i,j", offset: 23},
+	} {
+		record, err := r.Read()
+		if expected.err != (err != nil) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err == nil && strings.Join(record, ",") != expected.record {
+			t.Errorf("expected record %q, got %q", expected.record, record)
+		}
+		if offset := r.InputOffset(); offset != expected.offset {
+			t.Errorf("expected offset %d after %q, got %d", expected.offset, expected.record, offset)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
 // nTimes is an io.Reader which yields the string s n times.
 type nTimes struct {
 	s   string
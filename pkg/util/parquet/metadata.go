@@ -0,0 +1,294 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+// The types and constants in this file mirror the definitions in
+// parquet-format's parquet.thrift. Field ids in the encode methods are the
+// Thrift field ids from that file.
+//
+// See https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift
+
+// magic is written at the start and at the end of every Parquet file.
+const magic = "PAR1"
+
+// physicalType is the Thrift enum `Type`.
+type physicalType int32
+
+const (
+	physicalBoolean           physicalType = 0
+	physicalInt32             physicalType = 1
+	physicalInt64             physicalType = 2
+	physicalInt96             physicalType = 3
+	physicalFloat             physicalType = 4
+	physicalDouble            physicalType = 5
+	physicalByteArray         physicalType = 6
+	physicalFixedLenByteArray physicalType = 7
+)
+
+// convertedType is the Thrift enum `ConvertedType`. It is the legacy way of
+// annotating physical types and is still the one understood by the widest
+// range of readers, so it is written alongside logicalType.
+type convertedType int32
+
+const (
+	convertedNone            convertedType = -1
+	convertedUTF8            convertedType = 0
+	convertedList            convertedType = 3
+	convertedEnum            convertedType = 4
+	convertedDecimal         convertedType = 5
+	convertedDate            convertedType = 6
+	convertedTimeMillis      convertedType = 7
+	convertedTimeMicros      convertedType = 8
+	convertedTimestampMillis convertedType = 9
+	convertedTimestampMicros convertedType = 10
+	convertedUint64          convertedType = 14
+	convertedInt16           convertedType = 16
+	convertedInt32           convertedType = 17
+	convertedInt64           convertedType = 18
+	convertedJSON            convertedType = 19
+)
+
+// logicalTypeID is the id of the field set in the Thrift union `LogicalType`.
+type logicalTypeID int16
+
+const (
+	logicalNone      logicalTypeID = 0
+	logicalString    logicalTypeID = 1
+	logicalList      logicalTypeID = 3
+	logicalEnum      logicalTypeID = 4
+	logicalDecimal   logicalTypeID = 5
+	logicalDate      logicalTypeID = 6
+	logicalTime      logicalTypeID = 7
+	logicalTimestamp logicalTypeID = 8
+	logicalInteger   logicalTypeID = 10
+	logicalJSON      logicalTypeID = 12
+	logicalUUID      logicalTypeID = 14
+)
+
+// timeUnit is the id of the field set in the Thrift union `TimeUnit`.
+type timeUnit int16
+
+const (
+	timeUnitMillis timeUnit = 1
+	timeUnitMicros timeUnit = 2
+	timeUnitNanos  timeUnit = 3
+)
+
+// repetitionType is the Thrift enum `FieldRepetitionType`.
+type repetitionType int32
+
+const (
+	repetitionRequired repetitionType = 0
+	repetitionOptional repetitionType = 1
+	repetitionRepeated repetitionType = 2
+)
+
+// encoding is the Thrift enum `Encoding`.
+type encoding int32
+
+const (
+	encodingPlain           encoding = 0
+	encodingPlainDictionary encoding = 2
+	encodingRLE             encoding = 3
+	encodingRLEDictionary   encoding = 8
+)
+
+// CompressionCodec is the Thrift enum `CompressionCodec`. It selects how the
+// pages of a file are compressed.
+type CompressionCodec int32
+
+const (
+	// CompressionNone leaves pages uncompressed.
+	CompressionNone CompressionCodec = 0
+	// CompressionGzip compresses every page with gzip.
+	CompressionGzip CompressionCodec = 2
+
+	// compressionSnappy is only supported when reading files.
+	compressionSnappy CompressionCodec = 1
+)
+
+// Values of the Thrift enum `PageType`.
+const (
+	pageTypeDataPage       = 0
+	pageTypeDictionaryPage = 2
+	pageTypeDataPageV2     = 3
+)
+
+// schemaElement is the Thrift struct `SchemaElement`. A Parquet schema is a
+// depth-first flattening of a tree of these, starting with the root.
+type schemaElement struct {
+	name        string
+	physical    physicalType
+	hasPhysical bool
+	typeLength  int32
+	repetition  repetitionType
+	numChildren int32
+	converted   convertedType
+	logical     logicalType
+	scale       int32
+	precision   int32
+}
+
+// logicalType is the Thrift union `LogicalType`, restricted to the members
+// that are written by this package.
+type logicalType struct {
+	id logicalTypeID
+	// isAdjustedToUTC and unit are used by TIME and TIMESTAMP. Only
+	// microseconds are written, but all units can be read.
+	isAdjustedToUTC bool
+	unit            timeUnit
+	// bitWidth and isSigned are used by INTEGER.
+	bitWidth int8
+	isSigned bool
+	// scale and precision are used by DECIMAL.
+	scale, precision int32
+}
+
+func (e *schemaElement) encode(w *thriftWriter) {
+	w.structBegin()
+	if e.hasPhysical {
+		w.i32Field(1, int32(e.physical))
+		if e.physical == physicalFixedLenByteArray {
+			w.i32Field(2, e.typeLength)
+		}
+	}
+	w.i32Field(3, int32(e.repetition))
+	w.stringField(4, e.name)
+	if e.numChildren > 0 {
+		w.i32Field(5, e.numChildren)
+	}
+	if e.converted != convertedNone {
+		w.i32Field(6, int32(e.converted))
+	}
+	if e.converted == convertedDecimal {
+		w.i32Field(7, e.scale)
+		w.i32Field(8, e.precision)
+	}
+	if e.logical.id != logicalNone {
+		w.structFieldBegin(10)
+		e.logical.encode(w)
+		w.structEnd()
+	}
+	w.structEnd()
+}
+
+// encode writes the fields of the LogicalType union; the caller is
+// responsible for beginning and ending the enclosing struct.
+func (l *logicalType) encode(w *thriftWriter) {
+	w.structFieldBegin(int16(l.id))
+	switch l.id {
+	case logicalDecimal:
+		w.i32Field(1, l.scale)
+		w.i32Field(2, l.precision)
+	case logicalTime, logicalTimestamp:
+		w.boolField(1, l.isAdjustedToUTC)
+		// TimeUnit is itself a union; field 2 is MICROS.
+		w.structFieldBegin(2)
+		w.structFieldBegin(2)
+		w.structEnd()
+		w.structEnd()
+	case logicalInteger:
+		w.fieldHeader(1, thriftByte)
+		w.buf = append(w.buf, byte(l.bitWidth))
+		w.boolField(2, l.isSigned)
+	}
+	w.structEnd()
+}
+
+// columnChunkMeta is the information about a column chunk that ends up in
+// the Thrift struct `ColumnChunk` and its nested `ColumnMetaData`.
+type columnChunkMeta struct {
+	physical              physicalType
+	path                  []string
+	codec                 CompressionCodec
+	numValues             int64
+	totalUncompressedSize int64
+	totalCompressedSize   int64
+	dataPageOffset        int64
+}
+
+func (c *columnChunkMeta) encode(w *thriftWriter) {
+	w.structBegin()
+	w.i64Field(2, c.dataPageOffset)
+	w.structFieldBegin(3)
+	w.i32Field(1, int32(c.physical))
+	w.listFieldBegin(2, thriftI32, 2)
+	w.writeI32(int32(encodingPlain))
+	w.writeI32(int32(encodingRLE))
+	w.listFieldBegin(3, thriftBinary, len(c.path))
+	for _, p := range c.path {
+		w.writeString(p)
+	}
+	w.i32Field(4, int32(c.codec))
+	w.i64Field(5, c.numValues)
+	w.i64Field(6, c.totalUncompressedSize)
+	w.i64Field(7, c.totalCompressedSize)
+	w.i64Field(9, c.dataPageOffset)
+	w.structEnd()
+	w.structEnd()
+}
+
+// rowGroupMeta is the Thrift struct `RowGroup`.
+type rowGroupMeta struct {
+	columns       []columnChunkMeta
+	totalByteSize int64
+	numRows       int64
+}
+
+func (g *rowGroupMeta) encode(w *thriftWriter) {
+	w.structBegin()
+	w.listFieldBegin(1, thriftStruct, len(g.columns))
+	for i := range g.columns {
+		g.columns[i].encode(w)
+	}
+	w.i64Field(2, g.totalByteSize)
+	w.i64Field(3, g.numRows)
+	w.structEnd()
+}
+
+// encodeFileMetaData writes the Thrift struct `FileMetaData`, which makes up
+// the footer of a Parquet file.
+func encodeFileMetaData(
+	w *thriftWriter, schema []schemaElement, numRows int64, rowGroups []rowGroupMeta,
+) {
+	w.structBegin()
+	w.i32Field(1, 1 /* version */)
+	w.listFieldBegin(2, thriftStruct, len(schema))
+	for i := range schema {
+		schema[i].encode(w)
+	}
+	w.i64Field(3, numRows)
+	w.listFieldBegin(4, thriftStruct, len(rowGroups))
+	for i := range rowGroups {
+		rowGroups[i].encode(w)
+	}
+	w.stringField(6, createdBy)
+	w.structEnd()
+}
+
+// createdBy is recorded in the footer of every file written by this package.
+const createdBy = "CockroachDB"
+
+// encodeDataPageHeader writes the Thrift struct `PageHeader` for a v1 data
+// page.
+func encodeDataPageHeader(w *thriftWriter, numValues, uncompressedSize, compressedSize int32) {
+	w.structBegin()
+	w.i32Field(1, pageTypeDataPage)
+	w.i32Field(2, uncompressedSize)
+	w.i32Field(3, compressedSize)
+	w.structFieldBegin(5)
+	w.i32Field(1, numValues)
+	w.i32Field(2, int32(encodingPlain))
+	w.i32Field(3, int32(encodingRLE))
+	w.i32Field(4, int32(encodingRLE))
+	w.structEnd()
+	w.structEnd()
+}
@@ -0,0 +1,1025 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// Reader reads the rows of a Parquet file. Only the footer of the file and the
+// column chunks of the selected columns are read, one row group at a time, so
+// the file doesn't need to fit in memory. Besides the files
+// written by Writer, it can read the files produced by most other writers, as
+// long as they stick to:
+//
+//  - top-level columns that are either primitives or lists of primitives (LIST
+//    annotated groups, in their two- and three-level forms, and repeated
+//    primitives);
+//  - the PLAIN, PLAIN_DICTIONARY, RLE_DICTIONARY and (for booleans) RLE
+//    encodings, in v1 or v2 data pages;
+//  - uncompressed, snappy or gzip compressed pages.
+//
+// Columns that don't fit these restrictions are reported by Columns but can't
+// be selected. Values are returned as datums of the type that best matches the
+// physical and logical type of each column. A Reader is not safe for
+// concurrent use.
+type Reader struct {
+	r         io.ReaderAt
+	size      int64
+	cols      []readColumn
+	rowGroups []thriftStructValue
+	numRows   int64
+
+	// selected has the indexes in cols of the columns returned by Next.
+	selected []int
+	// chunks has, for every selected column, the decoded column chunk of the
+	// current row group.
+	chunks      []decodedChunk
+	nextGroup   int
+	rowsInGroup int64
+	rowInGroup  int64
+	rowsRead    int64
+}
+
+// readColumn is a top-level column of the file being read.
+type readColumn struct {
+	name string
+	// err is set if the column can't be read.
+	err error
+	// leaf is the schema element of the primitive values of the column.
+	leaf *schemaElement
+	// leafIdx is the index of the column's leaf among all the leaves of the
+	// schema, which is also the index of its chunk in every row group.
+	leafIdx int
+	// typ is the type of the datums decoded from the column's values.
+	typ *types.T
+	// maxDef is the maximum definition level of the leaf.
+	maxDef int32
+	// isList is set for columns holding lists. listDef is the definition level
+	// at which the list itself is non-NULL and elemDef the one at which it has
+	// at least one, possibly NULL, element.
+	isList           bool
+	listDef, elemDef int32
+}
+
+// NewReader returns a Reader for the Parquet file in data. All the columns
+// that can be read are initially selected.
+func NewReader(data []byte) (*Reader, error) {
+	return NewReaderAt(bytes.NewReader(data), int64(len(data)))
+}
+
+// NewReaderAt returns a Reader for the Parquet file of the given size read
+// from r. All the columns that can be read are initially selected.
+func NewReaderAt(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < int64(2*len(magic)+4) {
+		return nil, errors.New("parquet: not a parquet file")
+	}
+	head := make([]byte, len(magic))
+	if err := readFullAt(r, head, 0); err != nil {
+		return nil, errors.Wrap(err, "parquet: reading header")
+	}
+	tail := make([]byte, 4+len(magic))
+	if err := readFullAt(r, tail, size-int64(len(tail))); err != nil {
+		return nil, errors.Wrap(err, "parquet: reading footer")
+	}
+	if string(head) != magic || string(tail[4:]) != magic {
+		return nil, errors.New("parquet: not a parquet file")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(tail))
+	footerEnd := size - int64(len(tail))
+	if footerLen <= 0 || footerLen > footerEnd-int64(len(magic)) {
+		return nil, errors.New("parquet: invalid footer length")
+	}
+	footerBuf := make([]byte, footerLen)
+	if err := readFullAt(r, footerBuf, footerEnd-footerLen); err != nil {
+		return nil, errors.Wrap(err, "parquet: reading footer")
+	}
+	footer := thriftReader{buf: footerBuf}
+	meta, err := footer.readStruct()
+	if err != nil {
+		return nil, errors.Wrap(err, "parquet: reading footer")
+	}
+
+	rd := &Reader{r: r, size: size, numRows: meta.i64(3)}
+	var elements []schemaElement
+	for _, e := range meta.list(2) {
+		s, ok := e.(thriftStructValue)
+		if !ok {
+			return nil, errors.New("parquet: invalid schema")
+		}
+		elements = append(elements, decodeSchemaElement(s))
+	}
+	if rd.cols, err = readSchema(elements); err != nil {
+		return nil, err
+	}
+	for _, rg := range meta.list(4) {
+		s, ok := rg.(thriftStructValue)
+		if !ok {
+			return nil, errors.New("parquet: invalid row group")
+		}
+		rd.rowGroups = append(rd.rowGroups, s)
+	}
+	for i := range rd.cols {
+		if rd.cols[i].err == nil {
+			rd.selected = append(rd.selected, i)
+		}
+	}
+	return rd, nil
+}
+
+// Columns returns the names of the top-level columns of the file.
+func (r *Reader) Columns() []string {
+	names := make([]string, len(r.cols))
+	for i := range r.cols {
+		names[i] = r.cols[i].name
+	}
+	return names
+}
+
+// ColumnType returns the type of the datums returned for the given column.
+// It returns an error if the column can't be read.
+func (r *Reader) ColumnType(col int) (*types.T, error) {
+	if r.cols[col].err != nil {
+		return nil, r.cols[col].err
+	}
+	return r.cols[col].typ, nil
+}
+
+// Select sets the columns returned by Next, which are identified by their
+// index in Columns. It must be called before the first call to Next.
+func (r *Reader) Select(cols []int) error {
+	if r.rowsRead > 0 || r.nextGroup > 0 {
+		return errors.AssertionFailedf("parquet: Select called after Next")
+	}
+	for _, c := range cols {
+		if c < 0 || c >= len(r.cols) {
+			return errors.AssertionFailedf("parquet: invalid column %d", c)
+		}
+		if err := r.cols[c].err; err != nil {
+			return err
+		}
+	}
+	r.selected = append(r.selected[:0], cols...)
+	return nil
+}
+
+// NumRows returns the number of rows in the file.
+func (r *Reader) NumRows() int64 {
+	return r.numRows
+}
+
+// RowsRead returns the number of rows returned by Next so far.
+func (r *Reader) RowsRead() int64 {
+	return r.rowsRead
+}
+
+// Next returns the values of the selected columns in the next row, or io.EOF
+// if all rows have been read. NULL values are returned as tree.DNull.
+func (r *Reader) Next() (tree.Datums, error) {
+	for r.rowInGroup >= r.rowsInGroup {
+		if r.nextGroup >= len(r.rowGroups) {
+			return nil, io.EOF
+		}
+		if err := r.readRowGroup(r.rowGroups[r.nextGroup]); err != nil {
+			return nil, err
+		}
+		r.nextGroup++
+	}
+	row := make(tree.Datums, len(r.selected))
+	for i, c := range r.selected {
+		d, err := r.chunks[i].next(&r.cols[c])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parquet: column %q", r.cols[c].name)
+		}
+		row[i] = d
+	}
+	r.rowInGroup++
+	r.rowsRead++
+	return row, nil
+}
+
+func (r *Reader) readRowGroup(rg thriftStructValue) error {
+	chunks := rg.list(1)
+	r.chunks = r.chunks[:0]
+	for _, c := range r.selected {
+		col := &r.cols[c]
+		if col.leafIdx >= len(chunks) {
+			return errors.Newf("parquet: missing column chunk for %q", col.name)
+		}
+		chunk, ok := chunks[col.leafIdx].(thriftStructValue)
+		if !ok {
+			return errors.New("parquet: invalid column chunk")
+		}
+		decoded, err := r.readColumnChunk(col, chunk.strct(3))
+		if err != nil {
+			return errors.Wrapf(err, "parquet: column %q", col.name)
+		}
+		r.chunks = append(r.chunks, decoded)
+	}
+	r.rowsInGroup = rg.i64(3)
+	r.rowInGroup = 0
+	return nil
+}
+
+// decodedChunk holds the levels and values of a column chunk.
+type decodedChunk struct {
+	defLevels []int32
+	// repLevels is only set for lists.
+	repLevels []int32
+	values    []tree.Datum
+	levelPos  int
+	valuePos  int
+}
+
+// next returns the value of the column in the next row.
+func (c *decodedChunk) next(col *readColumn) (tree.Datum, error) {
+	if c.levelPos >= len(c.defLevels) {
+		return nil, errors.New("not enough values")
+	}
+	if !col.isList {
+		def := c.defLevels[c.levelPos]
+		c.levelPos++
+		if def < col.maxDef {
+			return tree.DNull, nil
+		}
+		return c.nextValue()
+	}
+
+	def := c.defLevels[c.levelPos]
+	if def < col.listDef {
+		c.levelPos++
+		return tree.DNull, nil
+	}
+	arr := tree.NewDArray(col.typ.ArrayContents())
+	if def < col.elemDef {
+		c.levelPos++
+		return arr, nil
+	}
+	for {
+		def := c.defLevels[c.levelPos]
+		c.levelPos++
+		elem := tree.DNull
+		if def >= col.maxDef {
+			var err error
+			if elem, err = c.nextValue(); err != nil {
+				return nil, err
+			}
+		}
+		if err := arr.Append(elem); err != nil {
+			return nil, err
+		}
+		if c.levelPos >= len(c.repLevels) || c.repLevels[c.levelPos] == 0 {
+			return arr, nil
+		}
+	}
+}
+
+func (c *decodedChunk) nextValue() (tree.Datum, error) {
+	if c.valuePos >= len(c.values) {
+		return nil, errors.New("not enough values")
+	}
+	d := c.values[c.valuePos]
+	c.valuePos++
+	return d, nil
+}
+
+// readFullAt reads len(buf) bytes of r at off.
+func readFullAt(r io.ReaderAt, buf []byte, off int64) error {
+	n, err := r.ReadAt(buf, off)
+	if n == len(buf) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (r *Reader) readColumnChunk(col *readColumn, meta thriftStructValue) (decodedChunk, error) {
+	var c decodedChunk
+	codec := CompressionCodec(meta.i64(4))
+	numValues := meta.i64(5)
+	chunkStart := meta.i64(9)
+	if dictOffset := meta.i64(11); meta.has(11) && dictOffset > 0 && dictOffset < chunkStart {
+		chunkStart = dictOffset
+	}
+	// The pages of the chunk, including their headers, are contiguous, so the
+	// whole chunk is read at once.
+	chunkLen := meta.i64(7)
+	if chunkStart < 0 || chunkLen <= 0 || chunkStart+chunkLen > r.size {
+		return c, errors.New("invalid column chunk range")
+	}
+	data := make([]byte, chunkLen)
+	if err := readFullAt(r.r, data, chunkStart); err != nil {
+		return c, errors.Wrap(err, "reading column chunk")
+	}
+	var offset int64
+
+	var dict []tree.Datum
+	var maxRep int32
+	if col.isList {
+		maxRep = 1
+	}
+	for int64(len(c.defLevels)) < numValues {
+		if offset >= int64(len(data)) {
+			return c, errors.New("invalid page offset")
+		}
+		tr := thriftReader{buf: data, pos: int(offset)}
+		header, err := tr.readStruct()
+		if err != nil {
+			return c, errors.Wrap(err, "reading page header")
+		}
+		compressedSize := header.i64(3)
+		start := int64(tr.pos)
+		if compressedSize < 0 || start+compressedSize > int64(len(data)) {
+			return c, errors.New("truncated page")
+		}
+		body := data[start : start+compressedSize]
+		offset = start + compressedSize
+		uncompressedSize := header.i64(2)
+
+		switch header.i64(1) {
+		case pageTypeDictionaryPage:
+			page, err := decompress(codec, body, uncompressedSize)
+			if err != nil {
+				return c, err
+			}
+			dictHeader := header.strct(7)
+			n := int(dictHeader.i64(1))
+			if dict, _, err = decodePlain(col, page, n); err != nil {
+				return c, err
+			}
+
+		case pageTypeDataPage:
+			page, err := decompress(codec, body, uncompressedSize)
+			if err != nil {
+				return c, err
+			}
+			h := header.strct(5)
+			n := int(h.i64(1))
+			if maxRep > 0 {
+				var levels []int32
+				if levels, page, err = decodeLevelsV1(page, maxRep, n); err != nil {
+					return c, err
+				}
+				c.repLevels = append(c.repLevels, levels...)
+			}
+			defLevels := make([]int32, n)
+			if col.maxDef > 0 {
+				if defLevels, page, err = decodeLevelsV1(page, col.maxDef, n); err != nil {
+					return c, err
+				}
+			}
+			c.defLevels = append(c.defLevels, defLevels...)
+			if err := c.appendValues(col, encoding(h.i64(2)), page, countValues(defLevels, col.maxDef), dict); err != nil {
+				return c, err
+			}
+
+		case pageTypeDataPageV2:
+			h := header.strct(8)
+			n := int(h.i64(1))
+			repLen, defLen := h.i64(6), h.i64(5)
+			if repLen < 0 || defLen < 0 || repLen+defLen > int64(len(body)) {
+				return c, errors.New("invalid level lengths")
+			}
+			if maxRep > 0 {
+				levels, err := decodeHybrid(body[:repLen], bitWidth(maxRep), n)
+				if err != nil {
+					return c, err
+				}
+				c.repLevels = append(c.repLevels, levels...)
+			}
+			defLevels := make([]int32, n)
+			if col.maxDef > 0 {
+				var err error
+				if defLevels, err = decodeHybrid(body[repLen:repLen+defLen], bitWidth(col.maxDef), n); err != nil {
+					return c, err
+				}
+			}
+			c.defLevels = append(c.defLevels, defLevels...)
+			page := body[repLen+defLen:]
+			// Pages are compressed unless is_compressed is explicitly false.
+			if isCompressed, ok := h[7].(bool); !ok || isCompressed {
+				var err error
+				if page, err = decompress(codec, page, uncompressedSize-repLen-defLen); err != nil {
+					return c, err
+				}
+			}
+			if err := c.appendValues(col, encoding(h.i64(4)), page, countValues(defLevels, col.maxDef), dict); err != nil {
+				return c, err
+			}
+
+		default:
+			// Index pages and unknown page types are skipped.
+		}
+	}
+	return c, nil
+}
+
+func countValues(defLevels []int32, maxDef int32) int {
+	var n int
+	for _, d := range defLevels {
+		if d >= maxDef {
+			n++
+		}
+	}
+	return n
+}
+
+// appendValues decodes the n values of a data page.
+func (c *decodedChunk) appendValues(
+	col *readColumn, enc encoding, page []byte, n int, dict []tree.Datum,
+) error {
+	switch enc {
+	case encodingPlain:
+		values, _, err := decodePlain(col, page, n)
+		if err != nil {
+			return err
+		}
+		c.values = append(c.values, values...)
+	case encodingPlainDictionary, encodingRLEDictionary:
+		if n == 0 {
+			return nil
+		}
+		if len(page) == 0 {
+			return errors.New("truncated dictionary indexes")
+		}
+		idxs, err := decodeHybrid(page[1:], int(page[0]), n)
+		if err != nil {
+			return err
+		}
+		for _, idx := range idxs {
+			if idx < 0 || int(idx) >= len(dict) {
+				return errors.Newf("invalid dictionary index %d", idx)
+			}
+			c.values = append(c.values, dict[idx])
+		}
+	case encodingRLE:
+		if col.leaf.physical != physicalBoolean {
+			return errors.Newf("unsupported encoding %d", enc)
+		}
+		if len(page) < 4 {
+			return errors.New("truncated boolean values")
+		}
+		bools, err := decodeHybrid(page[4:], 1, n)
+		if err != nil {
+			return err
+		}
+		for _, b := range bools {
+			c.values = append(c.values, tree.MakeDBool(b != 0))
+		}
+	default:
+		return errors.Newf("unsupported encoding %d", enc)
+	}
+	return nil
+}
+
+func decompress(codec CompressionCodec, body []byte, uncompressedSize int64) ([]byte, error) {
+	switch codec {
+	case CompressionNone:
+		return body, nil
+	case compressionSnappy:
+		return snappy.Decode(nil, body)
+	case CompressionGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		if uncompressedSize > 0 {
+			buf := bytes.NewBuffer(make([]byte, 0, uncompressedSize))
+			_, err := io.Copy(buf, gz)
+			return buf.Bytes(), err
+		}
+		return ioutil.ReadAll(gz)
+	default:
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"parquet: unsupported compression codec %d", codec)
+	}
+}
+
+// bitWidth returns the number of bits needed to store levels up to max.
+func bitWidth(max int32) int {
+	w := 0
+	for max > 0 {
+		w++
+		max >>= 1
+	}
+	return w
+}
+
+// decodeLevelsV1 decodes the levels at the start of a v1 data page, which are
+// preceded by their length, and returns the rest of the page.
+func decodeLevelsV1(page []byte, max int32, n int) ([]int32, []byte, error) {
+	if len(page) < 4 {
+		return nil, nil, errors.New("truncated levels")
+	}
+	l := int(binary.LittleEndian.Uint32(page))
+	if l < 0 || l > len(page)-4 {
+		return nil, nil, errors.New("truncated levels")
+	}
+	levels, err := decodeHybrid(page[4:4+l], bitWidth(max), n)
+	return levels, page[4+l:], err
+}
+
+// decodeHybrid decodes n values from the RLE/bit-packed hybrid encoding.
+func decodeHybrid(buf []byte, width int, n int) ([]int32, error) {
+	if width < 0 || width > 32 {
+		return nil, errors.Newf("invalid bit width %d", width)
+	}
+	out := make([]int32, 0, n)
+	byteWidth := (width + 7) / 8
+	for len(out) < n {
+		header, k := binary.Uvarint(buf)
+		if k <= 0 {
+			return nil, errors.New("truncated RLE data")
+		}
+		buf = buf[k:]
+		if header&1 == 0 {
+			// An RLE run: the repeat count followed by the value, stored in the
+			// smallest number of bytes that fit the bit width.
+			count := int(header >> 1)
+			if len(buf) < byteWidth {
+				return nil, errors.New("truncated RLE run")
+			}
+			var v uint32
+			for i := 0; i < byteWidth; i++ {
+				v |= uint32(buf[i]) << (8 * uint(i))
+			}
+			buf = buf[byteWidth:]
+			for i := 0; i < count && len(out) < n; i++ {
+				out = append(out, int32(v))
+			}
+			continue
+		}
+		// A bit-packed run of groups of 8 values, least significant bit first.
+		// Some writers don't pad the last run, so it may be cut short.
+		groups := int(header >> 1)
+		size := groups * width
+		if size > len(buf) {
+			size = len(buf)
+		}
+		for i := 0; i < groups*8 && len(out) < n; i++ {
+			var v uint32
+			for b := 0; b < width; b++ {
+				bit := i*width + b
+				if bit/8 >= size {
+					return nil, errors.New("truncated bit-packed run")
+				}
+				v |= uint32(buf[bit/8]>>(uint(bit)%8)&1) << uint(b)
+			}
+			out = append(out, int32(v))
+		}
+		buf = buf[size:]
+	}
+	return out, nil
+}
+
+// decodePlain decodes n PLAIN encoded values of a column and returns them
+// along with the rest of the buffer.
+func decodePlain(col *readColumn, buf []byte, n int) ([]tree.Datum, []byte, error) {
+	values := make([]tree.Datum, 0, n)
+	leaf := col.leaf
+	typ := col.typ
+	if col.isList {
+		typ = typ.ArrayContents()
+	}
+	truncated := errors.New("truncated values")
+	for i := 0; i < n; i++ {
+		var raw interface{}
+		switch leaf.physical {
+		case physicalBoolean:
+			byteIdx := i / 8
+			if byteIdx >= len(buf) {
+				return nil, nil, truncated
+			}
+			values = append(values, tree.MakeDBool(buf[byteIdx]&(1<<uint(i%8)) != 0))
+			if i == n-1 {
+				buf = buf[byteIdx+1:]
+			}
+			continue
+		case physicalInt32:
+			if len(buf) < 4 {
+				return nil, nil, truncated
+			}
+			raw = int64(int32(le.Uint32(buf)))
+			buf = buf[4:]
+		case physicalInt64:
+			if len(buf) < 8 {
+				return nil, nil, truncated
+			}
+			raw = int64(le.Uint64(buf))
+			buf = buf[8:]
+		case physicalInt96:
+			if len(buf) < 12 {
+				return nil, nil, truncated
+			}
+			raw = buf[:12]
+			buf = buf[12:]
+		case physicalFloat:
+			if len(buf) < 4 {
+				return nil, nil, truncated
+			}
+			raw = float64(math.Float32frombits(le.Uint32(buf)))
+			buf = buf[4:]
+		case physicalDouble:
+			if len(buf) < 8 {
+				return nil, nil, truncated
+			}
+			raw = math.Float64frombits(le.Uint64(buf))
+			buf = buf[8:]
+		case physicalByteArray:
+			if len(buf) < 4 {
+				return nil, nil, truncated
+			}
+			l := int(le.Uint32(buf))
+			if l < 0 || l > len(buf)-4 {
+				return nil, nil, truncated
+			}
+			raw = buf[4 : 4+l]
+			buf = buf[4+l:]
+		case physicalFixedLenByteArray:
+			l := int(leaf.typeLength)
+			if l < 0 || l > len(buf) {
+				return nil, nil, truncated
+			}
+			raw = buf[:l]
+			buf = buf[l:]
+		default:
+			return nil, nil, errors.Newf("unsupported physical type %d", leaf.physical)
+		}
+		d, err := leaf.toDatum(typ, raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values, d)
+	}
+	return values, buf, nil
+}
+
+// decodeSchemaElement decodes the Thrift struct `SchemaElement`.
+func decodeSchemaElement(s thriftStructValue) schemaElement {
+	e := schemaElement{
+		name:        string(s.bytes(4)),
+		physical:    physicalType(s.i64(1)),
+		hasPhysical: s.has(1),
+		typeLength:  int32(s.i64(2)),
+		repetition:  repetitionType(s.i64(3)),
+		numChildren: int32(s.i64(5)),
+		converted:   convertedNone,
+		scale:       int32(s.i64(7)),
+		precision:   int32(s.i64(8)),
+	}
+	if s.has(6) {
+		e.converted = convertedType(s.i64(6))
+	}
+	if l := s.strct(10); l != nil {
+		for id, v := range l {
+			e.logical.id = logicalTypeID(id)
+			member, _ := v.(thriftStructValue)
+			switch e.logical.id {
+			case logicalDecimal:
+				e.logical.scale, e.logical.precision = int32(member.i64(1)), int32(member.i64(2))
+			case logicalTime, logicalTimestamp:
+				e.logical.isAdjustedToUTC, _ = member[1].(bool)
+				for unit := range member.strct(2) {
+					e.logical.unit = timeUnit(unit)
+				}
+			case logicalInteger:
+				e.logical.bitWidth = int8(member.i64(1))
+				e.logical.isSigned, _ = member[2].(bool)
+			}
+		}
+	}
+	return e
+}
+
+// readSchema returns the top-level columns described by a flattened schema.
+func readSchema(elements []schemaElement) ([]readColumn, error) {
+	if len(elements) == 0 {
+		return nil, errors.New("parquet: empty schema")
+	}
+	var cols []readColumn
+	pos := 1
+	leaves := 0
+	for i := int32(0); i < elements[0].numChildren; i++ {
+		if pos >= len(elements) {
+			return nil, errors.New("parquet: truncated schema")
+		}
+		start := pos
+		end, err := skipSchemaElement(elements, pos)
+		if err != nil {
+			return nil, err
+		}
+		pos = end
+		col := readColumn{name: elements[start].name, leafIdx: leaves}
+		for j := start; j < end; j++ {
+			if elements[j].numChildren == 0 {
+				leaves++
+			}
+		}
+		if leaves-col.leafIdx != 1 {
+			col.err = unsupportedColumn(col.name)
+		} else {
+			col.err = col.init(elements[start:end])
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// skipSchemaElement returns the position following the element at pos and all
+// of its descendants.
+func skipSchemaElement(elements []schemaElement, pos int) (int, error) {
+	n := elements[pos].numChildren
+	pos++
+	for i := int32(0); i < n; i++ {
+		if pos >= len(elements) {
+			return 0, errors.New("parquet: truncated schema")
+		}
+		var err error
+		if pos, err = skipSchemaElement(elements, pos); err != nil {
+			return 0, err
+		}
+	}
+	return pos, nil
+}
+
+func unsupportedColumn(name string) error {
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"parquet: column %q has a nested type that is not supported", name)
+}
+
+// init sets up a column from its schema elements, which are the top-level
+// element followed by its descendants, which lead to a single leaf.
+func (col *readColumn) init(elements []schemaElement) error {
+	top := &elements[0]
+	switch {
+	case len(elements) == 1 && top.repetition != repetitionRepeated:
+		col.leaf = top
+		if top.repetition == repetitionOptional {
+			col.maxDef = 1
+		}
+
+	case len(elements) == 1:
+		// A repeated primitive is a list of non-NULL values, which is empty when
+		// there are none.
+		col.leaf = top
+		col.isList = true
+		col.listDef, col.elemDef, col.maxDef = 0, 1, 1
+
+	case top.converted == convertedList || top.logical.id == logicalList:
+		if top.repetition == repetitionRepeated || top.numChildren != 1 ||
+			elements[1].repetition != repetitionRepeated {
+			return unsupportedColumn(col.name)
+		}
+		col.isList = true
+		if top.repetition == repetitionOptional {
+			col.listDef = 1
+		}
+		col.elemDef = col.listDef + 1
+		switch {
+		case len(elements) == 2:
+			// Two-level list: the repeated element is the primitive.
+			col.leaf = &elements[1]
+			col.maxDef = col.elemDef
+		case len(elements) == 3 && elements[1].numChildren == 1 &&
+			elements[2].repetition != repetitionRepeated:
+			col.leaf = &elements[2]
+			col.maxDef = col.elemDef
+			if col.leaf.repetition == repetitionOptional {
+				col.maxDef++
+			}
+		default:
+			return unsupportedColumn(col.name)
+		}
+
+	default:
+		return unsupportedColumn(col.name)
+	}
+
+	typ, err := col.leaf.datumType()
+	if err != nil {
+		return errors.Wrapf(err, "parquet: column %q", col.name)
+	}
+	col.typ = typ
+	if col.isList {
+		col.typ = types.MakeArray(typ)
+	}
+	return nil
+}
+
+// datumType returns the type of the datums decoded from a leaf's values.
+func (e *schemaElement) datumType() (*types.T, error) {
+	switch e.physical {
+	case physicalBoolean:
+		return types.Bool, nil
+	case physicalInt32, physicalInt64:
+		switch {
+		case e.isDecimal():
+			return types.MakeDecimal(e.decimalPrecision(), e.decimalScale()), nil
+		case e.logical.id == logicalDate || e.converted == convertedDate:
+			return types.Date, nil
+		case e.logical.id == logicalTime ||
+			e.converted == convertedTimeMillis || e.converted == convertedTimeMicros:
+			return types.Time, nil
+		case e.logical.id == logicalTimestamp:
+			if e.logical.isAdjustedToUTC {
+				return types.TimestampTZ, nil
+			}
+			return types.Timestamp, nil
+		case e.converted == convertedTimestampMillis || e.converted == convertedTimestampMicros:
+			return types.TimestampTZ, nil
+		case e.physical == physicalInt32:
+			return types.Int4, nil
+		default:
+			return types.Int, nil
+		}
+	case physicalInt96:
+		// INT96 is a deprecated timestamp representation, still written by
+		// some writers. The values are instants, adjusted to UTC.
+		return types.TimestampTZ, nil
+	case physicalFloat:
+		return types.Float4, nil
+	case physicalDouble:
+		return types.Float, nil
+	case physicalByteArray, physicalFixedLenByteArray:
+		switch {
+		case e.isDecimal():
+			return types.MakeDecimal(e.decimalPrecision(), e.decimalScale()), nil
+		case e.logical.id == logicalString || e.logical.id == logicalEnum ||
+			e.converted == convertedUTF8 || e.converted == convertedEnum:
+			return types.String, nil
+		case e.logical.id == logicalJSON || e.converted == convertedJSON:
+			return types.Jsonb, nil
+		case e.logical.id == logicalUUID && e.physical == physicalFixedLenByteArray &&
+			e.typeLength == uuid.Size:
+			return types.Uuid, nil
+		default:
+			return types.Bytes, nil
+		}
+	default:
+		return nil, errors.Newf("unsupported physical type %d", e.physical)
+	}
+}
+
+func (e *schemaElement) isDecimal() bool {
+	return e.logical.id == logicalDecimal || e.converted == convertedDecimal
+}
+
+func (e *schemaElement) decimalScale() int32 {
+	if e.logical.id == logicalDecimal {
+		return e.logical.scale
+	}
+	return e.scale
+}
+
+func (e *schemaElement) decimalPrecision() int32 {
+	if e.logical.id == logicalDecimal {
+		return e.logical.precision
+	}
+	return e.precision
+}
+
+// timeUnit returns the unit of TIME and TIMESTAMP values.
+func (e *schemaElement) timeUnit() timeUnit {
+	switch {
+	case e.logical.id == logicalTime || e.logical.id == logicalTimestamp:
+		if e.logical.unit == 0 {
+			return timeUnitMicros
+		}
+		return e.logical.unit
+	case e.converted == convertedTimeMillis || e.converted == convertedTimestampMillis:
+		return timeUnitMillis
+	default:
+		return timeUnitMicros
+	}
+}
+
+// julianDayOfUnixEpoch is the Julian day number of 1970-01-01, which INT96
+// timestamps are relative to.
+const julianDayOfUnixEpoch = 2440588
+
+// toDatum converts a raw PLAIN decoded value, which is an int64, a float64 or
+// a []byte depending on the physical type, to a datum of the leaf's datumType.
+func (e *schemaElement) toDatum(typ *types.T, raw interface{}) (tree.Datum, error) {
+	switch v := raw.(type) {
+	case int64:
+		switch typ.Family() {
+		case types.DecimalFamily:
+			return decodeDecimal(big.NewInt(v), e.decimalScale()), nil
+		case types.DateFamily:
+			date, err := pgdate.MakeDateFromUnixEpoch(v)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDDate(date), nil
+		case types.TimeFamily:
+			return tree.MakeDTime(timeofday.TimeOfDay(toMicros(v, e.timeUnit()))), nil
+		case types.TimestampFamily, types.TimestampTZFamily:
+			var t time.Time
+			switch e.timeUnit() {
+			case timeUnitMillis:
+				t = time.Unix(v/1e3, (v%1e3)*1e6)
+			case timeUnitNanos:
+				t = time.Unix(0, v)
+			default:
+				t = time.Unix(v/1e6, (v%1e6)*1e3)
+			}
+			if typ.Family() == types.TimestampTZFamily {
+				return tree.MakeDTimestampTZ(t.UTC(), time.Microsecond)
+			}
+			return tree.MakeDTimestamp(t.UTC(), time.Microsecond)
+		default:
+			return tree.NewDInt(tree.DInt(v)), nil
+		}
+	case float64:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+	case []byte:
+		if e.physical == physicalInt96 {
+			nanos := int64(le.Uint64(v))
+			days := int64(le.Uint32(v[8:]))
+			t := time.Unix((days-julianDayOfUnixEpoch)*86400, nanos).UTC()
+			return tree.MakeDTimestampTZ(t, time.Microsecond)
+		}
+		switch typ.Family() {
+		case types.DecimalFamily:
+			return decodeDecimal(decodeBigEndianInt(v), e.decimalScale()), nil
+		case types.StringFamily:
+			return tree.NewDString(string(v)), nil
+		case types.JsonFamily:
+			j, err := json.ParseJSON(string(v))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDJSON(j), nil
+		case types.UuidFamily:
+			u, err := uuid.FromBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDUuid(tree.DUuid{UUID: u}), nil
+		default:
+			return tree.NewDBytes(tree.DBytes(v)), nil
+		}
+	default:
+		return nil, errors.AssertionFailedf("unexpected raw value %T", raw)
+	}
+}
+
+func toMicros(v int64, unit timeUnit) int64 {
+	switch unit {
+	case timeUnitMillis:
+		return v * 1000
+	case timeUnitNanos:
+		return v / 1000
+	default:
+		return v
+	}
+}
+
+// decodeBigEndianInt decodes a big-endian two's complement integer, the
+// inverse of what encodeDecimal writes.
+func decodeBigEndianInt(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return v
+}
+
+func decodeDecimal(unscaled *big.Int, scale int32) *tree.DDecimal {
+	d := &tree.DDecimal{}
+	d.Coeff.Abs(unscaled)
+	d.Negative = unscaled.Sign() < 0
+	d.Exponent = -scale
+	d.Form = apd.Finite
+	return d
+}
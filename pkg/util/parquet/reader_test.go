@@ -0,0 +1,277 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, r *Reader) []string {
+	t.Helper()
+	var rows []string
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row.String())
+	}
+}
+
+// countingReaderAt counts the bytes read from the wrapped io.ReaderAt.
+type countingReaderAt struct {
+	r io.ReaderAt
+	n int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.n += int64(n)
+	return n, err
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	names := []string{`b`, `i4`, `i`, `f`, `d`, `s`, `date`, `ts`, `u`, `j`, `a`, `by`}
+	typs := []*types.T{
+		types.Bool, types.Int4, types.Int, types.Float, types.MakeDecimal(10, 2), types.String,
+		types.Date, types.TimestampTZ, types.Uuid, types.Jsonb, types.MakeArray(types.Int),
+		types.Bytes,
+	}
+	sch, err := NewSchema(names, typs)
+	require.NoError(t, err)
+
+	date, err := pgdate.MakeDateFromUnixEpoch(18262)
+	require.NoError(t, err)
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	u := uuid.FromStringOrNil(`8a4d1b2e-6b35-4a4f-9d43-5b3a5d8e7f01`)
+	j, err := tree.ParseDJSON(`{"a": [1, "b"]}`)
+	require.NoError(t, err)
+	d, err := tree.ParseDDecimal(`-12.34`)
+	require.NoError(t, err)
+	arr := tree.NewDArray(types.Int)
+	require.NoError(t, arr.Append(tree.NewDInt(1)))
+	require.NoError(t, arr.Append(tree.DNull))
+
+	rows := []tree.Datums{
+		{
+			tree.DBoolTrue, tree.NewDInt(-7), tree.NewDInt(1 << 40), tree.NewDFloat(1.5),
+			d, tree.NewDString(`hello`), tree.NewDDate(date),
+			tree.MustMakeDTimestampTZ(ts, time.Microsecond), tree.NewDUuid(tree.DUuid{UUID: u}),
+			j, arr, tree.NewDBytes(`\x00`),
+		},
+		{
+			tree.DBoolFalse, tree.DNull, tree.DNull, tree.DNull,
+			tree.DNull, tree.DNull, tree.DNull,
+			tree.DNull, tree.DNull,
+			tree.DNull, tree.NewDArray(types.Int), tree.DNull,
+		},
+		{
+			tree.DNull, tree.NewDInt(0), tree.NewDInt(-1), tree.NewDFloat(-0.25),
+			tree.DNull, tree.NewDString(``), tree.DNull,
+			tree.DNull, tree.DNull,
+			tree.DNull, tree.DNull, tree.DNull,
+		},
+	}
+	expected := []string{
+		`(true, -7, 1099511627776, 1.5, -12.34, 'hello', '2020-01-01', ` +
+			`'2020-01-02 03:04:05.000006+00:00', '8a4d1b2e-6b35-4a4f-9d43-5b3a5d8e7f01', ` +
+			`'{"a": [1, "b"]}', ARRAY[1,NULL], '\x5c783030')`,
+		`(false, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, ARRAY[], NULL)`,
+		`(NULL, 0, -1, -0.25, NULL, '', NULL, NULL, NULL, NULL, NULL, NULL)`,
+	}
+
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionGzip} {
+		t.Run(fmt.Sprintf("codec=%d", codec), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(sch, &buf, WriterOptions{RowGroupSize: 2, Compression: codec})
+			for _, row := range rows {
+				require.NoError(t, w.AddRow(row))
+			}
+			require.NoError(t, w.Close())
+
+			r, err := NewReader(buf.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, names, r.Columns())
+			require.Equal(t, int64(3), r.NumRows())
+			for i := range typs {
+				typ, err := r.ColumnType(i)
+				require.NoError(t, err)
+				require.True(t, typ.Equivalent(typs[i]), "%s vs %s", typ, typs[i])
+			}
+			require.Equal(t, expected, readAll(t, r))
+			require.Equal(t, int64(3), r.RowsRead())
+
+			// Only the footer and the column chunks of the selected columns are
+			// read.
+			counter := &countingReaderAt{r: bytes.NewReader(buf.Bytes())}
+			r, err = NewReaderAt(counter, int64(buf.Len()))
+			require.NoError(t, err)
+			require.NoError(t, r.Select([]int{5, 0}))
+			require.Equal(t, []string{`('hello', true)`, `(NULL, false)`, `('', NULL)`}, readAll(t, r))
+			require.True(t, counter.n < int64(buf.Len()), "read %d of %d bytes", counter.n, buf.Len())
+		})
+	}
+}
+
+// TestReaderDictionary reads a file with a snappy compressed, dictionary
+// encoded column in a v2 data page, which is what many other writers produce.
+func TestReaderDictionary(t *testing.T) {
+	var tw thriftWriter
+	file := []byte(magic)
+
+	// The dictionary page holds "a" and "bc".
+	dict := []byte{1, 0, 0, 0, 'a', 2, 0, 0, 0, 'b', 'c'}
+	compressedDict := snappy.Encode(nil, dict)
+	dictOffset := len(file)
+	tw.structBegin()
+	tw.i32Field(1, pageTypeDictionaryPage)
+	tw.i32Field(2, int32(len(dict)))
+	tw.i32Field(3, int32(len(compressedDict)))
+	tw.structFieldBegin(7)
+	tw.i32Field(1, 2)
+	tw.i32Field(2, int32(encodingPlainDictionary))
+	tw.structEnd()
+	tw.structEnd()
+	file = append(file, tw.buf...)
+	file = append(file, compressedDict...)
+
+	// Five rows: "bc", NULL, "a", "bc", "bc". The definition levels are a
+	// bit-packed run of 8 values: 1, 0, 1, 1, 1 followed by padding, and the
+	// indexes a bit-packed run of 1, 0, 1, 1 with a bit width of 1.
+	defLevels := []byte{1<<1 /* header */ | 1, 0x1d}
+	indexes := []byte{1, 1<<1 | 1, 0x0d}
+	compressedIndexes := snappy.Encode(nil, indexes)
+	dataOffset := len(file)
+	tw.reset()
+	tw.structBegin()
+	tw.i32Field(1, pageTypeDataPageV2)
+	tw.i32Field(2, int32(len(defLevels)+len(indexes)))
+	tw.i32Field(3, int32(len(defLevels)+len(compressedIndexes)))
+	tw.structFieldBegin(8)
+	tw.i32Field(1, 5)
+	tw.i32Field(2, 1)
+	tw.i32Field(3, 5)
+	tw.i32Field(4, int32(encodingRLEDictionary))
+	tw.i32Field(5, int32(len(defLevels)))
+	tw.i32Field(6, 0)
+	tw.structEnd()
+	tw.structEnd()
+	file = append(file, tw.buf...)
+	file = append(file, defLevels...)
+	file = append(file, compressedIndexes...)
+	chunkLen := len(file) - dictOffset
+
+	tw.reset()
+	tw.structBegin()
+	tw.listFieldBegin(2, thriftStruct, 2)
+	(&schemaElement{
+		name: "schema", repetition: repetitionRequired, numChildren: 1, converted: convertedNone,
+	}).encode(&tw)
+	(&schemaElement{
+		name: "s", physical: physicalByteArray, hasPhysical: true, repetition: repetitionOptional,
+		converted: convertedUTF8,
+	}).encode(&tw)
+	tw.i64Field(3, 5)
+	tw.listFieldBegin(4, thriftStruct, 1)
+	tw.structBegin()
+	tw.listFieldBegin(1, thriftStruct, 1)
+	tw.structBegin()
+	tw.i64Field(2, int64(dataOffset))
+	tw.structFieldBegin(3)
+	tw.i32Field(1, int32(physicalByteArray))
+	tw.listFieldBegin(2, thriftI32, 1)
+	tw.writeI32(int32(encodingRLEDictionary))
+	tw.listFieldBegin(3, thriftBinary, 1)
+	tw.writeString("s")
+	tw.i32Field(4, int32(compressionSnappy))
+	tw.i64Field(5, 5)
+	tw.i64Field(6, 0)
+	tw.i64Field(7, int64(chunkLen))
+	tw.i64Field(9, int64(dataOffset))
+	tw.i64Field(11, int64(dictOffset))
+	tw.structEnd()
+	tw.structEnd()
+	tw.i64Field(2, 0)
+	tw.i64Field(3, 5)
+	tw.structEnd()
+	tw.structEnd()
+	file = append(file, tw.buf...)
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(tw.buf)))
+	file = append(file, footerLen[:]...)
+	file = append(file, magic...)
+
+	r, err := NewReader(file)
+	require.NoError(t, err)
+	require.Equal(t, []string{`('bc')`, `(NULL)`, `('a')`, `('bc')`, `('bc')`}, readAll(t, r))
+}
+
+func TestReaderErrors(t *testing.T) {
+	_, err := NewReader([]byte(`not parquet`))
+	require.EqualError(t, err, `parquet: not a parquet file`)
+
+	// Nested groups that aren't lists can't be read, but the other columns can.
+	var tw thriftWriter
+	tw.structBegin()
+	tw.listFieldBegin(2, thriftStruct, 4)
+	for _, e := range []schemaElement{
+		{name: "schema", repetition: repetitionRequired, numChildren: 2, converted: convertedNone},
+		{name: "i", physical: physicalInt64, hasPhysical: true, repetition: repetitionRequired, converted: convertedNone},
+		{name: "g", repetition: repetitionOptional, numChildren: 1, converted: convertedNone},
+		{name: "x", physical: physicalInt64, hasPhysical: true, repetition: repetitionOptional, converted: convertedNone},
+	} {
+		e := e
+		e.encode(&tw)
+	}
+	tw.i64Field(3, 0)
+	tw.listFieldBegin(4, thriftStruct, 0)
+	tw.structEnd()
+	file := append([]byte(magic), tw.buf...)
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(tw.buf)))
+	file = append(file, footerLen[:]...)
+	file = append(file, magic...)
+
+	r, err := NewReader(file)
+	require.NoError(t, err)
+	require.Equal(t, []string{`i`, `g`}, r.Columns())
+	typ, err := r.ColumnType(0)
+	require.NoError(t, err)
+	require.Equal(t, types.Int, typ)
+	require.EqualError(t, r.Select([]int{0, 1}),
+		`parquet: column "g" has a nested type that is not supported`)
+	require.Empty(t, readAll(t, r))
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	// An RLE run of three 5s followed by a bit-packed run of 0..7 with a bit
+	// width of 3.
+	buf := []byte{3 << 1, 5, 1<<1 | 1, 0x88, 0xc6, 0xfa}
+	levels, err := decodeHybrid(buf, 3, 11)
+	require.NoError(t, err)
+	require.Equal(t, []int32{5, 5, 5, 0, 1, 2, 3, 4, 5, 6, 7}, levels)
+
+	_, err = decodeHybrid(buf[:4], 3, 11)
+	require.EqualError(t, err, `truncated bit-packed run`)
+}
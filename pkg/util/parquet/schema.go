@@ -0,0 +1,391 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// Schema describes the columns of a Parquet file. It is created from SQL
+// column names and types and is immutable, so it can be shared by any number
+// of Writers.
+type Schema struct {
+	cols []column
+	// elements is the flattened Parquet schema, as written to the footer.
+	elements []schemaElement
+}
+
+// column is a top-level column of a Schema.
+type column struct {
+	name string
+	typ  *types.T
+	// leaf describes how the values of the column (or of its elements, for
+	// arrays) are stored.
+	leaf leafType
+	// isArray is set for columns written as a three-level LIST:
+	//
+	//   optional group <name> (LIST) {
+	//     repeated group list {
+	//       optional <leaf> element;
+	//     }
+	//   }
+	//
+	// whose maximum definition level is 3 and maximum repetition level is 1.
+	// Other columns have a maximum definition level of 1 and no repetition
+	// levels.
+	isArray bool
+	// path is the path of the leaf in the schema, as recorded in the column
+	// chunk metadata.
+	path []string
+}
+
+// leafType is the physical and logical type of a leaf column, along with the
+// function used to encode datums of that type.
+type leafType struct {
+	physical   physicalType
+	typeLength int32
+	converted  convertedType
+	logical    logicalType
+	// encode appends the PLAIN encoding of a non-NULL datum to the chunk.
+	encode func(c *columnChunk, d tree.Datum) error
+}
+
+// NewSchema returns a Schema for columns with the given names and types. All
+// columns are optional (nullable). An error is returned for types that cannot
+// be represented in Parquet, which currently are tuples and nested arrays.
+func NewSchema(names []string, typs []*types.T) (*Schema, error) {
+	if len(names) != len(typs) {
+		return nil, errors.AssertionFailedf(
+			"expected %d column names, got %d", len(typs), len(names))
+	}
+	s := &Schema{cols: make([]column, len(typs))}
+	s.elements = append(s.elements, schemaElement{
+		name:        "schema",
+		repetition:  repetitionRequired,
+		numChildren: int32(len(typs)),
+		converted:   convertedNone,
+	})
+	for i, typ := range typs {
+		c := column{name: names[i], typ: typ}
+		leafTyp := typ
+		if typ.Family() == types.ArrayFamily {
+			c.isArray = true
+			leafTyp = typ.ArrayContents()
+		}
+		var ok bool
+		if c.leaf, ok = makeLeafType(leafTyp); !ok {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"column %q: type %s is not supported by the parquet format", names[i], typ)
+		}
+		if c.isArray {
+			c.path = []string{names[i], "list", "element"}
+			s.elements = append(s.elements,
+				schemaElement{
+					name:        names[i],
+					repetition:  repetitionOptional,
+					numChildren: 1,
+					converted:   convertedList,
+					logical:     logicalType{id: logicalList},
+				},
+				schemaElement{
+					name:        "list",
+					repetition:  repetitionRepeated,
+					numChildren: 1,
+					converted:   convertedNone,
+				},
+				c.leaf.schemaElement("element"),
+			)
+		} else {
+			c.path = []string{names[i]}
+			s.elements = append(s.elements, c.leaf.schemaElement(names[i]))
+		}
+		s.cols[i] = c
+	}
+	return s, nil
+}
+
+func (l *leafType) schemaElement(name string) schemaElement {
+	e := schemaElement{
+		name:        name,
+		physical:    l.physical,
+		hasPhysical: true,
+		typeLength:  l.typeLength,
+		repetition:  repetitionOptional,
+		converted:   l.converted,
+		logical:     l.logical,
+	}
+	if l.converted == convertedDecimal {
+		e.scale, e.precision = l.logical.scale, l.logical.precision
+	}
+	return e
+}
+
+// makeLeafType returns the leafType used for values of the given type, or
+// false if the type cannot be written to a leaf column.
+func makeLeafType(typ *types.T) (leafType, bool) {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return leafType{
+			physical:  physicalBoolean,
+			converted: convertedNone,
+			encode: func(c *columnChunk, d tree.Datum) error {
+				c.appendBool(bool(*d.(*tree.DBool)))
+				return nil
+			},
+		}, true
+
+	case types.IntFamily:
+		switch typ.Width() {
+		case 16, 32:
+			converted := convertedInt32
+			if typ.Width() == 16 {
+				converted = convertedInt16
+			}
+			return leafType{
+				physical:  physicalInt32,
+				converted: converted,
+				logical:   logicalType{id: logicalInteger, bitWidth: int8(typ.Width()), isSigned: true},
+				encode: func(c *columnChunk, d tree.Datum) error {
+					c.appendUint32(uint32(int32(*d.(*tree.DInt))))
+					return nil
+				},
+			}, true
+		default:
+			return leafType{
+				physical:  physicalInt64,
+				converted: convertedInt64,
+				logical:   logicalType{id: logicalInteger, bitWidth: 64, isSigned: true},
+				encode: func(c *columnChunk, d tree.Datum) error {
+					c.appendUint64(uint64(*d.(*tree.DInt)))
+					return nil
+				},
+			}, true
+		}
+
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			return leafType{
+				physical:  physicalFloat,
+				converted: convertedNone,
+				encode: func(c *columnChunk, d tree.Datum) error {
+					c.appendUint32(math.Float32bits(float32(*d.(*tree.DFloat))))
+					return nil
+				},
+			}, true
+		}
+		return leafType{
+			physical:  physicalDouble,
+			converted: convertedNone,
+			encode: func(c *columnChunk, d tree.Datum) error {
+				c.appendUint64(math.Float64bits(float64(*d.(*tree.DFloat))))
+				return nil
+			},
+		}, true
+
+	case types.DecimalFamily:
+		if typ.Precision() == 0 {
+			// Without a precision there is no Parquet DECIMAL that can hold every
+			// value, so unconstrained decimals are written as strings.
+			return stringLeafType(), true
+		}
+		precision, scale := typ.Precision(), typ.Scale()
+		return leafType{
+			physical:  physicalByteArray,
+			converted: convertedDecimal,
+			logical: logicalType{
+				id: logicalDecimal, scale: scale, precision: precision,
+			},
+			encode: func(c *columnChunk, d tree.Datum) error {
+				b, err := encodeDecimal(c.scratch[:0], &d.(*tree.DDecimal).Decimal, precision, scale)
+				if err != nil {
+					return err
+				}
+				c.scratch = b
+				c.appendByteArray(b)
+				return nil
+			},
+		}, true
+
+	case types.StringFamily, types.CollatedStringFamily:
+		return stringLeafType(), true
+
+	case types.BytesFamily:
+		return leafType{
+			physical:  physicalByteArray,
+			converted: convertedNone,
+			encode: func(c *columnChunk, d tree.Datum) error {
+				c.appendByteArray([]byte(*d.(*tree.DBytes)))
+				return nil
+			},
+		}, true
+
+	case types.DateFamily:
+		return leafType{
+			physical:  physicalInt32,
+			converted: convertedDate,
+			logical:   logicalType{id: logicalDate},
+			encode: func(c *columnChunk, d tree.Datum) error {
+				date := d.(*tree.DDate).Date
+				if !date.IsFinite() {
+					return pgerror.Newf(pgcode.DatetimeFieldOverflow,
+						"cannot write infinite date %s to parquet", d)
+				}
+				c.appendUint32(uint32(int32(date.UnixEpochDays())))
+				return nil
+			},
+		}, true
+
+	case types.TimestampFamily, types.TimestampTZFamily:
+		// The TIMESTAMP_MICROS converted type implies that values are adjusted to
+		// UTC, so only the logical type is set for TIMESTAMP.
+		converted := convertedNone
+		if typ.Family() == types.TimestampTZFamily {
+			converted = convertedTimestampMicros
+		}
+		return leafType{
+			physical:  physicalInt64,
+			converted: converted,
+			logical: logicalType{
+				id: logicalTimestamp, isAdjustedToUTC: typ.Family() == types.TimestampTZFamily,
+			},
+			encode: func(c *columnChunk, d tree.Datum) error {
+				switch t := d.(type) {
+				case *tree.DTimestamp:
+					c.appendUint64(uint64(t.Unix()*1e6 + int64(t.Nanosecond()/1e3)))
+				case *tree.DTimestampTZ:
+					c.appendUint64(uint64(t.Unix()*1e6 + int64(t.Nanosecond()/1e3)))
+				}
+				return nil
+			},
+		}, true
+
+	case types.TimeFamily:
+		// As for TIMESTAMP, the TIME_MICROS converted type would imply that
+		// values are adjusted to UTC.
+		return leafType{
+			physical:  physicalInt64,
+			converted: convertedNone,
+			logical:   logicalType{id: logicalTime},
+			encode: func(c *columnChunk, d tree.Datum) error {
+				// TimeOfDay is a number of microseconds since midnight.
+				c.appendUint64(uint64(*d.(*tree.DTime)))
+				return nil
+			},
+		}, true
+
+	case types.UuidFamily:
+		return leafType{
+			physical:   physicalFixedLenByteArray,
+			typeLength: 16,
+			converted:  convertedNone,
+			logical:    logicalType{id: logicalUUID},
+			encode: func(c *columnChunk, d tree.Datum) error {
+				c.values = append(c.values, d.(*tree.DUuid).GetBytes()...)
+				return nil
+			},
+		}, true
+
+	case types.JsonFamily:
+		return leafType{
+			physical:  physicalByteArray,
+			converted: convertedJSON,
+			logical:   logicalType{id: logicalJSON},
+			encode: func(c *columnChunk, d tree.Datum) error {
+				c.appendString(d.(*tree.DJSON).JSON.String())
+				return nil
+			},
+		}, true
+
+	case types.TupleFamily, types.ArrayFamily:
+		return leafType{}, false
+
+	default:
+		// Everything else (intervals, inet, bit strings, geospatial types, etc.)
+		// has no Parquet counterpart and is written using its text
+		// representation.
+		return stringLeafType(), true
+	}
+}
+
+func stringLeafType() leafType {
+	return leafType{
+		physical:  physicalByteArray,
+		converted: convertedUTF8,
+		logical:   logicalType{id: logicalString},
+		encode: func(c *columnChunk, d tree.Datum) error {
+			switch t := d.(type) {
+			case *tree.DString:
+				c.appendString(string(*t))
+			case *tree.DCollatedString:
+				c.appendString(t.Contents)
+			default:
+				c.fmtCtx.Reset()
+				c.fmtCtx.FormatNode(d)
+				c.appendByteArray(c.fmtCtx.Bytes())
+			}
+			return nil
+		},
+	}
+}
+
+// encodeDecimal appends the unscaled value of d, rounded to the given scale,
+// as a big-endian two's complement integer, which is the representation
+// Parquet uses for DECIMAL values stored in byte arrays.
+func encodeDecimal(appendTo []byte, d *apd.Decimal, precision, scale int32) ([]byte, error) {
+	if d.Form != apd.Finite {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"cannot write decimal %s to parquet", d)
+	}
+	var q apd.Decimal
+	q.Set(d)
+	if err := tree.LimitDecimalWidth(&q, int(precision), int(scale)); err != nil {
+		return nil, err
+	}
+	// LimitDecimalWidth may leave a smaller exponent than the scale (for
+	// example for zero), so scale the coefficient explicitly.
+	var v big.Int
+	v.Set(&q.Coeff)
+	if shift := int64(q.Exponent) + int64(scale); shift > 0 {
+		v.Mul(&v, new(big.Int).Exp(big.NewInt(10), big.NewInt(shift), nil))
+	} else if shift < 0 {
+		v.Quo(&v, new(big.Int).Exp(big.NewInt(10), big.NewInt(-shift), nil))
+	}
+	// A negative number -x is written as the bitwise complement of x-1.
+	negative := q.Negative && v.Sign() != 0
+	if negative {
+		v.Sub(&v, big.NewInt(1))
+	}
+	b := v.Bytes()
+	start := len(appendTo)
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		// Make room for the sign bit.
+		appendTo = append(appendTo, 0)
+	}
+	appendTo = append(appendTo, b...)
+	if negative {
+		for i := start; i < len(appendTo); i++ {
+			appendTo[i] = ^appendTo[i]
+		}
+	}
+	return appendTo, nil
+}
+
+// The PLAIN encoding of fixed-width values is little-endian.
+var le = binary.LittleEndian
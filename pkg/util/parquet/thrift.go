@@ -0,0 +1,315 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Parquet stores all of its metadata (the file footer and the page headers)
+// as Thrift structs serialized with the Thrift compact protocol. Only the
+// small subset of the protocol needed for those structs is implemented here.
+//
+// See https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+
+// Compact protocol type identifiers, as they appear in field headers and list
+// headers.
+const (
+	thriftBoolTrue  byte = 1
+	thriftBoolFalse byte = 2
+	thriftByte      byte = 3
+	thriftI16       byte = 4
+	thriftI32       byte = 5
+	thriftI64       byte = 6
+	thriftDouble    byte = 7
+	thriftBinary    byte = 8
+	thriftList      byte = 9
+	thriftSet       byte = 10
+	thriftMap       byte = 11
+	thriftStruct    byte = 12
+)
+
+// thriftWriter serializes Thrift structs with the compact protocol.
+type thriftWriter struct {
+	buf []byte
+	// lastFieldID is a stack with the id of the last field written in each
+	// of the structs currently being written. The compact protocol encodes
+	// field ids as a delta from the previous one.
+	lastFieldID []int16
+}
+
+func (w *thriftWriter) reset() {
+	w.buf = w.buf[:0]
+	w.lastFieldID = w.lastFieldID[:0]
+}
+
+func (w *thriftWriter) writeVarint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	w.buf = append(w.buf, scratch[:n]...)
+}
+
+func (w *thriftWriter) writeZigZag(v int64) {
+	w.writeVarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) structBegin() {
+	w.lastFieldID = append(w.lastFieldID, 0)
+}
+
+func (w *thriftWriter) structEnd() {
+	w.buf = append(w.buf, 0 /* stop */)
+	w.lastFieldID = w.lastFieldID[:len(w.lastFieldID)-1]
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastFieldID[len(w.lastFieldID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.writeZigZag(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) boolField(id int16, v bool) {
+	if v {
+		w.fieldHeader(id, thriftBoolTrue)
+	} else {
+		w.fieldHeader(id, thriftBoolFalse)
+	}
+}
+
+func (w *thriftWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.writeZigZag(int64(v))
+}
+
+func (w *thriftWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.writeZigZag(v)
+}
+
+func (w *thriftWriter) binaryField(id int16, v []byte) {
+	w.fieldHeader(id, thriftBinary)
+	w.writeBinary(v)
+}
+
+func (w *thriftWriter) stringField(id int16, v string) {
+	w.fieldHeader(id, thriftBinary)
+	w.writeString(v)
+}
+
+// structFieldBegin starts a field holding a nested struct. It must be
+// followed by the nested struct's fields and a call to structEnd.
+func (w *thriftWriter) structFieldBegin(id int16) {
+	w.fieldHeader(id, thriftStruct)
+	w.structBegin()
+}
+
+// listFieldBegin starts a field holding a list of n elements of the given
+// type. It must be followed by exactly n elements.
+func (w *thriftWriter) listFieldBegin(id int16, elemType byte, n int) {
+	w.fieldHeader(id, thriftList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elemType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elemType)
+		w.writeVarint(uint64(n))
+	}
+}
+
+func (w *thriftWriter) writeI32(v int32) {
+	w.writeZigZag(int64(v))
+}
+
+func (w *thriftWriter) writeBinary(v []byte) {
+	w.writeVarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+func (w *thriftWriter) writeString(v string) {
+	w.writeVarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// thriftStructValue is a decoded Thrift struct, keyed by field id. Values are
+// bool, int64 (for every integer type), float64, []byte, []interface{} (for
+// lists and sets) and thriftStructValue (for nested structs). Maps are not
+// used by Parquet and are not supported.
+type thriftStructValue map[int16]interface{}
+
+func (s thriftStructValue) i64(id int16) int64 {
+	v, _ := s[id].(int64)
+	return v
+}
+
+func (s thriftStructValue) bytes(id int16) []byte {
+	v, _ := s[id].([]byte)
+	return v
+}
+
+func (s thriftStructValue) strct(id int16) thriftStructValue {
+	v, _ := s[id].(thriftStructValue)
+	return v
+}
+
+func (s thriftStructValue) list(id int16) []interface{} {
+	v, _ := s[id].([]interface{})
+	return v
+}
+
+func (s thriftStructValue) has(id int16) bool {
+	_, ok := s[id]
+	return ok
+}
+
+// thriftReader decodes Thrift structs serialized with the compact protocol.
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+var errThriftTruncated = errors.New("parquet: truncated thrift data")
+
+func (r *thriftReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errThriftTruncated
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) readVarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errThriftTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) readZigZag() (int64, error) {
+	v, err := r.readVarint()
+	if err != nil {
+		return 0, err
+	}
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+func (r *thriftReader) readBinary() ([]byte, error) {
+	n, err := r.readVarint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.buf)-r.pos) < n {
+		return nil, errThriftTruncated
+	}
+	v := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return v, nil
+}
+
+// readStruct decodes a struct, up to and including its stop byte.
+func (r *thriftReader) readStruct() (thriftStructValue, error) {
+	s := make(thriftStructValue)
+	var lastID int16
+	for {
+		b, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == 0 {
+			return s, nil
+		}
+		typ := b & 0x0f
+		var id int16
+		if delta := int16(b >> 4); delta != 0 {
+			id = lastID + delta
+		} else {
+			v, err := r.readZigZag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		lastID = id
+		switch typ {
+		case thriftBoolTrue:
+			s[id] = true
+		case thriftBoolFalse:
+			s[id] = false
+		default:
+			if s[id], err = r.readValue(typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) (interface{}, error) {
+	switch typ {
+	case thriftByte:
+		b, err := r.readByte()
+		return int64(int8(b)), err
+	case thriftI16, thriftI32, thriftI64:
+		return r.readZigZag()
+	case thriftDouble:
+		if len(r.buf)-r.pos < 8 {
+			return nil, errThriftTruncated
+		}
+		v := binary.LittleEndian.Uint64(r.buf[r.pos:])
+		r.pos += 8
+		return math.Float64frombits(v), nil
+	case thriftBinary:
+		return r.readBinary()
+	case thriftList, thriftSet:
+		h, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		n := uint64(h >> 4)
+		if n == 15 {
+			if n, err = r.readVarint(); err != nil {
+				return nil, err
+			}
+		}
+		elemType := h & 0x0f
+		if n > uint64(len(r.buf)-r.pos) {
+			// Every element takes at least one byte.
+			return nil, errThriftTruncated
+		}
+		l := make([]interface{}, n)
+		for i := range l {
+			if elemType == thriftBoolTrue || elemType == thriftBoolFalse {
+				b, err := r.readByte()
+				if err != nil {
+					return nil, err
+				}
+				l[i] = b == thriftBoolTrue
+				continue
+			}
+			if l[i], err = r.readValue(elemType); err != nil {
+				return nil, err
+			}
+		}
+		return l, nil
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		return nil, errors.Errorf("parquet: unsupported thrift type %d", typ)
+	}
+}
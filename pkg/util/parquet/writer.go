@@ -0,0 +1,373 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package parquet implements a writer and a reader for the Apache Parquet
+// columnar file format, as used by EXPORT, IMPORT and changefeeds. The writer
+// supports the subset of the format needed to write SQL rows: flat schemas
+// whose columns are either scalars or one-dimensional arrays, PLAIN encoded
+// values and optional gzip compression. The reader additionally understands
+// the dictionary encodings and snappy compression used by most other writers;
+// see Reader.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// DefaultRowGroupSize is the number of rows in each row group of a file when
+// WriterOptions.RowGroupSize is not set.
+const DefaultRowGroupSize = 10000
+
+// WriterOptions configures a Writer.
+type WriterOptions struct {
+	// RowGroupSize is the number of rows buffered in memory before they are
+	// written out as a row group.
+	RowGroupSize int
+	// Compression is the codec used to compress every page of the file.
+	Compression CompressionCodec
+}
+
+// Writer writes rows to a Parquet file. Rows are buffered in memory column by
+// column and written to the underlying io.Writer one row group at a time.
+// Every column chunk is written as a single PLAIN encoded data page. The file
+// is only valid once Close has returned. A Writer is not safe for concurrent
+// use.
+type Writer struct {
+	sch  *Schema
+	w    io.Writer
+	opts WriterOptions
+
+	chunks      []columnChunk
+	rowsInGroup int
+	numRows     int64
+	rowGroups   []rowGroupMeta
+	// offset is the number of bytes written to w so far.
+	offset int64
+
+	tw         thriftWriter
+	page       []byte
+	compressed bytes.Buffer
+	gz         *gzip.Writer
+	closed     bool
+}
+
+// NewWriter returns a Writer that writes a Parquet file with the given schema
+// to w.
+func NewWriter(sch *Schema, w io.Writer, opts WriterOptions) *Writer {
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = DefaultRowGroupSize
+	}
+	pw := &Writer{
+		sch:    sch,
+		w:      w,
+		opts:   opts,
+		chunks: make([]columnChunk, len(sch.cols)),
+	}
+	fmtCtx := tree.NewFmtCtx(tree.FmtExport)
+	for i := range pw.chunks {
+		pw.chunks[i].fmtCtx = fmtCtx
+	}
+	return pw
+}
+
+// AddRow adds a row to the file. The datums must match the types of the
+// columns the Schema was created with. If an error is returned, the row is
+// not added but the Writer can still be used.
+func (w *Writer) AddRow(row tree.Datums) error {
+	if w.closed {
+		return errors.AssertionFailedf("parquet writer is closed")
+	}
+	if len(row) != len(w.chunks) {
+		return errors.AssertionFailedf("expected %d datums, got %d", len(w.chunks), len(row))
+	}
+	for i := range row {
+		if err := w.chunks[i].add(&w.sch.cols[i], row[i]); err != nil {
+			// Drop the values that were already added for this row, so that every
+			// column chunk has the same number of rows.
+			for j := 0; j <= i; j++ {
+				w.chunks[j].truncate(w.rowsInGroup)
+			}
+			return err
+		}
+	}
+	w.rowsInGroup++
+	w.numRows++
+	if w.rowsInGroup >= w.opts.RowGroupSize {
+		return w.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes out any buffered rows followed by the file footer. It does not
+// close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if w.rowsInGroup > 0 {
+		if err := w.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+	w.closed = true
+
+	w.tw.reset()
+	encodeFileMetaData(&w.tw, w.sch.elements, w.numRows, w.rowGroups)
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(w.tw.buf)))
+	if err := w.write(w.tw.buf); err != nil {
+		return err
+	}
+	if err := w.write(footerLen[:]); err != nil {
+		return err
+	}
+	return w.write([]byte(magic))
+}
+
+// write writes b to the underlying io.Writer, preceded by the leading magic
+// bytes if nothing has been written yet.
+func (w *Writer) write(b []byte) error {
+	if w.offset == 0 {
+		if _, err := io.WriteString(w.w, magic); err != nil {
+			return err
+		}
+		w.offset += int64(len(magic))
+	}
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+func (w *Writer) flushRowGroup() error {
+	rg := rowGroupMeta{
+		columns: make([]columnChunkMeta, len(w.chunks)),
+		numRows: int64(w.rowsInGroup),
+	}
+	for i := range w.chunks {
+		col, c := &w.sch.cols[i], &w.chunks[i]
+
+		w.page = w.page[:0]
+		if col.isArray {
+			w.page = appendLevels(w.page, c.repLevels)
+		}
+		w.page = appendLevels(w.page, c.defLevels)
+		w.page = appendBools(w.page, c.bools)
+		w.page = append(w.page, c.values...)
+
+		body := w.page
+		if w.opts.Compression == CompressionGzip {
+			w.compressed.Reset()
+			if w.gz == nil {
+				w.gz = gzip.NewWriter(&w.compressed)
+			} else {
+				w.gz.Reset(&w.compressed)
+			}
+			if _, err := w.gz.Write(w.page); err != nil {
+				return err
+			}
+			if err := w.gz.Close(); err != nil {
+				return err
+			}
+			body = w.compressed.Bytes()
+		}
+
+		w.tw.reset()
+		encodeDataPageHeader(&w.tw, int32(len(c.defLevels)), int32(len(w.page)), int32(len(body)))
+		headerLen := int64(len(w.tw.buf))
+		if err := w.write(w.tw.buf); err != nil {
+			return err
+		}
+		// The page header is the first thing written for the chunk; write adds the
+		// leading magic bytes before it if this is the first chunk of the file.
+		pageOffset := w.offset - headerLen
+		if err := w.write(body); err != nil {
+			return err
+		}
+
+		rg.columns[i] = columnChunkMeta{
+			physical:              col.leaf.physical,
+			path:                  col.path,
+			codec:                 w.opts.Compression,
+			numValues:             int64(len(c.defLevels)),
+			totalUncompressedSize: headerLen + int64(len(w.page)),
+			totalCompressedSize:   headerLen + int64(len(body)),
+			dataPageOffset:        pageOffset,
+		}
+		rg.totalByteSize += rg.columns[i].totalUncompressedSize
+		c.reset()
+	}
+	w.rowGroups = append(w.rowGroups, rg)
+	w.rowsInGroup = 0
+	return nil
+}
+
+// columnChunk buffers the levels and values of a column for the current row
+// group.
+type columnChunk struct {
+	// defLevels has an entry for every value, including NULLs and empty arrays.
+	// repLevels has the same length but is only used for arrays.
+	defLevels []uint8
+	repLevels []uint8
+	// values holds the PLAIN encoded non-NULL values, except for booleans which
+	// are kept in bools and bit-packed when the page is written.
+	values []byte
+	bools  []bool
+	// rowStarts has the index in defLevels, values and bools at which each
+	// row of the row group starts, so that a partially added row can be
+	// dropped.
+	rowStarts []chunkPos
+
+	scratch []byte
+	fmtCtx  *tree.FmtCtx
+}
+
+type chunkPos struct {
+	levels, values, bools int
+}
+
+func (c *columnChunk) add(col *column, d tree.Datum) error {
+	c.rowStarts = append(c.rowStarts, chunkPos{
+		levels: len(c.defLevels), values: len(c.values), bools: len(c.bools),
+	})
+	d = tree.UnwrapDatum(nil, d)
+	if !col.isArray {
+		if d == tree.DNull {
+			c.defLevels = append(c.defLevels, 0)
+			return nil
+		}
+		c.defLevels = append(c.defLevels, 1)
+		return col.leaf.encode(c, d)
+	}
+
+	if d == tree.DNull {
+		c.defLevels = append(c.defLevels, 0)
+		c.repLevels = append(c.repLevels, 0)
+		return nil
+	}
+	arr, ok := d.(*tree.DArray)
+	if !ok {
+		return errors.AssertionFailedf("expected array for column %q, got %T", col.name, d)
+	}
+	if len(arr.Array) == 0 {
+		c.defLevels = append(c.defLevels, 1)
+		c.repLevels = append(c.repLevels, 0)
+		return nil
+	}
+	for i, elem := range arr.Array {
+		if i == 0 {
+			c.repLevels = append(c.repLevels, 0)
+		} else {
+			c.repLevels = append(c.repLevels, 1)
+		}
+		elem = tree.UnwrapDatum(nil, elem)
+		if elem == tree.DNull {
+			c.defLevels = append(c.defLevels, 2)
+			continue
+		}
+		c.defLevels = append(c.defLevels, 3)
+		if err := col.leaf.encode(c, elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncate drops everything added for the rows at or after the given one.
+func (c *columnChunk) truncate(row int) {
+	if row >= len(c.rowStarts) {
+		return
+	}
+	pos := c.rowStarts[row]
+	c.defLevels = c.defLevels[:pos.levels]
+	if len(c.repLevels) > pos.levels {
+		c.repLevels = c.repLevels[:pos.levels]
+	}
+	c.values = c.values[:pos.values]
+	c.bools = c.bools[:pos.bools]
+	c.rowStarts = c.rowStarts[:row]
+}
+
+func (c *columnChunk) reset() {
+	c.defLevels = c.defLevels[:0]
+	c.repLevels = c.repLevels[:0]
+	c.values = c.values[:0]
+	c.bools = c.bools[:0]
+	c.rowStarts = c.rowStarts[:0]
+}
+
+func (c *columnChunk) appendBool(v bool) {
+	c.bools = append(c.bools, v)
+}
+
+func (c *columnChunk) appendUint32(v uint32) {
+	var b [4]byte
+	le.PutUint32(b[:], v)
+	c.values = append(c.values, b[:]...)
+}
+
+func (c *columnChunk) appendUint64(v uint64) {
+	var b [8]byte
+	le.PutUint64(b[:], v)
+	c.values = append(c.values, b[:]...)
+}
+
+// appendByteArray appends a BYTE_ARRAY value, which is a 4-byte little-endian
+// length followed by the bytes.
+func (c *columnChunk) appendByteArray(v []byte) {
+	c.appendUint32(uint32(len(v)))
+	c.values = append(c.values, v...)
+}
+
+func (c *columnChunk) appendString(v string) {
+	c.appendUint32(uint32(len(v)))
+	c.values = append(c.values, v...)
+}
+
+// appendLevels appends repetition or definition levels using the RLE/bit-packed
+// hybrid encoding, preceded by its 4-byte little-endian length as required in
+// v1 data pages. Only RLE runs are written. Since levels never exceed 3, every
+// run's value fits in a single byte.
+func appendLevels(buf []byte, levels []uint8) []byte {
+	lenPos := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+	var scratch [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		n := binary.PutUvarint(scratch[:], uint64(j-i)<<1)
+		buf = append(buf, scratch[:n]...)
+		buf = append(buf, levels[i])
+		i = j
+	}
+	le.PutUint32(buf[lenPos:], uint32(len(buf)-lenPos-4))
+	return buf
+}
+
+// appendBools appends PLAIN encoded booleans, which are bit-packed starting
+// with the least significant bit.
+func appendBools(buf []byte, bools []bool) []byte {
+	for i, v := range bools {
+		if i%8 == 0 {
+			buf = append(buf, 0)
+		}
+		if v {
+			buf[len(buf)-1] |= 1 << uint(i%8)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,297 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// testFile is a Parquet file decoded by readTestFile.
+type testFile struct {
+	meta thriftStructValue
+	// rows has, for every row, the value of each column: nil for NULL, a
+	// []interface{} for arrays and otherwise a bool, int64, float64 or string
+	// depending on the physical type.
+	rows [][]interface{}
+}
+
+// readTestFile decodes a file written by Writer. It only understands what
+// Writer produces: a single PLAIN encoded v1 data page per column chunk with
+// RLE encoded levels.
+func readTestFile(t *testing.T, data []byte) testFile {
+	t.Helper()
+	require.True(t, bytes.HasPrefix(data, []byte(magic)))
+	require.True(t, bytes.HasSuffix(data, []byte(magic)))
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &thriftReader{buf: data[len(data)-8-footerLen : len(data)-8]}
+	meta, err := footer.readStruct()
+	require.NoError(t, err)
+
+	var f testFile
+	f.meta = meta
+	schema := meta.list(2)
+	for _, rg := range meta.list(4) {
+		rg := rg.(thriftStructValue)
+		numRows := int(rg.i64(3))
+		rows := make([][]interface{}, numRows)
+		for i := range rows {
+			rows[i] = make([]interface{}, len(rg.list(1)))
+		}
+		leaf := 0
+		for colIdx, cc := range rg.list(1) {
+			cm := cc.(thriftStructValue).strct(3)
+			isArray := len(cm.list(3)) == 3
+			// Find the schema element of the leaf, skipping the groups of arrays.
+			leaf++
+			if isArray {
+				leaf += 2
+			}
+			physical := physicalType(schema[leaf].(thriftStructValue).i64(1))
+
+			r := &thriftReader{buf: data, pos: int(cm.i64(9))}
+			header, err := r.readStruct()
+			require.NoError(t, err)
+			require.Equal(t, int64(pageTypeDataPage), header.i64(1))
+			page := data[r.pos : r.pos+int(header.i64(3))]
+			if CompressionCodec(cm.i64(4)) == CompressionGzip {
+				gz, err := gzip.NewReader(bytes.NewReader(page))
+				require.NoError(t, err)
+				page, err = ioutil.ReadAll(gz)
+				require.NoError(t, err)
+			}
+			require.Equal(t, int(header.i64(2)), len(page))
+			numValues := int(header.strct(5).i64(1))
+			require.Equal(t, cm.i64(5), int64(numValues))
+
+			var repLevels []uint8
+			if isArray {
+				repLevels, page = readTestLevels(t, page, numValues)
+			}
+			defLevels, page := readTestLevels(t, page, numValues)
+			maxDef := uint8(1)
+			if isArray {
+				maxDef = 3
+			}
+			var numNonNull int
+			for _, l := range defLevels {
+				if l == maxDef {
+					numNonNull++
+				}
+			}
+			values := readTestValues(t, physical, page, numNonNull)
+
+			row := -1
+			for i, def := range defLevels {
+				var v interface{}
+				if def == maxDef {
+					v, values = values[0], values[1:]
+				}
+				if !isArray {
+					rows[i][colIdx] = v
+					continue
+				}
+				if repLevels[i] == 0 {
+					row++
+					if def == 0 {
+						continue
+					}
+					rows[row][colIdx] = []interface{}{}
+					if def == 1 {
+						continue
+					}
+				}
+				rows[row][colIdx] = append(rows[row][colIdx].([]interface{}), v)
+			}
+		}
+		f.rows = append(f.rows, rows...)
+	}
+	return f
+}
+
+func readTestLevels(t *testing.T, page []byte, n int) ([]uint8, []byte) {
+	t.Helper()
+	length := int(binary.LittleEndian.Uint32(page))
+	b, rest := page[4:4+length], page[4+length:]
+	var levels []uint8
+	for len(b) > 0 {
+		header, k := binary.Uvarint(b)
+		require.True(t, k > 0)
+		require.Zero(t, header&1, "bit-packed runs are not expected")
+		for i := uint64(0); i < header>>1; i++ {
+			levels = append(levels, b[k])
+		}
+		b = b[k+1:]
+	}
+	require.Len(t, levels, n)
+	return levels, rest
+}
+
+func readTestValues(t *testing.T, physical physicalType, b []byte, n int) []interface{} {
+	t.Helper()
+	values := make([]interface{}, n)
+	for i := range values {
+		switch physical {
+		case physicalBoolean:
+			values[i] = b[i/8]&(1<<uint(i%8)) != 0
+		case physicalInt32:
+			values[i] = int64(int32(binary.LittleEndian.Uint32(b)))
+			b = b[4:]
+		case physicalInt64:
+			values[i] = int64(binary.LittleEndian.Uint64(b))
+			b = b[8:]
+		case physicalDouble:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+			b = b[8:]
+		case physicalByteArray:
+			l := int(binary.LittleEndian.Uint32(b))
+			values[i] = string(b[4 : 4+l])
+			b = b[4+l:]
+		case physicalFixedLenByteArray:
+			values[i] = string(b[:16])
+			b = b[16:]
+		default:
+			t.Fatalf("unexpected physical type %d", physical)
+		}
+	}
+	return values
+}
+
+func TestWriter(t *testing.T) {
+	names := []string{`b`, `i4`, `i`, `f`, `d`, `s`, `date`, `ts`, `u`, `j`, `a`, `interval`}
+	typs := []*types.T{
+		types.Bool, types.Int4, types.Int, types.Float, types.MakeDecimal(10, 2), types.String,
+		types.Date, types.TimestampTZ, types.Uuid, types.Jsonb, types.MakeArray(types.Int),
+		types.Interval,
+	}
+	sch, err := NewSchema(names, typs)
+	require.NoError(t, err)
+
+	decimal := func(s string) tree.Datum {
+		d, err := tree.ParseDDecimal(s)
+		require.NoError(t, err)
+		return d
+	}
+	date, err := pgdate.MakeDateFromUnixEpoch(18262)
+	require.NoError(t, err)
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	u := uuid.MakeV4()
+	j, err := tree.ParseDJSON(`{"a": [1, "b"]}`)
+	require.NoError(t, err)
+	arr := func(elems ...tree.Datum) tree.Datum {
+		a := tree.NewDArray(types.Int)
+		for _, e := range elems {
+			require.NoError(t, a.Append(e))
+		}
+		return a
+	}
+	interval, err := tree.ParseDInterval(`1 day 2 hours`)
+	require.NoError(t, err)
+
+	rows := []tree.Datums{
+		{
+			tree.DBoolTrue, tree.NewDInt(-7), tree.NewDInt(1 << 40), tree.NewDFloat(1.5),
+			decimal(`12.34`), tree.NewDString(`hello`), tree.NewDDate(date),
+			tree.MustMakeDTimestampTZ(ts, time.Microsecond), tree.NewDUuid(tree.DUuid{UUID: u}),
+			j, arr(tree.NewDInt(1), tree.DNull, tree.NewDInt(3)), interval,
+		},
+		{
+			tree.DBoolFalse, tree.DNull, tree.DNull, tree.DNull,
+			decimal(`-1.5`), tree.DNull, tree.DNull,
+			tree.DNull, tree.DNull,
+			tree.DNull, arr(), tree.DNull,
+		},
+		{
+			tree.DNull, tree.NewDInt(0), tree.NewDInt(-1), tree.NewDFloat(-0.25),
+			decimal(`0`), tree.NewDString(``), tree.DNull,
+			tree.DNull, tree.DNull,
+			tree.DNull, tree.DNull, tree.DNull,
+		},
+	}
+	expected := [][]interface{}{
+		{
+			true, int64(-7), int64(1 << 40), 1.5,
+			"\x04\xd2", `hello`, int64(18262),
+			ts.UnixNano() / 1000, string(u.GetBytes()),
+			`{"a": [1, "b"]}`, []interface{}{int64(1), nil, int64(3)}, `1 day 02:00:00`,
+		},
+		{
+			false, nil, nil, nil,
+			"\xff\x6a", nil, nil,
+			nil, nil,
+			nil, []interface{}{}, nil,
+		},
+		{
+			nil, int64(0), int64(-1), -0.25,
+			"\x00", ``, nil,
+			nil, nil,
+			nil, nil, nil,
+		},
+	}
+
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionGzip} {
+		t.Run(fmt.Sprintf("codec=%d", codec), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(sch, &buf, WriterOptions{RowGroupSize: 2, Compression: codec})
+			for i, row := range rows {
+				require.NoError(t, w.AddRow(row))
+				if i == 0 {
+					// A row that fails to encode is dropped without affecting the rest.
+					bad := append(tree.Datums(nil), row...)
+					bad[4] = decimal(`123456789.5`)
+					require.EqualError(t, w.AddRow(bad),
+						`value with precision 10, scale 2 must round to an absolute value less than 10^8`)
+				}
+			}
+			require.NoError(t, w.Close())
+
+			f := readTestFile(t, buf.Bytes())
+			require.Equal(t, int64(len(rows)), f.meta.i64(3))
+			require.Len(t, f.meta.list(4), 2)
+			require.Equal(t, expected, f.rows)
+
+			var elemNames []string
+			for _, e := range f.meta.list(2) {
+				elemNames = append(elemNames, string(e.(thriftStructValue).bytes(4)))
+			}
+			require.Equal(t, []string{
+				`schema`, `b`, `i4`, `i`, `f`, `d`, `s`, `date`, `ts`, `u`, `j`,
+				`a`, `list`, `element`, `interval`,
+			}, elemNames)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, NewWriter(sch, &buf, WriterOptions{}).Close())
+		f := readTestFile(t, buf.Bytes())
+		require.Zero(t, f.meta.i64(3))
+		require.Empty(t, f.rows)
+	})
+}
+
+func TestNewSchemaUnsupported(t *testing.T) {
+	_, err := NewSchema([]string{`t`}, []*types.T{types.MakeTuple([]*types.T{types.Int})})
+	require.EqualError(t, err,
+		`column "t": type tuple{int} is not supported by the parquet format`)
+}